@@ -0,0 +1,165 @@
+package kraken
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostBasisMethod the method matching closing trades with the trades which
+// opened a position
+type CostBasisMethod byte
+
+// String return a string value of the cost basis method
+func (m CostBasisMethod) String() string {
+	switch m {
+	case CostBasisFIFO:
+		return "fifo"
+	case CostBasisAverage:
+		return "average"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// CostBasisFIFO enum representing closing the earliest opened volume first
+	CostBasisFIFO = iota
+	// CostBasisAverage enum representing closing volume at the average cost of
+	// the position
+	CostBasisAverage
+	// CostBasisUnknown enum representing an unknown cost basis method
+	CostBasisUnknown
+)
+
+// PairPnL the realized profit and loss of the trades of a pair and the
+// position they leave open, in the quote currency of the pair. Position is
+// positive when long and negative when short, CostBasis is the cost of the
+// open volume including the fees paid opening it and AverageEntryPrice is the
+// cost basis per unit of the open volume
+type PairPnL struct {
+	Pair              string
+	Position          decimal.Decimal
+	AverageEntryPrice decimal.Decimal
+	CostBasis         decimal.Decimal
+	RealizedPnL       decimal.Decimal
+	Fees              decimal.Decimal
+	Trades            int
+}
+
+// costBasisLot open volume of a position at a cost per unit including fees
+type costBasisLot struct {
+	volume decimal.Decimal
+	price  decimal.Decimal
+}
+
+// RealizedPnL return the realized profit and loss, by pair, of trades such as
+// those of a TradesHistory response, matching the volume of closing trades
+// with open volume by a cost basis method. Fees are added to the cost of
+// opening volume, so a long position is opened at its price plus the fee and
+// a short at its price less the fee, and subtracted from the profit of
+// closing volume. A trade closing more than the open volume opens a position
+// in its direction with the remainder, its fee split between the two by
+// volume. The trades need not be sorted, they are processed in time order
+func RealizedPnL(trades []TradeHistoryEntry, method CostBasisMethod) (map[string]PairPnL, error) {
+	if method >= CostBasisUnknown {
+		return nil, fmt.Errorf("unknown cost basis method")
+	}
+
+	sorted := append([]TradeHistoryEntry(nil), trades...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Time.Equal(sorted[j].Time) {
+			return sorted[i].TransactionID < sorted[j].TransactionID
+		}
+
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	results := map[string]PairPnL{}
+	lots := map[string][]costBasisLot{}
+	for _, trade := range sorted {
+		if trade.Action >= OrderActionUnknown {
+			return nil, fmt.Errorf("trade %s has an unknown action", trade.TransactionID)
+		}
+
+		if !trade.Volume.IsPositive() {
+			return nil, fmt.Errorf("trade %s volume must be positive", trade.TransactionID)
+		}
+
+		if trade.Price.IsNegative() || trade.Cost.IsNegative() || trade.Fee.IsNegative() {
+			return nil, fmt.Errorf("trade %s price, cost and fee must not be negative", trade.TransactionID)
+		}
+
+		result := results[trade.Pair]
+		result.Pair = trade.Pair
+		result.Fees = result.Fees.Add(trade.Fee)
+		result.Trades++
+
+		cost := trade.Cost
+		if cost.IsZero() {
+			cost = trade.Price.Mul(trade.Volume)
+		}
+
+		price := cost.Div(trade.Volume)
+		fee := trade.Fee.Div(trade.Volume)
+		remaining := trade.Volume
+
+		// close open volume in the other direction
+		open := lots[trade.Pair]
+		for len(open) != 0 && remaining.IsPositive() && (result.Position.IsPositive() == (trade.Action == OrderActionSell)) {
+			volume := decimal.Min(open[0].volume, remaining)
+			profit := price.Sub(open[0].price)
+			if trade.Action == OrderActionBuy {
+				profit = profit.Neg()
+			}
+
+			result.RealizedPnL = result.RealizedPnL.Add(volume.Mul(profit.Sub(fee)))
+			if trade.Action == OrderActionBuy {
+				result.Position = result.Position.Add(volume)
+			} else {
+				result.Position = result.Position.Sub(volume)
+			}
+
+			remaining = remaining.Sub(volume)
+			if open[0].volume = open[0].volume.Sub(volume); !open[0].volume.IsPositive() {
+				open = open[1:]
+			}
+		}
+
+		// open volume in the direction of the trade with the remainder
+		if remaining.IsPositive() {
+			lot := costBasisLot{volume: remaining, price: price.Add(fee)}
+			if trade.Action == OrderActionSell {
+				lot.price = price.Sub(fee)
+				result.Position = result.Position.Sub(remaining)
+			} else {
+				result.Position = result.Position.Add(remaining)
+			}
+
+			if method == CostBasisAverage && len(open) != 0 {
+				volume := open[0].volume.Add(lot.volume)
+				lot.price = open[0].volume.Mul(open[0].price).Add(lot.volume.Mul(lot.price)).Div(volume)
+				lot.volume = volume
+				open = open[:0]
+			}
+
+			open = append(open, lot)
+		}
+		lots[trade.Pair] = open
+
+		result.CostBasis = decimal.Zero
+		for _, lot := range open {
+			result.CostBasis = result.CostBasis.Add(lot.volume.Mul(lot.price))
+		}
+
+		result.AverageEntryPrice = decimal.Zero
+		if !result.Position.IsZero() {
+			result.AverageEntryPrice = result.CostBasis.Div(result.Position.Abs())
+		}
+
+		results[trade.Pair] = result
+	}
+
+	return results, nil
+}
@@ -0,0 +1,161 @@
+package kraken_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// pnlTrade a trade of a pair at an offset in minutes from the epoch with a
+// price, volume and fee, its cost is left for RealizedPnL to calculate
+func pnlTrade(offset int, pair string, action kraken.OrderAction, volume, price, fee string) kraken.TradeHistoryEntry {
+	return kraken.TradeHistoryEntry{
+		TransactionID: fmt.Sprintf("T%d", offset),
+		Pair:          pair,
+		Time:          time.Unix(int64(offset)*60, 0).UTC(),
+		Action:        action,
+		Price:         decimal.RequireFromString(price),
+		Volume:        decimal.RequireFromString(volume),
+		Fee:           decimal.RequireFromString(fee),
+	}
+}
+
+// pnl a PairPnL of the XXBTZUSD pair
+func pnl(position, average, basis, realized, fees string, trades int) kraken.PairPnL {
+	return kraken.PairPnL{
+		Pair:              "XXBTZUSD",
+		Position:          decimal.RequireFromString(position),
+		AverageEntryPrice: decimal.RequireFromString(average),
+		CostBasis:         decimal.RequireFromString(basis),
+		RealizedPnL:       decimal.RequireFromString(realized),
+		Fees:              decimal.RequireFromString(fees),
+		Trades:            trades,
+	}
+}
+
+func TestRealizedPnL(t *testing.T) {
+	buy, sell := kraken.OrderAction(kraken.OrderActionBuy), kraken.OrderAction(kraken.OrderActionSell)
+	partial := []kraken.TradeHistoryEntry{
+		pnlTrade(0, "XXBTZUSD", buy, "1", "100", "1"),
+		pnlTrade(1, "XXBTZUSD", buy, "1", "120", "1"),
+		pnlTrade(2, "XXBTZUSD", sell, "1.5", "130", "1.5"),
+	}
+
+	tcs := []struct {
+		name     string
+		trades   []kraken.TradeHistoryEntry
+		method   kraken.CostBasisMethod
+		expected map[string]kraken.PairPnL
+	}{
+		{
+			name:     "FIFO",
+			trades:   partial,
+			method:   kraken.CostBasisFIFO,
+			expected: map[string]kraken.PairPnL{"XXBTZUSD": pnl("0.5", "121", "60.5", "32", "3.5", 3)},
+		},
+		{
+			name:     "Average",
+			trades:   partial,
+			method:   kraken.CostBasisAverage,
+			expected: map[string]kraken.PairPnL{"XXBTZUSD": pnl("0.5", "111", "55.5", "27", "3.5", 3)},
+		},
+		{
+			name:     "Unsorted",
+			trades:   []kraken.TradeHistoryEntry{partial[2], partial[1], partial[0]},
+			method:   kraken.CostBasisFIFO,
+			expected: map[string]kraken.PairPnL{"XXBTZUSD": pnl("0.5", "121", "60.5", "32", "3.5", 3)},
+		},
+		{
+			name: "LongToShort",
+			trades: []kraken.TradeHistoryEntry{
+				pnlTrade(0, "XXBTZUSD", buy, "1", "100", "1"),
+				pnlTrade(1, "XXBTZUSD", sell, "3", "110", "3"),
+			},
+			method:   kraken.CostBasisFIFO,
+			expected: map[string]kraken.PairPnL{"XXBTZUSD": pnl("-2", "109", "218", "8", "4", 2)},
+		},
+		{
+			name: "LongToShortClosed",
+			trades: []kraken.TradeHistoryEntry{
+				pnlTrade(0, "XXBTZUSD", buy, "1", "100", "1"),
+				pnlTrade(1, "XXBTZUSD", sell, "3", "110", "3"),
+				pnlTrade(2, "XXBTZUSD", buy, "2", "100", "2"),
+			},
+			method:   kraken.CostBasisAverage,
+			expected: map[string]kraken.PairPnL{"XXBTZUSD": pnl("0", "0", "0", "24", "6", 3)},
+		},
+		{
+			name: "ShortToLong",
+			trades: []kraken.TradeHistoryEntry{
+				pnlTrade(0, "XXBTZUSD", sell, "2", "50", "1"),
+				pnlTrade(1, "XXBTZUSD", buy, "3", "40", "3"),
+			},
+			method:   kraken.CostBasisFIFO,
+			expected: map[string]kraken.PairPnL{"XXBTZUSD": pnl("1", "41", "41", "17", "4", 2)},
+		},
+		{
+			name: "Pairs",
+			trades: []kraken.TradeHistoryEntry{
+				pnlTrade(0, "XXBTZUSD", buy, "1", "100", "0"),
+				pnlTrade(1, "XETHZUSD", sell, "4", "10", "0.4"),
+				pnlTrade(2, "XXBTZUSD", sell, "1", "90", "0"),
+			},
+			method: kraken.CostBasisFIFO,
+			expected: map[string]kraken.PairPnL{
+				"XXBTZUSD": pnl("0", "0", "0", "-10", "0", 2),
+				"XETHZUSD": {
+					Pair:              "XETHZUSD",
+					Position:          decimal.RequireFromString("-4"),
+					AverageEntryPrice: decimal.RequireFromString("9.9"),
+					CostBasis:         decimal.RequireFromString("39.6"),
+					RealizedPnL:       decimal.Zero,
+					Fees:              decimal.RequireFromString("0.4"),
+					Trades:            1,
+				},
+			},
+		},
+		{
+			name:     "Empty",
+			method:   kraken.CostBasisFIFO,
+			expected: map[string]kraken.PairPnL{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := kraken.RealizedPnL(tc.trades, tc.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(actual, tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRealizedPnLErrors(t *testing.T) {
+	tcs := []struct {
+		name   string
+		trade  kraken.TradeHistoryEntry
+		method kraken.CostBasisMethod
+	}{
+		{name: "Method", trade: pnlTrade(0, "XXBTZUSD", kraken.OrderActionBuy, "1", "100", "0"), method: kraken.CostBasisUnknown},
+		{name: "Action", trade: pnlTrade(0, "XXBTZUSD", kraken.OrderActionUnknown, "1", "100", "0")},
+		{name: "Volume", trade: pnlTrade(0, "XXBTZUSD", kraken.OrderActionBuy, "0", "100", "0")},
+		{name: "Fee", trade: pnlTrade(0, "XXBTZUSD", kraken.OrderActionBuy, "1", "100", "-1")},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kraken.RealizedPnL([]kraken.TradeHistoryEntry{tc.trade}, tc.method); err == nil {
+				t.Error("EXPECTED: error\nACTUAL: <nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package kraken
+
+import (
+	"strings"
+	"sync"
+)
+
+// staticAssets the assets known without a response from the API, with their
+// common name, Kraken name and Kraken alternative name. The names of pairs of
+// two legacy assets join their Kraken names, such as "XXBTZUSD", and those of
+// other pairs join their alternative names, such as "XBTUSDT"
+var staticAssets = []struct {
+	common string
+	kraken string
+	alt    string
+	legacy bool
+}{
+	{common: "BTC", kraken: "XXBT", alt: "XBT", legacy: true},
+	{common: "ETH", kraken: "XETH", alt: "ETH", legacy: true},
+	{common: "LTC", kraken: "XLTC", alt: "LTC", legacy: true},
+	{common: "XRP", kraken: "XXRP", alt: "XRP", legacy: true},
+	{common: "XLM", kraken: "XXLM", alt: "XLM", legacy: true},
+	{common: "ETC", kraken: "XETC", alt: "ETC", legacy: true},
+	{common: "XMR", kraken: "XXMR", alt: "XMR", legacy: true},
+	{common: "ZEC", kraken: "XZEC", alt: "ZEC", legacy: true},
+	{common: "MLN", kraken: "XMLN", alt: "MLN", legacy: true},
+	{common: "REP", kraken: "XREP", alt: "REP", legacy: true},
+	// DOGE is a legacy asset but its pairs use its alternative name, such as
+	// "XDGUSD", and XDG is not the common name
+	{common: "DOGE", kraken: "XXDG", alt: "XDG"},
+	{common: "USD", kraken: "ZUSD", alt: "USD", legacy: true},
+	{common: "EUR", kraken: "ZEUR", alt: "EUR", legacy: true},
+	{common: "GBP", kraken: "ZGBP", alt: "GBP", legacy: true},
+	{common: "JPY", kraken: "ZJPY", alt: "JPY", legacy: true},
+	{common: "CAD", kraken: "ZCAD", alt: "CAD", legacy: true},
+	{common: "AUD", kraken: "ZAUD", alt: "AUD"},
+	{common: "USDT", kraken: "USDT", alt: "USDT"},
+	{common: "USDC", kraken: "USDC", alt: "USDC"},
+	{common: "DOT", kraken: "DOT", alt: "DOT"},
+	{common: "SOL", kraken: "SOL", alt: "SOL"},
+	{common: "ADA", kraken: "ADA", alt: "ADA"},
+}
+
+// defaultAssetNames the AssetNames of the static assets used by the package
+// level functions
+var defaultAssetNames = NewAssetNames()
+
+// NormalizeAsset return the common name of an asset given by its common,
+// Kraken or alternative name, such as "BTC" for "XXBT" or "XBT", using the
+// static assets. Unknown assets are returned upper cased
+func NormalizeAsset(name string) string {
+	return defaultAssetNames.NormalizeAsset(name)
+}
+
+// ToKrakenAsset return the Kraken name of an asset given by its common,
+// Kraken or alternative name, such as "XXBT" for "BTC" or "XBT", using the
+// static assets. Unknown assets are returned upper cased
+func ToKrakenAsset(name string) string {
+	return defaultAssetNames.ToKrakenAsset(name)
+}
+
+// NormalizePair return the common name of a pair, such as "BTC/USD" for
+// "BTCUSD", "XBT/USD" or "XXBTZUSD", using the static assets. Unknown pairs
+// are returned upper cased
+func NormalizePair(name string) string {
+	return defaultAssetNames.NormalizePair(name)
+}
+
+// ToKrakenPair return the Kraken name of a pair used by the REST API, such as
+// "XXBTZUSD" for "BTCUSD", "XBT/USD" or "BTC/USD", using the static assets.
+// Unknown pairs are returned upper cased
+func ToKrakenPair(name string) string {
+	return defaultAssetNames.ToKrakenPair(name)
+}
+
+// AssetNames map between the common and Kraken names of assets and pairs,
+// from the static assets and the assets and pairs added from responses of the
+// API. Names added from responses take precedence and the names are case
+// insensitive. It is safe for concurrent use
+type AssetNames struct {
+	mu         sync.RWMutex
+	assets     map[string]string
+	common     map[string]string
+	alt        map[string]string
+	legacy     map[string]bool
+	pairs      map[string]string
+	pairCommon map[string]string
+}
+
+// NewAssetNames helper function for creating a new AssetNames of the static
+// assets
+func NewAssetNames() *AssetNames {
+	n := AssetNames{
+		assets:     map[string]string{},
+		common:     map[string]string{},
+		alt:        map[string]string{},
+		legacy:     map[string]bool{},
+		pairs:      map[string]string{},
+		pairCommon: map[string]string{},
+	}
+
+	for _, asset := range staticAssets {
+		n.addAsset(asset.kraken, asset.common, asset.alt)
+		n.legacy[asset.kraken] = asset.legacy
+	}
+
+	return &n
+}
+
+// AddAssets add the assets of an Assets response, an asset not in the static
+// assets has its alternative name as its common name
+func (n *AssetNames) AddAssets(assets Assets) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for name, asset := range assets.Assets {
+		common, ok := n.common[name]
+		if !ok {
+			common = strings.ToUpper(asset.AltName)
+		}
+
+		n.addAsset(name, common, asset.AltName)
+	}
+}
+
+// AddAssetPairs add the pairs of an AssetPairs response, so they resolve by
+// their Kraken, alternative, websocket and common names
+func (n *AssetNames) AddAssetPairs(pairs AssetPairs) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for name, pair := range pairs.Pairs {
+		for _, asset := range []string{pair.Base, pair.Quote} {
+			if _, ok := n.common[asset]; !ok && asset != "" {
+				n.addAsset(asset, strings.ToUpper(asset), asset)
+			}
+		}
+
+		// pairs with a suffix, such as the dark pool "XXBTZUSD.d", do not
+		// take the common name of the pair without one
+		common := n.common[pair.Base] + "/" + n.common[pair.Quote]
+		aliases := []string{name, pair.AltName, pair.WebSocketName}
+		if !strings.Contains(name, ".") {
+			aliases = append(aliases, common)
+		}
+
+		for _, alias := range aliases {
+			if alias != "" {
+				n.pairs[strings.ToUpper(alias)] = name
+			}
+		}
+		n.pairCommon[name] = common
+	}
+}
+
+// NormalizeAsset return the common name of an asset given by its common,
+// Kraken or alternative name, unknown assets are returned upper cased
+func (n *AssetNames) NormalizeAsset(name string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if kraken, ok := n.assets[name]; ok {
+		return n.common[kraken]
+	}
+
+	return name
+}
+
+// ToKrakenAsset return the Kraken name of an asset given by its common,
+// Kraken or alternative name, unknown assets are returned upper cased
+func (n *AssetNames) ToKrakenAsset(name string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if kraken, ok := n.assets[name]; ok {
+		return kraken
+	}
+
+	return name
+}
+
+// NormalizePair return the common name of a pair given by its Kraken,
+// alternative, websocket or common name, with or without a "/" between the
+// assets. Unknown pairs, and those which can be split into known assets more
+// than one way, are returned upper cased
+func (n *AssetNames) NormalizePair(name string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if kraken, ok := n.pairs[name]; ok {
+		return n.pairCommon[kraken]
+	}
+
+	base, quote, ok := n.splitPair(name)
+	if !ok {
+		return name
+	}
+
+	return n.common[base] + "/" + n.common[quote]
+}
+
+// ToKrakenPair return the Kraken name of a pair used by the REST API given by
+// its Kraken, alternative, websocket or common name, with or without a "/"
+// between the assets. Pairs not added from a response are named following the
+// static assets. Unknown pairs, and those which can be split into known
+// assets more than one way, are returned upper cased
+func (n *AssetNames) ToKrakenPair(name string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if kraken, ok := n.pairs[name]; ok {
+		return kraken
+	}
+
+	base, quote, ok := n.splitPair(name)
+	if !ok {
+		return name
+	}
+
+	if kraken, ok := n.pairs[n.common[base]+"/"+n.common[quote]]; ok {
+		return kraken
+	}
+
+	if n.legacy[base] && n.legacy[quote] {
+		return base + quote
+	}
+
+	return n.alt[base] + n.alt[quote]
+}
+
+// addAsset add an asset by its Kraken name with its common and alternative
+// names
+func (n *AssetNames) addAsset(kraken, common, alt string) {
+	if alt == "" {
+		alt = kraken
+	}
+
+	n.common[kraken] = common
+	n.alt[kraken] = strings.ToUpper(alt)
+	for _, alias := range []string{kraken, common, alt} {
+		n.assets[strings.ToUpper(alias)] = kraken
+	}
+}
+
+// splitPair the Kraken names of the base and quote assets of a pair name,
+// false when the name is not a pair of known assets or can be split more than
+// one way
+func (n *AssetNames) splitPair(name string) (string, string, bool) {
+	if parts := strings.Split(name, "/"); len(parts) == 2 {
+		base, baseOK := n.assets[parts[0]]
+		quote, quoteOK := n.assets[parts[1]]
+
+		return base, quote, baseOK && quoteOK
+	}
+
+	var base, quote string
+	found := false
+	for i := 1; i < len(name); i++ {
+		b, baseOK := n.assets[name[:i]]
+		q, quoteOK := n.assets[name[i:]]
+		if !baseOK || !quoteOK {
+			continue
+		}
+
+		if found && (b != base || q != quote) {
+			return "", "", false
+		}
+
+		base, quote, found = b, q, true
+	}
+
+	return base, quote, found
+}
@@ -0,0 +1,131 @@
+package kraken_test
+
+import (
+	"testing"
+
+	"github.com/oliread/kraken"
+)
+
+func TestNormalizeAsset(t *testing.T) {
+	tcs := []struct {
+		input  string
+		common string
+		kraken string
+	}{
+		{input: "BTC", common: "BTC", kraken: "XXBT"},
+		{input: "XBT", common: "BTC", kraken: "XXBT"},
+		{input: "XXBT", common: "BTC", kraken: "XXBT"},
+		{input: "xbt", common: "BTC", kraken: "XXBT"},
+		{input: "USD", common: "USD", kraken: "ZUSD"},
+		{input: "ZUSD", common: "USD", kraken: "ZUSD"},
+		{input: "XETH", common: "ETH", kraken: "XETH"},
+		{input: "DOGE", common: "DOGE", kraken: "XXDG"},
+		{input: "XDG", common: "DOGE", kraken: "XXDG"},
+		{input: "XXDG", common: "DOGE", kraken: "XXDG"},
+		{input: "XRP", common: "XRP", kraken: "XXRP"},
+		{input: "USDT", common: "USDT", kraken: "USDT"},
+		{input: "abc", common: "ABC", kraken: "ABC"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			if actual := kraken.NormalizeAsset(tc.input); actual != tc.common {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.common, actual)
+			}
+
+			if actual := kraken.ToKrakenAsset(tc.input); actual != tc.kraken {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.kraken, actual)
+			}
+		})
+	}
+}
+
+func TestNormalizePair(t *testing.T) {
+	tcs := []struct {
+		input  string
+		common string
+		kraken string
+	}{
+		{input: "BTCUSD", common: "BTC/USD", kraken: "XXBTZUSD"},
+		{input: "XBT/USD", common: "BTC/USD", kraken: "XXBTZUSD"},
+		{input: "XXBTZUSD", common: "BTC/USD", kraken: "XXBTZUSD"},
+		{input: "XBTUSD", common: "BTC/USD", kraken: "XXBTZUSD"},
+		{input: "btc/usd", common: "BTC/USD", kraken: "XXBTZUSD"},
+		{input: "ETHXBT", common: "ETH/BTC", kraken: "XETHXXBT"},
+		{input: "XBTUSDT", common: "BTC/USDT", kraken: "XBTUSDT"},
+		{input: "USDTUSD", common: "USDT/USD", kraken: "USDTUSD"},
+		{input: "DOGEUSD", common: "DOGE/USD", kraken: "XDGUSD"},
+		{input: "XDGUSD", common: "DOGE/USD", kraken: "XDGUSD"},
+		{input: "XDG/EUR", common: "DOGE/EUR", kraken: "XDGEUR"},
+		{input: "ABCUSD", common: "ABCUSD", kraken: "ABCUSD"},
+		{input: "ABC/USD", common: "ABC/USD", kraken: "ABC/USD"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			if actual := kraken.NormalizePair(tc.input); actual != tc.common {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.common, actual)
+			}
+
+			if actual := kraken.ToKrakenPair(tc.input); actual != tc.kraken {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.kraken, actual)
+			}
+		})
+	}
+}
+
+func TestAssetNames(t *testing.T) {
+	n := kraken.NewAssetNames()
+	n.AddAssets(kraken.Assets{
+		Assets: map[string]kraken.Asset{
+			"XXBT":  {AltName: "XBT"},
+			"MATIC": {AltName: "MATIC"},
+		},
+	})
+	n.AddAssetPairs(kraken.AssetPairs{
+		Pairs: map[string]kraken.AssetPair{
+			"XXBTZUSD":   {AltName: "XBTUSD", WebSocketName: "XBT/USD", Base: "XXBT", Quote: "ZUSD"},
+			"MATICUSD":   {AltName: "MATICUSD", WebSocketName: "MATIC/USD", Base: "MATIC", Quote: "ZUSD"},
+			"XDGUSD":     {AltName: "XDGUSD", WebSocketName: "XDG/USD", Base: "XXDG", Quote: "ZUSD"},
+			"PEPEUSD":    {AltName: "PEPEUSD", WebSocketName: "PEPE/USD", Base: "PEPE", Quote: "ZUSD"},
+			"XETHXXBT":   {AltName: "ETHXBT", WebSocketName: "ETH/XBT", Base: "XETH", Quote: "XXBT"},
+			"XXBTZUSD.d": {AltName: "XBTUSD.d", Base: "XXBT", Quote: "ZUSD"},
+		},
+	})
+
+	tcs := []struct {
+		input  string
+		common string
+		kraken string
+	}{
+		{input: "XBT/USD", common: "BTC/USD", kraken: "XXBTZUSD"},
+		{input: "MATICUSD", common: "MATIC/USD", kraken: "MATICUSD"},
+		{input: "matic/usd", common: "MATIC/USD", kraken: "MATICUSD"},
+		{input: "DOGE/USD", common: "DOGE/USD", kraken: "XDGUSD"},
+		{input: "PEPE/USD", common: "PEPE/USD", kraken: "PEPEUSD"},
+		{input: "PEPEUSD", common: "PEPE/USD", kraken: "PEPEUSD"},
+		{input: "ETH/XBT", common: "ETH/BTC", kraken: "XETHXXBT"},
+		{input: "XBTUSD.d", common: "BTC/USD", kraken: "XXBTZUSD.d"},
+		{input: "BTC/USD", common: "BTC/USD", kraken: "XXBTZUSD"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			if actual := n.NormalizePair(tc.input); actual != tc.common {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.common, actual)
+			}
+
+			if actual := n.ToKrakenPair(tc.input); actual != tc.kraken {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.kraken, actual)
+			}
+		})
+	}
+
+	if actual := n.NormalizeAsset("matic"); actual != "MATIC" {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", "MATIC", actual)
+	}
+
+	if actual := n.ToKrakenAsset("BTC"); actual != "XXBT" {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", "XXBT", actual)
+	}
+}
@@ -0,0 +1,355 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CachingClient handles caching of slow moving data. Assets, AssetPairs and
+// Status responses are cached for a TTL per operation, the clock skew from a
+// Time response is cached so that the server time can be estimated locally,
+// all other calls are passed through. Concurrent cache misses for the same key
+// result in a single upstream call. Cached results are shared between callers
+// and must not be modified
+type CachingClient struct {
+	inner      Client
+	clock      Clock
+	ttls       map[string]time.Duration
+	serveStale bool
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	flights flightGroup
+}
+
+// cacheEntry a cached result and the time it was fetched
+type cacheEntry struct {
+	value   interface{}
+	fetched time.Time
+}
+
+var _ Client = (*CachingClient)(nil)
+
+// NewCachingClient helper function for creating a new caching client, Assets
+// and AssetPairs are cached for an hour, Time for ten minutes and Status for
+// thirty seconds unless otherwise given
+func NewCachingClient(inner Client, opts ...CachingOption) (*CachingClient, error) {
+	c := CachingClient{
+		inner: inner,
+		clock: systemClock{},
+		ttls: map[string]time.Duration{
+			"Time":       10 * time.Minute,
+			"Status":     30 * time.Second,
+			"Assets":     time.Hour,
+			"AssetPairs": time.Hour,
+		},
+		entries: make(map[string]cacheEntry),
+	}
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+// cached return the cached result of an operation when it is within its TTL,
+// otherwise fetch and cache a new result. A failed fetch, including a result
+// with API errors, is not cached and the stale result is returned instead when
+// serving stale results is enabled
+func (c *CachingClient) cached(ctx context.Context, operation, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && c.clock.Now().Sub(entry.fetched) < c.ttls[operation] {
+		return entry.value, nil
+	}
+
+	v, err := c.flights.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		v, err := fetch(ctx)
+		if err != nil || len(resultErrors(v)) != 0 {
+			return v, err
+		}
+
+		c.mu.Lock()
+		c.entries[key] = cacheEntry{
+			value:   v,
+			fetched: c.clock.Now(),
+		}
+		c.mu.Unlock()
+
+		return v, nil
+	})
+
+	if (err != nil || len(resultErrors(v)) != 0) && ok && c.serveStale {
+		return entry.value, nil
+	}
+
+	return v, err
+}
+
+// Time handles caching for client Time function, the returned timestamp is
+// the local time adjusted by the cached skew to the server time
+func (c *CachingClient) Time(ctx context.Context) (Time, error) {
+	v, err := c.cached(ctx, "Time", "Time", func(ctx context.Context) (interface{}, error) {
+		t, err := c.inner.Time(ctx)
+		if err != nil || len(t.Errors) != 0 {
+			return t, err
+		}
+
+		return t.Timestamp.Sub(c.clock.Now()), nil
+	})
+
+	switch v := v.(type) {
+	case time.Duration:
+		return Time{
+			Timestamp: c.clock.Now().Add(v),
+		}, err
+	case Time:
+		return v, err
+	default:
+		return Time{}, err
+	}
+}
+
+// Status handles caching for client Status function
+func (c *CachingClient) Status(ctx context.Context) (SystemStatus, error) {
+	v, err := c.cached(ctx, "Status", "Status", func(ctx context.Context) (interface{}, error) {
+		return c.inner.Status(ctx)
+	})
+
+	status, _ := v.(SystemStatus)
+
+	return status, err
+}
+
+// Assets handles caching for client Assets function
+func (c *CachingClient) Assets(ctx context.Context) (Assets, error) {
+	v, err := c.cached(ctx, "Assets", "Assets", func(ctx context.Context) (interface{}, error) {
+		return c.inner.Assets(ctx)
+	})
+
+	assets, _ := v.(Assets)
+
+	return assets, err
+}
+
+// AssetPairs handles caching for client AssetPairs function, results are cached
+// per info value and set of pairs
+func (c *CachingClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
+	key := fmt.Sprintf("AssetPairs:%s:%s", info, strings.Join(sortedStrings(pairs), ","))
+
+	v, err := c.cached(ctx, "AssetPairs", key, func(ctx context.Context) (interface{}, error) {
+		return c.inner.AssetPairs(ctx, info, pairs...)
+	})
+
+	assetPairs, _ := v.(AssetPairs)
+
+	return assetPairs, err
+}
+
+// Tickers passes through to the client Tickers function
+func (c *CachingClient) Tickers(ctx context.Context, pairs ...string) (Tickers, error) {
+	return c.inner.Tickers(ctx, pairs...)
+}
+
+// OHLC passes through to the client OHLC function
+func (c *CachingClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
+	return c.inner.OHLC(ctx, interval, since, pairs...)
+}
+
+// OrderBook passes through to the client OrderBook function
+func (c *CachingClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
+	return c.inner.OrderBook(ctx, count, pairs...)
+}
+
+// RecentTrades passes through to the client RecentTrades function
+func (c *CachingClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
+	return c.inner.RecentTrades(ctx, since, pairs...)
+}
+
+// RecentSpreads passes through to the client RecentSpreads function
+func (c *CachingClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
+	return c.inner.RecentSpreads(ctx, since, pairs...)
+}
+
+// TradeBalance passes through to the client TradeBalance function
+func (c *CachingClient) TradeBalance(ctx context.Context, asset string) (TradeBalance, error) {
+	return c.inner.TradeBalance(ctx, asset)
+}
+
+// OpenOrders passes through to the client OpenOrders function
+func (c *CachingClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error) {
+	return c.inner.OpenOrders(ctx, trades, userref)
+}
+
+// ClosedOrders passes through to the client ClosedOrders function
+func (c *CachingClient) ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error) {
+	return c.inner.ClosedOrders(ctx, query)
+}
+
+// QueryOrders passes through to the client QueryOrders function
+func (c *CachingClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error) {
+	return c.inner.QueryOrders(ctx, trades, userref, txids...)
+}
+
+// TradesHistory passes through to the client TradesHistory function
+func (c *CachingClient) TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error) {
+	return c.inner.TradesHistory(ctx, query)
+}
+
+// OpenPositions passes through to the client OpenPositions function
+func (c *CachingClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error) {
+	return c.inner.OpenPositions(ctx, docalcs, txids...)
+}
+
+// Ledgers passes through to the client Ledgers function
+func (c *CachingClient) Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error) {
+	return c.inner.Ledgers(ctx, query)
+}
+
+// QueryLedgers passes through to the client QueryLedgers function
+func (c *CachingClient) QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error) {
+	return c.inner.QueryLedgers(ctx, ids...)
+}
+
+// TradeVolume passes through to the client TradeVolume function
+func (c *CachingClient) TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error) {
+	return c.inner.TradeVolume(ctx, pairs...)
+}
+
+// AddOrder passes through to the client AddOrder function
+func (c *CachingClient) AddOrder(ctx context.Context, req OrderRequest) (AddOrderResult, error) {
+	return c.inner.AddOrder(ctx, req)
+}
+
+// CancelOrder passes through to the client CancelOrder function
+func (c *CachingClient) CancelOrder(ctx context.Context, txid string) (CancelResult, error) {
+	return c.inner.CancelOrder(ctx, txid)
+}
+
+// CancelAllOrders passes through to the client CancelAllOrders function
+func (c *CachingClient) CancelAllOrders(ctx context.Context) (CancelResult, error) {
+	return c.inner.CancelAllOrders(ctx)
+}
+
+// CancelAllOrdersAfter passes through to the client CancelAllOrdersAfter
+// function
+func (c *CachingClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error) {
+	return c.inner.CancelAllOrdersAfter(ctx, timeout)
+}
+
+// EditOrder passes through to the client EditOrder function
+func (c *CachingClient) EditOrder(ctx context.Context, req EditOrderRequest) (EditOrderResult, error) {
+	return c.inner.EditOrder(ctx, req)
+}
+
+// DepositMethods passes through to the client DepositMethods function
+func (c *CachingClient) DepositMethods(ctx context.Context, asset string) (DepositMethods, error) {
+	return c.inner.DepositMethods(ctx, asset)
+}
+
+// DepositAddresses passes through to the client DepositAddresses function
+func (c *CachingClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error) {
+	return c.inner.DepositAddresses(ctx, asset, method, new)
+}
+
+// DepositStatus passes through to the client DepositStatus function
+func (c *CachingClient) DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error) {
+	return c.inner.DepositStatus(ctx, asset, method)
+}
+
+// WithdrawInfo passes through to the client WithdrawInfo function
+func (c *CachingClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error) {
+	return c.inner.WithdrawInfo(ctx, asset, key, amount)
+}
+
+// Withdraw passes through to the client Withdraw function
+func (c *CachingClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error) {
+	return c.inner.Withdraw(ctx, asset, key, amount)
+}
+
+// WithdrawStatus passes through to the client WithdrawStatus function
+func (c *CachingClient) WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error) {
+	return c.inner.WithdrawStatus(ctx, asset, method)
+}
+
+// WithdrawCancel passes through to the client WithdrawCancel function
+func (c *CachingClient) WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error) {
+	return c.inner.WithdrawCancel(ctx, asset, refid)
+}
+
+// WalletTransfer passes through to the client WalletTransfer function
+func (c *CachingClient) WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error) {
+	return c.inner.WalletTransfer(ctx, asset, from, to, amount)
+}
+
+// EarnStrategies passes through to the client EarnStrategies function
+func (c *CachingClient) EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error) {
+	return c.inner.EarnStrategies(ctx, asset, cursor)
+}
+
+// EarnAllocate passes through to the client EarnAllocate function
+func (c *CachingClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	return c.inner.EarnAllocate(ctx, strategyID, amount)
+}
+
+// EarnDeallocate passes through to the client EarnDeallocate function
+func (c *CachingClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	return c.inner.EarnDeallocate(ctx, strategyID, amount)
+}
+
+// EarnAllocationStatus passes through to the client EarnAllocationStatus
+// function
+func (c *CachingClient) EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error) {
+	return c.inner.EarnAllocationStatus(ctx, strategyID)
+}
+
+// EarnAllocations passes through to the client EarnAllocations function
+func (c *CachingClient) EarnAllocations(ctx context.Context) (EarnAllocations, error) {
+	return c.inner.EarnAllocations(ctx)
+}
+
+// AddExport passes through to the client AddExport function
+func (c *CachingClient) AddExport(ctx context.Context, req ExportRequest) (AddExportResult, error) {
+	return c.inner.AddExport(ctx, req)
+}
+
+// ExportStatus passes through to the client ExportStatus function
+func (c *CachingClient) ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error) {
+	return c.inner.ExportStatus(ctx, report)
+}
+
+// RetrieveExport passes through to the client RetrieveExport function
+func (c *CachingClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.inner.RetrieveExport(ctx, id)
+}
+
+// RemoveExport passes through to the client RemoveExport function
+func (c *CachingClient) RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error) {
+	return c.inner.RemoveExport(ctx, id, removal)
+}
+
+// CreateSubaccount passes through to the client CreateSubaccount function
+func (c *CachingClient) CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error) {
+	return c.inner.CreateSubaccount(ctx, username, email)
+}
+
+// AccountTransfer passes through to the client AccountTransfer function
+func (c *CachingClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error) {
+	return c.inner.AccountTransfer(ctx, asset, amount, fromUser, toUser)
+}
+
+// GetWebSocketsToken passes through to the client GetWebSocketsToken function
+func (c *CachingClient) GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error) {
+	return c.inner.GetWebSocketsToken(ctx)
+}
@@ -0,0 +1,56 @@
+package kraken
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestCachingClientConcurrentMisses(t *testing.T) {
+	release := make(chan struct{})
+
+	var mu sync.Mutex
+	requests := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		<-release
+		w.Write([]byte(`{"error":[],"result":{}}`))
+	}))
+	defer s.Close()
+
+	inner, err := NewHTTPClient(HTTPClientWithBaseURL(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewCachingClient(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.Assets(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// every miss but the one requesting the assets waits on its request
+	awaitWaiters(t, &c.flights, "Assets", 9)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("EXPECTED: 1 request\nACTUAL: %d", requests)
+	}
+}
@@ -0,0 +1,155 @@
+package kraken_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+)
+
+func TestCachingClient(t *testing.T) {
+	tcs := []struct {
+		name     string
+		opts     []kraken.CachingOption
+		calls    func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool)
+		expected map[string]int
+	}{
+		{
+			name: "AssetsWithinTTL",
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.Assets(context.Background())
+				clock.now = clock.now.Add(59 * time.Minute)
+				c.Assets(context.Background())
+			},
+			expected: map[string]int{"/public/Assets": 1},
+		},
+		{
+			name: "AssetsExpired",
+			opts: []kraken.CachingOption{
+				kraken.CachingWithTTL("Assets", time.Minute),
+			},
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.Assets(context.Background())
+				clock.now = clock.now.Add(time.Minute)
+				c.Assets(context.Background())
+			},
+			expected: map[string]int{"/public/Assets": 2},
+		},
+		{
+			name: "AssetPairsKey",
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.AssetPairs(context.Background(), kraken.AssetPairInfoFees, "XBTUSD", "ETHUSD")
+				c.AssetPairs(context.Background(), kraken.AssetPairInfoFees, "ETHUSD", "XBTUSD")
+				c.AssetPairs(context.Background(), kraken.AssetPairInfoMargin, "ETHUSD", "XBTUSD")
+			},
+			expected: map[string]int{"/public/AssetPairs": 2},
+		},
+		{
+			name: "TimeSkew",
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.Time(context.Background())
+				clock.now = clock.now.Add(5 * time.Second)
+
+				res, err := c.Time(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// the server is 18 seconds ahead of the local clock
+				if expected := time.Unix(1616663623, 0); !res.Timestamp.Equal(expected) {
+					t.Errorf("EXPECTED: %s\nACTUAL: %s", expected, res.Timestamp)
+				}
+			},
+			expected: map[string]int{"/public/Time": 1},
+		},
+		{
+			name: "StaleOnError",
+			opts: []kraken.CachingOption{
+				kraken.CachingWithStaleOnError(),
+			},
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.Status(context.Background())
+
+				*fail = true
+				clock.now = clock.now.Add(time.Minute)
+
+				res, err := c.Status(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if res.Status != kraken.SystemStatusValueOnline {
+					t.Errorf("EXPECTED: online\nACTUAL: %s", res.Status)
+				}
+			},
+			expected: map[string]int{"/public/SystemStatus": 2},
+		},
+		{
+			name: "ErrorWithoutStale",
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.Status(context.Background())
+
+				*fail = true
+				clock.now = clock.now.Add(time.Minute)
+
+				if _, err := c.Status(context.Background()); err == nil {
+					t.Fatal("expected an error")
+				}
+			},
+			expected: map[string]int{"/public/SystemStatus": 2},
+		},
+		{
+			name: "PassThrough",
+			calls: func(t *testing.T, c *kraken.CachingClient, clock *fakeClock, fail *bool) {
+				c.Tickers(context.Background(), "XBTUSD")
+				c.Tickers(context.Background(), "XBTUSD")
+			},
+			expected: map[string]int{"/public/Ticker": 2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			fail := false
+			requests := map[string]int{}
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests[r.URL.Path]++
+				if fail {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				switch r.URL.Path {
+				case "/public/Time":
+					w.Write([]byte(`{"error":[],"result":{"unixtime":1616663618,"rfc1123":"Thu, 25 Mar 21 09:13:38 +0000"}}`))
+				case "/public/SystemStatus":
+					w.Write([]byte(`{"error":[],"result":{"status":"online","timestamp":"2021-03-25T09:13:38Z"}}`))
+				default:
+					w.Write([]byte(`{"error":[],"result":{}}`))
+				}
+			}))
+			defer s.Close()
+
+			inner, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			clock := &fakeClock{now: time.Unix(1616663600, 0)}
+			c, err := kraken.NewCachingClient(inner, append([]kraken.CachingOption{kraken.CachingWithClock(clock)}, tc.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.calls(t, c, clock, &fail)
+
+			if diff := deep.Equal(tc.expected, requests); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
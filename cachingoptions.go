@@ -0,0 +1,50 @@
+package kraken
+
+import (
+	"fmt"
+	"time"
+)
+
+// CachingOption options used when creating a new CachingClient
+type CachingOption func(c *CachingClient) error
+
+// CachingWithTTL set the TTL of the cached results of an operation, one of
+// "Time", "Status", "Assets" or "AssetPairs"
+func CachingWithTTL(operation string, ttl time.Duration) CachingOption {
+	return CachingOption(func(c *CachingClient) error {
+		if _, ok := c.ttls[operation]; !ok {
+			return fmt.Errorf("operation %s is not cached", operation)
+		}
+
+		if ttl <= 0 {
+			return fmt.Errorf("ttl must be positive")
+		}
+
+		c.ttls[operation] = ttl
+
+		return nil
+	})
+}
+
+// CachingWithStaleOnError return the last cached result of an operation when
+// refreshing it fails, rather than the error
+func CachingWithStaleOnError() CachingOption {
+	return CachingOption(func(c *CachingClient) error {
+		c.serveStale = true
+
+		return nil
+	})
+}
+
+// CachingWithClock set the clock used to expire cached results
+func CachingWithClock(clock Clock) CachingOption {
+	return CachingOption(func(c *CachingClient) error {
+		if clock == nil {
+			return fmt.Errorf("clock is required")
+		}
+
+		c.clock = clock
+
+		return nil
+	})
+}
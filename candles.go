@@ -0,0 +1,391 @@
+package kraken
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TimeRange a window of time from From up to but not including To
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// AggregateOHLC combine candles into candles of a longer interval, such as
+// two hours from those of an hour. Each candle is bucketed by the start of
+// the target interval containing its time, aligned to UTC boundaries, and the
+// buckets without candles are left out. The volume weighted average price is
+// recomputed from the volume of each candle. An error is returned when the
+// interval of the candles, taken from the spacing of their times, does not
+// divide the target or two candles have the same time
+func AggregateOHLC(candles []OHLC, target OHLCInterval) ([]OHLC, error) {
+	if target <= 0 {
+		return nil, fmt.Errorf("target interval must be positive")
+	}
+
+	sorted := append([]OHLC(nil), candles...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var spacing int64
+	for i := 1; i < len(sorted); i++ {
+		d := sorted[i].Time.Unix() - sorted[i-1].Time.Unix()
+		if d == 0 {
+			return nil, fmt.Errorf("duplicate candle at %s", sorted[i].Time.UTC().Format(time.RFC3339))
+		}
+
+		spacing = gcd(spacing, d)
+	}
+
+	size := int64(target.Duration() / time.Second)
+	if spacing != 0 && size%spacing != 0 {
+		return nil, fmt.Errorf("candles %s apart do not divide a target interval of %s", time.Duration(spacing)*time.Second, target.Duration())
+	}
+
+	aggregated := []OHLC{}
+	var weighted decimal.Decimal
+	for _, candle := range sorted {
+		start := time.Unix(candle.Time.Unix()-mod(candle.Time.Unix(), size), 0).UTC()
+		if len(aggregated) == 0 || !aggregated[len(aggregated)-1].Time.Equal(start) {
+			if len(aggregated) != 0 {
+				setVWAP(&aggregated[len(aggregated)-1], weighted)
+			}
+
+			aggregated = append(aggregated, OHLC{
+				Time: start,
+				Open: candle.Open,
+				High: candle.High,
+				Low:  candle.Low,
+			})
+			weighted = decimal.Zero
+		}
+
+		bucket := &aggregated[len(aggregated)-1]
+		bucket.High = decimal.Max(bucket.High, candle.High)
+		bucket.Low = decimal.Min(bucket.Low, candle.Low)
+		bucket.Close = candle.Close
+		bucket.Volume = bucket.Volume.Add(candle.Volume)
+		bucket.Count += candle.Count
+		bucket.VolumeWeightedAveragePrice = candle.VolumeWeightedAveragePrice
+		weighted = weighted.Add(candle.VolumeWeightedAveragePrice.Mul(candle.Volume))
+	}
+
+	if len(aggregated) != 0 {
+		setVWAP(&aggregated[len(aggregated)-1], weighted)
+	}
+
+	return aggregated, nil
+}
+
+// FindOHLCGaps return the windows without a candle between the first and last
+// of candles of an interval, each candle expected at an interval aligned to
+// UTC boundaries. The candles need not be sorted, a sorted copy is searched,
+// and none are found for an interval which is not positive
+func FindOHLCGaps(candles []OHLC, interval OHLCInterval) []TimeRange {
+	if interval <= 0 || len(candles) == 0 {
+		return []TimeRange{}
+	}
+
+	sorted := sortOHLC(candles)
+	aligned := make([]OHLC, 0, len(sorted))
+	for _, candle := range sorted {
+		aligned = append(aligned, OHLC{Time: ohlcStart(candle.Time, interval)})
+	}
+
+	return ohlcGaps(aligned, interval, aligned[0].Time, aligned[len(aligned)-1].Time.Add(interval.Duration()))
+}
+
+// FillOHLCGaps return a sorted copy of candles of an interval with a flat
+// candle inserted for each window found by FindOHLCGaps, so charts of sparse
+// pairs have a candle at every interval. The inserted candles have the close
+// of the candle before as their prices, no volume and are marked Synthetic
+func FillOHLCGaps(candles []OHLC, interval OHLCInterval) []OHLC {
+	sorted := sortOHLC(candles)
+	if interval <= 0 {
+		return sorted
+	}
+
+	filled := make([]OHLC, 0, len(sorted))
+	for i, candle := range sorted {
+		filled = append(filled, candle)
+		if i+1 == len(sorted) {
+			break
+		}
+
+		next := ohlcStart(sorted[i+1].Time, interval)
+		for t := ohlcStart(candle.Time, interval).Add(interval.Duration()); t.Before(next); t = t.Add(interval.Duration()) {
+			filled = append(filled, OHLC{
+				Time:                       t,
+				Open:                       candle.Close,
+				High:                       candle.Close,
+				Low:                        candle.Close,
+				Close:                      candle.Close,
+				VolumeWeightedAveragePrice: candle.Close,
+				Synthetic:                  true,
+			})
+		}
+	}
+
+	return filled
+}
+
+// sortOHLC a copy of candles sorted by time
+func sortOHLC(candles []OHLC) []OHLC {
+	sorted := append([]OHLC{}, candles...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	return sorted
+}
+
+// ohlcStart the start of the interval containing t, aligned to UTC boundaries
+func ohlcStart(t time.Time, interval OHLCInterval) time.Time {
+	size := int64(interval.Duration() / time.Second)
+
+	return time.Unix(t.Unix()-mod(t.Unix(), size), 0).UTC()
+}
+
+// setVWAP set the volume weighted average price of a candle from the sum of
+// the price times volume of its trades or component candles, the price is
+// left as it is when the candle has no volume
+func setVWAP(candle *OHLC, weighted decimal.Decimal) {
+	if candle.Volume.IsZero() {
+		return
+	}
+
+	candle.VolumeWeightedAveragePrice = weighted.Div(candle.Volume)
+}
+
+// ohlcGaps the windows from from to to without a candle of sorted candles of
+// an interval, a candle is expected at each interval aligned to UTC
+// boundaries
+func ohlcGaps(sorted []OHLC, interval OHLCInterval, from, to time.Time) []TimeRange {
+	size := int64(interval.Duration() / time.Second)
+	expected := time.Unix(from.Unix()+mod(-from.Unix(), size), 0).UTC()
+	if from.Nanosecond() != 0 && mod(from.Unix(), size) == 0 {
+		expected = expected.Add(interval.Duration())
+	}
+
+	gaps := []TimeRange{}
+	for _, candle := range sorted {
+		if candle.Time.Before(expected) {
+			continue
+		}
+
+		if !candle.Time.Before(to) {
+			break
+		}
+
+		if candle.Time.After(expected) {
+			gaps = append(gaps, TimeRange{From: expected, To: candle.Time})
+		}
+
+		expected = candle.Time.Add(interval.Duration())
+	}
+
+	if expected.Before(to) {
+		gaps = append(gaps, TimeRange{From: expected, To: to})
+	}
+
+	return gaps
+}
+
+// gcd the greatest common divisor of a and b
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+// mod the non-negative remainder of a divided by b
+func mod(a, b int64) int64 {
+	return (a%b + b) % b
+}
+
+// candleBucket an open candle of a CandleBuilder, with the times of the
+// trades its open and close were taken from
+type candleBucket struct {
+	candle   OHLC
+	weighted decimal.Decimal
+	first    time.Time
+	last     time.Time
+}
+
+// CandleBuilder build candles of an interval from trades, such as those of a
+// TradesIterator or the websocket trade channel. A candle is completed once a
+// trade is added at least the tolerance after its interval ends, so trades
+// arriving out of order within the tolerance are included in their candle
+// and later ones are rejected with ErrLateTrade. Intervals without trades are
+// skipped unless configured to emit empty candles
+type CandleBuilder struct {
+	interval  OHLCInterval
+	tolerance time.Duration
+	emitEmpty bool
+
+	mu        sync.Mutex
+	open      []candleBucket
+	latest    time.Time
+	emitted   time.Time
+	lastClose decimal.Decimal
+}
+
+// NewCandleBuilder helper function for creating a new CandleBuilder of
+// candles of an interval aligned to UTC boundaries
+func NewCandleBuilder(interval OHLCInterval, opts ...CandleBuilderOption) (*CandleBuilder, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+
+	b := CandleBuilder{
+		interval: interval,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&b); err != nil {
+			return nil, err
+		}
+	}
+
+	return &b, nil
+}
+
+// Add add trades to their candles in order, returning the candles completed
+// oldest first. Trades for a completed candle are left out and ErrLateTrade is
+// returned along with the candles completed by the other trades
+func (b *CandleBuilder) Add(trades ...RecentTrade) ([]OHLC, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	completed := []OHLC{}
+	var err error
+	for _, trade := range trades {
+		start := b.start(trade.Time)
+		if !b.emitted.IsZero() && start.Before(b.emitted) {
+			if err == nil {
+				err = fmt.Errorf("%w:trade at %s is before %s", ErrLateTrade, trade.Time.UTC().Format(time.RFC3339Nano), b.emitted.Format(time.RFC3339))
+			}
+
+			continue
+		}
+
+		b.bucket(start).add(trade)
+		if trade.Time.After(b.latest) {
+			b.latest = trade.Time
+		}
+		completed = append(completed, b.complete(false)...)
+	}
+
+	return completed, err
+}
+
+// Current the candle of the latest interval with trades which has not been
+// completed, false when there is none
+func (b *CandleBuilder) Current() (OHLC, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.open) == 0 {
+		return OHLC{}, false
+	}
+
+	bucket := b.open[len(b.open)-1]
+	candle := bucket.candle
+	setVWAP(&candle, bucket.weighted)
+
+	return candle, true
+}
+
+// Flush complete every candle regardless of the tolerance, such as at the end
+// of a TradesIterator, returning them oldest first
+func (b *CandleBuilder) Flush() []OHLC {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.complete(true)
+}
+
+// start the start of the interval containing t
+func (b *CandleBuilder) start(t time.Time) time.Time {
+	return ohlcStart(t, b.interval)
+}
+
+// bucket the open candle of the interval starting at start, opening it when
+// there is none
+func (b *CandleBuilder) bucket(start time.Time) *candleBucket {
+	i := sort.Search(len(b.open), func(i int) bool { return !b.open[i].candle.Time.Before(start) })
+	if i == len(b.open) || !b.open[i].candle.Time.Equal(start) {
+		b.open = append(b.open, candleBucket{})
+		copy(b.open[i+1:], b.open[i:])
+		b.open[i] = candleBucket{candle: OHLC{Time: start}}
+	}
+
+	return &b.open[i]
+}
+
+// complete remove the candles completed by the latest trade, or every candle
+// when all, along with empty candles for the intervals between them when
+// configured
+func (b *CandleBuilder) complete(all bool) []OHLC {
+	completed := []OHLC{}
+	for {
+		var candle OHLC
+		switch {
+		case len(b.open) != 0 && (!b.emitEmpty || b.emitted.IsZero() || !b.open[0].candle.Time.After(b.emitted)):
+			candle = b.open[0].candle
+			setVWAP(&candle, b.open[0].weighted)
+		case b.emitEmpty && !b.emitted.IsZero() && (len(b.open) != 0 || !all):
+			candle = OHLC{
+				Time:                       b.emitted,
+				Open:                       b.lastClose,
+				High:                       b.lastClose,
+				Low:                        b.lastClose,
+				Close:                      b.lastClose,
+				VolumeWeightedAveragePrice: b.lastClose,
+			}
+		default:
+			return completed
+		}
+
+		end := candle.Time.Add(b.interval.Duration())
+		if !all && end.Add(b.tolerance).After(b.latest) {
+			return completed
+		}
+
+		if candle.Count != 0 {
+			b.open = b.open[1:]
+		}
+
+		completed = append(completed, candle)
+		b.emitted = end
+		b.lastClose = candle.Close
+	}
+}
+
+// add add a trade to the candle, its open and close are the trades with the
+// earliest and latest times, in the order added when the times are equal
+func (c *candleBucket) add(trade RecentTrade) {
+	if c.candle.Count == 0 {
+		c.candle.Open, c.candle.High, c.candle.Low, c.candle.Close = trade.Price, trade.Price, trade.Price, trade.Price
+		c.first, c.last = trade.Time, trade.Time
+	}
+
+	if trade.Time.Before(c.first) {
+		c.candle.Open = trade.Price
+		c.first = trade.Time
+	}
+
+	if !trade.Time.Before(c.last) {
+		c.candle.Close = trade.Price
+		c.last = trade.Time
+	}
+
+	c.candle.High = decimal.Max(c.candle.High, trade.Price)
+	c.candle.Low = decimal.Min(c.candle.Low, trade.Price)
+	c.candle.Volume = c.candle.Volume.Add(trade.Volume)
+	c.candle.Count++
+	c.weighted = c.weighted.Add(trade.Price.Mul(trade.Volume))
+}
@@ -0,0 +1,393 @@
+package kraken_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// candle an OHLC at a time given as a RFC3339 string with prices, volume
+// weighted average price and volume given as strings
+func candle(t string, open, high, low, close, vwap, volume string, count uint64) kraken.OHLC {
+	parsed, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		panic(err)
+	}
+
+	return kraken.OHLC{
+		Time:                       parsed,
+		Open:                       decimal.RequireFromString(open),
+		High:                       decimal.RequireFromString(high),
+		Low:                        decimal.RequireFromString(low),
+		Close:                      decimal.RequireFromString(close),
+		VolumeWeightedAveragePrice: decimal.RequireFromString(vwap),
+		Volume:                     decimal.RequireFromString(volume),
+		Count:                      count,
+	}
+}
+
+func TestAggregateOHLC(t *testing.T) {
+	tcs := []struct {
+		name     string
+		candles  []kraken.OHLC
+		target   kraken.OHLCInterval
+		expected []kraken.OHLC
+	}{
+		{
+			name:     "Empty",
+			target:   kraken.OHLCInterval(120),
+			expected: []kraken.OHLC{},
+		},
+		{
+			name: "TwoHours",
+			candles: []kraken.OHLC{
+				candle("2022-02-01T01:00:00Z", "100", "110", "95", "105", "102", "1", 3),
+				candle("2022-02-01T00:00:00Z", "90", "101", "89", "100", "95", "2", 4),
+				candle("2022-02-01T02:00:00Z", "105", "106", "104", "106", "105", "0.5", 1),
+				candle("2022-02-01T03:00:00Z", "106", "120", "100", "118", "110", "1.5", 6),
+			},
+			target: kraken.OHLCInterval(120),
+			expected: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "90", "110", "89", "105", "97.3333333333333333", "3", 7),
+				candle("2022-02-01T02:00:00Z", "105", "120", "100", "118", "108.75", "2", 7),
+			},
+		},
+		{
+			name: "Gaps",
+			candles: []kraken.OHLC{
+				candle("2022-02-01T05:00:00Z", "100", "110", "95", "105", "102", "1", 3),
+				candle("2022-02-01T09:00:00Z", "105", "106", "104", "106", "105", "0.5", 1),
+				candle("2022-02-01T20:00:00Z", "106", "120", "100", "118", "110", "1.5", 6),
+			},
+			target: kraken.OHLCInterval(480),
+			expected: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "100", "110", "95", "105", "102", "1", 3),
+				candle("2022-02-01T08:00:00Z", "105", "106", "104", "106", "105", "0.5", 1),
+				candle("2022-02-01T16:00:00Z", "106", "120", "100", "118", "110", "1.5", 6),
+			},
+		},
+		{
+			name: "NoVolume",
+			candles: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "100", "100", "100", "100", "0", "0", 0),
+				candle("2022-02-01T00:05:00Z", "100", "100", "100", "100", "0", "0", 0),
+			},
+			target: kraken.OHLCInterval15Minutes,
+			expected: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "100", "100", "100", "100", "0", "0", 0),
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := kraken.AggregateOHLC(tc.candles, tc.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAggregateOHLCErrors(t *testing.T) {
+	tcs := []struct {
+		name     string
+		candles  []kraken.OHLC
+		target   kraken.OHLCInterval
+		expected string
+	}{
+		{
+			name:     "Target",
+			target:   0,
+			expected: "target interval must be positive",
+		},
+		{
+			name: "Divide",
+			candles: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "1", "1", "1", "1", "1", "1", 1),
+				candle("2022-02-01T04:00:00Z", "1", "1", "1", "1", "1", "1", 1),
+			},
+			target:   kraken.OHLCInterval(360),
+			expected: "candles 4h0m0s apart do not divide a target interval of 6h0m0s",
+		},
+		{
+			name: "Duplicate",
+			candles: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "1", "1", "1", "1", "1", "1", 1),
+				candle("2022-02-01T00:00:00Z", "1", "1", "1", "1", "1", "1", 1),
+			},
+			target:   kraken.OHLCInterval(120),
+			expected: "duplicate candle at 2022-02-01T00:00:00Z",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kraken.AggregateOHLC(tc.candles, tc.target); err == nil || err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// trade a trade at an offset from 2022-02-01T00:00:00Z with a price and
+// volume given as strings
+func trade(offset time.Duration, price, volume string) kraken.RecentTrade {
+	return kraken.RecentTrade{
+		Price:  decimal.RequireFromString(price),
+		Volume: decimal.RequireFromString(volume),
+		Time:   time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC).Add(offset),
+	}
+}
+
+func TestCandleBuilder(t *testing.T) {
+	tcs := []struct {
+		name      string
+		opts      []kraken.CandleBuilderOption
+		trades    []kraken.RecentTrade
+		completed []kraken.OHLC
+		current   *kraken.OHLC
+		flushed   []kraken.OHLC
+	}{
+		{
+			name: "Trades",
+			trades: []kraken.RecentTrade{
+				trade(10*time.Second, "100", "1"),
+				trade(20*time.Second, "104", "3"),
+				trade(50*time.Second, "98", "1"),
+				trade(70*time.Second, "101", "2"),
+				trade(80*time.Second, "102", "2"),
+			},
+			completed: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "100", "104", "98", "98", "102", "5", 3),
+			},
+			current: &[]kraken.OHLC{candle("2022-02-01T00:01:00Z", "101", "102", "101", "102", "101.5", "4", 2)}[0],
+			flushed: []kraken.OHLC{
+				candle("2022-02-01T00:01:00Z", "101", "102", "101", "102", "101.5", "4", 2),
+			},
+		},
+		{
+			name: "OutOfOrder",
+			opts: []kraken.CandleBuilderOption{kraken.CandleBuilderWithTolerance(5 * time.Second)},
+			trades: []kraken.RecentTrade{
+				trade(10*time.Second, "100", "1"),
+				trade(62*time.Second, "101", "1"),
+				trade(5*time.Second, "99", "1"),
+				trade(55*time.Second, "103", "1"),
+				trade(65*time.Second, "102", "1"),
+			},
+			completed: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "99", "103", "99", "103", "100.6666666666666667", "3", 3),
+			},
+			current: &[]kraken.OHLC{candle("2022-02-01T00:01:00Z", "101", "102", "101", "102", "101.5", "2", 2)}[0],
+			flushed: []kraken.OHLC{
+				candle("2022-02-01T00:01:00Z", "101", "102", "101", "102", "101.5", "2", 2),
+			},
+		},
+		{
+			name: "SkipEmpty",
+			trades: []kraken.RecentTrade{
+				trade(10*time.Second, "100", "1"),
+				trade(190*time.Second, "102", "1"),
+			},
+			completed: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "100", "100", "100", "100", "100", "1", 1),
+			},
+			current: &[]kraken.OHLC{candle("2022-02-01T00:03:00Z", "102", "102", "102", "102", "102", "1", 1)}[0],
+			flushed: []kraken.OHLC{
+				candle("2022-02-01T00:03:00Z", "102", "102", "102", "102", "102", "1", 1),
+			},
+		},
+		{
+			name: "EmitEmpty",
+			opts: []kraken.CandleBuilderOption{kraken.CandleBuilderWithEmptyCandles()},
+			trades: []kraken.RecentTrade{
+				trade(10*time.Second, "100", "1"),
+				trade(190*time.Second, "102", "1"),
+			},
+			completed: []kraken.OHLC{
+				candle("2022-02-01T00:00:00Z", "100", "100", "100", "100", "100", "1", 1),
+				candle("2022-02-01T00:01:00Z", "100", "100", "100", "100", "100", "0", 0),
+				candle("2022-02-01T00:02:00Z", "100", "100", "100", "100", "100", "0", 0),
+			},
+			current: &[]kraken.OHLC{candle("2022-02-01T00:03:00Z", "102", "102", "102", "102", "102", "1", 1)}[0],
+			flushed: []kraken.OHLC{
+				candle("2022-02-01T00:03:00Z", "102", "102", "102", "102", "102", "1", 1),
+			},
+		},
+		{
+			name:      "Empty",
+			completed: []kraken.OHLC{},
+			flushed:   []kraken.OHLC{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := kraken.NewCandleBuilder(kraken.OHLCIntervalMinute, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			completed := []kraken.OHLC{}
+			for _, trade := range tc.trades {
+				candles, err := b.Add(trade)
+				if err != nil {
+					t.Fatal(err)
+				}
+				completed = append(completed, candles...)
+			}
+
+			if diff := deep.Equal(tc.completed, completed); diff != nil {
+				t.Error(diff)
+			}
+
+			current, ok := b.Current()
+			if (tc.current != nil) != ok {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.current != nil, ok)
+			}
+
+			if ok {
+				if diff := deep.Equal(*tc.current, current); diff != nil {
+					t.Error(diff)
+				}
+			}
+
+			if diff := deep.Equal(tc.flushed, b.Flush()); diff != nil {
+				t.Error(diff)
+			}
+
+			if _, ok := b.Current(); ok {
+				t.Error("EXPECTED: no current candle once flushed\nACTUAL: true")
+			}
+		})
+	}
+}
+
+func TestCandleBuilderLateTrade(t *testing.T) {
+	b, err := kraken.NewCandleBuilder(kraken.OHLCIntervalMinute, kraken.CandleBuilderWithTolerance(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed, err := b.Add(trade(10*time.Second, "100", "1"), trade(70*time.Second, "101", "1"), trade(20*time.Second, "99", "1"))
+	if !errors.Is(err, kraken.ErrLateTrade) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrLateTrade, err)
+	}
+
+	// the late trade is left out of the completed candle
+	expected := []kraken.OHLC{
+		candle("2022-02-01T00:00:00Z", "100", "100", "100", "100", "100", "1", 1),
+	}
+	if diff := deep.Equal(expected, completed); diff != nil {
+		t.Error(diff)
+	}
+
+	if _, err := kraken.NewCandleBuilder(0); err == nil {
+		t.Error("EXPECTED: interval must be positive\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewCandleBuilder(kraken.OHLCIntervalMinute, kraken.CandleBuilderWithTolerance(-time.Second)); err == nil {
+		t.Error("EXPECTED: tolerance must not be negative\nACTUAL: <nil>")
+	}
+}
+
+// dstCandles daily candles, given in New York time either side of the start
+// of daylight saving time on 2022-03-13, missing those of 2022-03-13 and
+// 2022-03-16 UTC and out of order
+func dstCandles() []kraken.OHLC {
+	return []kraken.OHLC{
+		candle("2022-03-16T20:00:00-04:00", "120", "125", "115", "121", "120", "2", 4),
+		candle("2022-03-11T19:00:00-05:00", "95", "105", "90", "100", "98", "1", 2),
+		candle("2022-03-14T20:00:00-04:00", "110", "120", "105", "115", "112", "3", 6),
+		candle("2022-03-13T20:00:00-04:00", "100", "112", "99", "110", "105", "2", 3),
+	}
+}
+
+// synthetic a flat candle at a time with the price of a previous close
+func synthetic(t, price string) kraken.OHLC {
+	c := candle(t, price, price, price, price, price, "0", 0)
+	c.Synthetic = true
+
+	return c
+}
+
+func TestFindOHLCGaps(t *testing.T) {
+	tcs := []struct {
+		name     string
+		candles  []kraken.OHLC
+		interval kraken.OHLCInterval
+		expected []kraken.TimeRange
+	}{
+		{
+			name:     "DaylightSaving",
+			candles:  dstCandles(),
+			interval: kraken.OHLCIntervalDaily,
+			expected: []kraken.TimeRange{
+				{From: time.Date(2022, 3, 13, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 3, 14, 0, 0, 0, 0, time.UTC)},
+				{From: time.Date(2022, 3, 16, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 3, 17, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "Unaligned",
+			candles: []kraken.OHLC{
+				candle("2022-02-01T10:15:00Z", "1", "1", "1", "1", "1", "1", 1),
+				candle("2022-02-01T11:15:00Z", "1", "1", "1", "1", "1", "1", 1),
+				candle("2022-02-01T13:15:00Z", "1", "1", "1", "1", "1", "1", 1),
+			},
+			interval: kraken.OHLCIntervalHour,
+			expected: []kraken.TimeRange{{From: time.Date(2022, 2, 1, 12, 0, 0, 0, time.UTC), To: time.Date(2022, 2, 1, 13, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:     "Complete",
+			candles:  dstCandles()[2:],
+			interval: kraken.OHLCIntervalDaily,
+			expected: []kraken.TimeRange{},
+		},
+		{name: "Empty", interval: kraken.OHLCIntervalDaily, expected: []kraken.TimeRange{}},
+		{name: "Interval", candles: dstCandles(), expected: []kraken.TimeRange{}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := deep.Equal(kraken.FindOHLCGaps(tc.candles, tc.interval), tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFillOHLCGaps(t *testing.T) {
+	candles := dstCandles()
+	filled := kraken.FillOHLCGaps(candles, kraken.OHLCIntervalDaily)
+
+	expected := []kraken.OHLC{
+		candles[1],
+		synthetic("2022-03-13T00:00:00Z", "100"),
+		candles[3],
+		candles[2],
+		synthetic("2022-03-16T00:00:00Z", "115"),
+		candles[0],
+	}
+
+	if diff := deep.Equal(filled, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// the candles are left as they are
+	if diff := deep.Equal(candles, dstCandles()); diff != nil {
+		t.Error(diff)
+	}
+
+	if filled := kraken.FillOHLCGaps(nil, kraken.OHLCIntervalDaily); len(filled) != 0 {
+		t.Errorf("EXPECTED: []\nACTUAL: %v", filled)
+	}
+}
@@ -0,0 +1,35 @@
+package kraken
+
+import (
+	"fmt"
+	"time"
+)
+
+// CandleBuilderOption options used when creating a new CandleBuilder
+type CandleBuilderOption func(b *CandleBuilder) error
+
+// CandleBuilderWithTolerance keep candles open for a duration after their
+// interval ends so trades arriving out of order are included, candles are
+// completed by the first trade of the next interval by default
+func CandleBuilderWithTolerance(tolerance time.Duration) CandleBuilderOption {
+	return CandleBuilderOption(func(b *CandleBuilder) error {
+		if tolerance < 0 {
+			return fmt.Errorf("tolerance must not be negative")
+		}
+
+		b.tolerance = tolerance
+
+		return nil
+	})
+}
+
+// CandleBuilderWithEmptyCandles emit a candle for intervals without trades,
+// with no volume and every price the close of the previous candle. Intervals
+// before the first trade have no candle
+func CandleBuilderWithEmptyCandles() CandleBuilderOption {
+	return CandleBuilderOption(func(b *CandleBuilder) error {
+		b.emitEmpty = true
+
+		return nil
+	})
+}
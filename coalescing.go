@@ -0,0 +1,441 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CoalescingClient handles coalescing of concurrent identical calls. Read only
+// calls made while a call with the same operation and parameters is in flight
+// wait for and share its result, including any error, rather than making
+// another request. Calls that modify state, and RetrieveExport, are passed
+// through. The in flight call keeps running when the caller that started it
+// gives up, each caller only returning early once its own context is done.
+// Shared results must not be modified
+type CoalescingClient struct {
+	inner   Client
+	flights flightGroup
+}
+
+var _ Client = (*CoalescingClient)(nil)
+
+// NewCoalescingClient helper function for creating a new coalescing client
+func NewCoalescingClient(inner Client) (*CoalescingClient, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("inner client is required")
+	}
+
+	return &CoalescingClient{
+		inner: inner,
+	}, nil
+}
+
+// coalesceKey return the key identifying a call by its operation and encoded
+// parameters, a key that cannot be encoded is not coalesced with other calls
+func coalesceKey(operation string, params ...interface{}) string {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Sprintf("%s:%p", operation, &params)
+	}
+
+	return operation + ":" + string(b)
+}
+
+// sortedStrings return a sorted copy of s, so that lists of pairs or ids given
+// in a different order result in the same key
+func sortedStrings(s []string) []string {
+	sorted := append([]string(nil), s...)
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// Time coalesces concurrent calls to the client Time function
+func (c *CoalescingClient) Time(ctx context.Context) (Time, error) {
+	v, err := c.flights.do(ctx, coalesceKey("Time"), func(ctx context.Context) (interface{}, error) {
+		return c.inner.Time(ctx)
+	})
+
+	res, _ := v.(Time)
+
+	return res, err
+}
+
+// Status coalesces concurrent calls to the client Status function
+func (c *CoalescingClient) Status(ctx context.Context) (SystemStatus, error) {
+	v, err := c.flights.do(ctx, coalesceKey("Status"), func(ctx context.Context) (interface{}, error) {
+		return c.inner.Status(ctx)
+	})
+
+	res, _ := v.(SystemStatus)
+
+	return res, err
+}
+
+// Assets coalesces concurrent calls to the client Assets function
+func (c *CoalescingClient) Assets(ctx context.Context) (Assets, error) {
+	v, err := c.flights.do(ctx, coalesceKey("Assets"), func(ctx context.Context) (interface{}, error) {
+		return c.inner.Assets(ctx)
+	})
+
+	res, _ := v.(Assets)
+
+	return res, err
+}
+
+// AssetPairs coalesces concurrent calls to the client AssetPairs function
+func (c *CoalescingClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
+	v, err := c.flights.do(ctx, coalesceKey("AssetPairs", info, sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.AssetPairs(ctx, info, pairs...)
+	})
+
+	res, _ := v.(AssetPairs)
+
+	return res, err
+}
+
+// Tickers coalesces concurrent calls to the client Tickers function
+func (c *CoalescingClient) Tickers(ctx context.Context, pairs ...string) (Tickers, error) {
+	v, err := c.flights.do(ctx, coalesceKey("Tickers", sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.Tickers(ctx, pairs...)
+	})
+
+	res, _ := v.(Tickers)
+
+	return res, err
+}
+
+// OHLC coalesces concurrent calls to the client OHLC function
+func (c *CoalescingClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
+	v, err := c.flights.do(ctx, coalesceKey("OHLC", interval, since, sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.OHLC(ctx, interval, since, pairs...)
+	})
+
+	res, _ := v.(OHLCs)
+
+	return res, err
+}
+
+// OrderBook coalesces concurrent calls to the client OrderBook function
+func (c *CoalescingClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
+	v, err := c.flights.do(ctx, coalesceKey("OrderBook", count, sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.OrderBook(ctx, count, pairs...)
+	})
+
+	res, _ := v.(OrderBook)
+
+	return res, err
+}
+
+// RecentTrades coalesces concurrent calls to the client RecentTrades function
+func (c *CoalescingClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
+	v, err := c.flights.do(ctx, coalesceKey("RecentTrades", since, sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.RecentTrades(ctx, since, pairs...)
+	})
+
+	res, _ := v.(RecentTrades)
+
+	return res, err
+}
+
+// RecentSpreads coalesces concurrent calls to the client RecentSpreads function
+func (c *CoalescingClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
+	v, err := c.flights.do(ctx, coalesceKey("RecentSpreads", since, sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.RecentSpreads(ctx, since, pairs...)
+	})
+
+	res, _ := v.(RecentSpreads)
+
+	return res, err
+}
+
+// TradeBalance coalesces concurrent calls to the client TradeBalance function
+func (c *CoalescingClient) TradeBalance(ctx context.Context, asset string) (TradeBalance, error) {
+	v, err := c.flights.do(ctx, coalesceKey("TradeBalance", asset), func(ctx context.Context) (interface{}, error) {
+		return c.inner.TradeBalance(ctx, asset)
+	})
+
+	res, _ := v.(TradeBalance)
+
+	return res, err
+}
+
+// OpenOrders coalesces concurrent calls to the client OpenOrders function
+func (c *CoalescingClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error) {
+	v, err := c.flights.do(ctx, coalesceKey("OpenOrders", trades, userref), func(ctx context.Context) (interface{}, error) {
+		return c.inner.OpenOrders(ctx, trades, userref)
+	})
+
+	res, _ := v.(OpenOrders)
+
+	return res, err
+}
+
+// ClosedOrders coalesces concurrent calls to the client ClosedOrders function
+func (c *CoalescingClient) ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error) {
+	v, err := c.flights.do(ctx, coalesceKey("ClosedOrders", query), func(ctx context.Context) (interface{}, error) {
+		return c.inner.ClosedOrders(ctx, query)
+	})
+
+	res, _ := v.(ClosedOrders)
+
+	return res, err
+}
+
+// QueryOrders coalesces concurrent calls to the client QueryOrders function
+func (c *CoalescingClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error) {
+	v, err := c.flights.do(ctx, coalesceKey("QueryOrders", trades, userref, sortedStrings(txids)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.QueryOrders(ctx, trades, userref, txids...)
+	})
+
+	res, _ := v.(OrdersInfo)
+
+	return res, err
+}
+
+// TradesHistory coalesces concurrent calls to the client TradesHistory function
+func (c *CoalescingClient) TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error) {
+	v, err := c.flights.do(ctx, coalesceKey("TradesHistory", query), func(ctx context.Context) (interface{}, error) {
+		return c.inner.TradesHistory(ctx, query)
+	})
+
+	res, _ := v.(TradesHistory)
+
+	return res, err
+}
+
+// OpenPositions coalesces concurrent calls to the client OpenPositions function
+func (c *CoalescingClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error) {
+	v, err := c.flights.do(ctx, coalesceKey("OpenPositions", docalcs, sortedStrings(txids)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.OpenPositions(ctx, docalcs, txids...)
+	})
+
+	res, _ := v.(OpenPositions)
+
+	return res, err
+}
+
+// Ledgers coalesces concurrent calls to the client Ledgers function
+func (c *CoalescingClient) Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error) {
+	v, err := c.flights.do(ctx, coalesceKey("Ledgers", query), func(ctx context.Context) (interface{}, error) {
+		return c.inner.Ledgers(ctx, query)
+	})
+
+	res, _ := v.(Ledgers)
+
+	return res, err
+}
+
+// QueryLedgers coalesces concurrent calls to the client QueryLedgers function
+func (c *CoalescingClient) QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error) {
+	v, err := c.flights.do(ctx, coalesceKey("QueryLedgers", sortedStrings(ids)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.QueryLedgers(ctx, ids...)
+	})
+
+	res, _ := v.(LedgersInfo)
+
+	return res, err
+}
+
+// TradeVolume coalesces concurrent calls to the client TradeVolume function
+func (c *CoalescingClient) TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error) {
+	v, err := c.flights.do(ctx, coalesceKey("TradeVolume", sortedStrings(pairs)), func(ctx context.Context) (interface{}, error) {
+		return c.inner.TradeVolume(ctx, pairs...)
+	})
+
+	res, _ := v.(TradeVolume)
+
+	return res, err
+}
+
+// AddOrder passes through to the client AddOrder function
+func (c *CoalescingClient) AddOrder(ctx context.Context, req OrderRequest) (AddOrderResult, error) {
+	return c.inner.AddOrder(ctx, req)
+}
+
+// CancelOrder passes through to the client CancelOrder function
+func (c *CoalescingClient) CancelOrder(ctx context.Context, txid string) (CancelResult, error) {
+	return c.inner.CancelOrder(ctx, txid)
+}
+
+// CancelAllOrders passes through to the client CancelAllOrders function
+func (c *CoalescingClient) CancelAllOrders(ctx context.Context) (CancelResult, error) {
+	return c.inner.CancelAllOrders(ctx)
+}
+
+// CancelAllOrdersAfter passes through to the client CancelAllOrdersAfter
+// function
+func (c *CoalescingClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error) {
+	return c.inner.CancelAllOrdersAfter(ctx, timeout)
+}
+
+// EditOrder passes through to the client EditOrder function
+func (c *CoalescingClient) EditOrder(ctx context.Context, req EditOrderRequest) (EditOrderResult, error) {
+	return c.inner.EditOrder(ctx, req)
+}
+
+// DepositMethods coalesces concurrent calls to the client DepositMethods
+// function
+func (c *CoalescingClient) DepositMethods(ctx context.Context, asset string) (DepositMethods, error) {
+	v, err := c.flights.do(ctx, coalesceKey("DepositMethods", asset), func(ctx context.Context) (interface{}, error) {
+		return c.inner.DepositMethods(ctx, asset)
+	})
+
+	res, _ := v.(DepositMethods)
+
+	return res, err
+}
+
+// DepositAddresses passes through to the client DepositAddresses function
+func (c *CoalescingClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error) {
+	return c.inner.DepositAddresses(ctx, asset, method, new)
+}
+
+// DepositStatus coalesces concurrent calls to the client DepositStatus function
+func (c *CoalescingClient) DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error) {
+	v, err := c.flights.do(ctx, coalesceKey("DepositStatus", asset, method), func(ctx context.Context) (interface{}, error) {
+		return c.inner.DepositStatus(ctx, asset, method)
+	})
+
+	res, _ := v.(DepositStatus)
+
+	return res, err
+}
+
+// WithdrawInfo coalesces concurrent calls to the client WithdrawInfo function
+func (c *CoalescingClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error) {
+	v, err := c.flights.do(ctx, coalesceKey("WithdrawInfo", asset, key, amount), func(ctx context.Context) (interface{}, error) {
+		return c.inner.WithdrawInfo(ctx, asset, key, amount)
+	})
+
+	res, _ := v.(WithdrawInfo)
+
+	return res, err
+}
+
+// Withdraw passes through to the client Withdraw function
+func (c *CoalescingClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error) {
+	return c.inner.Withdraw(ctx, asset, key, amount)
+}
+
+// WithdrawStatus coalesces concurrent calls to the client WithdrawStatus
+// function
+func (c *CoalescingClient) WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error) {
+	v, err := c.flights.do(ctx, coalesceKey("WithdrawStatus", asset, method), func(ctx context.Context) (interface{}, error) {
+		return c.inner.WithdrawStatus(ctx, asset, method)
+	})
+
+	res, _ := v.(WithdrawStatus)
+
+	return res, err
+}
+
+// WithdrawCancel passes through to the client WithdrawCancel function
+func (c *CoalescingClient) WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error) {
+	return c.inner.WithdrawCancel(ctx, asset, refid)
+}
+
+// WalletTransfer passes through to the client WalletTransfer function
+func (c *CoalescingClient) WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error) {
+	return c.inner.WalletTransfer(ctx, asset, from, to, amount)
+}
+
+// EarnStrategies coalesces concurrent calls to the client EarnStrategies
+// function
+func (c *CoalescingClient) EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error) {
+	v, err := c.flights.do(ctx, coalesceKey("EarnStrategies", asset, cursor), func(ctx context.Context) (interface{}, error) {
+		return c.inner.EarnStrategies(ctx, asset, cursor)
+	})
+
+	res, _ := v.(EarnStrategies)
+
+	return res, err
+}
+
+// EarnAllocate passes through to the client EarnAllocate function
+func (c *CoalescingClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	return c.inner.EarnAllocate(ctx, strategyID, amount)
+}
+
+// EarnDeallocate passes through to the client EarnDeallocate function
+func (c *CoalescingClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	return c.inner.EarnDeallocate(ctx, strategyID, amount)
+}
+
+// EarnAllocationStatus coalesces concurrent calls to the client EarnAllocationStatus
+// function
+func (c *CoalescingClient) EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error) {
+	v, err := c.flights.do(ctx, coalesceKey("EarnAllocationStatus", strategyID), func(ctx context.Context) (interface{}, error) {
+		return c.inner.EarnAllocationStatus(ctx, strategyID)
+	})
+
+	res, _ := v.(EarnAllocationStatus)
+
+	return res, err
+}
+
+// EarnAllocations coalesces concurrent calls to the client EarnAllocations
+// function
+func (c *CoalescingClient) EarnAllocations(ctx context.Context) (EarnAllocations, error) {
+	v, err := c.flights.do(ctx, coalesceKey("EarnAllocations"), func(ctx context.Context) (interface{}, error) {
+		return c.inner.EarnAllocations(ctx)
+	})
+
+	res, _ := v.(EarnAllocations)
+
+	return res, err
+}
+
+// AddExport passes through to the client AddExport function
+func (c *CoalescingClient) AddExport(ctx context.Context, req ExportRequest) (AddExportResult, error) {
+	return c.inner.AddExport(ctx, req)
+}
+
+// ExportStatus coalesces concurrent calls to the client ExportStatus function
+func (c *CoalescingClient) ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error) {
+	v, err := c.flights.do(ctx, coalesceKey("ExportStatus", report), func(ctx context.Context) (interface{}, error) {
+		return c.inner.ExportStatus(ctx, report)
+	})
+
+	res, _ := v.(ExportStatus)
+
+	return res, err
+}
+
+// RetrieveExport passes through to the client RetrieveExport function
+func (c *CoalescingClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.inner.RetrieveExport(ctx, id)
+}
+
+// RemoveExport passes through to the client RemoveExport function
+func (c *CoalescingClient) RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error) {
+	return c.inner.RemoveExport(ctx, id, removal)
+}
+
+// CreateSubaccount passes through to the client CreateSubaccount function
+func (c *CoalescingClient) CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error) {
+	return c.inner.CreateSubaccount(ctx, username, email)
+}
+
+// AccountTransfer passes through to the client AccountTransfer function
+func (c *CoalescingClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error) {
+	return c.inner.AccountTransfer(ctx, asset, amount, fromUser, toUser)
+}
+
+// GetWebSocketsToken coalesces concurrent calls to the client
+// GetWebSocketsToken function
+func (c *CoalescingClient) GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error) {
+	v, err := c.flights.do(ctx, coalesceKey("GetWebSocketsToken"), func(ctx context.Context) (interface{}, error) {
+		return c.inner.GetWebSocketsToken(ctx)
+	})
+
+	res, _ := v.(WebSocketsToken)
+
+	return res, err
+}
@@ -0,0 +1,172 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestCoalescingClient(t *testing.T) {
+	tcs := []struct {
+		name        string
+		status      int
+		pairs       [][]string
+		expected    map[string]int
+		expectedErr error
+	}{
+		{
+			name:   "Identical",
+			status: http.StatusOK,
+			pairs: [][]string{
+				{"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"},
+				{"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"},
+			},
+			expected: map[string]int{"XBTUSD": 1},
+		},
+		{
+			name:   "Distinct",
+			status: http.StatusOK,
+			pairs: [][]string{
+				{"XBTUSD"}, {"XBTUSD"}, {"ETHUSD"}, {"ETHUSD"},
+				{"ETHUSD", "XBTUSD"}, {"XBTUSD", "ETHUSD"},
+			},
+			expected: map[string]int{"XBTUSD": 1, "ETHUSD": 1, "ETHUSD,XBTUSD": 1},
+		},
+		{
+			name:   "Error",
+			status: http.StatusServiceUnavailable,
+			pairs: [][]string{
+				{"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"}, {"XBTUSD"},
+			},
+			expected:    map[string]int{"XBTUSD": 1},
+			expectedErr: ErrServerError,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+
+			var mu sync.Mutex
+			requests := map[string]int{}
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pairs := strings.Split(r.URL.Query().Get("pair"), ",")
+				sort.Strings(pairs)
+
+				mu.Lock()
+				requests[strings.Join(pairs, ",")]++
+				mu.Unlock()
+
+				<-release
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"error":[],"result":{}}`))
+			}))
+			defer s.Close()
+
+			inner, err := NewHTTPClient(HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := NewCoalescingClient(inner)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wg sync.WaitGroup
+			for _, pairs := range tc.pairs {
+				wg.Add(1)
+				go func(pairs []string) {
+					defer wg.Done()
+
+					if _, err := c.Tickers(context.Background(), pairs...); !errors.Is(err, tc.expectedErr) {
+						t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expectedErr, err)
+					}
+				}(pairs)
+			}
+
+			// every call but the first with the same pairs waits on its request
+			calls := map[string]int{}
+			for _, pairs := range tc.pairs {
+				calls[coalesceKey("Tickers", sortedStrings(pairs))]++
+			}
+			for key, n := range calls {
+				awaitWaiters(t, &c.flights, key, n-1)
+			}
+			close(release)
+			wg.Wait()
+
+			if diff := deep.Equal(tc.expected, requests); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCoalescingClientCancel(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+
+	var mu sync.Mutex
+	requests := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		close(started)
+		<-release
+		w.Write([]byte(`{"error":[],"result":{"unixtime":1643714160,"rfc1123":"Tue,  1 Feb 22 11:16:00 +0000"}}`))
+	}))
+	defer s.Close()
+
+	inner, err := NewHTTPClient(HTTPClientWithBaseURL(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewCoalescingClient(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.Time(ctx)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan Time, 1)
+	go func() {
+		res, err := c.Time(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		second <- res
+	}()
+
+	// the first caller gives up while the second waits on its call
+	awaitWaiters(t, &c.flights, coalesceKey("Time"), 1)
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, err)
+	}
+
+	close(release)
+	if res := <-second; res.Timestamp.Unix() != 1643714160 {
+		t.Errorf("EXPECTED: 1643714160\nACTUAL: %d", res.Timestamp.Unix())
+	}
+
+	if requests != 1 {
+		t.Errorf("EXPECTED: 1\nACTUAL: %d", requests)
+	}
+}
@@ -0,0 +1,206 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultConversionHops the most pairs ConvertAmount converts an amount
+// through
+const defaultConversionHops = 3
+
+// liquidAssets the Kraken names of the assets preferred as intermediates of a
+// conversion, most liquid first
+var liquidAssets = []string{"ZUSD", "XXBT", "ZEUR", "XETH", "USDT", "USDC"}
+
+// ConversionStep a trade converting From into To with a pair, selling its
+// base when Sell is set and buying it otherwise
+type ConversionStep struct {
+	Pair string
+	From string
+	To   string
+	Sell bool
+}
+
+// convert the amount of To received for an amount of From at the best bid or
+// ask of a ticker of the step's pair, false when the ticker has no price
+func (s ConversionStep) convert(amount decimal.Decimal, ticker Ticker) (decimal.Decimal, bool) {
+	if s.Sell {
+		if !ticker.Bid.Price.IsPositive() {
+			return decimal.Zero, false
+		}
+
+		return amount.Mul(ticker.Bid.Price), true
+	}
+
+	if !ticker.Ask.Price.IsPositive() {
+		return decimal.Zero, false
+	}
+
+	return amount.Div(ticker.Ask.Price), true
+}
+
+// FindConversionPath return the steps converting one asset into another
+// through at most maxHops pairs, the assets given by their common, Kraken or
+// alternative names. Paths with fewer steps are preferred, then those through
+// more liquid intermediates such as ZUSD and XXBT. A path never converts
+// through an asset twice, so cycles in the pairs are not followed, and
+// converting an asset into itself takes no steps. ErrNoConversionPath is
+// returned when the assets are not connected within maxHops pairs
+func (x *PairIndex) FindConversionPath(from, to string, maxHops int) ([]ConversionStep, error) {
+	if maxHops < 1 {
+		return nil, fmt.Errorf("max hops must be positive")
+	}
+
+	from, to = ToKrakenAsset(from), ToKrakenAsset(to)
+	if from == to {
+		return []ConversionStep{}, nil
+	}
+
+	x.mu.RLock()
+	edges := map[string][]ConversionStep{}
+	for assets, pair := range x.assets {
+		edges[assets.base] = append(edges[assets.base], ConversionStep{Pair: pair, From: assets.base, To: assets.quote, Sell: true})
+		edges[assets.quote] = append(edges[assets.quote], ConversionStep{Pair: pair, From: assets.quote, To: assets.base})
+	}
+	x.mu.RUnlock()
+
+	// a breadth first search keeping the preferred path to each asset reached
+	// in each number of steps, assets reached in fewer steps are not revisited
+	visited := map[string]bool{from: true}
+	paths := map[string][]ConversionStep{from: {}}
+	for hop := 0; hop < maxHops; hop++ {
+		next := map[string][]ConversionStep{}
+		for asset, path := range paths {
+			for _, step := range edges[asset] {
+				if visited[step.To] {
+					continue
+				}
+
+				candidate := append(append(make([]ConversionStep, 0, len(path)+1), path...), step)
+				if existing, ok := next[step.To]; !ok || preferredPath(candidate, existing) {
+					next[step.To] = candidate
+				}
+			}
+		}
+
+		if path, ok := next[to]; ok {
+			return path, nil
+		}
+
+		if len(next) == 0 {
+			break
+		}
+
+		for asset := range next {
+			visited[asset] = true
+		}
+		paths = next
+	}
+
+	return nil, fmt.Errorf("%w:%s to %s within %d pairs", ErrNoConversionPath, from, to, maxHops)
+}
+
+// ConvertAmount return the estimated amount of an asset received converting
+// an amount of another through the path found by FindConversionPath, at the
+// best bid when selling and ask when buying of the current tickers, less the
+// taker fee of each pair at its lowest volume tier. Pairs without a fee
+// schedule are taken to charge no fee
+func ConvertAmount(ctx context.Context, client Client, amount decimal.Decimal, from, to string) (decimal.Decimal, []ConversionStep, error) {
+	if client == nil {
+		return decimal.Zero, nil, fmt.Errorf("client is required")
+	}
+
+	if !amount.IsPositive() {
+		return decimal.Zero, nil, fmt.Errorf("amount must be positive")
+	}
+
+	pairs, err := client.AssetPairs(ctx, AssetPairInfoInfo)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+
+	index, err := NewPairIndex(pairs)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+
+	path, err := index.FindConversionPath(from, to, defaultConversionHops)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+
+	if len(path) == 0 {
+		return amount, path, nil
+	}
+
+	names := make([]string, 0, len(path))
+	for _, step := range path {
+		names = append(names, step.Pair)
+	}
+
+	tickers, err := client.Tickers(ctx, names...)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+
+	if len(tickers.Errors) != 0 {
+		return decimal.Zero, nil, tickers.Errors[0]
+	}
+
+	for _, step := range path {
+		var ok bool
+		if amount, ok = step.convert(amount, tickers.Result[step.Pair]); !ok {
+			return decimal.Zero, nil, fmt.Errorf("no price for %s", step.Pair)
+		}
+
+		pair := pairs.Pairs[step.Pair]
+		if len(pair.FeesTaker) == 0 {
+			continue
+		}
+
+		tier, err := FeeTier(pair.FeesTaker, decimal.Zero)
+		if err != nil {
+			return decimal.Zero, nil, fmt.Errorf("%s taker %w", step.Pair, err)
+		}
+
+		amount = amount.Sub(amount.Mul(tier.Percentage).Div(hundred))
+	}
+
+	return amount, path, nil
+}
+
+// preferredPath whether a path is preferred to another of the same length,
+// comparing the liquidity of the assets they convert through in order and
+// then their names and pairs
+func preferredPath(a, b []ConversionStep) bool {
+	for i := range a {
+		if rankA, rankB := liquidityRank(a[i].To), liquidityRank(b[i].To); rankA != rankB {
+			return rankA < rankB
+		}
+
+		if a[i].To != b[i].To {
+			return a[i].To < b[i].To
+		}
+
+		if a[i].Pair != b[i].Pair {
+			return a[i].Pair < b[i].Pair
+		}
+	}
+
+	return false
+}
+
+// liquidityRank the position of an asset in the liquid assets, assets which
+// are not liquid rank after all those which are
+func liquidityRank(asset string) int {
+	for i, liquid := range liquidAssets {
+		if asset == liquid {
+			return i
+		}
+	}
+
+	return len(liquidAssets)
+}
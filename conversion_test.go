@@ -0,0 +1,190 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+// conversionPairs an AssetPairs result with a cycle between XXBT, ZUSD and
+// XETH, two routes between ADA and DOT and a pair of assets not connected to
+// the others
+func conversionPairs() kraken.AssetPairs {
+	return kraken.AssetPairs{
+		Pairs: map[string]kraken.AssetPair{
+			"XXBTZUSD": {AltName: "XBTUSD", Base: "XXBT", Quote: "ZUSD"},
+			"XETHZUSD": {
+				AltName:   "ETHUSD",
+				Base:      "XETH",
+				Quote:     "ZUSD",
+				FeesTaker: []kraken.Fee{{Volume: 50000, Percentage: decimal.RequireFromString("0.24")}, {Volume: 0, Percentage: decimal.RequireFromString("0.26")}},
+			},
+			"XETHXXBT": {AltName: "ETHXBT", Base: "XETH", Quote: "XXBT"},
+			"ZEURZUSD": {AltName: "EURUSD", Base: "ZEUR", Quote: "ZUSD"},
+			"ADAUSD":   {AltName: "ADAUSD", Base: "ADA", Quote: "ZUSD"},
+			"ADAEUR":   {AltName: "ADAEUR", Base: "ADA", Quote: "ZEUR"},
+			"DOTUSD":   {AltName: "DOTUSD", Base: "DOT", Quote: "ZUSD"},
+			"DOTEUR":   {AltName: "DOTEUR", Base: "DOT", Quote: "ZEUR"},
+			"SOLETH":   {AltName: "SOLETH", Base: "SOL", Quote: "XETH"},
+			"FOOBAR":   {AltName: "FOOBAR", Base: "FOO", Quote: "BAR"},
+		},
+	}
+}
+
+func TestFindConversionPath(t *testing.T) {
+	index, err := kraken.NewPairIndex(conversionPairs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name     string
+		from     string
+		to       string
+		maxHops  int
+		expected []kraken.ConversionStep
+		err      error
+	}{
+		{
+			name:     "Direct",
+			from:     "BTC",
+			to:       "USD",
+			maxHops:  1,
+			expected: []kraken.ConversionStep{{Pair: "XXBTZUSD", From: "XXBT", To: "ZUSD", Sell: true}},
+		},
+		{
+			name:     "Reverse",
+			from:     "USD",
+			to:       "XBT",
+			maxHops:  1,
+			expected: []kraken.ConversionStep{{Pair: "XXBTZUSD", From: "ZUSD", To: "XXBT"}},
+		},
+		{
+			name:    "FewerHops",
+			from:    "ETH",
+			to:      "BTC",
+			maxHops: 3,
+			expected: []kraken.ConversionStep{
+				{Pair: "XETHXXBT", From: "XETH", To: "XXBT", Sell: true},
+			},
+		},
+		{
+			name:    "LiquidIntermediate",
+			from:    "ADA",
+			to:      "DOT",
+			maxHops: 3,
+			expected: []kraken.ConversionStep{
+				{Pair: "ADAUSD", From: "ADA", To: "ZUSD", Sell: true},
+				{Pair: "DOTUSD", From: "ZUSD", To: "DOT"},
+			},
+		},
+		{
+			name:    "ThreeHops",
+			from:    "SOL",
+			to:      "ADA",
+			maxHops: 3,
+			expected: []kraken.ConversionStep{
+				{Pair: "SOLETH", From: "SOL", To: "XETH", Sell: true},
+				{Pair: "XETHZUSD", From: "XETH", To: "ZUSD", Sell: true},
+				{Pair: "ADAUSD", From: "ZUSD", To: "ADA"},
+			},
+		},
+		{name: "MaxHops", from: "SOL", to: "ADA", maxHops: 2, err: kraken.ErrNoConversionPath},
+		{name: "Same", from: "BTC", to: "XXBT", maxHops: 1, expected: []kraken.ConversionStep{}},
+		{name: "Unreachable", from: "BTC", to: "FOO", maxHops: 2, err: kraken.ErrNoConversionPath},
+		{name: "Cycle", from: "BTC", to: "FOO", maxHops: 10, err: kraken.ErrNoConversionPath},
+		{name: "Unknown", from: "BTC", to: "QUX", maxHops: 3, err: kraken.ErrNoConversionPath},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := index.FindConversionPath(tc.from, tc.to, tc.maxHops)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+
+			if diff := deep.Equal(path, tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+
+	if _, err := index.FindConversionPath("BTC", "USD", 0); err == nil {
+		t.Error("EXPECTED: max hops must be positive\nACTUAL: <nil>")
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.ReturnAssetPairs(conversionPairs(), nil)
+	m.ReturnTickers(kraken.Tickers{
+		Result: map[string]kraken.Ticker{
+			"XETHZUSD": portfolioTicker("2001", "2000"),
+			"ADAUSD":   portfolioTicker("0.5", "0.49"),
+		},
+	}, nil)
+
+	// 2 ETH sold for 4000 USD less a 0.26% fee buys 3989.6 / 0.5 ADA
+	amount, path, err := kraken.ConvertAmount(context.Background(), m, decimal.RequireFromString("2"), "ETH", "ADA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := decimal.RequireFromString("7979.2"); !amount.Equal(expected) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", expected, amount)
+	}
+
+	expected := []kraken.ConversionStep{
+		{Pair: "XETHZUSD", From: "XETH", To: "ZUSD", Sell: true},
+		{Pair: "ADAUSD", From: "ZUSD", To: "ADA"},
+	}
+	if diff := deep.Equal(path, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	if diff := deep.Equal(m.CallsTo("Tickers")[0].Args[0], []string{"XETHZUSD", "ADAUSD"}); diff != nil {
+		t.Error(diff)
+	}
+
+	// converting an asset into itself requests no tickers
+	if amount, _, err := kraken.ConvertAmount(context.Background(), m, decimal.RequireFromString("2"), "ETH", "XETH"); err != nil || !amount.Equal(decimal.RequireFromString("2")) {
+		t.Errorf("EXPECTED: 2 <nil>\nACTUAL: %v %v", amount, err)
+	}
+
+	if calls := len(m.CallsTo("Tickers")); calls != 1 {
+		t.Errorf("EXPECTED: 1\nACTUAL: %v", calls)
+	}
+}
+
+func TestConvertAmountErrors(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.ReturnAssetPairs(conversionPairs(), nil)
+	m.ReturnTickers(kraken.Tickers{Result: map[string]kraken.Ticker{"XXBTZUSD": portfolioTicker("0", "0")}}, nil)
+
+	if _, _, err := kraken.ConvertAmount(context.Background(), m, decimal.RequireFromString("1"), "BTC", "FOO"); !errors.Is(err, kraken.ErrNoConversionPath) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrNoConversionPath, err)
+	}
+
+	if _, _, err := kraken.ConvertAmount(context.Background(), m, decimal.RequireFromString("1"), "BTC", "USD"); err == nil {
+		t.Error("EXPECTED: no price for XXBTZUSD\nACTUAL: <nil>")
+	}
+
+	if _, _, err := kraken.ConvertAmount(context.Background(), m, decimal.Zero, "BTC", "USD"); err == nil {
+		t.Error("EXPECTED: amount must be positive\nACTUAL: <nil>")
+	}
+
+	if _, _, err := kraken.ConvertAmount(context.Background(), nil, decimal.RequireFromString("1"), "BTC", "USD"); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+}
@@ -0,0 +1,199 @@
+package kraken
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ohlcCSVHeader the columns of an OHLC CSV file, times are RFC3339 with
+	// nanoseconds in UTC
+	ohlcCSVHeader = []string{"time", "open", "high", "low", "close", "vwap", "volume", "count"}
+	// tradeCSVHeader the columns of a trade CSV file, times are RFC3339 with
+	// nanoseconds in UTC and the action and type are their string values
+	tradeCSVHeader = []string{"time", "price", "volume", "action", "type", "miscellaneous", "trade_id"}
+)
+
+// WriteOHLCCSV write candles as CSV with a header row and the columns time,
+// open, high, low, close, vwap, volume and count. Times are RFC3339 with
+// nanoseconds in UTC and decimals are written exactly
+func WriteOHLCCSV(w io.Writer, candles []OHLC) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ohlcCSVHeader); err != nil {
+		return err
+	}
+
+	for _, candle := range candles {
+		err := cw.Write([]string{
+			candle.Time.UTC().Format(time.RFC3339Nano),
+			candle.Open.String(),
+			candle.High.String(),
+			candle.Low.String(),
+			candle.Close.String(),
+			candle.VolumeWeightedAveragePrice.String(),
+			candle.Volume.String(),
+			strconv.FormatUint(candle.Count, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ReadOHLCCSV read candles written by WriteOHLCCSV, errors give the row they
+// occurred on counting the header as row 1 and match ErrParse
+func ReadOHLCCSV(r io.Reader) ([]OHLC, error) {
+	candles := []OHLC{}
+	err := readCSV(r, ohlcCSVHeader, func(row int, record []string) error {
+		candle := OHLC{}
+
+		var err error
+		if candle.Time, err = time.Parse(time.RFC3339Nano, record[0]); err != nil {
+			return csvError(row, "time", err)
+		}
+		candle.Time = candle.Time.UTC()
+
+		for i, d := range []*decimal.Decimal{&candle.Open, &candle.High, &candle.Low, &candle.Close, &candle.VolumeWeightedAveragePrice, &candle.Volume} {
+			if *d, err = decimal.NewFromString(record[i+1]); err != nil {
+				return csvError(row, ohlcCSVHeader[i+1], err)
+			}
+		}
+
+		if candle.Count, err = strconv.ParseUint(record[7], 10, 64); err != nil {
+			return csvError(row, "count", err)
+		}
+
+		candles = append(candles, candle)
+
+		return nil
+	})
+
+	return candles, err
+}
+
+// WriteTradesCSV write trades as CSV with a header row and the columns time,
+// price, volume, action, type, miscellaneous and trade_id. Times are RFC3339
+// with nanoseconds in UTC, decimals are written exactly and the action and
+// type are their string values, such as "buy" and "limit"
+func WriteTradesCSV(w io.Writer, trades []RecentTrade) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(tradeCSVHeader); err != nil {
+		return err
+	}
+
+	for _, trade := range trades {
+		err := cw.Write([]string{
+			trade.Time.UTC().Format(time.RFC3339Nano),
+			trade.Price.String(),
+			trade.Volume.String(),
+			trade.Action.String(),
+			trade.Type.String(),
+			trade.Miscellaneous,
+			strconv.FormatUint(trade.TradeID, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ReadTradesCSV read trades written by WriteTradesCSV, errors give the row
+// they occurred on counting the header as row 1 and match ErrParse
+func ReadTradesCSV(r io.Reader) ([]RecentTrade, error) {
+	p := Parser{}
+	trades := []RecentTrade{}
+	err := readCSV(r, tradeCSVHeader, func(row int, record []string) error {
+		trade := RecentTrade{Miscellaneous: record[5]}
+
+		var err error
+		if trade.Time, err = time.Parse(time.RFC3339Nano, record[0]); err != nil {
+			return csvError(row, "time", err)
+		}
+		trade.Time = trade.Time.UTC()
+
+		if trade.Price, err = decimal.NewFromString(record[1]); err != nil {
+			return csvError(row, "price", err)
+		}
+
+		if trade.Volume, err = decimal.NewFromString(record[2]); err != nil {
+			return csvError(row, "volume", err)
+		}
+
+		if trade.Action = p.parseOrderAction(record[3]); trade.Action == OrderActionUnknown && record[3] != trade.Action.String() {
+			return csvError(row, "action", fmt.Errorf("unknown order action %q", record[3]))
+		}
+
+		if trade.Type = p.parseOrderType(record[4]); trade.Type == OrderTypeUnknown && record[4] != trade.Type.String() {
+			return csvError(row, "type", fmt.Errorf("unknown order type %q", record[4]))
+		}
+
+		if trade.TradeID, err = strconv.ParseUint(record[6], 10, 64); err != nil {
+			return csvError(row, "trade_id", err)
+		}
+
+		trades = append(trades, trade)
+
+		return nil
+	})
+
+	return trades, err
+}
+
+// readCSV read the records of a CSV file after a header row matching header,
+// every record must have a column for each of the header
+func readCSV(r io.Reader, header []string, fn func(row int, record []string) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			if row == 1 {
+				return fmt.Errorf("%w:header row is missing", ErrParse)
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w:row %d: %s", ErrParse, row, err)
+		}
+
+		if len(record) != len(header) {
+			return fmt.Errorf("%w:row %d has %d columns, expected %d", ErrParse, row, len(record), len(header))
+		}
+
+		if row == 1 {
+			for i := range header {
+				if record[i] != header[i] {
+					return fmt.Errorf("%w:row 1 column %d is %q, expected %q", ErrParse, i+1, record[i], header[i])
+				}
+			}
+
+			continue
+		}
+
+		if err := fn(row, record); err != nil {
+			return err
+		}
+	}
+}
+
+// csvError an error parsing a column of a CSV row
+func csvError(row int, column string, err error) error {
+	return fmt.Errorf("%w:row %d %s: %s", ErrParse, row, column, err)
+}
@@ -0,0 +1,154 @@
+package kraken_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+// newFixtureClient a client replaying the public fixtures
+func newFixtureClient(t *testing.T) *kraken.HTTPClient {
+	replayer, err := krakentest.NewReplayer("testdata/fixtures/public.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithHTTPClient(&http.Client{Transport: replayer}),
+		kraken.HTTPClientWithBaseURL("http://kraken.invalid/0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestOHLCCSV(t *testing.T) {
+	res, err := newFixtureClient(t).OHLC(context.Background(), kraken.OHLCIntervalMinute, nil, "XBTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candles := res.Result["XXBTZUSD"]
+	if len(candles) == 0 {
+		t.Fatal("EXPECTED: candles in the fixture\nACTUAL: none")
+	}
+
+	buf := bytes.Buffer{}
+	if err := kraken.WriteOHLCCSV(&buf, candles); err != nil {
+		t.Fatal(err)
+	}
+
+	if header, _, _ := strings.Cut(buf.String(), "\n"); header != "time,open,high,low,close,vwap,volume,count" {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", "time,open,high,low,close,vwap,volume,count", header)
+	}
+
+	actual, err := kraken.ReadOHLCCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(candles, actual); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTradesCSV(t *testing.T) {
+	res, err := newFixtureClient(t).RecentTrades(context.Background(), nil, "XBTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trades := res.Trades["XXBTZUSD"]
+	if len(trades) == 0 {
+		t.Fatal("EXPECTED: trades in the fixture\nACTUAL: none")
+	}
+
+	buf := bytes.Buffer{}
+	if err := kraken.WriteTradesCSV(&buf, trades); err != nil {
+		t.Fatal(err)
+	}
+
+	if header, _, _ := strings.Cut(buf.String(), "\n"); header != "time,price,volume,action,type,miscellaneous,trade_id" {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", "time,price,volume,action,type,miscellaneous,trade_id", header)
+	}
+
+	actual, err := kraken.ReadTradesCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(trades, actual); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		trades   bool
+		expected string
+	}{
+		{
+			name:     "Empty",
+			input:    "",
+			expected: "parse error:header row is missing",
+		},
+		{
+			name:     "Header",
+			input:    "time,open,high,low,close,volume,vwap,count\n",
+			expected: `parse error:row 1 column 6 is "volume", expected "vwap"`,
+		},
+		{
+			name:     "Columns",
+			input:    "time,open,high,low,close,vwap,volume,count\n2022-02-01T00:00:00Z,1,1,1,1,1,1\n",
+			expected: "parse error:row 2 has 7 columns, expected 8",
+		},
+		{
+			name:     "Time",
+			input:    "time,open,high,low,close,vwap,volume,count\n2022-02-01T00:00:00Z,1,1,1,1,1,1,1\n1643673600,1,1,1,1,1,1,1\n",
+			expected: `parse error:row 3 time: parsing time "1643673600" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "673600" as "-"`,
+		},
+		{
+			name:     "Decimal",
+			input:    "time,open,high,low,close,vwap,volume,count\n2022-02-01T00:00:00Z,1,1,1,1,1,abc,1\n",
+			expected: "parse error:row 2 volume: can't convert abc to decimal",
+		},
+		{
+			name:     "Action",
+			input:    "time,price,volume,action,type,miscellaneous,trade_id\n2022-02-01T00:00:00Z,1,1,hold,limit,,1\n",
+			trades:   true,
+			expected: `parse error:row 2 action: unknown order action "hold"`,
+		},
+		{
+			name:     "TradeID",
+			input:    "time,price,volume,action,type,miscellaneous,trade_id\n2022-02-01T00:00:00Z,1,1,buy,limit,,-1\n",
+			trades:   true,
+			expected: `parse error:row 2 trade_id: strconv.ParseUint: parsing "-1": invalid syntax`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.trades {
+				_, err = kraken.ReadTradesCSV(strings.NewReader(tc.input))
+			} else {
+				_, err = kraken.ReadOHLCCSV(strings.NewReader(tc.input))
+			}
+
+			if !errors.Is(err, kraken.ErrParse) || err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package kraken
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"time"
 )
 
 var (
@@ -30,4 +34,199 @@ var (
 	ErrParse = errors.New("parse error")
 	// ErrNetwork error occoured during the transportation of a message
 	ErrNetwork = errors.New("network error")
+	// ErrRateLimit the request was rejected for exceeding a rate limit, either
+	// with HTTP 429 or an API error
+	ErrRateLimit = errors.New("rate limit exceeded")
+	// ErrInvalidKey the API key is invalid, EAPI:Invalid key
+	ErrInvalidKey = errors.New("invalid key")
+	// ErrInvalidSignature the request signature is invalid, EAPI:Invalid
+	// signature
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidNonce the nonce is not greater than the last nonce used with
+	// the API key, EAPI:Invalid nonce
+	ErrInvalidNonce = errors.New("invalid nonce")
+	// ErrUnknownAssetPair the asset pair is not known, EQuery:Unknown asset
+	// pair
+	ErrUnknownAssetPair = errors.New("unknown asset pair")
+	// ErrInsufficientFunds the account has insufficient funds for the order,
+	// EOrder:Insufficient funds
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrOrderMinimum the order volume is below the minimum for the pair,
+	// EOrder:Order minimum not met
+	ErrOrderMinimum = errors.New("order minimum not met")
+	// ErrUnknownOrder the order is not known, EOrder:Unknown order
+	ErrUnknownOrder = errors.New("unknown order")
+	// ErrServiceUnavailable the API is unavailable, EService:Unavailable
+	ErrServiceUnavailable = errors.New("service unavailable")
+	// ErrThrottled the API responded with HTTP 429 Too Many Requests, it wraps
+	// ErrRateLimit
+	ErrThrottled = fmt.Errorf("throttled: %w", ErrRateLimit)
+	// ErrServerError the API responded with a HTTP 5xx status
+	ErrServerError = errors.New("server error")
+	// ErrSubscription a websocket subscription was rejected by the API
+	ErrSubscription = errors.New("subscription error")
+	// ErrWSClosed the websocket connection has been closed
+	ErrWSClosed = errors.New("websocket closed")
+	// ErrWSDropped a websocket message was dropped as the buffer of its
+	// subscription was full
+	ErrWSDropped = errors.New("websocket message dropped")
+	// ErrChecksum the checksum of a local order book does not match the
+	// checksum given by the API
+	ErrChecksum = errors.New("checksum mismatch")
+	// ErrWSGap websocket updates were missed or received out of order
+	ErrWSGap = errors.New("websocket gap detected")
+	// ErrLateTrade a trade arrived after the candle of its interval was
+	// completed by a CandleBuilder
+	ErrLateTrade = errors.New("late trade")
+	// ErrInsufficientDepth the levels of a book do not cover the volume to
+	// be filled
+	ErrInsufficientDepth = errors.New("insufficient depth")
+	// ErrCrossedBook the best bid of a book is not below its best ask
+	ErrCrossedBook = errors.New("crossed book")
+	// ErrUnsortedBook the levels of a book are not in price order
+	ErrUnsortedBook = errors.New("unsorted book")
+	// ErrOrderValidation an order request violates the metadata of its pair
+	ErrOrderValidation = errors.New("order validation error")
+	// ErrMissingOHLC candles of a requested range are not available
+	ErrMissingOHLC = errors.New("missing ohlc")
+	// ErrNoConversionPath no pairs connect one asset to another
+	ErrNoConversionPath = errors.New("no conversion path")
 )
+
+// ErrorSeverity the severity of an error returned by the Kraken API
+type ErrorSeverity byte
+
+// String return a string value of the error severity
+func (s ErrorSeverity) String() string {
+	switch s {
+	case ErrorSeverityError:
+		return "error"
+	case ErrorSeverityWarning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// ErrorSeverityError enum representing an error, prefixed with E
+	ErrorSeverityError = iota
+	// ErrorSeverityWarning enum representing a warning, prefixed with W
+	ErrorSeverityWarning
+	// ErrorSeverityUnknown enum representing an unknown severity
+	ErrorSeverityUnknown
+)
+
+// KrakenError an error returned by the Kraken API in the format
+// <severity><category>:<message>, such as "EOrder:Insufficient funds". It
+// matches the error for its category, such as ErrOrder, and for well known
+// messages a more specific error, such as ErrInsufficientFunds. An error which
+// does not follow the format has no category and matches ErrAPIUnknown
+type KrakenError struct {
+	Severity ErrorSeverity
+	Category string
+	Message  string
+
+	category error
+	known    error
+}
+
+// Error return the error as returned by the API
+func (e *KrakenError) Error() string {
+	if e.Category == "" {
+		return fmt.Sprintf("%s:%s", ErrAPIUnknown, e.Message)
+	}
+
+	return e.apiError()
+}
+
+// MarshalJSON marshal the error as the string returned by the API
+func (e KrakenError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.apiError())
+}
+
+// UnmarshalJSON unmarshal an API error string
+func (e *KrakenError) UnmarshalJSON(data []byte) error {
+	var errString string
+	if err := json.Unmarshal(data, &errString); err != nil {
+		return err
+	}
+
+	*e = *(&Parser{}).parseError(errString)
+	return nil
+}
+
+// apiError return the error string as returned by the API
+func (e KrakenError) apiError() string {
+	if e.Category == "" {
+		return e.Message
+	}
+
+	var severity string
+	switch e.Severity {
+	case ErrorSeverityError:
+		severity = "E"
+	case ErrorSeverityWarning:
+		severity = "W"
+	}
+
+	return fmt.Sprintf("%s%s:%s", severity, e.Category, e.Message)
+}
+
+// Unwrap return the category error and the error for a well known message
+func (e *KrakenError) Unwrap() []error {
+	if e.known == nil {
+		return []error{e.category}
+	}
+
+	return []error{e.category, e.known}
+}
+
+// RequestSummary a request as it would have been sent to the Kraken API,
+// credentials are removed from the headers and body
+type RequestSummary struct {
+	Method string
+	URL    string
+	Query  string
+	Body   string
+	Header http.Header
+}
+
+// DryRunError returned instead of sending a request when dry run has been
+// specified, it matches ErrDryRun with errors.Is
+type DryRunError struct {
+	Request RequestSummary
+}
+
+// Error return a description of the request which was not sent
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("%s: %s %s", ErrDryRun, e.Request.Method, e.Request.URL)
+}
+
+// Unwrap return ErrDryRun
+func (e *DryRunError) Unwrap() error {
+	return ErrDryRun
+}
+
+// BackfillGapError returned by BackfillOHLC alongside the candles it found
+// when candles of the requested range are missing, such as those older than
+// the API keeps. It matches ErrMissingOHLC with errors.Is
+type BackfillGapError struct {
+	Pair     string
+	Interval OHLCInterval
+	Gaps     []TimeRange
+}
+
+// Error return a description of the missing candles
+func (e *BackfillGapError) Error() string {
+	if len(e.Gaps) == 0 {
+		return fmt.Sprintf("%s: no gaps in the %s ohlc of %s", ErrMissingOHLC, e.Interval.Duration(), e.Pair)
+	}
+
+	return fmt.Sprintf("%s: %d gaps in the %s ohlc of %s, the first from %s to %s", ErrMissingOHLC, len(e.Gaps), e.Interval.Duration(), e.Pair, e.Gaps[0].From.Format(time.RFC3339), e.Gaps[0].To.Format(time.RFC3339))
+}
+
+// Unwrap return ErrMissingOHLC
+func (e *BackfillGapError) Unwrap() error {
+	return ErrMissingOHLC
+}
@@ -0,0 +1,83 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// hundred the divisor of a fee percentage
+var hundred = decimal.NewFromInt(100)
+
+// FeeTier return the tier of a fee schedule applying to a 30 day volume, the
+// tier with the highest volume no greater than it. The tiers need not be
+// sorted, an error is returned when there are none or none apply
+func FeeTier(fees []Fee, volume30d decimal.Decimal) (Fee, error) {
+	if len(fees) == 0 {
+		return Fee{}, fmt.Errorf("fee schedule is empty")
+	}
+
+	sorted := append([]Fee(nil), fees...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Volume < sorted[j].Volume })
+
+	i := sort.Search(len(sorted), func(i int) bool { return decimal.NewFromInt(int64(sorted[i].Volume)).GreaterThan(volume30d) })
+	if i == 0 {
+		return Fee{}, fmt.Errorf("no fee tier for a volume of %s", volume30d)
+	}
+
+	return sorted[i-1], nil
+}
+
+// EstimateFees return the maker and taker fees of a trade of a notional value
+// in the quote currency of a pair, with the fee tiers applying to a 30 day
+// volume. Pairs without a maker fee schedule charge the taker fee to makers.
+// The pair must be requested with AssetPairInfoInfo or AssetPairInfoFees for
+// its schedules to be populated
+func EstimateFees(pair AssetPair, volume30d, notional decimal.Decimal) (maker, taker decimal.Decimal, err error) {
+	if volume30d.IsNegative() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("volume must not be negative")
+	}
+
+	if notional.IsNegative() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("notional must not be negative")
+	}
+
+	takerTier, err := FeeTier(pair.FeesTaker, volume30d)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("taker %w", err)
+	}
+
+	makerTier := takerTier
+	if len(pair.FeesMaker) != 0 {
+		if makerTier, err = FeeTier(pair.FeesMaker, volume30d); err != nil {
+			return decimal.Zero, decimal.Zero, fmt.Errorf("maker %w", err)
+		}
+	}
+
+	maker = notional.Mul(makerTier.Percentage).Div(hundred)
+	taker = notional.Mul(takerTier.Percentage).Div(hundred)
+
+	return maker, taker, nil
+}
+
+// EstimateAccountFees return the maker and taker fees of a trade of a
+// notional value with EstimateFees, using the 30 day volume of the account
+// from the TradeVolume endpoint
+func EstimateAccountFees(ctx context.Context, client Client, pair AssetPair, notional decimal.Decimal) (maker, taker decimal.Decimal, err error) {
+	if client == nil {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("client is required")
+	}
+
+	res, err := client.TradeVolume(ctx)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	if len(res.Errors) != 0 {
+		return decimal.Zero, decimal.Zero, res.Errors[0]
+	}
+
+	return EstimateFees(pair, res.Volume, notional)
+}
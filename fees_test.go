@@ -0,0 +1,170 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+// feePair a pair with the fee schedules of XXBTZUSD
+func feePair() kraken.AssetPair {
+	return kraken.AssetPair{
+		FeesTaker: []kraken.Fee{
+			{Volume: 0, Percentage: decimal.RequireFromString("0.26")},
+			{Volume: 50000, Percentage: decimal.RequireFromString("0.24")},
+			{Volume: 100000, Percentage: decimal.RequireFromString("0.22")},
+		},
+		FeesMaker: []kraken.Fee{
+			{Volume: 100000, Percentage: decimal.RequireFromString("0.12")},
+			{Volume: 0, Percentage: decimal.RequireFromString("0.16")},
+			{Volume: 50000, Percentage: decimal.RequireFromString("0.14")},
+		},
+	}
+}
+
+func TestEstimateFees(t *testing.T) {
+	tcs := []struct {
+		name      string
+		pair      kraken.AssetPair
+		volume30d string
+		maker     string
+		taker     string
+	}{
+		{
+			name:      "NoVolume",
+			pair:      feePair(),
+			volume30d: "0",
+			maker:     "1.6",
+			taker:     "2.6",
+		},
+		{
+			name:      "BelowBoundary",
+			pair:      feePair(),
+			volume30d: "49999.99",
+			maker:     "1.6",
+			taker:     "2.6",
+		},
+		{
+			name:      "AtBoundary",
+			pair:      feePair(),
+			volume30d: "50000",
+			maker:     "1.4",
+			taker:     "2.4",
+		},
+		{
+			name:      "AboveHighestTier",
+			pair:      feePair(),
+			volume30d: "250000",
+			maker:     "1.2",
+			taker:     "2.2",
+		},
+		{
+			name:      "NoMakerSchedule",
+			pair:      kraken.AssetPair{FeesTaker: feePair().FeesTaker},
+			volume30d: "50000",
+			maker:     "2.4",
+			taker:     "2.4",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			maker, taker, err := kraken.EstimateFees(tc.pair, decimal.RequireFromString(tc.volume30d), decimal.NewFromInt(1000))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !maker.Equal(decimal.RequireFromString(tc.maker)) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.maker, maker)
+			}
+
+			if !taker.Equal(decimal.RequireFromString(tc.taker)) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.taker, taker)
+			}
+		})
+	}
+}
+
+func TestEstimateFeesErrors(t *testing.T) {
+	tcs := []struct {
+		name      string
+		pair      kraken.AssetPair
+		volume30d decimal.Decimal
+		notional  decimal.Decimal
+		expected  string
+	}{
+		{
+			name:      "NoSchedule",
+			volume30d: decimal.Zero,
+			notional:  decimal.NewFromInt(1000),
+			expected:  "taker fee schedule is empty",
+		},
+		{
+			name:      "NoTier",
+			pair:      kraken.AssetPair{FeesTaker: []kraken.Fee{{Volume: 10, Percentage: decimal.NewFromInt(1)}}},
+			volume30d: decimal.NewFromInt(5),
+			notional:  decimal.NewFromInt(1000),
+			expected:  "taker no fee tier for a volume of 5",
+		},
+		{
+			name:      "NegativeVolume",
+			pair:      feePair(),
+			volume30d: decimal.NewFromInt(-1),
+			notional:  decimal.NewFromInt(1000),
+			expected:  "volume must not be negative",
+		},
+		{
+			name:      "NegativeNotional",
+			pair:      feePair(),
+			volume30d: decimal.Zero,
+			notional:  decimal.NewFromInt(-1),
+			expected:  "notional must not be negative",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := kraken.EstimateFees(tc.pair, tc.volume30d, tc.notional); err == nil || err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestEstimateAccountFees(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	m.OnTradeVolume(func(ctx context.Context, pairs ...string) (kraken.TradeVolume, error) {
+		calls++
+		if calls == 2 {
+			return kraken.TradeVolume{Errors: []error{kraken.ErrInvalidKey}}, nil
+		}
+
+		return kraken.TradeVolume{Currency: "ZUSD", Volume: decimal.NewFromInt(50000)}, nil
+	})
+
+	maker, taker, err := kraken.EstimateAccountFees(context.Background(), m, feePair(), decimal.NewFromInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !maker.Equal(decimal.RequireFromString("1.4")) || !taker.Equal(decimal.RequireFromString("2.4")) {
+		t.Errorf("EXPECTED: 1.4 2.4\nACTUAL: %v %v", maker, taker)
+	}
+
+	if _, _, err := kraken.EstimateAccountFees(context.Background(), m, feePair(), decimal.NewFromInt(1000)); !errors.Is(err, kraken.ErrInvalidKey) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrInvalidKey, err)
+	}
+
+	if _, _, err := kraken.EstimateAccountFees(context.Background(), nil, feePair(), decimal.NewFromInt(1000)); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+}
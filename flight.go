@@ -0,0 +1,101 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// flightCall a call in flight, done is closed once the result is set.
+// recovered is the value the call panicked with, if any, and waiters the
+// number of callers sharing the result
+type flightCall struct {
+	done      chan struct{}
+	val       interface{}
+	err       error
+	recovered interface{}
+	waiters   int
+}
+
+// flightGroup deduplicates concurrent calls with the same key so that only one
+// is executed and its result is shared with every caller
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do execute fn unless a call with the same key is already in flight, in which
+// case the result of that call is returned once it completes. fn runs with
+// the values of ctx but is not cancelled with it, so the caller which started
+// the call giving up does not fail it for the others, and each caller returns
+// the error of its own ctx once it is done. When fn panics the panic is passed
+// on to the caller which started it and the waiters are returned an error
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	call, waiting := g.calls[key]
+	if waiting {
+		call.waiters++
+	} else {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.call(context.WithoutCancel(ctx), key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		if waiting {
+			g.mu.Lock()
+			call.waiters--
+			g.mu.Unlock()
+		}
+
+		return nil, ctx.Err()
+	}
+
+	if !waiting && call.recovered != nil {
+		panic(call.recovered)
+	}
+
+	return call.val, call.err
+}
+
+// call execute fn setting the result of call and releasing the key, a panic
+// of fn is recovered and recorded on call
+func (g *flightGroup) call(ctx context.Context, key string, call *flightCall, fn func(ctx context.Context) (interface{}, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.val, call.err, call.recovered = nil, fmt.Errorf("call panicked: %v", r), r
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	call.val, call.err = fn(ctx)
+}
+
+// waiting return the number of callers waiting on the call in flight with a
+// key, zero when there is none
+func (g *flightGroup) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call.waiters
+	}
+
+	return 0
+}
@@ -0,0 +1,77 @@
+package kraken
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// awaitWaiters block until n callers are waiting on the call in flight with
+// a key, failing the test if they have not within a second
+func awaitWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for g.waiting(key) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("EXPECTED: %d waiters\nACTUAL: %d", n, g.waiting(key))
+		}
+
+		runtime.Gosched()
+	}
+}
+
+func TestFlightGroupPanic(t *testing.T) {
+	g := flightGroup{}
+	started, release := make(chan struct{}), make(chan struct{})
+
+	recovered := make(chan interface{}, 1)
+	go func() {
+		defer func() { recovered <- recover() }()
+
+		g.do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, err := g.do(context.Background(), "key", func(ctx context.Context) (interface{}, error) { return "not shared", nil })
+			if v != nil {
+				t.Errorf("EXPECTED: <nil>\nACTUAL: %v", v)
+			}
+			errs <- err
+		}()
+	}
+
+	// the waiters are blocked on the call in flight before it panics
+	awaitWaiters(t, &g, "key", cap(errs))
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	if r := <-recovered; r != "boom" {
+		t.Errorf("EXPECTED: boom\nACTUAL: %v", r)
+	}
+
+	for err := range errs {
+		if err == nil || err.Error() != "call panicked: boom" {
+			t.Errorf("EXPECTED: call panicked: boom\nACTUAL: %v", err)
+		}
+	}
+
+	// the key is released so a later call executes
+	if v, err := g.do(context.Background(), "key", func(ctx context.Context) (interface{}, error) { return "value", nil }); v != "value" || err != nil {
+		t.Errorf("EXPECTED: value <nil>\nACTUAL: %v %v", v, err)
+	}
+}
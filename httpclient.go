@@ -1,17 +1,27 @@
 package kraken
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // HTTPClient used to interact with the Kraken API and return parsed responses
@@ -19,8 +29,44 @@ type HTTPClient struct {
 	httpClient *http.Client
 	parser     Parser
 	dryRun     bool
+	apiKey     string
 	secret     string
 	baseURL    string
+	nonces     NonceGenerator
+	otp        func() (string, error)
+
+	nonceStoreMu sync.Mutex
+	nonceStore   NonceStore
+
+	retry          retryPolicy
+	failOnAPIError bool
+	validateOrders bool
+	maxRawCapture  int
+
+	assetPrecisionsMu sync.Mutex
+	assetPrecisions   map[string]int32
+}
+
+var _ Client = (*HTTPClient)(nil)
+
+const (
+	// maxErrorBody the number of bytes of a response body included in errors
+	maxErrorBody = 128
+	// maxParsePayload the number of bytes of a payload included in parse errors
+	maxParsePayload = 256
+	// defaultMaxRawCapture the number of bytes of a payload captured in the
+	// Raw field of results when raw capture is enabled without a limit
+	defaultMaxRawCapture = 1 << 20
+	// keepAliveBufferSize the number of refresh failures buffered by
+	// KeepAlive, failures are dropped while the buffer is full
+	keepAliveBufferSize = 16
+)
+
+// retryPolicy how failed public requests are retried
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
 }
 
 // NewHTTPClient helper function for creating a new Kraken HTTPClient
@@ -29,6 +75,10 @@ func NewHTTPClient(opts ...HTTPClientOption) (*HTTPClient, error) {
 		httpClient: http.DefaultClient,
 		baseURL:    "https://api.kraken.com/0",
 		parser:     Parser{},
+		nonces:     NewClockNonceGenerator(),
+		retry: retryPolicy{
+			maxAttempts: 1,
+		},
 	}
 
 	for _, opt := range opts {
@@ -37,281 +87,1227 @@ func NewHTTPClient(opts ...HTTPClientOption) (*HTTPClient, error) {
 		}
 	}
 
+	if (c.apiKey == "") != (c.secret == "") {
+		return nil, fmt.Errorf("API key and secret must be set together")
+	}
+
 	return &c, nil
 }
 
 // Time query the Kraken /public/time endpoint and return a parsed response
 func (c *HTTPClient) Time(ctx context.Context) (Time, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/time", c.baseURL), nil)
-	if err != nil {
-		return Time{}, err
+	return public[Time](ctx, c, "Time", nil)
+}
+
+// Status query the Kraken /public/SystemStatus endpoint and return a
+// parsed response
+func (c *HTTPClient) Status(ctx context.Context) (SystemStatus, error) {
+	return public[SystemStatus](ctx, c, "SystemStatus", nil)
+}
+
+// Assets query the Kraken /public/Assets endpoint and return a parsed response
+func (c *HTTPClient) Assets(ctx context.Context) (Assets, error) {
+	return public[Assets](ctx, c, "Assets", nil)
+}
+
+// AssetPairs query the Kraken /public/AssetPairs endpoint and return a parsed
+// response
+func (c *HTTPClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
+	query := url.Values{}
+	if info != "" {
+		query["info"] = []string{string(info)}
 	}
 
-	res, err := c.execute(req)
+	if len(pairs) != 0 {
+		query["pair"] = []string{strings.Join(pairs, ",")}
+	}
+	return public[AssetPairs](ctx, c, "AssetPairs", query)
+}
+
+// Tickers query the Kraken /public/Ticker endpoint and return a parsed
+// response, the tickers of all tradable pairs are returned when no pairs are
+// given
+func (c *HTTPClient) Tickers(ctx context.Context, pairs ...string) (Tickers, error) {
+	query := url.Values{}
+	if len(pairs) != 0 {
+		query["pair"] = []string{strings.Join(pairs, ",")}
+	}
+	return public[Tickers](ctx, c, "Ticker", query)
+}
+
+// OHLC query the Kraken /public/OHLC endpoint and return a parsed
+// response
+func (c *HTTPClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
+	if len(pairs) == 0 {
+		return OHLCs{}, fmt.Errorf("pairs are required")
+	}
+
+	query := url.Values{}
+	query["pair"] = []string{strings.Join(pairs, ",")}
+	query["interval"] = []string{strconv.Itoa(int(interval))}
+
+	if since != nil {
+		query["since"] = []string{strconv.FormatUint(*since, 10)}
+	}
+	return public[OHLCs](ctx, c, "OHLC", query)
+}
+
+// OrderBook query the Kraken /public/Depth endpoint and return a parsed
+// response
+func (c *HTTPClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
+	if len(pairs) == 0 {
+		return OrderBook{}, fmt.Errorf("pairs are required")
+	}
+
+	query := url.Values{}
+	query["pair"] = []string{strings.Join(pairs, ",")}
+	query["count"] = []string{strconv.FormatUint(uint64(count), 10)}
+	return public[OrderBook](ctx, c, "Depth", query)
+}
+
+// RecentTrades query the Kraken /public/Trades endpoint and return a parsed
+// response
+func (c *HTTPClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
+	if len(pairs) == 0 {
+		return RecentTrades{}, fmt.Errorf("pairs are required")
+	}
+
+	query := url.Values{}
+	query["pair"] = []string{strings.Join(pairs, ",")}
+
+	if since != nil {
+		query["since"] = []string{strconv.FormatUint(*since, 10)}
+	}
+	return public[RecentTrades](ctx, c, "Trades", query)
+}
+
+// RecentSpreads query the Kraken /public/Spread endpoint and return a parsed
+// response
+func (c *HTTPClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
+	if len(pairs) == 0 {
+		return RecentSpreads{}, fmt.Errorf("pairs are required")
+	}
+
+	query := url.Values{}
+	query["pair"] = []string{strings.Join(pairs, ",")}
+
+	if since != nil {
+		query["since"] = []string{strconv.FormatUint(*since, 10)}
+	}
+	return public[RecentSpreads](ctx, c, "Spread", query)
+}
+
+// TradeBalance query the Kraken /private/TradeBalance endpoint and return a
+// parsed response, the balance is expressed in ZUSD if no asset is given
+func (c *HTTPClient) TradeBalance(ctx context.Context, asset string) (TradeBalance, error) {
+	if asset == "" {
+		asset = "ZUSD"
+	}
+
+	params := url.Values{}
+	params["asset"] = []string{asset}
+
+	return private[TradeBalance](ctx, c, "TradeBalance", params)
+}
+
+// OpenOrders query the Kraken /private/OpenOrders endpoint and return a parsed
+// response, orders can be filtered to a user reference ID
+func (c *HTTPClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error) {
+	params := url.Values{}
+	params["trades"] = []string{strconv.FormatBool(trades)}
+
+	if userref != nil {
+		params["userref"] = []string{strconv.FormatInt(int64(*userref), 10)}
+	}
+
+	return private[OpenOrders](ctx, c, "OpenOrders", params)
+}
+
+// ClosedOrders query the Kraken /private/ClosedOrders endpoint and return a
+// parsed response, results are paged with the query offset and the parsed
+// count of matching orders
+func (c *HTTPClient) ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error) {
+	params := url.Values{}
+	params["trades"] = []string{strconv.FormatBool(query.Trades)}
+
+	if query.UserRef != nil {
+		params["userref"] = []string{strconv.FormatInt(int64(*query.UserRef), 10)}
+	}
+
+	if query.Start != "" {
+		params["start"] = []string{string(query.Start)}
+	}
+
+	if query.End != "" {
+		params["end"] = []string{string(query.End)}
+	}
+
+	if query.Offset != 0 {
+		params["ofs"] = []string{strconv.FormatUint(query.Offset, 10)}
+	}
+
+	if query.CloseTime != "" {
+		params["closetime"] = []string{string(query.CloseTime)}
+	}
+
+	return private[ClosedOrders](ctx, c, "ClosedOrders", params)
+}
+
+// QueryOrders query the Kraken /private/QueryOrders endpoint and return a
+// parsed response, a maximum of 50 transaction IDs can be queried at once
+func (c *HTTPClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error) {
+	if len(txids) == 0 {
+		return OrdersInfo{}, fmt.Errorf("txids are required")
+	}
+
+	if len(txids) > 50 {
+		return OrdersInfo{}, fmt.Errorf("a maximum of 50 txids can be queried, got %d", len(txids))
+	}
+
+	params := url.Values{}
+	params["trades"] = []string{strconv.FormatBool(trades)}
+	params["txid"] = []string{strings.Join(txids, ",")}
+
+	if userref != nil {
+		params["userref"] = []string{strconv.FormatInt(int64(*userref), 10)}
+	}
+
+	return private[OrdersInfo](ctx, c, "QueryOrders", params)
+}
+
+// TradesHistory query the Kraken /private/TradesHistory endpoint and return a
+// parsed response, results are paged with the query offset and the parsed
+// count of matching trades
+func (c *HTTPClient) TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error) {
+	params := url.Values{}
+	params["trades"] = []string{strconv.FormatBool(query.Trades)}
+
+	if query.Type != "" {
+		params["type"] = []string{string(query.Type)}
+	}
+
+	if query.Start != "" {
+		params["start"] = []string{string(query.Start)}
+	}
+
+	if query.End != "" {
+		params["end"] = []string{string(query.End)}
+	}
+
+	if query.Offset != 0 {
+		params["ofs"] = []string{strconv.FormatUint(query.Offset, 10)}
+	}
+
+	return private[TradesHistory](ctx, c, "TradesHistory", params)
+}
+
+// OpenPositions query the Kraken /private/OpenPositions endpoint and return a
+// parsed response, positions are valued when calculations are requested
+func (c *HTTPClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error) {
+	params := url.Values{}
+	params["docalcs"] = []string{strconv.FormatBool(docalcs)}
+
+	if len(txids) != 0 {
+		params["txid"] = []string{strings.Join(txids, ",")}
+	}
+
+	return private[OpenPositions](ctx, c, "OpenPositions", params)
+}
+
+// Ledgers query the Kraken /private/Ledgers endpoint and return a parsed
+// response, results are paged with the query offset and the parsed count of
+// matching ledger entries
+func (c *HTTPClient) Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error) {
+	params := url.Values{}
+
+	if len(query.Assets) != 0 {
+		params["asset"] = []string{strings.Join(query.Assets, ",")}
+	}
+
+	if query.AssetClass != "" {
+		params["aclass"] = []string{query.AssetClass}
+	}
+
+	if query.Type != "" {
+		params["type"] = []string{string(query.Type)}
+	}
+
+	if query.Start != "" {
+		params["start"] = []string{string(query.Start)}
+	}
+
+	if query.End != "" {
+		params["end"] = []string{string(query.End)}
+	}
+
+	if query.Offset != 0 {
+		params["ofs"] = []string{strconv.FormatUint(query.Offset, 10)}
+	}
+
+	return private[Ledgers](ctx, c, "Ledgers", params)
+}
+
+// QueryLedgers query the Kraken /private/QueryLedgers endpoint and return a
+// parsed response, a maximum of 20 ledger IDs can be queried at once
+func (c *HTTPClient) QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error) {
+	if len(ids) == 0 {
+		return LedgersInfo{}, fmt.Errorf("ids are required")
+	}
+
+	if len(ids) > 20 {
+		return LedgersInfo{}, fmt.Errorf("a maximum of 20 ids can be queried, got %d", len(ids))
+	}
+
+	params := url.Values{}
+	params["id"] = []string{strings.Join(ids, ",")}
+
+	return private[LedgersInfo](ctx, c, "QueryLedgers", params)
+}
+
+// TradeVolume query the Kraken /private/TradeVolume endpoint and return a
+// parsed response, fee info is included for any pairs given
+func (c *HTTPClient) TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error) {
+	params := url.Values{}
+
+	if len(pairs) != 0 {
+		params["pair"] = []string{strings.Join(pairs, ",")}
+		params["fee-info"] = []string{strconv.FormatBool(true)}
+	}
+
+	return private[TradeVolume](ctx, c, "TradeVolume", params)
+}
+
+// AddOrder place an order with the Kraken /private/AddOrder endpoint and return
+// a parsed response, the order is only validated by the API when
+// ValidateOnly is set
+func (c *HTTPClient) AddOrder(ctx context.Context, order OrderRequest) (AddOrderResult, error) {
+	order, err := order.prepare(c.validateOrders)
 	if err != nil {
-		return Time{}, err
+		return AddOrderResult{}, err
 	}
 
-	defer res.Body.Close()
-	payload, err := ioutil.ReadAll(res.Body)
+	params := url.Values{}
+	params["pair"] = []string{order.Pair}
+	params["type"] = []string{order.Action.String()}
+	params["ordertype"] = []string{order.Type.String()}
+	params["volume"] = []string{order.Volume.String()}
+
+	if !order.Price.IsZero() {
+		params["price"] = []string{order.Price.String()}
+	}
+
+	if !order.SecondaryPrice.IsZero() {
+		params["price2"] = []string{order.SecondaryPrice.String()}
+	}
+
+	if order.Leverage != "" {
+		params["leverage"] = []string{order.Leverage}
+	}
+
+	if len(order.Flags) != 0 {
+		params["oflags"] = []string{strings.Join(order.Flags, ",")}
+	}
+
+	if !order.StartTime.IsZero() {
+		params["starttm"] = []string{strconv.FormatInt(order.StartTime.Unix(), 10)}
+	}
+
+	if !order.ExpireTime.IsZero() {
+		params["expiretm"] = []string{strconv.FormatInt(order.ExpireTime.Unix(), 10)}
+	}
+
+	if order.UserReference != nil {
+		params["userref"] = []string{strconv.FormatInt(int64(*order.UserReference), 10)}
+	}
+
+	if order.ValidateOnly {
+		params["validate"] = []string{strconv.FormatBool(true)}
+	}
+
+	return private[AddOrderResult](ctx, c, "AddOrder", params)
+}
+
+// EditOrder amend an open order with the Kraken /private/EditOrder endpoint and
+// return a parsed response, the order is only validated by the API when
+// ValidateOnly is set
+func (c *HTTPClient) EditOrder(ctx context.Context, order EditOrderRequest) (EditOrderResult, error) {
+	if order.TransactionID == "" {
+		return EditOrderResult{}, fmt.Errorf("txid is required")
+	}
+
+	if order.Pair == "" {
+		return EditOrderResult{}, fmt.Errorf("pair is required")
+	}
+
+	params := url.Values{}
+	params["txid"] = []string{order.TransactionID}
+	params["pair"] = []string{order.Pair}
+
+	if !order.Volume.IsZero() {
+		params["volume"] = []string{order.Volume.String()}
+	}
+
+	if !order.Price.IsZero() {
+		params["price"] = []string{order.Price.String()}
+	}
+
+	if !order.SecondaryPrice.IsZero() {
+		params["price2"] = []string{order.SecondaryPrice.String()}
+	}
+
+	if len(order.Flags) != 0 {
+		params["oflags"] = []string{strings.Join(order.Flags, ",")}
+	}
+
+	if order.UserReference != nil {
+		params["userref"] = []string{strconv.FormatInt(int64(*order.UserReference), 10)}
+	}
+
+	if order.ValidateOnly {
+		params["validate"] = []string{strconv.FormatBool(true)}
+	}
+
+	return private[EditOrderResult](ctx, c, "EditOrder", params)
+}
+
+// CancelOrder cancel an order with the Kraken /private/CancelOrder endpoint and
+// return a parsed response, the txid can be either a transaction ID or a user
+// reference ID to cancel all orders with that reference
+func (c *HTTPClient) CancelOrder(ctx context.Context, txid string) (CancelResult, error) {
+	if txid == "" {
+		return CancelResult{}, fmt.Errorf("txid is required")
+	}
+
+	params := url.Values{}
+	params["txid"] = []string{txid}
+
+	return private[CancelResult](ctx, c, "CancelOrder", params)
+}
+
+// CancelOrderByUserReference cancel all orders with a user reference ID with
+// the Kraken /private/CancelOrder endpoint and return a parsed response
+func (c *HTTPClient) CancelOrderByUserReference(ctx context.Context, userref int32) (CancelResult, error) {
+	return c.CancelOrder(ctx, strconv.FormatInt(int64(userref), 10))
+}
+
+// CancelAllOrders cancel all open orders with the Kraken /private/CancelAll
+// endpoint and return a parsed response
+func (c *HTTPClient) CancelAllOrders(ctx context.Context) (CancelResult, error) {
+	return private[CancelResult](ctx, c, "CancelAll", nil)
+}
+
+// CancelAllOrdersAfter set the dead man's switch with the Kraken
+// /private/CancelAllOrdersAfter endpoint and return a parsed response, all
+// orders are canceled once the timeout passes without the switch being
+// refreshed, a zero timeout disables the switch. The timeout is sent in whole
+// seconds, so a timeout under a second, which would disable the switch, is
+// rejected
+func (c *HTTPClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error) {
+	if timeout < 0 {
+		return CancelAllOrdersAfterResult{}, fmt.Errorf("timeout cannot be negative")
+	}
+
+	if timeout > 0 && timeout < time.Second {
+		return CancelAllOrdersAfterResult{}, fmt.Errorf("timeout must be zero or at least a second")
+	}
+
+	params := url.Values{}
+	params["timeout"] = []string{strconv.FormatInt(int64(timeout/time.Second), 10)}
+
+	return private[CancelAllOrdersAfterResult](ctx, c, "CancelAllOrdersAfter", params)
+}
+
+// KeepAlive refresh the dead man's switch with the given timeout every
+// interval until the context is cancelled, refresh failures are sent on the
+// returned channel unless its buffer is full and it is closed once the
+// context is cancelled. The timeout must be at least a second. The switch
+// is left set when the context is cancelled, use CancelAllOrdersAfter with a
+// zero timeout to disable it
+func (c *HTTPClient) KeepAlive(ctx context.Context, interval, timeout time.Duration) (<-chan error, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+
+	if timeout < time.Second {
+		return nil, fmt.Errorf("timeout must be at least a second")
+	}
+
+	if interval >= timeout {
+		return nil, fmt.Errorf("interval must be shorter than the timeout")
+	}
+
+	errs := make(chan error, keepAliveBufferSize)
+
+	go func() {
+		defer close(errs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.refreshCancelAllOrdersAfter(ctx, timeout); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return errs, nil
+}
+
+func (c *HTTPClient) refreshCancelAllOrdersAfter(ctx context.Context, timeout time.Duration) error {
+	res, err := c.CancelAllOrdersAfter(ctx, timeout)
 	if err != nil {
-		return Time{}, err
+		return err
 	}
 
-	msg := Time{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return Time{}, err
+	if len(res.Errors) != 0 {
+		return res.Errors[0]
 	}
 
-	return msg, err
+	return nil
 }
 
-// Status query the Kraken /public/SystemStatus endpoint and return a
+// DepositMethods query the Kraken /private/DepositMethods endpoint and return a
 // parsed response
-func (c *HTTPClient) Status(ctx context.Context) (SystemStatus, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/SystemStatus", c.baseURL), nil)
+func (c *HTTPClient) DepositMethods(ctx context.Context, asset string) (DepositMethods, error) {
+	if asset == "" {
+		return DepositMethods{}, fmt.Errorf("asset is required")
+	}
+
+	params := url.Values{}
+	params["asset"] = []string{asset}
+
+	return private[DepositMethods](ctx, c, "DepositMethods", params)
+}
+
+// DepositAddresses query the Kraken /private/DepositAddresses endpoint and
+// return a parsed response, a new address is generated when requested
+func (c *HTTPClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error) {
+	if asset == "" {
+		return DepositAddresses{}, fmt.Errorf("asset is required")
+	}
+
+	if method == "" {
+		return DepositAddresses{}, fmt.Errorf("method is required")
+	}
+
+	params := url.Values{}
+	params["asset"] = []string{asset}
+	params["method"] = []string{method}
+	params["new"] = []string{strconv.FormatBool(new)}
+
+	return private[DepositAddresses](ctx, c, "DepositAddresses", params)
+}
+
+// DepositStatus query the Kraken /private/DepositStatus endpoint and return a
+// parsed response of recent deposits, optionally filtered by asset and method
+func (c *HTTPClient) DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error) {
+	params := url.Values{}
+
+	if asset != "" {
+		params["asset"] = []string{asset}
+	}
+
+	if method != "" {
+		params["method"] = []string{method}
+	}
+
+	return private[DepositStatus](ctx, c, "DepositStatus", params)
+}
+
+// WithdrawInfo query the Kraken /private/WithdrawInfo endpoint and return a
+// parsed response, the amount is formatted to the precision of the asset
+func (c *HTTPClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error) {
+	if key == "" {
+		return WithdrawInfo{}, fmt.Errorf("key is required")
+	}
+
+	formattedAmount, err := c.formatAmount(ctx, asset, amount)
 	if err != nil {
-		return SystemStatus{}, err
+		return WithdrawInfo{}, err
 	}
 
-	res, err := c.execute(req)
+	params := url.Values{}
+	params["asset"] = []string{asset}
+	params["key"] = []string{key}
+	params["amount"] = []string{formattedAmount}
+
+	return private[WithdrawInfo](ctx, c, "WithdrawInfo", params)
+}
+
+// Withdraw withdraw funds to a withdrawal key with the Kraken /private/Withdraw
+// endpoint and return a parsed response, the amount is formatted to the
+// precision of the asset
+func (c *HTTPClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error) {
+	if key == "" {
+		return WithdrawResult{}, fmt.Errorf("key is required")
+	}
+
+	formattedAmount, err := c.formatAmount(ctx, asset, amount)
 	if err != nil {
-		return SystemStatus{}, err
+		return WithdrawResult{}, err
 	}
 
-	defer res.Body.Close()
-	payload, err := ioutil.ReadAll(res.Body)
+	params := url.Values{}
+	params["asset"] = []string{asset}
+	params["key"] = []string{key}
+	params["amount"] = []string{formattedAmount}
+
+	return private[WithdrawResult](ctx, c, "Withdraw", params)
+}
+
+// WithdrawStatus query the Kraken /private/WithdrawStatus endpoint and return a
+// parsed response of recent withdrawals, optionally filtered by asset and
+// method
+func (c *HTTPClient) WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error) {
+	params := url.Values{}
+
+	if asset != "" {
+		params["asset"] = []string{asset}
+	}
+
+	if method != "" {
+		params["method"] = []string{method}
+	}
+
+	return private[WithdrawStatus](ctx, c, "WithdrawStatus", params)
+}
+
+// WithdrawCancel request the cancellation of a withdrawal with the Kraken
+// /private/WithdrawCancel endpoint and return a parsed response
+func (c *HTTPClient) WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error) {
+	if asset == "" {
+		return WithdrawCancelResult{}, fmt.Errorf("asset is required")
+	}
+
+	if refid == "" {
+		return WithdrawCancelResult{}, fmt.Errorf("refid is required")
+	}
+
+	params := url.Values{}
+	params["asset"] = []string{asset}
+	params["refid"] = []string{refid}
+
+	return private[WithdrawCancelResult](ctx, c, "WithdrawCancel", params)
+}
+
+// WalletTransfer transfer funds between the spot and futures wallets with the
+// Kraken /private/WalletTransfer endpoint and return a parsed response
+func (c *HTTPClient) WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error) {
+	if from >= WalletUnknown || to >= WalletUnknown {
+		return WalletTransferResult{}, fmt.Errorf("unknown wallet")
+	}
+
+	if from == to {
+		return WalletTransferResult{}, fmt.Errorf("from and to wallets must differ")
+	}
+
+	formattedAmount, err := c.formatAmount(ctx, asset, amount)
 	if err != nil {
-		return SystemStatus{}, err
+		return WalletTransferResult{}, err
 	}
 
-	msg := SystemStatus{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return SystemStatus{}, err
+	params := url.Values{}
+	params["asset"] = []string{asset}
+	params["from"] = []string{from.String()}
+	params["to"] = []string{to.String()}
+	params["amount"] = []string{formattedAmount}
+
+	return private[WalletTransferResult](ctx, c, "WalletTransfer", params)
+}
+
+// EarnStrategies query the Kraken /private/Earn/Strategies endpoint and return
+// a parsed page of earn strategies, optionally filtered by asset, the cursor
+// of the next page is returned in the response and is empty for the first
+// page
+func (c *HTTPClient) EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error) {
+	params := url.Values{}
+	if asset != "" {
+		params["asset"] = []string{asset}
 	}
 
-	return msg, err
+	if cursor != "" {
+		params["cursor"] = []string{cursor}
+	}
+
+	return private[EarnStrategies](ctx, c, "Earn/Strategies", params)
 }
 
-// Assets query the Kraken /public/Assets endpoint and return a parsed response
-func (c *HTTPClient) Assets(ctx context.Context) (Assets, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/Assets", c.baseURL), nil)
+// EarnAllocate allocate funds to an earn strategy with the Kraken
+// /private/Earn/Allocate endpoint, allocation is asynchronous and its progress
+// can be checked with EarnAllocationStatus
+func (c *HTTPClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	if strategyID == "" {
+		return EarnAllocationResult{}, fmt.Errorf("strategy ID is required")
+	}
+
+	if !amount.IsPositive() {
+		return EarnAllocationResult{}, fmt.Errorf("amount must be positive")
+	}
+
+	params := url.Values{}
+	params["strategy_id"] = []string{strategyID}
+	params["amount"] = []string{amount.String()}
+
+	return private[EarnAllocationResult](ctx, c, "Earn/Allocate", params)
+}
+
+// EarnDeallocate deallocate funds from an earn strategy with the Kraken
+// /private/Earn/Deallocate endpoint
+func (c *HTTPClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	if strategyID == "" {
+		return EarnAllocationResult{}, fmt.Errorf("strategy ID is required")
+	}
+
+	if !amount.IsPositive() {
+		return EarnAllocationResult{}, fmt.Errorf("amount must be positive")
+	}
+
+	params := url.Values{}
+	params["strategy_id"] = []string{strategyID}
+	params["amount"] = []string{amount.String()}
+
+	return private[EarnAllocationResult](ctx, c, "Earn/Deallocate", params)
+}
+
+// EarnAllocationStatus query the Kraken /private/Earn/AllocateStatus endpoint
+// and return whether an allocation to the strategy is still pending
+func (c *HTTPClient) EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error) {
+	if strategyID == "" {
+		return EarnAllocationStatus{}, fmt.Errorf("strategy ID is required")
+	}
+
+	params := url.Values{}
+	params["strategy_id"] = []string{strategyID}
+
+	return private[EarnAllocationStatus](ctx, c, "Earn/AllocateStatus", params)
+}
+
+// EarnAllocations query the Kraken /private/Earn/Allocations endpoint and
+// return a parsed response of all earn allocations, zero allocations are
+// omitted
+func (c *HTTPClient) EarnAllocations(ctx context.Context) (EarnAllocations, error) {
+	params := url.Values{}
+	params["hide_zero_allocations"] = []string{"true"}
+
+	return private[EarnAllocations](ctx, c, "Earn/Allocations", params)
+}
+
+// AddExport request a report export with the Kraken /private/AddExport
+// endpoint and return the ID of the queued export
+func (c *HTTPClient) AddExport(ctx context.Context, export ExportRequest) (AddExportResult, error) {
+	if export.Report != ExportReportTrades && export.Report != ExportReportLedgers {
+		return AddExportResult{}, fmt.Errorf("unknown report type")
+	}
+
+	if export.Description == "" {
+		return AddExportResult{}, fmt.Errorf("description is required")
+	}
+
+	params := url.Values{}
+	params["report"] = []string{string(export.Report)}
+	params["description"] = []string{export.Description}
+
+	if export.Format != "" {
+		params["format"] = []string{string(export.Format)}
+	}
+
+	if len(export.Fields) > 0 {
+		params["fields"] = []string{strings.Join(export.Fields, ",")}
+	}
+
+	if !export.Start.IsZero() {
+		params["starttm"] = []string{strconv.FormatInt(export.Start.Unix(), 10)}
+	}
+
+	if !export.End.IsZero() {
+		params["endtm"] = []string{strconv.FormatInt(export.End.Unix(), 10)}
+	}
+
+	return private[AddExportResult](ctx, c, "AddExport", params)
+}
+
+// ExportStatus query the Kraken /private/ExportStatus endpoint and return a
+// parsed response of the exports of a report type
+func (c *HTTPClient) ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error) {
+	if report != ExportReportTrades && report != ExportReportLedgers {
+		return ExportStatus{}, fmt.Errorf("unknown report type")
+	}
+
+	params := url.Values{}
+	params["report"] = []string{string(report)}
+
+	return private[ExportStatus](ctx, c, "ExportStatus", params)
+}
+
+// RetrieveExport download a processed export with the Kraken
+// /private/RetrieveExport endpoint, the returned reader streams the zip
+// archive and must be closed by the caller. Kraken responds with JSON rather
+// than an archive on failure, in which case the first API error is returned
+func (c *HTTPClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+
+	params := url.Values{}
+	params["id"] = []string{id}
+
+	req, err := c.newPrivateRequest(ctx, "RetrieveExport", params)
 	if err != nil {
-		return Assets{}, err
+		return nil, err
 	}
 
 	res, err := c.execute(req)
 	if err != nil {
-		return Assets{}, err
+		return nil, err
+	}
+
+	if err := checkStatus(res); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+		return res.Body, nil
 	}
 
 	defer res.Body.Close()
 	payload, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return Assets{}, err
+		return nil, err
+	}
+
+	return nil, c.parser.parseErrorPayload(payload)
+}
+
+// RemoveExport cancel or delete an export with the Kraken
+// /private/RemoveExport endpoint and return a parsed response
+func (c *HTTPClient) RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error) {
+	if id == "" {
+		return RemoveExportResult{}, fmt.Errorf("id is required")
 	}
 
-	msg := Assets{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return Assets{}, err
+	if removal != ExportRemovalCancel && removal != ExportRemovalDelete {
+		return RemoveExportResult{}, fmt.Errorf("unknown removal type")
 	}
 
-	return msg, err
+	params := url.Values{}
+	params["id"] = []string{id}
+	params["type"] = []string{string(removal)}
+
+	return private[RemoveExportResult](ctx, c, "RemoveExport", params)
 }
 
-// AssetPairs query the Kraken /public/AssetPairs endpoint and return a parsed
-// response
-func (c *HTTPClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/AssetPairs", c.baseURL), nil)
-	if err != nil {
-		return AssetPairs{}, err
+// CreateSubaccount create a trading sub-account of an institutional account
+// with the Kraken /private/CreateSubaccount endpoint
+func (c *HTTPClient) CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error) {
+	if username == "" {
+		return CreateSubaccountResult{}, fmt.Errorf("username is required")
 	}
 
-	query := req.URL.Query()
-	query["info"] = []string{string(info)}
-	if len(pairs) != 0 {
-		query["pairs"] = []string{strings.Join(pairs, ",")}
+	if email == "" {
+		return CreateSubaccountResult{}, fmt.Errorf("email is required")
 	}
-	req.URL.RawQuery = query.Encode()
 
-	res, err := c.execute(req)
+	params := url.Values{}
+	params["username"] = []string{username}
+	params["email"] = []string{email}
+
+	return private[CreateSubaccountResult](ctx, c, "CreateSubaccount", params)
+}
+
+// AccountTransfer transfer funds between a master account and its
+// sub-accounts with the Kraken /private/AccountTransfer endpoint, the amount is
+// formatted to the precision of the asset
+func (c *HTTPClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error) {
+	if fromUser == "" || toUser == "" {
+		return AccountTransferResult{}, fmt.Errorf("from and to accounts are required")
+	}
+
+	formattedAmount, err := c.formatAmount(ctx, asset, amount)
 	if err != nil {
-		return AssetPairs{}, err
+		return AccountTransferResult{}, err
 	}
 
-	defer res.Body.Close()
-	payload, err := ioutil.ReadAll(res.Body)
+	params := url.Values{}
+	params["asset"] = []string{asset}
+	params["amount"] = []string{formattedAmount}
+	params["from"] = []string{fromUser}
+	params["to"] = []string{toUser}
+
+	return private[AccountTransferResult](ctx, c, "AccountTransfer", params)
+}
+
+// GetWebSocketsToken query the Kraken /private/GetWebSocketsToken endpoint
+// and return a parsed token for authenticating with the websocket API
+func (c *HTTPClient) GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error) {
+	return private[WebSocketsToken](ctx, c, "GetWebSocketsToken", url.Values{})
+}
+
+// formatAmount format an amount to the precision of an asset, an amount more
+// precise than the asset allows is rejected rather than rounded
+func (c *HTTPClient) formatAmount(ctx context.Context, asset string, amount decimal.Decimal) (string, error) {
+	if asset == "" {
+		return "", fmt.Errorf("asset is required")
+	}
+
+	if !amount.IsPositive() {
+		return "", fmt.Errorf("amount must be positive")
+	}
+
+	precision, err := c.assetPrecision(ctx, asset)
 	if err != nil {
-		return AssetPairs{}, err
+		return "", err
 	}
 
-	msg := AssetPairs{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return AssetPairs{}, err
+	if !amount.Truncate(precision).Equal(amount) {
+		return "", fmt.Errorf("amount %s exceeds the %d decimal precision of %s", amount, precision, asset)
 	}
 
-	return msg, err
+	return amount.StringFixed(precision), nil
 }
 
-// OHLC query the Kraken /public/OHLC endpoint and return a parsed
-// response
-func (c *HTTPClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
-	if len(pairs) == 0 {
-		return OHLCs{}, fmt.Errorf("pairs are required")
+// assetPrecision return the precision of an asset by either its name or
+// alternative name, the precisions are queried from the /public/Assets
+// endpoint on first use. The lock is not held while querying, so a slow
+// request does not block callers which could otherwise time out on their own
+func (c *HTTPClient) assetPrecision(ctx context.Context, asset string) (int32, error) {
+	c.assetPrecisionsMu.Lock()
+	precisions := c.assetPrecisions
+	c.assetPrecisionsMu.Unlock()
+
+	if precisions == nil {
+		assets, err := c.Assets(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(assets.Errors) != 0 {
+			return 0, assets.Errors[0]
+		}
+
+		precisions = make(map[string]int32, len(assets.Assets)*2)
+		for name, a := range assets.Assets {
+			precisions[name] = int32(a.Precision)
+			precisions[a.AltName] = int32(a.Precision)
+		}
+
+		c.assetPrecisionsMu.Lock()
+		c.assetPrecisions = precisions
+		c.assetPrecisionsMu.Unlock()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/OHLC", c.baseURL), nil)
-	if err != nil {
-		return OHLCs{}, err
+	precision, ok := precisions[asset]
+	if !ok {
+		return 0, fmt.Errorf("unknown asset %s", asset)
 	}
 
-	query := req.URL.Query()
-	query["pairs"] = []string{strings.Join(pairs, ",")}
-	query["interval"] = []string{strconv.Itoa(int(interval))}
+	return precision, nil
+}
 
-	if since != nil {
-		query["since"] = []string{strconv.FormatUint(*since, 10)}
+// public execute a GET request to a Kraken /public endpoint and parse the
+// response into a T
+func public[T Parseable[T]](ctx context.Context, c *HTTPClient, path string, query url.Values) (T, error) {
+	var msg T
+	err := publicRetry(ctx, c, path, query, &msg)
+	return msg, c.failOnAPIErrors(msg, err)
+}
+
+// publicRetry execute a GET request to a Kraken /public endpoint, retrying
+// failures which may succeed on retry when retries are enabled
+func publicRetry[T Parseable[T]](ctx context.Context, c *HTTPClient, path string, query url.Values, msg *T) error {
+	delay := c.retry.baseDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := publicAttempt(ctx, c, path, query, msg)
+		if !retry || attempt >= c.retry.maxAttempts {
+			return err
+		}
+
+		// full jitter, wait a random duration up to the current delay
+		wait := time.Duration(rand.Int63n(int64(delay) + 1))
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > c.retry.maxDelay {
+			delay = c.retry.maxDelay
+		}
+	}
+}
+
+// publicAttempt execute a single GET request to a Kraken /public endpoint,
+// whether the request failed in a way which may succeed on retry is returned
+// along with any error
+func publicAttempt[T Parseable[T]](ctx context.Context, c *HTTPClient, path string, query url.Values, msg *T) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/%s", c.baseURL, path), nil)
+	if err != nil {
+		return false, err
 	}
 	req.URL.RawQuery = query.Encode()
 
 	res, err := c.execute(req)
 	if err != nil {
-		return OHLCs{}, err
+		return errors.Is(err, ErrNetwork), err
 	}
 
-	defer res.Body.Close()
-	payload, err := ioutil.ReadAll(res.Body)
+	payload, err := readPayload(res)
+	if err != nil {
+		return errors.Is(err, ErrNetwork) || errors.Is(err, ErrThrottled) || errors.Is(err, ErrServerError), err
+	}
+
+	parsed, err := ParseAs[T](&c.parser, payload)
+	c.captureRaw(&parsed, payload)
+	*msg = parsed
 	if err != nil {
-		return OHLCs{}, err
+		return false, parseContext(path, query, payload, err)
 	}
 
-	msg := OHLCs{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return OHLCs{}, err
+	for _, err := range resultErrors(parsed) {
+		if errors.Is(err, ErrService) {
+			return true, nil
+		}
 	}
 
-	return msg, err
+	return false, nil
 }
 
-// OrderBook query the Kraken /public/OrderBook endpoint and return a parsed
-// response
-func (c *HTTPClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
-	if len(pairs) == 0 {
-		return OrderBook{}, fmt.Errorf("pairs are required")
+// private execute a signed POST request to a Kraken /private endpoint and parse
+// the response into a T
+func private[T Parseable[T]](ctx context.Context, c *HTTPClient, path string, params url.Values) (T, error) {
+	var msg T
+	req, err := c.newPrivateRequest(ctx, path, params)
+	if err != nil {
+		return msg, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/OrderBook", c.baseURL), nil)
+	res, err := c.execute(req)
 	if err != nil {
-		return OrderBook{}, err
+		return msg, err
 	}
 
-	query := req.URL.Query()
-	query["pairs"] = []string{strings.Join(pairs, ",")}
-	query["count"] = []string{strconv.FormatUint(uint64(count), 10)}
-	req.URL.RawQuery = query.Encode()
-
-	res, err := c.execute(req)
+	payload, err := readPayload(res)
 	if err != nil {
-		return OrderBook{}, err
+		return msg, err
 	}
 
-	defer res.Body.Close()
-	payload, err := ioutil.ReadAll(res.Body)
+	msg, err = ParseAs[T](&c.parser, payload)
+	c.captureRaw(&msg, payload)
 	if err != nil {
-		return OrderBook{}, err
+		return msg, parseContext(path, params, payload, err)
+	}
+
+	return msg, c.failOnAPIErrors(msg, nil)
+}
+
+// captureRaw set the Raw field of a result to the payload it was parsed from
+// when raw capture is enabled, payloads over the limit are truncated and copied
+// so the rest of the payload is not retained
+func (c *HTTPClient) captureRaw(result interface{}, payload []byte) {
+	if c.maxRawCapture == 0 {
+		return
 	}
 
-	msg := OrderBook{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return OrderBook{}, err
+	field := reflect.Indirect(reflect.ValueOf(result)).FieldByName("Raw")
+	if !field.IsValid() {
+		return
 	}
 
-	return msg, err
+	if len(payload) > c.maxRawCapture {
+		payload = bytes.Clone(payload[:c.maxRawCapture])
+	}
+
+	field.SetBytes(payload)
 }
 
-// RecentTrades query the Kraken /public/Trades endpoint and return a parsed
-// response
-func (c *HTTPClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
-	if len(pairs) == 0 {
-		return RecentTrades{}, fmt.Errorf("pairs are required")
+// parseContext wrap a parse failure with the endpoint, the requested pairs and
+// the start of the payload which could not be parsed
+func parseContext(endpoint string, values url.Values, payload []byte, err error) error {
+	if pair := values.Get("pair"); pair != "" {
+		endpoint = fmt.Sprintf("%s pair=%s", endpoint, pair)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/Trades", c.baseURL), nil)
-	if err != nil {
-		return RecentTrades{}, err
+	return fmt.Errorf("%s: %w: payload: %s", endpoint, err, snippet(payload, maxParsePayload))
+}
+
+// failOnAPIErrors return the API errors of a parsed result joined into a
+// single error when failing on API errors has been specified, any other error
+// is returned as is
+func (c *HTTPClient) failOnAPIErrors(v interface{}, err error) error {
+	if err != nil || !c.failOnAPIError {
+		return err
 	}
 
-	query := req.URL.Query()
-	query["pairs"] = []string{strings.Join(pairs, ",")}
+	return errors.Join(resultErrors(v)...)
+}
 
-	if since != nil {
-		query["since"] = []string{strconv.FormatUint(*since, 10)}
+// checkStatus return a typed error for a response without a 2xx status, 429 is
+// returned as ErrThrottled and 5xx as ErrServerError. The error includes the
+// status and the start of the body, which is closed
+func checkStatus(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
 	}
 
-	res, err := c.execute(req)
-	if err != nil {
-		return RecentTrades{}, err
+	defer res.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBody+1))
+
+	var err error
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		err = ErrThrottled
+	case res.StatusCode >= http.StatusInternalServerError:
+		err = ErrServerError
+	default:
+		err = ErrAPIUnknown
+	}
+
+	return fmt.Errorf("%w: %s: %s", err, res.Status, snippet(body, maxErrorBody))
+}
+
+// readPayload read and close the body of a response, the payload is only
+// returned for a 2xx response with a JSON body
+func readPayload(res *http.Response) ([]byte, error) {
+	if err := checkStatus(res); err != nil {
+		return nil, err
 	}
 
 	defer res.Body.Close()
 	payload, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return RecentTrades{}, err
+		return nil, fmt.Errorf("%w: %s", ErrNetwork, err)
 	}
 
-	msg := RecentTrades{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return RecentTrades{}, err
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return nil, fmt.Errorf("%w: empty response body", ErrParse)
 	}
 
-	return msg, err
+	if !isJSON(res.Header.Get("Content-Type"), payload) {
+		return nil, fmt.Errorf("%w: unexpected content type %q: %s", ErrParse, res.Header.Get("Content-Type"), snippet(payload, maxErrorBody))
+	}
+
+	return payload, nil
 }
 
-// RecentSpreads query the Kraken /public/Spread endpoint and return a parsed
-// response
-func (c *HTTPClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
-	if len(pairs) == 0 {
-		return RecentSpreads{}, fmt.Errorf("pairs are required")
+// isJSON report whether a response body is JSON, a response without an
+// explicit JSON content type is accepted when the content type was sniffed as
+// plain text and the body looks like a JSON object
+func isJSON(contentType string, payload []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		return true
+	case "", "text/plain":
+		return bytes.HasPrefix(bytes.TrimSpace(payload), []byte("{"))
+	default:
+		return false
+	}
+}
+
+// snippet return up to max bytes from the start of a response body for use in
+// an error message
+func snippet(body []byte, max int) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > max {
+		s = s[:max] + "..."
+	}
+
+	return s
+}
+
+// newPrivateRequest create a signed POST request to a Kraken /private endpoint
+// with the given params form encoded in the body
+func (c *HTTPClient) newPrivateRequest(ctx context.Context, endpoint string, params url.Values) (*http.Request, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+
+	if c.otp != nil {
+		otp, err := c.otp()
+		if err != nil {
+			return nil, fmt.Errorf("otp: %w", err)
+		}
+
+		params["otp"] = []string{otp}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/Spread", c.baseURL), nil)
+	n, err := c.nextNonce()
 	if err != nil {
-		return RecentSpreads{}, err
+		return nil, err
 	}
-	query := req.URL.Query()
-	query["pairs"] = []string{strings.Join(pairs, ",")}
 
-	if since != nil {
-		query["since"] = []string{strconv.FormatUint(*since, 10)}
+	nonce := strconv.FormatUint(n, 10)
+	params["nonce"] = []string{nonce}
+	postData := params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/private/%s", c.baseURL, endpoint), strings.NewReader(postData))
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := c.execute(req)
+	signature, err := c.signature(req.URL.Path, nonce, postData)
 	if err != nil {
-		return RecentSpreads{}, err
+		return nil, err
 	}
 
-	defer res.Body.Close()
-	payload, err := ioutil.ReadAll(res.Body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("API-Key", c.apiKey)
+	req.Header.Set("API-Sign", signature)
+
+	return req, nil
+}
+
+// nextNonce return the nonce of the next private request, when a nonce store is
+// set the nonce is kept above the last saved nonce and saved before use
+func (c *HTTPClient) nextNonce() (uint64, error) {
+	if c.nonceStore == nil {
+		return c.nonces.Next(), nil
+	}
+
+	c.nonceStoreMu.Lock()
+	defer c.nonceStoreMu.Unlock()
+
+	last, err := c.nonceStore.Load()
 	if err != nil {
-		return RecentSpreads{}, err
+		return 0, fmt.Errorf("load nonce: %w", err)
+	}
+
+	nonce := c.nonces.Next()
+	if nonce <= last {
+		nonce = last + 1
 	}
 
-	msg := RecentSpreads{}
-	if err := c.parser.Parse(payload, &msg); err != nil {
-		return RecentSpreads{}, err
+	if err := c.nonceStore.Save(nonce); err != nil {
+		return 0, fmt.Errorf("save nonce: %w", err)
 	}
 
-	return msg, err
+	return nonce, nil
 }
 
-func (c *HTTPClient) signature(path string, query url.Values) (string, error) {
+// signature compute the API-Sign header of a private request, a HMAC-SHA512 of
+// the URI path and SHA256(nonce + postData) keyed with the decoded secret
+func (c *HTTPClient) signature(path, nonce, postData string) (string, error) {
 	decodedSecret, err := base64.StdEncoding.DecodeString(c.secret)
 	if err != nil {
 		return "", err
 	}
 
 	sha := sha256.New()
-	if _, err := sha.Write([]byte(query.Get("nonce") + query.Encode())); err != nil {
+	if _, err := sha.Write([]byte(nonce + postData)); err != nil {
 		return "", err
 	}
 	shaSum := sha.Sum(nil)
@@ -325,9 +1321,11 @@ func (c *HTTPClient) signature(path string, query url.Values) (string, error) {
 	return base64.StdEncoding.EncodeToString(macSum), nil
 }
 
+// execute send a request, when dry run has been specified the request is not
+// sent and a DryRunError describing it is returned instead
 func (c *HTTPClient) execute(req *http.Request) (*http.Response, error) {
 	if c.dryRun {
-		return nil, ErrDryRun
+		return nil, newDryRunError(req)
 	}
 
 	res, err := c.httpClient.Do(req)
@@ -337,3 +1335,45 @@ func (c *HTTPClient) execute(req *http.Request) (*http.Response, error) {
 
 	return res, nil
 }
+
+// newDryRunError create a DryRunError summarising a request, the API-Key and
+// API-Sign headers and the otp param are removed
+func newDryRunError(req *http.Request) error {
+	var body string
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		params, err := url.ParseQuery(string(data))
+		if err != nil {
+			return err
+		}
+		params.Del("otp")
+		body = params.Encode()
+	}
+
+	header := req.Header.Clone()
+	header.Del("API-Key")
+	header.Del("API-Sign")
+
+	u := *req.URL
+	u.RawQuery = ""
+
+	return &DryRunError{
+		Request: RequestSummary{
+			Method: req.Method,
+			URL:    u.String(),
+			Query:  req.URL.RawQuery,
+			Body:   body,
+			Header: header,
+		},
+	}
+}
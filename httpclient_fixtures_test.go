@@ -0,0 +1,112 @@
+package kraken_test
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+// record rewrites the fixture files with responses from the live API
+var record = flag.Bool("record", false, "record fixtures from the live Kraken API")
+
+func TestHTTPClientPublicFixtures(t *testing.T) {
+	var transport http.RoundTripper
+	if *record {
+		transport = krakentest.NewRecorder("testdata/fixtures/public.json", nil)
+	} else {
+		replayer, err := krakentest.NewReplayer("testdata/fixtures/public.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		transport = replayer
+	}
+
+	opts := []kraken.HTTPClientOption{
+		kraken.HTTPClientWithHTTPClient(&http.Client{Transport: transport}),
+		kraken.HTTPClientWithStrictParsing(),
+		kraken.HTTPClientWithRawCapture(),
+	}
+	if !*record {
+		opts = append(opts, kraken.HTTPClientWithBaseURL("http://kraken.invalid/0"))
+	}
+
+	c, err := kraken.NewHTTPClient(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	tcs := []struct {
+		name string
+		call func() (interface{}, error)
+	}{
+		{
+			name: "Time",
+			call: func() (interface{}, error) { return c.Time(ctx) },
+		},
+		{
+			name: "Status",
+			call: func() (interface{}, error) { return c.Status(ctx) },
+		},
+		{
+			name: "Assets",
+			call: func() (interface{}, error) { return c.Assets(ctx) },
+		},
+		{
+			name: "AssetPairs",
+			call: func() (interface{}, error) { return c.AssetPairs(ctx, "", "XBTUSD") },
+		},
+		{
+			name: "Tickers",
+			call: func() (interface{}, error) { return c.Tickers(ctx, "XBTUSD") },
+		},
+		{
+			name: "OHLC",
+			call: func() (interface{}, error) { return c.OHLC(ctx, kraken.OHLCIntervalMinute, nil, "XBTUSD") },
+		},
+		{
+			name: "OrderBook",
+			call: func() (interface{}, error) { return c.OrderBook(ctx, 2, "XBTUSD") },
+		},
+		{
+			name: "RecentTrades",
+			call: func() (interface{}, error) { return c.RecentTrades(ctx, nil, "XBTUSD") },
+		},
+		{
+			name: "RecentSpreads",
+			call: func() (interface{}, error) { return c.RecentSpreads(ctx, nil, "XBTUSD") },
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v := reflect.ValueOf(res)
+			if errs := v.FieldByName("Errors"); errs.Len() != 0 {
+				t.Errorf("unexpected result errors: %v", errs.Interface())
+			}
+
+			// every field other than Errors and Warnings should be populated,
+			// enums are byte values whose first value is also their zero value
+			for i := 0; i < v.NumField(); i++ {
+				field := v.Type().Field(i)
+				if field.Name == "Errors" || field.Name == "Warnings" || field.Type.Kind() == reflect.Uint8 {
+					continue
+				}
+
+				if v.Field(i).IsZero() {
+					t.Errorf("%s not populated", field.Name)
+				}
+			}
+		})
+	}
+}
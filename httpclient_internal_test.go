@@ -0,0 +1,39 @@
+package kraken
+
+import "testing"
+
+func TestSignature(t *testing.T) {
+	tcs := []struct {
+		name     string
+		secret   string
+		path     string
+		nonce    string
+		postData string
+		expected string
+	}{
+		{
+			// example from https://docs.kraken.com/rest/#section/Authentication/Headers-and-Signature
+			name:     "KrakenDocumentation",
+			secret:   "kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg==",
+			path:     "/0/private/AddOrder",
+			nonce:    "1616492376594",
+			postData: "nonce=1616492376594&ordertype=limit&pair=XBTUSD&price=37500&type=buy&volume=1.25",
+			expected: "4/dpxb3iT4tp/ZCVEwSnEsLxx0bqyhLpdfOpc6fn7OR8+UClSV5n9E6aSS8MPtnRfp32bAb0nmbRn6H8ndwLUQ==",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c := HTTPClient{secret: tc.secret}
+
+			signature, err := c.signature(tc.path, tc.nonce, tc.postData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if signature != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, signature)
+			}
+		})
+	}
+}
@@ -0,0 +1,1653 @@
+package kraken_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+func TestAddOrder(t *testing.T) {
+	tcs := []struct {
+		name    string
+		input   kraken.OrderRequest
+		isError bool
+	}{
+		{
+			name: "ValidMarketOrder",
+			input: kraken.OrderRequest{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionBuy,
+				Type:   kraken.OrderTypeMarket,
+				Volume: decimal.New(125, -2),
+			},
+		},
+		{
+			name: "ValidStopLossLimitOrder",
+			input: kraken.OrderRequest{
+				Pair:           "XBTUSD",
+				Action:         kraken.OrderActionSell,
+				Type:           kraken.OrderTypeStopLossLimit,
+				Volume:         decimal.New(125, -2),
+				Price:          decimal.New(380000, -1),
+				SecondaryPrice: decimal.New(360000, -1),
+			},
+		},
+		{
+			name: "MissingPair",
+			input: kraken.OrderRequest{
+				Action: kraken.OrderActionBuy,
+				Type:   kraken.OrderTypeMarket,
+				Volume: decimal.New(125, -2),
+			},
+			isError: true,
+		},
+		{
+			name: "UnknownAction",
+			input: kraken.OrderRequest{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionUnknown,
+				Type:   kraken.OrderTypeMarket,
+				Volume: decimal.New(125, -2),
+			},
+			isError: true,
+		},
+		{
+			name: "ZeroVolume",
+			input: kraken.OrderRequest{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionBuy,
+				Type:   kraken.OrderTypeMarket,
+			},
+			isError: true,
+		},
+		{
+			name: "LimitOrderWithoutPrice",
+			input: kraken.OrderRequest{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionBuy,
+				Type:   kraken.OrderTypeLimit,
+				Volume: decimal.New(125, -2),
+			},
+			isError: true,
+		},
+		{
+			name: "StopLossLimitOrderWithoutSecondaryPrice",
+			input: kraken.OrderRequest{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionSell,
+				Type:   kraken.OrderTypeStopLossLimit,
+				Volume: decimal.New(125, -2),
+				Price:  decimal.New(380000, -1),
+			},
+			isError: true,
+		},
+	}
+
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := c.AddOrder(context.Background(), tc.input)
+
+			if tc.isError {
+				if err == nil || errors.Is(err, kraken.ErrDryRun) {
+					t.Fatalf("expected a validation error, got %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, kraken.ErrDryRun) {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAddOrderRounding(t *testing.T) {
+	pair := &kraken.AssetPair{PairPrecision: 1, LotPrecision: 4}
+
+	tcs := []struct {
+		name     string
+		input    kraken.OrderRequest
+		expected url.Values
+		isError  bool
+	}{
+		{
+			name: "Rounded",
+			input: kraken.OrderRequest{
+				Pair:           "XBTUSD",
+				Action:         kraken.OrderActionSell,
+				Type:           kraken.OrderTypeStopLossLimit,
+				Volume:         decimal.RequireFromString("1.23456"),
+				Price:          decimal.RequireFromString("38000.06"),
+				SecondaryPrice: decimal.RequireFromString("36000.04"),
+				AssetPair:      pair,
+			},
+			expected: url.Values{"volume": {"1.2345"}, "price": {"38000.1"}, "price2": {"36000"}},
+		},
+		{
+			name: "ExactValues",
+			input: kraken.OrderRequest{
+				Pair:        "XBTUSD",
+				Action:      kraken.OrderActionBuy,
+				Type:        kraken.OrderTypeLimit,
+				Volume:      decimal.RequireFromString("1.23456"),
+				Price:       decimal.RequireFromString("38000.06"),
+				AssetPair:   pair,
+				ExactValues: true,
+			},
+			expected: url.Values{"volume": {"1.23456"}, "price": {"38000.06"}},
+		},
+		{
+			name: "VolumeRoundedToZero",
+			input: kraken.OrderRequest{
+				Pair:      "XBTUSD",
+				Action:    kraken.OrderActionBuy,
+				Type:      kraken.OrderTypeMarket,
+				Volume:    decimal.RequireFromString("0.00001"),
+				AssetPair: pair,
+			},
+			isError: true,
+		},
+	}
+
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := c.AddOrder(context.Background(), tc.input)
+
+			dryRun := &kraken.DryRunError{}
+			if !errors.As(err, &dryRun) {
+				if !tc.isError {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			if tc.isError {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+
+			body, err := url.ParseQuery(dryRun.Request.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for key, expected := range tc.expected {
+				if diff := deep.Equal(expected, body[key]); diff != nil {
+					t.Errorf("%s: %v", key, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestAddOrderValidation(t *testing.T) {
+	pair := validationPair(kraken.AssetPairStatusOnline)
+	order := limitOrder(kraken.OrderActionBuy, "0.00001", "38000")
+	order.AssetPair = &pair
+	order.ExactValues = true
+
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun(), kraken.HTTPClientWithOrderValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.AddOrder(context.Background(), order); !errors.Is(err, kraken.ErrOrderValidation) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrOrderValidation, err)
+	}
+
+	// orders without an AssetPair are not validated
+	order.AssetPair = nil
+	if _, err := c.AddOrder(context.Background(), order); !errors.Is(err, kraken.ErrDryRun) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrDryRun, err)
+	}
+
+	c, err = kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order.AssetPair = &pair
+	if _, err := c.AddOrder(context.Background(), order); !errors.Is(err, kraken.ErrDryRun) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrDryRun, err)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.KeepAlive(context.Background(), time.Minute, time.Second); err == nil {
+		t.Fatal("expected an error for an interval longer than the timeout")
+	}
+
+	// a timeout under a second is sent as zero, disabling the switch
+	if _, err := c.KeepAlive(context.Background(), 100*time.Millisecond, 900*time.Millisecond); err == nil {
+		t.Fatal("expected an error for a timeout under a second")
+	}
+
+	if _, err := c.CancelAllOrdersAfter(context.Background(), 500*time.Millisecond); err == nil || errors.Is(err, kraken.ErrDryRun) {
+		t.Fatalf("expected an error for a timeout under a second, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs, err := c.KeepAlive(ctx, time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, kraken.ErrDryRun) {
+			t.Fatal(err)
+		}
+	}
+
+	cancel()
+	for range errs {
+	}
+}
+
+func TestKeepAliveUnreadErrors(t *testing.T) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"error":["EService:Unavailable"]}`))
+	}))
+	defer s.Close()
+
+	c, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithBaseURL(s.URL+"/0"),
+		kraken.HTTPClientWithAPIKey("key"),
+		kraken.HTTPClientWithSecret("c2VjcmV0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// refreshes continue while failures go unread
+	errs, err := c.KeepAlive(ctx, time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 40 {
+		if time.Now().After(deadline) {
+			t.Fatalf("EXPECTED: at least 40 refreshes\nACTUAL: %d", atomic.LoadInt32(&requests))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	for range errs {
+	}
+}
+
+func TestWithdrawAmountPrecision(t *testing.T) {
+	tcs := []struct {
+		name     string
+		asset    string
+		amount   decimal.Decimal
+		expected string
+		isError  bool
+	}{
+		{
+			name:     "PaddedToPrecision",
+			asset:    "XXBT",
+			amount:   decimal.New(5, -1),
+			expected: "0.5000000000",
+		},
+		{
+			name:     "AlternativeName",
+			asset:    "USD",
+			amount:   decimal.New(1005, -1),
+			expected: "100.5000",
+		},
+		{
+			name:    "ExceedsPrecision",
+			asset:   "ZUSD",
+			amount:  decimal.New(100001, -5),
+			isError: true,
+		},
+		{
+			name:    "UnknownAsset",
+			asset:   "XDOGE",
+			amount:  decimal.New(1, 0),
+			isError: true,
+		},
+	}
+
+	var amount string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/public/Assets":
+			w.Write([]byte(`{"error":[],"result":{"XXBT":{"aclass":"currency","altname":"XBT","decimals":10,"display_decimals":5},"ZUSD":{"aclass":"currency","altname":"USD","decimals":4,"display_decimals":2}}}`))
+		case "/private/Withdraw":
+			body, _ := ioutil.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			amount = values.Get("amount")
+			w.Write([]byte(`{"error":[],"result":{"refid":"AGBSO6T-UFMTTQ-I7KGS6"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			amount = ""
+			_, err := c.Withdraw(context.Background(), tc.asset, "key", tc.amount)
+
+			if tc.isError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if amount != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, amount)
+			}
+		})
+	}
+}
+
+func TestWithdrawAmountPrecisionConcurrent(t *testing.T) {
+	fetching, release := make(chan struct{}, 2), make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/public/Assets":
+			fetching <- struct{}{}
+			<-release
+			w.Write([]byte(`{"error":[],"result":{"XXBT":{"aclass":"currency","altname":"XBT","decimals":10,"display_decimals":5}}}`))
+		default:
+			w.Write([]byte(`{"error":[],"result":{"refid":"AGBSO6T-UFMTTQ-I7KGS6"}}`))
+		}
+	}))
+	defer s.Close()
+	defer close(release)
+
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a withdrawal waiting on the assets does not hold up another withdrawal
+	// from timing out
+	go c.Withdraw(context.Background(), "XXBT", "key", decimal.New(1, 0))
+	<-fetching
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Withdraw(ctx, "XXBT", "key", decimal.New(1, 0))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("EXPECTED: %v\nACTUAL: <nil>", context.DeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("EXPECTED: withdrawal timed out\nACTUAL: blocked")
+	}
+}
+
+func TestWalletTransferWallets(t *testing.T) {
+	tcs := []struct {
+		name string
+		from kraken.Wallet
+		to   kraken.Wallet
+	}{
+		{
+			name: "UnknownFrom",
+			from: kraken.WalletUnknown,
+			to:   kraken.WalletFutures,
+		},
+		{
+			name: "UnknownTo",
+			from: kraken.WalletSpot,
+			to:   kraken.Wallet(42),
+		},
+		{
+			name: "SameWallet",
+			from: kraken.WalletSpot,
+			to:   kraken.WalletSpot,
+		},
+	}
+
+	c, err := kraken.NewHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := c.WalletTransfer(context.Background(), "XXBT", tc.from, tc.to, decimal.New(1, 0)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestRetrieveExport(t *testing.T) {
+	tcs := []struct {
+		name        string
+		id          string
+		contentType string
+		body        string
+		expected    []byte
+		err         error
+	}{
+		{
+			name:        "Archive",
+			id:          "VSKC",
+			contentType: "application/octet-stream",
+			body:        "PK\x03\x04archive",
+			expected:    []byte("PK\x03\x04archive"),
+		},
+		{
+			name:        "APIError",
+			id:          "TCJA",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"error":["EQuery:Unknown export"]}`,
+			err:         kraken.ErrQuery,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/private/RetrieveExport" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				body, _ := ioutil.ReadAll(r.Body)
+				values, _ := url.ParseQuery(string(body))
+				if values.Get("id") != tc.id {
+					t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.id, values.Get("id"))
+				}
+
+				w.Header().Set("Content-Type", tc.contentType)
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			archive, err := c.RetrieveExport(context.Background(), tc.id)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("EXPECTED: %s\nACTUAL: %s", tc.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer archive.Close()
+
+			data, err := ioutil.ReadAll(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, data); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientCredentials(t *testing.T) {
+	tcs := []struct {
+		name    string
+		opts    []kraken.HTTPClientOption
+		isError bool
+	}{
+		{
+			name: "NoCredentials",
+		},
+		{
+			name: "KeyAndSecret",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientWithAPIKey("key"),
+				kraken.HTTPClientWithSecret("c2VjcmV0"),
+			},
+		},
+		{
+			name: "KeyWithoutSecret",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientWithAPIKey("key"),
+			},
+			isError: true,
+		},
+		{
+			name: "SecretWithoutKey",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientWithSecret("c2VjcmV0"),
+			},
+			isError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := kraken.NewHTTPClient(tc.opts...)
+			if tc.isError != (err != nil) {
+				t.Fatalf("EXPECTED ERROR: %t\nACTUAL: %v", tc.isError, err)
+			}
+		})
+	}
+}
+
+func TestPrivateRequest(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	var header http.Header
+	var path, body string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		header = r.Header
+		path = r.URL.Path
+		body = string(payload)
+		w.Write([]byte(`{"error":[],"result":{"count":1}}`))
+	}))
+	defer s.Close()
+
+	c, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithBaseURL(s.URL+"/0"),
+		kraken.HTTPClientWithAPIKey("key"),
+		kraken.HTTPClientWithSecret(secret),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.CancelOrder(context.Background(), "OYVGEW-VYV5B-UUEXSK")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(kraken.CancelResult{Count: 1}, res); diff != nil {
+		t.Error(diff)
+	}
+
+	if path != "/0/private/CancelOrder" {
+		t.Errorf("EXPECTED: /0/private/CancelOrder\nACTUAL: %s", path)
+	}
+
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := values.Get("nonce")
+	if nonce == "" {
+		t.Fatal("nonce not set")
+	}
+
+	expectedBody := "nonce=" + nonce + "&txid=OYVGEW-VYV5B-UUEXSK"
+	if body != expectedBody {
+		t.Errorf("EXPECTED: %s\nACTUAL: %s", expectedBody, body)
+	}
+
+	sha := sha256.Sum256([]byte(nonce + body))
+	mac := hmac.New(sha512.New, []byte("secret"))
+	mac.Write(append([]byte(path), sha[:]...))
+
+	expectedHeaders := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+		"API-Key":      "key",
+		"API-Sign":     base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	}
+
+	for k, v := range expectedHeaders {
+		if header.Get(k) != v {
+			t.Errorf("%s EXPECTED: %s\nACTUAL: %s", k, v, header.Get(k))
+		}
+	}
+}
+
+func TestPrivateRequestErrors(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s.Close()
+
+	tcs := []struct {
+		name string
+		opts []kraken.HTTPClientOption
+		err  error
+	}{
+		{
+			name: "DryRun",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientDryRun(),
+			},
+			err: kraken.ErrDryRun,
+		},
+		{
+			name: "Network",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientWithBaseURL(s.URL),
+			},
+			err: kraken.ErrNetwork,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := kraken.NewHTTPClient(tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := c.CancelAllOrders(context.Background()); !errors.Is(err, tc.err) {
+				t.Fatalf("EXPECTED: %s\nACTUAL: %s", tc.err, err)
+			}
+		})
+	}
+}
+
+type staticNonceGenerator uint64
+
+func (g *staticNonceGenerator) Next() uint64 {
+	*g++
+
+	return uint64(*g)
+}
+
+func TestHTTPClientWithNonceGenerator(t *testing.T) {
+	var nonces []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(payload))
+		nonces = append(nonces, values.Get("nonce"))
+		w.Write([]byte(`{"error":[],"result":{"count":0}}`))
+	}))
+	defer s.Close()
+
+	g := staticNonceGenerator(41)
+	c, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithBaseURL(s.URL),
+		kraken.HTTPClientWithNonceGenerator(&g),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.CancelAllOrders(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := deep.Equal([]string{"42", "43"}, nonces); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHTTPClientWithNonceStore(t *testing.T) {
+	var nonces []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(payload))
+		nonces = append(nonces, values.Get("nonce"))
+		w.Write([]byte(`{"error":[],"result":{"count":0}}`))
+	}))
+	defer s.Close()
+
+	store := kraken.NewFileNonceStore(filepath.Join(t.TempDir(), "nonce"))
+
+	// the first process runs with a clock ahead of the second, simulating a
+	// restart after the wall clock has been moved backwards
+	for _, clock := range []staticNonceGenerator{999, 99} {
+		g := clock
+		c, err := kraken.NewHTTPClient(
+			kraken.HTTPClientWithBaseURL(s.URL),
+			kraken.HTTPClientWithNonceGenerator(&g),
+			kraken.HTTPClientWithNonceStore(store),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.CancelAllOrders(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := deep.Equal([]string{"1000", "1001"}, nonces); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHTTPClientWithOTPProvider(t *testing.T) {
+	errOTP := errors.New("otp unavailable")
+
+	tcs := []struct {
+		name     string
+		provider func() (string, error)
+		expected []string
+		err      error
+	}{
+		{
+			name: "Password",
+			provider: func() (string, error) {
+				return "123456", nil
+			},
+			expected: []string{"123456"},
+		},
+		{
+			name: "ProviderError",
+			provider: func() (string, error) {
+				return "", errOTP
+			},
+			err: errOTP,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var otps []string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				payload, _ := ioutil.ReadAll(r.Body)
+				values, _ := url.ParseQuery(string(payload))
+				otps = append(otps, values.Get("otp"))
+				w.Write([]byte(`{"error":[],"result":{"count":0}}`))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(
+				kraken.HTTPClientWithBaseURL(s.URL),
+				kraken.HTTPClientWithOTPProvider(tc.provider),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := c.CancelAllOrders(context.Background()); !errors.Is(err, tc.err) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+
+			if diff := deep.Equal(tc.expected, otps); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTickers(t *testing.T) {
+	tcs := []struct {
+		name     string
+		pairs    []string
+		expected string
+	}{
+		{
+			name:     "AllPairs",
+			expected: "",
+		},
+		{
+			name:     "Pairs",
+			pairs:    []string{"XBTUSD", "ETHUSD"},
+			expected: "pair=XBTUSD%2CETHUSD",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var path, query string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				query = r.URL.RawQuery
+				w.Write([]byte(`{"error":[],"result":{}}`))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := c.Tickers(context.Background(), tc.pairs...); err != nil {
+				t.Fatal(err)
+			}
+
+			if path != "/public/Ticker" {
+				t.Errorf("EXPECTED: /public/Ticker\nACTUAL: %s", path)
+			}
+
+			if query != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, query)
+			}
+		})
+	}
+}
+
+func TestPublicQuery(t *testing.T) {
+	since := uint64(1616663618)
+
+	tcs := []struct {
+		name     string
+		call     func(c *kraken.HTTPClient) error
+		path     string
+		expected string
+	}{
+		{
+			name: "AssetPairs",
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.AssetPairs(context.Background(), kraken.AssetPairInfoFees, "XBTUSD", "ETHUSD")
+				return err
+			},
+			path:     "/public/AssetPairs",
+			expected: "info=fees&pair=XBTUSD%2CETHUSD",
+		},
+		{
+			name: "AssetPairsDefaultInfo",
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.AssetPairs(context.Background(), "")
+				return err
+			},
+			path:     "/public/AssetPairs",
+			expected: "",
+		},
+		{
+			name: "OHLC",
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.OHLC(context.Background(), kraken.OHLCIntervalMinute, &since, "XBTUSD")
+				return err
+			},
+			path:     "/public/OHLC",
+			expected: "interval=1&pair=XBTUSD&since=1616663618",
+		},
+		{
+			name: "OrderBook",
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.OrderBook(context.Background(), 10, "XBTUSD")
+				return err
+			},
+			path:     "/public/Depth",
+			expected: "count=10&pair=XBTUSD",
+		},
+		{
+			name: "RecentTrades",
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.RecentTrades(context.Background(), &since, "XBTUSD")
+				return err
+			},
+			path:     "/public/Trades",
+			expected: "pair=XBTUSD&since=1616663618",
+		},
+		{
+			name: "RecentSpreads",
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.RecentSpreads(context.Background(), nil, "XBTUSD")
+				return err
+			},
+			path:     "/public/Spread",
+			expected: "pair=XBTUSD",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var path, query string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				query = r.URL.RawQuery
+				w.Write([]byte(`{"error":[],"result":{}}`))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tc.call(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if path != tc.path {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.path, path)
+			}
+
+			if query != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, query)
+			}
+		})
+	}
+}
+
+func TestHTTPClientWithRetry(t *testing.T) {
+	tcs := []struct {
+		name     string
+		failures int
+		failure  func(w http.ResponseWriter)
+		call     func(c *kraken.HTTPClient) error
+		expected int
+		isError  bool
+	}{
+		{
+			name:     "ServerError",
+			failures: 2,
+			failure: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.Time(context.Background())
+				return err
+			},
+			expected: 3,
+		},
+		{
+			name:     "ServiceUnavailable",
+			failures: 2,
+			failure: func(w http.ResponseWriter) {
+				w.Write([]byte(`{"error":["EService:Unavailable"]}`))
+			},
+			call: func(c *kraken.HTTPClient) error {
+				res, err := c.Time(context.Background())
+				if err == nil && len(res.Errors) != 0 {
+					return res.Errors[0]
+				}
+
+				return err
+			},
+			expected: 3,
+		},
+		{
+			name:     "AttemptsExhausted",
+			failures: 5,
+			failure: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.Time(context.Background())
+				return err
+			},
+			expected: 3,
+			isError:  true,
+		},
+		{
+			name:     "ContextDeadline",
+			failures: 5,
+			failure: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			call: func(c *kraken.HTTPClient) error {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+				defer cancel()
+
+				_, err := c.Status(ctx)
+				return err
+			},
+			expected: 0,
+			isError:  true,
+		},
+		{
+			name:     "PrivateNotRetried",
+			failures: 5,
+			failure: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.CancelAllOrders(context.Background())
+				return err
+			},
+			expected: 1,
+			isError:  true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tc.failures {
+					tc.failure(w)
+					return
+				}
+
+				w.Write([]byte(`{"error":[],"result":{"unixtime":1616663618,"rfc1123":"Thu, 25 Mar 21 09:13:38 +0000"}}`))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(
+				kraken.HTTPClientWithBaseURL(s.URL),
+				kraken.HTTPClientWithRetry(3, time.Millisecond, 4*time.Millisecond),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tc.call(c)
+			if tc.isError != (err != nil) {
+				t.Fatalf("EXPECTED ERROR: %t\nACTUAL: %v", tc.isError, err)
+			}
+
+			if requests != tc.expected {
+				t.Errorf("EXPECTED: %d requests\nACTUAL: %d", tc.expected, requests)
+			}
+		})
+	}
+}
+
+func TestDryRunError(t *testing.T) {
+	g := staticNonceGenerator(41)
+	c, err := kraken.NewHTTPClient(
+		kraken.HTTPClientDryRun(),
+		kraken.HTTPClientWithBaseURL("https://api.kraken.com/0"),
+		kraken.HTTPClientWithAPIKey("key"),
+		kraken.HTTPClientWithSecret("c2VjcmV0"),
+		kraken.HTTPClientWithNonceGenerator(&g),
+		kraken.HTTPClientWithOTPProvider(func() (string, error) { return "123456", nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name     string
+		call     func() error
+		expected kraken.RequestSummary
+	}{
+		{
+			name: "Public",
+			call: func() error {
+				_, err := c.Tickers(context.Background(), "XBTUSD", "ETHUSD")
+				return err
+			},
+			expected: kraken.RequestSummary{
+				Method: http.MethodGet,
+				URL:    "https://api.kraken.com/0/public/Ticker",
+				Query:  "pair=XBTUSD%2CETHUSD",
+				Header: http.Header{},
+			},
+		},
+		{
+			name: "Private",
+			call: func() error {
+				_, err := c.CancelOrder(context.Background(), "OQCLML-BW3P3-BUCMWZ")
+				return err
+			},
+			expected: kraken.RequestSummary{
+				Method: http.MethodPost,
+				URL:    "https://api.kraken.com/0/private/CancelOrder",
+				Body:   "nonce=42&txid=OQCLML-BW3P3-BUCMWZ",
+				Header: http.Header{
+					"Content-Type": []string{"application/x-www-form-urlencoded"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var dryRun *kraken.DryRunError
+			if err := tc.call(); !errors.As(err, &dryRun) || !errors.Is(err, kraken.ErrDryRun) {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, dryRun.Request); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCancelOrderByUserReference(t *testing.T) {
+	g := staticNonceGenerator(41)
+	c, err := kraken.NewHTTPClient(
+		kraken.HTTPClientDryRun(),
+		kraken.HTTPClientWithAPIKey("key"),
+		kraken.HTTPClientWithSecret("c2VjcmV0"),
+		kraken.HTTPClientWithNonceGenerator(&g),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dryRun *kraken.DryRunError
+	if _, err := c.CancelOrderByUserReference(context.Background(), -1234); !errors.As(err, &dryRun) {
+		t.Fatal(err)
+	}
+
+	if expected := "nonce=42&txid=-1234"; dryRun.Request.Body != expected {
+		t.Errorf("EXPECTED: %s\nACTUAL: %s", expected, dryRun.Request.Body)
+	}
+}
+
+func TestHTTPClientWithBaseURL(t *testing.T) {
+	tcs := []struct {
+		name     string
+		baseURL  string
+		expected string
+		isError  bool
+	}{
+		{
+			name:     "Valid",
+			baseURL:  "https://api.kraken.com/0",
+			expected: "https://api.kraken.com/0/public/Time",
+		},
+		{
+			name:     "TrailingSlash",
+			baseURL:  "https://api.kraken.com/0/",
+			expected: "https://api.kraken.com/0/public/Time",
+		},
+		{
+			name:     "NoPath",
+			baseURL:  "http://localhost:8080",
+			expected: "http://localhost:8080/public/Time",
+		},
+		{
+			name:    "Unparsable",
+			baseURL: "http://[::1",
+			isError: true,
+		},
+		{
+			name:    "NoScheme",
+			baseURL: "api.kraken.com/0",
+			isError: true,
+		},
+		{
+			name:    "NoHost",
+			baseURL: "https:///0",
+			isError: true,
+		},
+		{
+			name:    "Empty",
+			baseURL: "",
+			isError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun(), kraken.HTTPClientWithBaseURL(tc.baseURL))
+			if tc.isError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var dryRun *kraken.DryRunError
+			if _, err := c.Time(context.Background()); !errors.As(err, &dryRun) {
+				t.Fatal(err)
+			}
+
+			if dryRun.Request.URL != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, dryRun.Request.URL)
+			}
+		})
+	}
+}
+
+func TestHTTPClientWithSecret(t *testing.T) {
+	tcs := []struct {
+		name    string
+		secret  string
+		isError bool
+	}{
+		{
+			name:   "Valid",
+			secret: "c2VjcmV0",
+		},
+		{
+			name:    "InvalidBase64",
+			secret:  "not base64!",
+			isError: true,
+		},
+		{
+			name:    "Truncated",
+			secret:  "c2VjcmV0c",
+			isError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := kraken.NewHTTPClient(kraken.HTTPClientWithAPIKey("key"), kraken.HTTPClientWithSecret(tc.secret))
+			if tc.isError != (err != nil) {
+				t.Fatalf("EXPECTED ERROR: %t\nACTUAL: %v", tc.isError, err)
+			}
+		})
+	}
+}
+
+func TestResponseStatus(t *testing.T) {
+	tcs := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		err         error
+		contains    string
+	}{
+		{
+			name:        "MaintenancePage",
+			status:      http.StatusServiceUnavailable,
+			contentType: "text/html; charset=utf-8",
+			body:        "<html><body><h1>Kraken is down for maintenance</h1></body></html>",
+			err:         kraken.ErrServerError,
+			contains:    "503 Service Unavailable: <html><body><h1>Kraken is down",
+		},
+		{
+			name:     "EmptyServerError",
+			status:   520,
+			err:      kraken.ErrServerError,
+			contains: "520",
+		},
+		{
+			name:        "Throttled",
+			status:      http.StatusTooManyRequests,
+			contentType: "text/plain",
+			body:        "Too Many Requests",
+			err:         kraken.ErrThrottled,
+			contains:    "429 Too Many Requests: Too Many Requests",
+		},
+		{
+			name:        "HTMLOK",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body:        "<html><body>Just a moment...</body></html>",
+			err:         kraken.ErrParse,
+			contains:    "text/html",
+		},
+		{
+			name:   "EmptyOK",
+			status: http.StatusOK,
+			err:    kraken.ErrParse,
+		},
+		{
+			name:        "TruncatedBody",
+			status:      http.StatusBadGateway,
+			contentType: "text/html",
+			body:        strings.Repeat("a", 1000),
+			err:         kraken.ErrServerError,
+			contains:    strings.Repeat("a", 128) + "...",
+		},
+		{
+			name:        "JSON",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"error":[],"result":{"count":0}}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentType != "" {
+					w.Header().Set("Content-Type", tc.contentType)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, publicErr := c.Time(context.Background())
+			_, privateErr := c.CancelAllOrders(context.Background())
+
+			for _, err := range []error{publicErr, privateErr} {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+				}
+
+				if err != nil && !strings.Contains(err.Error(), tc.contains) {
+					t.Errorf("EXPECTED: %q in error\nACTUAL: %v", tc.contains, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitErrors(t *testing.T) {
+	tcs := []struct {
+		name     string
+		status   int
+		body     string
+		category error
+	}{
+		{
+			name:     "APIRateLimit",
+			status:   http.StatusOK,
+			body:     `{"error":["EAPI:Rate limit exceeded"]}`,
+			category: kraken.ErrAPI,
+		},
+		{
+			name:     "GeneralTooManyRequests",
+			status:   http.StatusOK,
+			body:     `{"error":["EGeneral:Too many requests"]}`,
+			category: kraken.ErrGeneral,
+		},
+		{
+			name:     "OrderRateLimit",
+			status:   http.StatusOK,
+			body:     `{"error":["EOrder:Rate limit exceeded"]}`,
+			category: kraken.ErrOrder,
+		},
+		{
+			name:     "TooManyRequests",
+			status:   http.StatusTooManyRequests,
+			category: kraken.ErrThrottled,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := c.TradeBalance(context.Background(), "ZUSD")
+			if err == nil && len(res.Errors) != 0 {
+				err = res.Errors[0]
+			}
+
+			if !errors.Is(err, kraken.ErrRateLimit) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrRateLimit, err)
+			}
+
+			if !errors.Is(err, tc.category) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.category, err)
+			}
+		})
+	}
+}
+
+func TestHTTPClientFailOnAPIError(t *testing.T) {
+	tcs := []struct {
+		name     string
+		body     string
+		opts     []kraken.HTTPClientOption
+		errs     []error
+		warnings int
+	}{
+		{
+			name: "Errors",
+			body: `{"error":["EQuery:Unknown asset pair","EGeneral:Invalid arguments"],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z"}}`,
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientFailOnAPIError(),
+			},
+			errs: []error{kraken.ErrQuery, kraken.ErrUnknownAssetPair, kraken.ErrGeneral},
+		},
+		{
+			name: "WarningsOnly",
+			body: `{"error":["WGeneral:Danger advisory"],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z"}}`,
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientFailOnAPIError(),
+			},
+			warnings: 1,
+		},
+		{
+			name: "Disabled",
+			body: `{"error":["EQuery:Unknown asset pair"],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z"}}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(append([]kraken.HTTPClientOption{kraken.HTTPClientWithBaseURL(s.URL)}, tc.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := c.Status(context.Background())
+			if (len(tc.errs) != 0) != (err != nil) {
+				t.Fatalf("EXPECTED ERROR: %t\nACTUAL: %v", len(tc.errs) != 0, err)
+			}
+
+			for _, expected := range tc.errs {
+				if !errors.Is(err, expected) {
+					t.Errorf("EXPECTED: %v\nACTUAL: %v", expected, err)
+				}
+			}
+
+			// the partially parsed result is returned alongside the error
+			if res.Status != kraken.SystemStatusValueOnline {
+				t.Errorf("EXPECTED: online\nACTUAL: %s", res.Status)
+			}
+
+			if len(res.Warnings) != tc.warnings {
+				t.Errorf("EXPECTED: %d warnings\nACTUAL: %v", tc.warnings, res.Warnings)
+			}
+		})
+	}
+}
+
+func TestHTTPClientWithStrictParsing(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":[],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z","region":"eu"}}`))
+	}))
+	defer s.Close()
+
+	c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Status(context.Background()); err != nil {
+		t.Errorf("EXPECTED: <nil>\nACTUAL: %v", err)
+	}
+
+	c, err = kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL), kraken.HTTPClientWithStrictParsing())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Status(context.Background())
+	if !errors.Is(err, kraken.ErrParse) || !strings.Contains(err.Error(), "region") {
+		t.Errorf("EXPECTED: %v naming the region field\nACTUAL: %v", kraken.ErrParse, err)
+	}
+}
+
+func TestHTTPClientWithRawCapture(t *testing.T) {
+	body := `{"error":[],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z"}}`
+
+	tcs := []struct {
+		name     string
+		opts     []kraken.HTTPClientOption
+		expected []byte
+	}{
+		{
+			name: "Disabled",
+		},
+		{
+			name:     "Enabled",
+			opts:     []kraken.HTTPClientOption{kraken.HTTPClientWithRawCapture()},
+			expected: []byte(body),
+		},
+		{
+			name:     "Truncated",
+			opts:     []kraken.HTTPClientOption{kraken.HTTPClientWithRawCaptureLimit(10)},
+			expected: []byte(body[:10]),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(append([]kraken.HTTPClientOption{kraken.HTTPClientWithBaseURL(s.URL)}, tc.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := c.Status(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(tc.expected, res.Raw) {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, res.Raw)
+			}
+		})
+	}
+}
+
+func TestHTTPClientWithRawCaptureLimit(t *testing.T) {
+	if _, err := kraken.NewHTTPClient(kraken.HTTPClientWithRawCaptureLimit(0)); err == nil {
+		t.Error("EXPECTED: error for a zero limit\nACTUAL: <nil>")
+	}
+}
+
+func TestParseErrorContext(t *testing.T) {
+	long := `{"error":[],"result":{"XXBTZUSD":[` + strings.Repeat(`[1643714160,"38311.6"],`, 20)
+
+	tcs := []struct {
+		name     string
+		body     string
+		call     func(c *kraken.HTTPClient) error
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "PublicPairs",
+			body: `{"error":[],"result":{"XXBTZUSD":[[1643714160,"38311.6"`,
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.OHLC(context.Background(), kraken.OHLCIntervalMinute, nil, "XBTUSD", "ETHUSD")
+				return err
+			},
+			contains: []string{
+				"OHLC pair=XBTUSD,ETHUSD: parse error",
+				`payload: {"error":[],"result":{"XXBTZUSD":[[1643714160,"38311.6"`,
+			},
+		},
+		{
+			name: "Private",
+			body: `{"error":[],"result":{"eb":`,
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.TradeBalance(context.Background(), "ZUSD")
+				return err
+			},
+			contains: []string{
+				`TradeBalance: parse error`,
+				`payload: {"error":[],"result":{"eb":`,
+			},
+		},
+		{
+			name: "TruncatedPayload",
+			body: long,
+			call: func(c *kraken.HTTPClient) error {
+				_, err := c.OHLC(context.Background(), kraken.OHLCIntervalMinute, nil, "XBTUSD")
+				return err
+			},
+			contains: []string{
+				"OHLC pair=XBTUSD: parse error",
+				"payload: " + long[:256] + "...",
+			},
+			excludes: []string{
+				long[:257],
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			c, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tc.call(c)
+			if !errors.Is(err, kraken.ErrParse) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", kraken.ErrParse, err)
+			}
+
+			for _, s := range tc.contains {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("EXPECTED: %q in error\nACTUAL: %v", s, err)
+				}
+			}
+
+			for _, s := range tc.excludes {
+				if strings.Contains(err.Error(), s) {
+					t.Errorf("UNEXPECTED: %q in error\nACTUAL: %v", s, err)
+				}
+			}
+		})
+	}
+}
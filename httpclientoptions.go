@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // HTTPClientOption options used when creating a new HTTPClient
@@ -19,14 +21,20 @@ func HTTPClientWithHTTPClient(httpClient *http.Client) HTTPClientOption {
 	})
 }
 
-// HTTPClientWithBaseURL set the base url of the Kraken client wrapper
+// HTTPClientWithBaseURL set the base url of the Kraken client wrapper, the url
+// must be absolute and any trailing slash is removed
 func HTTPClientWithBaseURL(baseURL string) HTTPClientOption {
 	return HTTPClientOption(func(c *HTTPClient) error {
-		if _, err := url.Parse(c.baseURL); err != nil {
-			return err
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base url: %s", err)
 		}
 
-		c.baseURL = baseURL
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base url: %s must include a scheme and host", baseURL)
+		}
+
+		c.baseURL = strings.TrimRight(baseURL, "/")
 
 		return nil
 	})
@@ -35,7 +43,7 @@ func HTTPClientWithBaseURL(baseURL string) HTTPClientOption {
 // HTTPClientWithSecret set the secret of the Kraken client wrapper
 func HTTPClientWithSecret(secret string) HTTPClientOption {
 	return HTTPClientOption(func(c *HTTPClient) error {
-		if _, err := base64.StdEncoding.DecodeString(c.secret); err != nil {
+		if _, err := base64.StdEncoding.DecodeString(secret); err != nil {
 			return fmt.Errorf("invalid secret: %s", err)
 		}
 
@@ -45,7 +53,138 @@ func HTTPClientWithSecret(secret string) HTTPClientOption {
 	})
 }
 
-// HTTPClientDryRun set the Kraken client to not execute requests
+// HTTPClientWithAPIKey set the API key of the Kraken client wrapper, a secret
+// must also be set
+func HTTPClientWithAPIKey(key string) HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		c.apiKey = key
+
+		return nil
+	})
+}
+
+// HTTPClientWithNonceGenerator set the generator of the nonces sent with
+// private requests
+func HTTPClientWithNonceGenerator(nonces NonceGenerator) HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		if nonces == nil {
+			return fmt.Errorf("nonce generator is required")
+		}
+
+		c.nonces = nonces
+
+		return nil
+	})
+}
+
+// HTTPClientWithNonceStore set the store used to persist the last nonce of
+// private requests between restarts
+func HTTPClientWithNonceStore(store NonceStore) HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		if store == nil {
+			return fmt.Errorf("nonce store is required")
+		}
+
+		c.nonceStore = store
+
+		return nil
+	})
+}
+
+// HTTPClientWithOTPProvider set the provider of the one time password sent
+// with private requests when two-factor authentication is enabled on the API
+// key, the provider is called once per request
+func HTTPClientWithOTPProvider(provider func() (string, error)) HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		if provider == nil {
+			return fmt.Errorf("otp provider is required")
+		}
+
+		c.otp = provider
+
+		return nil
+	})
+}
+
+// HTTPClientWithRetry retry public requests which fail with a network error, a
+// 5xx response or an EService error up to maxAttempts times in total, waiting
+// a random duration up to an exponentially increasing delay between attempts.
+// Private requests are never retried as they may not be idempotent
+func HTTPClientWithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("max attempts must be at least 1")
+		}
+
+		if baseDelay <= 0 || maxDelay < baseDelay {
+			return fmt.Errorf("invalid retry delays")
+		}
+
+		c.retry = retryPolicy{
+			maxAttempts: maxAttempts,
+			baseDelay:   baseDelay,
+			maxDelay:    maxDelay,
+		}
+
+		return nil
+	})
+}
+
+// HTTPClientFailOnAPIError return the API errors of a result joined into a
+// single error, alongside the partially parsed result, rather than only in the
+// Errors field. Warnings are not returned as errors
+func HTTPClientFailOnAPIError() HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		c.failOnAPIError = true
+
+		return nil
+	})
+}
+
+// HTTPClientWithRawCapture set the Raw field of results to the payload they
+// were parsed from, for auditing and bug reports. Payloads are truncated to
+// 1MiB, see HTTPClientWithRawCaptureLimit
+func HTTPClientWithRawCapture() HTTPClientOption {
+	return HTTPClientWithRawCaptureLimit(defaultMaxRawCapture)
+}
+
+// HTTPClientWithRawCaptureLimit set the Raw field of results to at most
+// maxBytes of the payload they were parsed from
+func HTTPClientWithRawCaptureLimit(maxBytes int) HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		if maxBytes < 1 {
+			return fmt.Errorf("raw capture limit must be at least 1 byte")
+		}
+
+		c.maxRawCapture = maxBytes
+
+		return nil
+	})
+}
+
+// HTTPClientWithStrictParsing fail to parse responses with fields which are
+// not mapped to the result, to detect changes to the Kraken API
+func HTTPClientWithStrictParsing() HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		c.parser.Strict = true
+
+		return nil
+	})
+}
+
+// HTTPClientWithOrderValidation check orders with an AssetPair against it
+// with ValidateOrder before they are sent, or described in dry run, failing
+// with the violations joined into an error matching ErrOrderValidation
+func HTTPClientWithOrderValidation() HTTPClientOption {
+	return HTTPClientOption(func(c *HTTPClient) error {
+		c.validateOrders = true
+
+		return nil
+	})
+}
+
+// HTTPClientDryRun set the Kraken client to not execute requests, a
+// DryRunError describing each request is returned instead
 func HTTPClientDryRun() HTTPClientOption {
 	return HTTPClientOption(func(c *HTTPClient) error {
 		c.dryRun = true
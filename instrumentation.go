@@ -2,43 +2,270 @@ package kraken
 
 import (
 	"context"
+	"errors"
+	"io"
+	"reflect"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-)
-
-var (
-	operationCount    *prometheus.CounterVec
-	operationDuration *prometheus.HistogramVec
-	errorCount        *prometheus.CounterVec
+	"github.com/shopspring/decimal"
 )
 
 // InstrumentationClient handles prometheus metrics for calls to
 // client functins
 type InstrumentationClient struct {
-	inner Client
+	inner           Client
+	registerer      prometheus.Registerer
+	namespace       string
+	subsystem       string
+	durationBuckets []float64
+
+	operationCount    *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	errorCount        *prometheus.CounterVec
 }
 
+var _ Client = (*InstrumentationClient)(nil)
+
 // NewInstrumentationClient helper function for creating a new instrumenation
-// client to add prometheus metrics
-func NewInstrumentationClient(inner Client) InstrumentationClient {
-	return InstrumentationClient{
-		inner: inner,
+// client to add prometheus metrics, the metrics are registered with the
+// default prometheus registerer in the "kraken" namespace unless otherwise
+// given
+func NewInstrumentationClient(inner Client, opts ...InstrumentationOption) (*InstrumentationClient, error) {
+	c, err := newInstrumentationConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	c.inner = inner
+
+	operationCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "operations_total",
+			Help:      "Number of Kraken client operations called.",
+		},
+		[]string{"operation"},
+	)
+
+	operationDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of Kraken client operations.",
+			Buckets:   c.durationBuckets,
+		},
+		[]string{"operation"},
+	)
+
+	errorCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "operation_errors_total",
+			Help:      "Number of errors returned by Kraken client operations, including errors returned by the API in a result.",
+		},
+		[]string{"operation", "kind"},
+	)
+
+	if c.operationCount, err = registerCounterVec(c.registerer, operationCount); err != nil {
+		return nil, err
+	}
+
+	if c.operationDuration, err = registerHistogramVec(c.registerer, operationDuration); err != nil {
+		return nil, err
 	}
+
+	if c.errorCount, err = registerCounterVec(c.registerer, errorCount); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// newInstrumentationConfig return an instrumentation client without an
+// inner client or metrics, configured by the defaults and opts
+func newInstrumentationConfig(opts []InstrumentationOption) (InstrumentationClient, error) {
+	c := InstrumentationClient{
+		registerer:      prometheus.DefaultRegisterer,
+		namespace:       "kraken",
+		durationBuckets: prometheus.DefBuckets,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return InstrumentationClient{}, err
+		}
+	}
+
+	return c, nil
+}
+
+// countErrors count the error returned by an operation and the API errors
+// embedded in the Errors field of its result, an error which only joins the
+// API errors of the result is not counted again
+func (c *InstrumentationClient) countErrors(operation string, result interface{}, err error) {
+	apiErrs := resultErrors(result)
+	if err != nil && !joinsAll(err, apiErrs) {
+		c.errorCount.WithLabelValues(operation, errorKind(err)).Inc()
+	}
+
+	for _, err := range apiErrs {
+		c.errorCount.WithLabelValues(operation, errorKind(err)).Inc()
+	}
+}
+
+// joinsAll report whether err matches every error in errs, as an error
+// returned when failing on API errors does
+func joinsAll(err error, errs []error) bool {
+	if len(errs) == 0 {
+		return false
+	}
+
+	for _, e := range errs {
+		if !errors.Is(err, e) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// errorKind classify an error as a network, http, parse, api or other error
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrNetwork):
+		return "network"
+	case errors.Is(err, ErrThrottled), errors.Is(err, ErrServerError):
+		return "http"
+	case errors.Is(err, ErrParse):
+		return "parse"
+	}
+
+	for _, apiErr := range []error{
+		ErrGeneral,
+		ErrAPI,
+		ErrQuery,
+		ErrOrder,
+		ErrTrade,
+		ErrFunding,
+		ErrService,
+		ErrSession,
+		ErrAPIUnknown,
+	} {
+		if errors.Is(err, apiErr) {
+			return "api"
+		}
+	}
+
+	return "other"
+}
+
+// resultErrors return the Errors field of a parsed result or a pointer to a
+// parsed result, nil is returned when the result has no Errors field
+func resultErrors(result interface{}) []error {
+	v := reflect.Indirect(reflect.ValueOf(result))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	field := v.FieldByName("Errors")
+	if !field.IsValid() {
+		return nil
+	}
+
+	errs, ok := field.Interface().([]error)
+	if !ok {
+		return nil
+	}
+
+	return errs
+}
+
+// registerCounterVec register a counter with the registerer, the already
+// registered counter is returned when an identical counter has been
+// registered by another client
+func registerCounterVec(r prometheus.Registerer, v *prometheus.CounterVec) (*prometheus.CounterVec, error) {
+	if err := r.Register(v); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+				return existing, nil
+			}
+		}
+
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// registerCounter register a counter with the registerer, the already
+// registered counter is returned when an identical counter has been
+// registered by another client
+func registerCounter(r prometheus.Registerer, v prometheus.Counter) (prometheus.Counter, error) {
+	if err := r.Register(v); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(prometheus.Counter); ok {
+				return existing, nil
+			}
+		}
+
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// registerGauge register a gauge with the registerer, the already
+// registered gauge is returned when an identical gauge has been registered
+// by another client
+func registerGauge(r prometheus.Registerer, v prometheus.Gauge) (prometheus.Gauge, error) {
+	if err := r.Register(v); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(prometheus.Gauge); ok {
+				return existing, nil
+			}
+		}
+
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// registerHistogramVec register a histogram with the registerer, the already
+// registered histogram is returned when an identical histogram has been
+// registered by another client
+func registerHistogramVec(r prometheus.Registerer, v *prometheus.HistogramVec) (*prometheus.HistogramVec, error) {
+	if err := r.Register(v); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(*prometheus.HistogramVec); ok {
+				return existing, nil
+			}
+		}
+
+		return nil, err
+	}
+
+	return v, nil
 }
 
 // Time handles prometheus metrics for client Time function
 func (c *InstrumentationClient) Time(ctx context.Context) (Time, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("Time"),
+		c.operationDuration.WithLabelValues("Time"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("Time").Inc()
+	c.operationCount.WithLabelValues("Time").Inc()
 
 	v, err := c.inner.Time(ctx)
-	if err != nil {
-		errorCount.WithLabelValues("Time").Inc()
-	}
+	c.countErrors("Time", v, err)
 
 	return v, err
 }
@@ -46,16 +273,14 @@ func (c *InstrumentationClient) Time(ctx context.Context) (Time, error) {
 // Status handles prometheus metrics for client Status function
 func (c *InstrumentationClient) Status(ctx context.Context) (SystemStatus, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("Status"),
+		c.operationDuration.WithLabelValues("Status"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("Status").Inc()
+	c.operationCount.WithLabelValues("Status").Inc()
 
 	v, err := c.inner.Status(ctx)
-	if err != nil {
-		errorCount.WithLabelValues("Status").Inc()
-	}
+	c.countErrors("Status", v, err)
 
 	return v, err
 }
@@ -63,16 +288,14 @@ func (c *InstrumentationClient) Status(ctx context.Context) (SystemStatus, error
 // Assets handles prometheus metrics for client Assets function
 func (c *InstrumentationClient) Assets(ctx context.Context) (Assets, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("Assets"),
+		c.operationDuration.WithLabelValues("Assets"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("Assets").Inc()
+	c.operationCount.WithLabelValues("Assets").Inc()
 
 	v, err := c.inner.Assets(ctx)
-	if err != nil {
-		errorCount.WithLabelValues("Assets").Inc()
-	}
+	c.countErrors("Assets", v, err)
 
 	return v, err
 }
@@ -80,16 +303,29 @@ func (c *InstrumentationClient) Assets(ctx context.Context) (Assets, error) {
 // AssetPairs handles prometheus metrics for client AssetPairs function
 func (c *InstrumentationClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("AssetPairs"),
+		c.operationDuration.WithLabelValues("AssetPairs"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("AssetPairs").Inc()
+	c.operationCount.WithLabelValues("AssetPairs").Inc()
 
 	v, err := c.inner.AssetPairs(ctx, info, pairs...)
-	if err != nil {
-		errorCount.WithLabelValues("AssetPairs").Inc()
-	}
+	c.countErrors("AssetPairs", v, err)
+
+	return v, err
+}
+
+// Tickers handles prometheus metrics for client Tickers function
+func (c *InstrumentationClient) Tickers(ctx context.Context, pairs ...string) (Tickers, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("Tickers"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("Tickers").Inc()
+
+	v, err := c.inner.Tickers(ctx, pairs...)
+	c.countErrors("Tickers", v, err)
 
 	return v, err
 }
@@ -97,16 +333,14 @@ func (c *InstrumentationClient) AssetPairs(ctx context.Context, info AssetPairIn
 // OHLC handles prometheus metrics for client OHLC function
 func (c *InstrumentationClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("OHLC"),
+		c.operationDuration.WithLabelValues("OHLC"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("OHLC").Inc()
+	c.operationCount.WithLabelValues("OHLC").Inc()
 
 	v, err := c.inner.OHLC(ctx, interval, since, pairs...)
-	if err != nil {
-		errorCount.WithLabelValues("OHLC").Inc()
-	}
+	c.countErrors("OHLC", v, err)
 
 	return v, err
 }
@@ -114,16 +348,14 @@ func (c *InstrumentationClient) OHLC(ctx context.Context, interval OHLCInterval,
 // OrderBook handles prometheus metrics for client OrderBook function
 func (c *InstrumentationClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("OrderBook"),
+		c.operationDuration.WithLabelValues("OrderBook"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("OrderBook").Inc()
+	c.operationCount.WithLabelValues("OrderBook").Inc()
 
 	v, err := c.inner.OrderBook(ctx, count, pairs...)
-	if err != nil {
-		errorCount.WithLabelValues("OrderBook").Inc()
-	}
+	c.countErrors("OrderBook", v, err)
 
 	return v, err
 }
@@ -131,16 +363,14 @@ func (c *InstrumentationClient) OrderBook(ctx context.Context, count uint, pairs
 // RecentTrades handles prometheus metrics for client RecentTrades function
 func (c *InstrumentationClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("RecentTrades"),
+		c.operationDuration.WithLabelValues("RecentTrades"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("RecentTrades").Inc()
+	c.operationCount.WithLabelValues("RecentTrades").Inc()
 
 	v, err := c.inner.RecentTrades(ctx, since, pairs...)
-	if err != nil {
-		errorCount.WithLabelValues("RecentTrades").Inc()
-	}
+	c.countErrors("RecentTrades", v, err)
 
 	return v, err
 }
@@ -148,16 +378,532 @@ func (c *InstrumentationClient) RecentTrades(ctx context.Context, since *uint64,
 // RecentSpreads handles prometheus metrics for client RecentSpreads function
 func (c *InstrumentationClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
 	timer := prometheus.NewTimer(
-		operationDuration.WithLabelValues("RecentSpreads"),
+		c.operationDuration.WithLabelValues("RecentSpreads"),
 	)
 	defer timer.ObserveDuration()
 
-	operationCount.WithLabelValues("RecentSpreads").Inc()
+	c.operationCount.WithLabelValues("RecentSpreads").Inc()
 
-	v, err := c.inner.RecentSpreads(ctx, pairs, since)
-	if err != nil {
-		errorCount.WithLabelValues("RecentSpreads").Inc()
-	}
+	v, err := c.inner.RecentSpreads(ctx, since, pairs...)
+	c.countErrors("RecentSpreads", v, err)
+
+	return v, err
+}
+
+// TradeBalance handles prometheus metrics for client TradeBalance function
+func (c *InstrumentationClient) TradeBalance(ctx context.Context, asset string) (TradeBalance, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("TradeBalance"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("TradeBalance").Inc()
+
+	v, err := c.inner.TradeBalance(ctx, asset)
+	c.countErrors("TradeBalance", v, err)
+
+	return v, err
+}
+
+// OpenOrders handles prometheus metrics for client OpenOrders function
+func (c *InstrumentationClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("OpenOrders"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("OpenOrders").Inc()
+
+	v, err := c.inner.OpenOrders(ctx, trades, userref)
+	c.countErrors("OpenOrders", v, err)
+
+	return v, err
+}
+
+// ClosedOrders handles prometheus metrics for client ClosedOrders function
+func (c *InstrumentationClient) ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("ClosedOrders"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("ClosedOrders").Inc()
+
+	v, err := c.inner.ClosedOrders(ctx, query)
+	c.countErrors("ClosedOrders", v, err)
+
+	return v, err
+}
+
+// QueryOrders handles prometheus metrics for client QueryOrders function
+func (c *InstrumentationClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("QueryOrders"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("QueryOrders").Inc()
+
+	v, err := c.inner.QueryOrders(ctx, trades, userref, txids...)
+	c.countErrors("QueryOrders", v, err)
+
+	return v, err
+}
+
+// TradesHistory handles prometheus metrics for client TradesHistory function
+func (c *InstrumentationClient) TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("TradesHistory"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("TradesHistory").Inc()
+
+	v, err := c.inner.TradesHistory(ctx, query)
+	c.countErrors("TradesHistory", v, err)
+
+	return v, err
+}
+
+// OpenPositions handles prometheus metrics for client OpenPositions function
+func (c *InstrumentationClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("OpenPositions"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("OpenPositions").Inc()
+
+	v, err := c.inner.OpenPositions(ctx, docalcs, txids...)
+	c.countErrors("OpenPositions", v, err)
+
+	return v, err
+}
+
+// Ledgers handles prometheus metrics for client Ledgers function
+func (c *InstrumentationClient) Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("Ledgers"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("Ledgers").Inc()
+
+	v, err := c.inner.Ledgers(ctx, query)
+	c.countErrors("Ledgers", v, err)
+
+	return v, err
+}
+
+// QueryLedgers handles prometheus metrics for client QueryLedgers function
+func (c *InstrumentationClient) QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("QueryLedgers"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("QueryLedgers").Inc()
+
+	v, err := c.inner.QueryLedgers(ctx, ids...)
+	c.countErrors("QueryLedgers", v, err)
+
+	return v, err
+}
+
+// TradeVolume handles prometheus metrics for client TradeVolume function
+func (c *InstrumentationClient) TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("TradeVolume"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("TradeVolume").Inc()
+
+	v, err := c.inner.TradeVolume(ctx, pairs...)
+	c.countErrors("TradeVolume", v, err)
+
+	return v, err
+}
+
+// AddOrder handles prometheus metrics for client AddOrder function
+func (c *InstrumentationClient) AddOrder(ctx context.Context, req OrderRequest) (AddOrderResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("AddOrder"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("AddOrder").Inc()
+
+	v, err := c.inner.AddOrder(ctx, req)
+	c.countErrors("AddOrder", v, err)
+
+	return v, err
+}
+
+// CancelOrder handles prometheus metrics for client CancelOrder function
+func (c *InstrumentationClient) CancelOrder(ctx context.Context, txid string) (CancelResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("CancelOrder"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("CancelOrder").Inc()
+
+	v, err := c.inner.CancelOrder(ctx, txid)
+	c.countErrors("CancelOrder", v, err)
+
+	return v, err
+}
+
+// CancelAllOrders handles prometheus metrics for client CancelAllOrders
+// function
+func (c *InstrumentationClient) CancelAllOrders(ctx context.Context) (CancelResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("CancelAllOrders"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("CancelAllOrders").Inc()
+
+	v, err := c.inner.CancelAllOrders(ctx)
+	c.countErrors("CancelAllOrders", v, err)
+
+	return v, err
+}
+
+// CancelAllOrdersAfter handles prometheus metrics for client CancelAllOrdersAfter
+// function
+func (c *InstrumentationClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("CancelAllOrdersAfter"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("CancelAllOrdersAfter").Inc()
+
+	v, err := c.inner.CancelAllOrdersAfter(ctx, timeout)
+	c.countErrors("CancelAllOrdersAfter", v, err)
+
+	return v, err
+}
+
+// EditOrder handles prometheus metrics for client EditOrder function
+func (c *InstrumentationClient) EditOrder(ctx context.Context, req EditOrderRequest) (EditOrderResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("EditOrder"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("EditOrder").Inc()
+
+	v, err := c.inner.EditOrder(ctx, req)
+	c.countErrors("EditOrder", v, err)
+
+	return v, err
+}
+
+// DepositMethods handles prometheus metrics for client DepositMethods function
+func (c *InstrumentationClient) DepositMethods(ctx context.Context, asset string) (DepositMethods, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("DepositMethods"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("DepositMethods").Inc()
+
+	v, err := c.inner.DepositMethods(ctx, asset)
+	c.countErrors("DepositMethods", v, err)
+
+	return v, err
+}
+
+// DepositAddresses handles prometheus metrics for client DepositAddresses
+// function
+func (c *InstrumentationClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("DepositAddresses"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("DepositAddresses").Inc()
+
+	v, err := c.inner.DepositAddresses(ctx, asset, method, new)
+	c.countErrors("DepositAddresses", v, err)
+
+	return v, err
+}
+
+// DepositStatus handles prometheus metrics for client DepositStatus function
+func (c *InstrumentationClient) DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("DepositStatus"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("DepositStatus").Inc()
+
+	v, err := c.inner.DepositStatus(ctx, asset, method)
+	c.countErrors("DepositStatus", v, err)
+
+	return v, err
+}
+
+// WithdrawInfo handles prometheus metrics for client WithdrawInfo function
+func (c *InstrumentationClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("WithdrawInfo"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("WithdrawInfo").Inc()
+
+	v, err := c.inner.WithdrawInfo(ctx, asset, key, amount)
+	c.countErrors("WithdrawInfo", v, err)
+
+	return v, err
+}
+
+// Withdraw handles prometheus metrics for client Withdraw function
+func (c *InstrumentationClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("Withdraw"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("Withdraw").Inc()
+
+	v, err := c.inner.Withdraw(ctx, asset, key, amount)
+	c.countErrors("Withdraw", v, err)
+
+	return v, err
+}
+
+// WithdrawStatus handles prometheus metrics for client WithdrawStatus function
+func (c *InstrumentationClient) WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("WithdrawStatus"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("WithdrawStatus").Inc()
+
+	v, err := c.inner.WithdrawStatus(ctx, asset, method)
+	c.countErrors("WithdrawStatus", v, err)
+
+	return v, err
+}
+
+// WithdrawCancel handles prometheus metrics for client WithdrawCancel function
+func (c *InstrumentationClient) WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("WithdrawCancel"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("WithdrawCancel").Inc()
+
+	v, err := c.inner.WithdrawCancel(ctx, asset, refid)
+	c.countErrors("WithdrawCancel", v, err)
+
+	return v, err
+}
+
+// WalletTransfer handles prometheus metrics for client WalletTransfer function
+func (c *InstrumentationClient) WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("WalletTransfer"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("WalletTransfer").Inc()
+
+	v, err := c.inner.WalletTransfer(ctx, asset, from, to, amount)
+	c.countErrors("WalletTransfer", v, err)
+
+	return v, err
+}
+
+// EarnStrategies handles prometheus metrics for client EarnStrategies function
+func (c *InstrumentationClient) EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("EarnStrategies"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("EarnStrategies").Inc()
+
+	v, err := c.inner.EarnStrategies(ctx, asset, cursor)
+	c.countErrors("EarnStrategies", v, err)
+
+	return v, err
+}
+
+// EarnAllocate handles prometheus metrics for client EarnAllocate function
+func (c *InstrumentationClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("EarnAllocate"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("EarnAllocate").Inc()
+
+	v, err := c.inner.EarnAllocate(ctx, strategyID, amount)
+	c.countErrors("EarnAllocate", v, err)
+
+	return v, err
+}
+
+// EarnDeallocate handles prometheus metrics for client EarnDeallocate function
+func (c *InstrumentationClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("EarnDeallocate"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("EarnDeallocate").Inc()
+
+	v, err := c.inner.EarnDeallocate(ctx, strategyID, amount)
+	c.countErrors("EarnDeallocate", v, err)
+
+	return v, err
+}
+
+// EarnAllocationStatus handles prometheus metrics for client EarnAllocationStatus
+// function
+func (c *InstrumentationClient) EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("EarnAllocationStatus"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("EarnAllocationStatus").Inc()
+
+	v, err := c.inner.EarnAllocationStatus(ctx, strategyID)
+	c.countErrors("EarnAllocationStatus", v, err)
+
+	return v, err
+}
+
+// EarnAllocations handles prometheus metrics for client EarnAllocations
+// function
+func (c *InstrumentationClient) EarnAllocations(ctx context.Context) (EarnAllocations, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("EarnAllocations"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("EarnAllocations").Inc()
+
+	v, err := c.inner.EarnAllocations(ctx)
+	c.countErrors("EarnAllocations", v, err)
+
+	return v, err
+}
+
+// AddExport handles prometheus metrics for client AddExport function
+func (c *InstrumentationClient) AddExport(ctx context.Context, req ExportRequest) (AddExportResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("AddExport"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("AddExport").Inc()
+
+	v, err := c.inner.AddExport(ctx, req)
+	c.countErrors("AddExport", v, err)
+
+	return v, err
+}
+
+// ExportStatus handles prometheus metrics for client ExportStatus function
+func (c *InstrumentationClient) ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("ExportStatus"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("ExportStatus").Inc()
+
+	v, err := c.inner.ExportStatus(ctx, report)
+	c.countErrors("ExportStatus", v, err)
+
+	return v, err
+}
+
+// RetrieveExport handles prometheus metrics for client RetrieveExport function
+func (c *InstrumentationClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("RetrieveExport"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("RetrieveExport").Inc()
+
+	v, err := c.inner.RetrieveExport(ctx, id)
+	c.countErrors("RetrieveExport", v, err)
+
+	return v, err
+}
+
+// RemoveExport handles prometheus metrics for client RemoveExport function
+func (c *InstrumentationClient) RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("RemoveExport"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("RemoveExport").Inc()
+
+	v, err := c.inner.RemoveExport(ctx, id, removal)
+	c.countErrors("RemoveExport", v, err)
+
+	return v, err
+}
+
+// CreateSubaccount handles prometheus metrics for client CreateSubaccount
+// function
+func (c *InstrumentationClient) CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("CreateSubaccount"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("CreateSubaccount").Inc()
+
+	v, err := c.inner.CreateSubaccount(ctx, username, email)
+	c.countErrors("CreateSubaccount", v, err)
+
+	return v, err
+}
+
+// AccountTransfer handles prometheus metrics for client AccountTransfer
+// function
+func (c *InstrumentationClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("AccountTransfer"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("AccountTransfer").Inc()
+
+	v, err := c.inner.AccountTransfer(ctx, asset, amount, fromUser, toUser)
+	c.countErrors("AccountTransfer", v, err)
+
+	return v, err
+}
+
+// GetWebSocketsToken handles prometheus metrics for client GetWebSocketsToken
+// function
+func (c *InstrumentationClient) GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error) {
+	timer := prometheus.NewTimer(
+		c.operationDuration.WithLabelValues("GetWebSocketsToken"),
+	)
+	defer timer.ObserveDuration()
+
+	c.operationCount.WithLabelValues("GetWebSocketsToken").Inc()
+
+	v, err := c.inner.GetWebSocketsToken(ctx)
+	c.countErrors("GetWebSocketsToken", v, err)
 
 	return v, err
 }
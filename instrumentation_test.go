@@ -0,0 +1,296 @@
+package kraken_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestInstrumentationClient(t *testing.T) {
+	inner, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := prometheus.NewRegistry()
+	c, err := kraken.NewInstrumentationClient(inner, kraken.InstrumentationWithRegisterer(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every call errors as the inner client is a dry run
+	client := reflect.TypeOf((*kraken.Client)(nil)).Elem()
+	v := reflect.ValueOf(c)
+	for i := 0; i < client.NumMethod(); i++ {
+		name := client.Method(i).Name
+		method := v.MethodByName(name)
+
+		args := make([]reflect.Value, method.Type().NumIn())
+		args[0] = reflect.ValueOf(context.Background())
+		for j := 1; j < len(args); j++ {
+			args[j] = reflect.Zero(method.Type().In(j))
+		}
+
+		if method.Type().IsVariadic() {
+			method.CallSlice(args)
+		} else {
+			method.Call(args)
+		}
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]map[string]float64{}
+	for _, family := range families {
+		counts[family.GetName()] = map[string]float64{}
+		for _, metric := range family.GetMetric() {
+			var operation string
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "operation" {
+					operation = label.GetValue()
+				}
+			}
+
+			if metric.GetHistogram() != nil {
+				counts[family.GetName()][operation] += float64(metric.GetHistogram().GetSampleCount())
+				continue
+			}
+
+			counts[family.GetName()][operation] += metric.GetCounter().GetValue()
+		}
+	}
+
+	for _, family := range []string{
+		"kraken_operations_total",
+		"kraken_operation_duration_seconds",
+		"kraken_operation_errors_total",
+	} {
+		for i := 0; i < client.NumMethod(); i++ {
+			name := client.Method(i).Name
+			if counts[family][name] != 1 {
+				t.Errorf("%s{operation=%q} EXPECTED: 1\nACTUAL: %v", family, name, counts[family][name])
+			}
+		}
+	}
+}
+
+func TestInstrumentationClientSharedRegisterer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	for i := 0; i < 2; i++ {
+		if _, err := kraken.NewInstrumentationClient(nil, kraken.InstrumentationWithRegisterer(reg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInstrumentationClientNamespaces(t *testing.T) {
+	inner, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := prometheus.NewRegistry()
+	buckets := []float64{0.3, 0.5, 1, 2}
+
+	clients := map[string]*kraken.InstrumentationClient{}
+	for _, namespace := range []string{"spot", "futures"} {
+		c, err := kraken.NewInstrumentationClient(
+			inner,
+			kraken.InstrumentationWithRegisterer(reg),
+			kraken.InstrumentationWithNamespace(namespace),
+			kraken.InstrumentationWithSubsystem("kraken"),
+			kraken.InstrumentationWithDurationBuckets(buckets),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clients[namespace] = c
+	}
+
+	clients["spot"].Time(context.Background())
+	clients["spot"].Time(context.Background())
+	clients["futures"].Time(context.Background())
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if h := metric.GetHistogram(); h != nil {
+				var upperBounds []float64
+				for _, b := range h.GetBucket() {
+					upperBounds = append(upperBounds, b.GetUpperBound())
+				}
+
+				if diff := deep.Equal(buckets, upperBounds); diff != nil {
+					t.Errorf("%s: %v", family.GetName(), diff)
+				}
+
+				continue
+			}
+
+			counts[family.GetName()] += metric.GetCounter().GetValue()
+		}
+	}
+
+	expected := map[string]float64{
+		"spot_kraken_operations_total":          2,
+		"spot_kraken_operation_errors_total":    2,
+		"futures_kraken_operations_total":       1,
+		"futures_kraken_operation_errors_total": 1,
+	}
+
+	if diff := deep.Equal(expected, counts); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInstrumentationWithDurationBuckets(t *testing.T) {
+	tcs := []struct {
+		name    string
+		buckets []float64
+		isError bool
+	}{
+		{
+			name:    "Increasing",
+			buckets: []float64{0.3, 0.5, 1, 2},
+		},
+		{
+			name:    "Empty",
+			isError: true,
+		},
+		{
+			name:    "Unordered",
+			buckets: []float64{0.5, 0.3},
+			isError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := kraken.NewInstrumentationClient(
+				nil,
+				kraken.InstrumentationWithRegisterer(prometheus.NewRegistry()),
+				kraken.InstrumentationWithDurationBuckets(tc.buckets),
+			)
+			if tc.isError != (err != nil) {
+				t.Fatalf("EXPECTED ERROR: %t\nACTUAL: %v", tc.isError, err)
+			}
+		})
+	}
+}
+
+func TestInstrumentationClientErrorKinds(t *testing.T) {
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	tcs := []struct {
+		name     string
+		body     string
+		opts     []kraken.HTTPClientOption
+		expected map[string]float64
+	}{
+		{
+			name: "API",
+			body: `{"error":["EQuery:Unknown asset pair","EGeneral:Invalid arguments"],"result":{}}`,
+			expected: map[string]float64{
+				"api": 2,
+			},
+		},
+		{
+			name: "FailOnAPIError",
+			body: `{"error":["EQuery:Unknown asset pair","EGeneral:Invalid arguments"],"result":{}}`,
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientFailOnAPIError(),
+			},
+			expected: map[string]float64{
+				"api": 2,
+			},
+		},
+		{
+			name: "Parse",
+			body: `{"error":`,
+			expected: map[string]float64{
+				"parse": 1,
+			},
+		},
+		{
+			name: "Network",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientWithBaseURL(closed.URL),
+			},
+			expected: map[string]float64{
+				"network": 1,
+			},
+		},
+		{
+			name: "Other",
+			opts: []kraken.HTTPClientOption{
+				kraken.HTTPClientDryRun(),
+			},
+			expected: map[string]float64{
+				"other": 1,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			inner, err := kraken.NewHTTPClient(append([]kraken.HTTPClientOption{kraken.HTTPClientWithBaseURL(s.URL)}, tc.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reg := prometheus.NewRegistry()
+			c, err := kraken.NewInstrumentationClient(inner, kraken.InstrumentationWithRegisterer(reg))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.Tickers(context.Background(), "XBTUSD")
+
+			families, err := reg.Gather()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kinds := map[string]float64{}
+			for _, family := range families {
+				if family.GetName() != "kraken_operation_errors_total" {
+					continue
+				}
+
+				for _, metric := range family.GetMetric() {
+					for _, label := range metric.GetLabel() {
+						if label.GetName() == "kind" {
+							kinds[label.GetValue()] += metric.GetCounter().GetValue()
+						}
+					}
+				}
+			}
+
+			if diff := deep.Equal(tc.expected, kinds); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package kraken
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InstrumentationOption options used when creating a new InstrumentationClient
+type InstrumentationOption func(c *InstrumentationClient) error
+
+// InstrumentationWithRegisterer set the registerer the metrics of the
+// instrumentation client are registered with
+func InstrumentationWithRegisterer(registerer prometheus.Registerer) InstrumentationOption {
+	return InstrumentationOption(func(c *InstrumentationClient) error {
+		if registerer == nil {
+			return fmt.Errorf("registerer is required")
+		}
+
+		c.registerer = registerer
+
+		return nil
+	})
+}
+
+// InstrumentationWithNamespace set the namespace of the metrics of the
+// instrumentation client, clients in different namespaces do not share
+// metrics
+func InstrumentationWithNamespace(namespace string) InstrumentationOption {
+	return InstrumentationOption(func(c *InstrumentationClient) error {
+		c.namespace = namespace
+
+		return nil
+	})
+}
+
+// InstrumentationWithSubsystem set the subsystem of the metrics of the
+// instrumentation client
+func InstrumentationWithSubsystem(subsystem string) InstrumentationOption {
+	return InstrumentationOption(func(c *InstrumentationClient) error {
+		c.subsystem = subsystem
+
+		return nil
+	})
+}
+
+// InstrumentationWithDurationBuckets set the buckets, in seconds, of the
+// operation duration histogram
+func InstrumentationWithDurationBuckets(buckets []float64) InstrumentationOption {
+	return InstrumentationOption(func(c *InstrumentationClient) error {
+		if len(buckets) == 0 {
+			return fmt.Errorf("buckets are required")
+		}
+
+		for i := 1; i < len(buckets); i++ {
+			if buckets[i] <= buckets[i-1] {
+				return fmt.Errorf("buckets must be in increasing order")
+			}
+		}
+
+		c.durationBuckets = buckets
+
+		return nil
+	})
+}
@@ -0,0 +1,499 @@
+// Package websocket a minimal RFC 6455 websocket implementation, enough for the
+// JSON text messages of the Kraken websocket API and for test servers speaking
+// it. Extensions and subprotocols are not supported
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// acceptGUID the GUID appended to a handshake key to derive its accept
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// maxControlPayload the largest payload of a control frame
+	maxControlPayload = 125
+	// closeTimeout how long a closing connection waits to write the close frame
+	closeTimeout = time.Second
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// close status codes
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseNoStatus        = 1005
+	CloseMessageTooLarge = 1009
+)
+
+// DefaultMaxMessage the default largest message a Conn reads
+const DefaultMaxMessage = 16 << 20
+
+var (
+	// ErrClosed the connection has been closed locally
+	ErrClosed = errors.New("websocket: closed")
+	// ErrHandshake the opening handshake failed
+	ErrHandshake = errors.New("websocket: bad handshake")
+)
+
+// CloseError the connection was closed by the peer with a close frame
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error implement the error interface
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: closed by peer: %d", e.Code)
+	}
+
+	return fmt.Sprintf("websocket: closed by peer: %d %s", e.Code, e.Reason)
+}
+
+// Conn a websocket connection exchanging whole messages. A Conn supports one
+// concurrent reader and any number of concurrent writers
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+
+	// MaxMessage the largest message ReadMessage accepts
+	MaxMessage int
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// Dial open a websocket connection to a ws or wss url, the handshake is bounded
+// by the context and header is sent with the handshake request
+func Dial(ctx context.Context, rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		secure = true
+	default:
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if secure {
+			port = "443"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if secure {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	c, err := clientHandshake(ctx, conn, u, header)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// clientHandshake send the opening handshake over conn and validate the
+// server's response
+func clientHandshake(ctx context.Context, conn net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(conn); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("%w: unexpected status %s", ErrHandshake, res.Status)
+	}
+
+	if !headerContains(res.Header, "Upgrade", "websocket") || !headerContains(res.Header, "Connection", "upgrade") {
+		return nil, fmt.Errorf("%w: missing upgrade headers", ErrHandshake)
+	}
+
+	if res.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, fmt.Errorf("%w: invalid accept key", ErrHandshake)
+	}
+
+	return &Conn{conn: conn, br: br, client: true, MaxMessage: DefaultMaxMessage}, nil
+}
+
+// Upgrade accept a websocket handshake request, on failure an error response
+// has been written
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("%w: method %s", ErrHandshake, r.Method)
+	case !headerContains(r.Header, "Upgrade", "websocket") || !headerContains(r.Header, "Connection", "upgrade"):
+		http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: missing upgrade headers", ErrHandshake)
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: unsupported version", ErrHandshake)
+	case key == "":
+		http.Error(w, "missing key", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: missing key", ErrHandshake)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("%w: response does not support hijacking", ErrHandshake)
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: conn, br: rw.Reader, MaxMessage: DefaultMaxMessage}, nil
+}
+
+// ReadMessage read the next text or binary message. Pings are answered while
+// reading, a close frame from the peer is answered and returned as a
+// *CloseError
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil && !errors.Is(err, ErrClosed) {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			closeErr := &CloseError{Code: CloseNoStatus}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			code := closeErr.Code
+			if code == CloseNoStatus {
+				code = CloseNormal
+			}
+			c.closeWith(code, "")
+			return nil, closeErr
+		case opText, opBinary:
+			if started {
+				c.closeWith(CloseProtocolError, "expected continuation frame")
+				return nil, fmt.Errorf("websocket: expected continuation frame")
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				c.closeWith(CloseProtocolError, "unexpected continuation frame")
+				return nil, fmt.Errorf("websocket: unexpected continuation frame")
+			}
+		default:
+			c.closeWith(CloseProtocolError, "unknown opcode")
+			return nil, fmt.Errorf("websocket: unknown opcode %d", op)
+		}
+
+		if len(message)+len(payload) > c.MaxMessage {
+			c.closeWith(CloseMessageTooLarge, "")
+			return nil, fmt.Errorf("websocket: message exceeds %d bytes", c.MaxMessage)
+		}
+
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame read a single frame, unmasking its payload
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var header [10]byte
+	if _, err := io.ReadFull(c.br, header[:2]); err != nil {
+		return false, 0, nil, c.readErr(err)
+	}
+
+	fin := header[0]&0x80 != 0
+	op := header[0] & 0x0f
+	if header[0]&0x70 != 0 {
+		c.closeWith(CloseProtocolError, "reserved bits set")
+		return false, 0, nil, fmt.Errorf("websocket: reserved bits set")
+	}
+
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		if _, err := io.ReadFull(c.br, header[2:4]); err != nil {
+			return false, 0, nil, c.readErr(err)
+		}
+		length = uint64(binary.BigEndian.Uint16(header[2:4]))
+	case 127:
+		if _, err := io.ReadFull(c.br, header[2:10]); err != nil {
+			return false, 0, nil, c.readErr(err)
+		}
+		length = binary.BigEndian.Uint64(header[2:10])
+	}
+
+	if op >= opClose && (length > maxControlPayload || !fin) {
+		c.closeWith(CloseProtocolError, "invalid control frame")
+		return false, 0, nil, fmt.Errorf("websocket: invalid control frame")
+	}
+
+	if length > uint64(c.MaxMessage) {
+		c.closeWith(CloseMessageTooLarge, "")
+		return false, 0, nil, fmt.Errorf("websocket: frame exceeds %d bytes", c.MaxMessage)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, c.readErr(err)
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, c.readErr(err)
+	}
+
+	if masked {
+		maskBytes(mask, payload)
+	}
+
+	return fin, op, payload, nil
+}
+
+// WriteMessage write a text message
+func (c *Conn) WriteMessage(p []byte) error {
+	return c.writeFrame(opText, p)
+}
+
+// Ping write a ping, the peer's pong is consumed by ReadMessage
+func (c *Conn) Ping(p []byte) error {
+	return c.writeFrame(opPing, p)
+}
+
+// writeFrame write a single final frame, frames written by a client are masked
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	return c.writeFrameLocked(op, payload)
+}
+
+func (c *Conn) writeFrameLocked(op byte, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+14)
+	frame = append(frame, 0x80|op)
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, maskBit|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+
+		start := len(frame)
+		frame = append(frame, payload...)
+		maskBytes(mask, frame[start:])
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close send a normal close frame and close the connection
+func (c *Conn) Close() error {
+	return c.closeWith(CloseNormal, "")
+}
+
+// closeWith send a close frame with a status code and close the connection,
+// closing an already closed connection does nothing
+func (c *Conn) closeWith(code int, reason string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > maxControlPayload {
+		payload = payload[:maxControlPayload]
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+	c.writeFrameLocked(opClose, payload)
+
+	return c.conn.Close()
+}
+
+// readErr return ErrClosed for reads failing because the connection was
+// closed locally
+func (c *Conn) readErr(err error) error {
+	c.writeMu.Lock()
+	closed := c.closed
+	c.writeMu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	return err
+}
+
+// acceptKey the Sec-WebSocket-Accept value for a Sec-WebSocket-Key
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// maskBytes apply a masking key to a payload in place
+func maskBytes(mask [4]byte, p []byte) {
+	for i := range p {
+		p[i] ^= mask[i%4]
+	}
+}
+
+// headerContains whether a comma separated header contains a token, compared
+// case insensitively
+func headerContains(header http.Header, name, token string) bool {
+	for _, v := range header.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ctxErr return the context's error when it ended the handshake
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
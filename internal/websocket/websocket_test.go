@@ -0,0 +1,148 @@
+package websocket_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oliread/kraken/internal/websocket"
+)
+
+// echoServer a test server echoing every message back to the client
+func echoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if err := conn.WriteMessage(message); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := echoServer(t)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conn, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tcs := []struct {
+		name    string
+		message []byte
+	}{
+		{name: "Empty", message: []byte{}},
+		{name: "Small", message: []byte(`{"event":"ping"}`)},
+		{name: "Extended16", message: bytes.Repeat([]byte("a"), 1000)},
+		{name: "Extended64", message: bytes.Repeat([]byte("b"), 70000)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := conn.WriteMessage(tc.message); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := conn.Ping([]byte("ping")); err != nil {
+				t.Fatal(err)
+			}
+
+			message, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(tc.message, message) {
+				t.Errorf("EXPECTED: %d bytes\nACTUAL: %d bytes", len(tc.message), len(message))
+			}
+		})
+	}
+}
+
+func TestMaxMessage(t *testing.T) {
+	s := echoServer(t)
+	defer s.Close()
+
+	conn, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.MaxMessage = 10
+	if err := conn.WriteMessage(bytes.Repeat([]byte("a"), 11)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.ReadMessage(); err == nil {
+		t.Error("EXPECTED: error for an oversized message\nACTUAL: <nil>")
+	}
+}
+
+func TestPeerClose(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close()
+	}))
+	defer s.Close()
+
+	conn, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ReadMessage()
+	closeErr := &websocket.CloseError{}
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormal {
+		t.Errorf("EXPECTED: close %d\nACTUAL: %v", websocket.CloseNormal, err)
+	}
+
+	if err := conn.WriteMessage([]byte("{}")); !errors.Is(err, websocket.ErrClosed) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", websocket.ErrClosed, err)
+	}
+}
+
+func TestDialHandshakeFailure(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	_, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	if !errors.Is(err, websocket.ErrHandshake) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", websocket.ErrHandshake, err)
+	}
+}
+
+func TestDialUnsupportedScheme(t *testing.T) {
+	if _, err := websocket.Dial(context.Background(), "http://localhost", nil); err == nil {
+		t.Error("EXPECTED: error for a http url\nACTUAL: <nil>")
+	}
+}
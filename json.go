@@ -0,0 +1,1008 @@
+package kraken
+
+import "encoding/json"
+
+// jsonErrors result errors marshalled as their API error strings, errors are
+// parsed back into KrakenErrors when unmarshalled
+type jsonErrors []error
+
+// MarshalJSON marshal the errors as an array of API error strings
+func (e jsonErrors) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+
+	errStrings := make([]string, len(e))
+	for i, err := range e {
+		if krakenErr, ok := err.(*KrakenError); ok {
+			errStrings[i] = krakenErr.apiError()
+			continue
+		}
+
+		errStrings[i] = err.Error()
+	}
+
+	return json.Marshal(errStrings)
+}
+
+// UnmarshalJSON unmarshal an array of API error strings
+func (e *jsonErrors) UnmarshalJSON(data []byte) error {
+	var errStrings []string
+	if err := json.Unmarshal(data, &errStrings); err != nil {
+		return err
+	}
+
+	if errStrings == nil {
+		*e = nil
+		return nil
+	}
+
+	p := Parser{}
+	errs := make([]error, len(errStrings))
+	for i, errString := range errStrings {
+		errs[i] = p.parseError(errString)
+	}
+
+	*e = errs
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r Time) MarshalJSON() ([]byte, error) {
+	type result Time
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *Time) UnmarshalJSON(data []byte) error {
+	type result Time
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r SystemStatus) MarshalJSON() ([]byte, error) {
+	type result SystemStatus
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *SystemStatus) UnmarshalJSON(data []byte) error {
+	type result SystemStatus
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r Assets) MarshalJSON() ([]byte, error) {
+	type result Assets
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *Assets) UnmarshalJSON(data []byte) error {
+	type result Assets
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r AssetPairs) MarshalJSON() ([]byte, error) {
+	type result AssetPairs
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *AssetPairs) UnmarshalJSON(data []byte) error {
+	type result AssetPairs
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r Tickers) MarshalJSON() ([]byte, error) {
+	type result Tickers
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *Tickers) UnmarshalJSON(data []byte) error {
+	type result Tickers
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r OHLCs) MarshalJSON() ([]byte, error) {
+	type result OHLCs
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *OHLCs) UnmarshalJSON(data []byte) error {
+	type result OHLCs
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r OrderBook) MarshalJSON() ([]byte, error) {
+	type result OrderBook
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *OrderBook) UnmarshalJSON(data []byte) error {
+	type result OrderBook
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r RecentTrades) MarshalJSON() ([]byte, error) {
+	type result RecentTrades
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *RecentTrades) UnmarshalJSON(data []byte) error {
+	type result RecentTrades
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r RecentSpreads) MarshalJSON() ([]byte, error) {
+	type result RecentSpreads
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *RecentSpreads) UnmarshalJSON(data []byte) error {
+	type result RecentSpreads
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r TradeBalance) MarshalJSON() ([]byte, error) {
+	type result TradeBalance
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *TradeBalance) UnmarshalJSON(data []byte) error {
+	type result TradeBalance
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r OpenOrders) MarshalJSON() ([]byte, error) {
+	type result OpenOrders
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *OpenOrders) UnmarshalJSON(data []byte) error {
+	type result OpenOrders
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r ClosedOrders) MarshalJSON() ([]byte, error) {
+	type result ClosedOrders
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *ClosedOrders) UnmarshalJSON(data []byte) error {
+	type result ClosedOrders
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r OrdersInfo) MarshalJSON() ([]byte, error) {
+	type result OrdersInfo
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *OrdersInfo) UnmarshalJSON(data []byte) error {
+	type result OrdersInfo
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r TradesHistory) MarshalJSON() ([]byte, error) {
+	type result TradesHistory
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *TradesHistory) UnmarshalJSON(data []byte) error {
+	type result TradesHistory
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r OpenPositions) MarshalJSON() ([]byte, error) {
+	type result OpenPositions
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *OpenPositions) UnmarshalJSON(data []byte) error {
+	type result OpenPositions
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r Ledgers) MarshalJSON() ([]byte, error) {
+	type result Ledgers
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *Ledgers) UnmarshalJSON(data []byte) error {
+	type result Ledgers
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r LedgersInfo) MarshalJSON() ([]byte, error) {
+	type result LedgersInfo
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *LedgersInfo) UnmarshalJSON(data []byte) error {
+	type result LedgersInfo
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r TradeVolume) MarshalJSON() ([]byte, error) {
+	type result TradeVolume
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *TradeVolume) UnmarshalJSON(data []byte) error {
+	type result TradeVolume
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r AddOrderResult) MarshalJSON() ([]byte, error) {
+	type result AddOrderResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *AddOrderResult) UnmarshalJSON(data []byte) error {
+	type result AddOrderResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r EditOrderResult) MarshalJSON() ([]byte, error) {
+	type result EditOrderResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *EditOrderResult) UnmarshalJSON(data []byte) error {
+	type result EditOrderResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r CancelResult) MarshalJSON() ([]byte, error) {
+	type result CancelResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *CancelResult) UnmarshalJSON(data []byte) error {
+	type result CancelResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r CancelAllOrdersAfterResult) MarshalJSON() ([]byte, error) {
+	type result CancelAllOrdersAfterResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *CancelAllOrdersAfterResult) UnmarshalJSON(data []byte) error {
+	type result CancelAllOrdersAfterResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r DepositMethods) MarshalJSON() ([]byte, error) {
+	type result DepositMethods
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *DepositMethods) UnmarshalJSON(data []byte) error {
+	type result DepositMethods
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r DepositAddresses) MarshalJSON() ([]byte, error) {
+	type result DepositAddresses
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *DepositAddresses) UnmarshalJSON(data []byte) error {
+	type result DepositAddresses
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r DepositStatus) MarshalJSON() ([]byte, error) {
+	type result DepositStatus
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *DepositStatus) UnmarshalJSON(data []byte) error {
+	type result DepositStatus
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r WithdrawInfo) MarshalJSON() ([]byte, error) {
+	type result WithdrawInfo
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *WithdrawInfo) UnmarshalJSON(data []byte) error {
+	type result WithdrawInfo
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r WithdrawResult) MarshalJSON() ([]byte, error) {
+	type result WithdrawResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *WithdrawResult) UnmarshalJSON(data []byte) error {
+	type result WithdrawResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r WithdrawStatus) MarshalJSON() ([]byte, error) {
+	type result WithdrawStatus
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *WithdrawStatus) UnmarshalJSON(data []byte) error {
+	type result WithdrawStatus
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r WithdrawCancelResult) MarshalJSON() ([]byte, error) {
+	type result WithdrawCancelResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *WithdrawCancelResult) UnmarshalJSON(data []byte) error {
+	type result WithdrawCancelResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r WalletTransferResult) MarshalJSON() ([]byte, error) {
+	type result WalletTransferResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *WalletTransferResult) UnmarshalJSON(data []byte) error {
+	type result WalletTransferResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r EarnStrategies) MarshalJSON() ([]byte, error) {
+	type result EarnStrategies
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *EarnStrategies) UnmarshalJSON(data []byte) error {
+	type result EarnStrategies
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r EarnAllocationResult) MarshalJSON() ([]byte, error) {
+	type result EarnAllocationResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *EarnAllocationResult) UnmarshalJSON(data []byte) error {
+	type result EarnAllocationResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r EarnAllocationStatus) MarshalJSON() ([]byte, error) {
+	type result EarnAllocationStatus
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *EarnAllocationStatus) UnmarshalJSON(data []byte) error {
+	type result EarnAllocationStatus
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r EarnAllocations) MarshalJSON() ([]byte, error) {
+	type result EarnAllocations
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *EarnAllocations) UnmarshalJSON(data []byte) error {
+	type result EarnAllocations
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r AddExportResult) MarshalJSON() ([]byte, error) {
+	type result AddExportResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *AddExportResult) UnmarshalJSON(data []byte) error {
+	type result AddExportResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r ExportStatus) MarshalJSON() ([]byte, error) {
+	type result ExportStatus
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *ExportStatus) UnmarshalJSON(data []byte) error {
+	type result ExportStatus
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r RemoveExportResult) MarshalJSON() ([]byte, error) {
+	type result RemoveExportResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *RemoveExportResult) UnmarshalJSON(data []byte) error {
+	type result RemoveExportResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r CreateSubaccountResult) MarshalJSON() ([]byte, error) {
+	type result CreateSubaccountResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *CreateSubaccountResult) UnmarshalJSON(data []byte) error {
+	type result CreateSubaccountResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r AccountTransferResult) MarshalJSON() ([]byte, error) {
+	type result AccountTransferResult
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *AccountTransferResult) UnmarshalJSON(data []byte) error {
+	type result AccountTransferResult
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
+
+// MarshalJSON marshal the result with errors as API error strings
+func (r WebSocketsToken) MarshalJSON() ([]byte, error) {
+	type result WebSocketsToken
+	return json.Marshal(struct {
+		result
+		Errors jsonErrors
+	}{result(r), jsonErrors(r.Errors)})
+}
+
+// UnmarshalJSON unmarshal a result marshalled with MarshalJSON
+func (r *WebSocketsToken) UnmarshalJSON(data []byte) error {
+	type result WebSocketsToken
+	v := struct {
+		*result
+		Errors jsonErrors
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	r.Errors = v.Errors
+	return nil
+}
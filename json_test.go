@@ -0,0 +1,145 @@
+package kraken_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+func TestResultJSONRoundTrip(t *testing.T) {
+	errs := kraken.Time{}
+	if err := (&kraken.Parser{}).Parse([]byte(`{"error":["EQuery:Unknown asset pair","WGeneral:Danger advisory","unexpected error"]}`), &errs); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []interface{}{
+		kraken.Time{},
+		kraken.SystemStatus{},
+		kraken.Assets{},
+		kraken.AssetPairs{},
+		kraken.Tickers{},
+		kraken.OHLCs{},
+		kraken.OrderBook{},
+		kraken.RecentTrades{},
+		kraken.RecentSpreads{},
+		kraken.TradeBalance{},
+		kraken.OpenOrders{},
+		kraken.ClosedOrders{},
+		kraken.OrdersInfo{},
+		kraken.TradesHistory{},
+		kraken.OpenPositions{},
+		kraken.Ledgers{},
+		kraken.LedgersInfo{},
+		kraken.TradeVolume{},
+		kraken.AddOrderResult{},
+		kraken.EditOrderResult{},
+		kraken.CancelResult{},
+		kraken.CancelAllOrdersAfterResult{},
+		kraken.DepositMethods{},
+		kraken.DepositAddresses{},
+		kraken.DepositStatus{},
+		kraken.WithdrawInfo{},
+		kraken.WithdrawResult{},
+		kraken.WithdrawStatus{},
+		kraken.WithdrawCancelResult{},
+		kraken.WalletTransferResult{},
+		kraken.EarnStrategies{},
+		kraken.EarnAllocationResult{},
+		kraken.EarnAllocationStatus{},
+		kraken.EarnAllocations{},
+		kraken.AddExportResult{},
+		kraken.ExportStatus{},
+		kraken.RemoveExportResult{},
+		kraken.CreateSubaccountResult{},
+		kraken.AccountTransferResult{},
+		kraken.WebSocketsToken{},
+	}
+
+	for _, result := range results {
+		t.Run(reflect.TypeOf(result).Name(), func(t *testing.T) {
+			expected := reflect.New(reflect.TypeOf(result))
+			fill(expected.Elem())
+			expected.Elem().FieldByName("Errors").Set(reflect.ValueOf(errs.Errors))
+			expected.Elem().FieldByName("Warnings").Set(reflect.ValueOf(errs.Warnings))
+
+			data, err := json.Marshal(expected.Elem().Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Contains(data, []byte(`["EQuery:Unknown asset pair","unexpected error"]`)) {
+				t.Errorf("errors not marshalled as API error strings: %s", data)
+			}
+
+			actual := reflect.New(reflect.TypeOf(result))
+			if err := json.Unmarshal(data, actual.Interface()); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(expected.Elem().Interface(), actual.Elem().Interface()); diff != nil {
+				t.Error(diff)
+			}
+
+			actualErrs := actual.Elem().FieldByName("Errors").Interface().([]error)
+			if !errors.Is(actualErrs[0], kraken.ErrUnknownAssetPair) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrUnknownAssetPair, actualErrs[0])
+			}
+
+			warnings := actual.Elem().FieldByName("Warnings").Interface().([]kraken.KrakenError)
+			if !errors.Is(&warnings[0], kraken.ErrGeneral) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrGeneral, &warnings[0])
+			}
+		})
+	}
+}
+
+// fill populate every field reachable from v with a non-zero value
+func fill(v reflect.Value) {
+	switch v.Interface().(type) {
+	case decimal.Decimal:
+		v.Set(reflect.ValueOf(decimal.New(12345, -3)))
+		return
+	case time.Time:
+		v.Set(reflect.ValueOf(time.Unix(1644189769, 912200000).UTC()))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(value)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	}
+}
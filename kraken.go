@@ -2,6 +2,10 @@ package kraken
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -14,29 +18,73 @@ type Client interface {
 	Status(ctx context.Context) (SystemStatus, error)
 	Assets(ctx context.Context) (Assets, error)
 	AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error)
+	Tickers(ctx context.Context, pairs ...string) (Tickers, error)
 	OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error)
 	OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error)
 	RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error)
-	RecentSpreads(ctx context.Context, pairs []string, since *uint64) (RecentSpreads, error)
+	RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error)
+	TradeBalance(ctx context.Context, asset string) (TradeBalance, error)
+	OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error)
+	ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error)
+	QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error)
+	TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error)
+	OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error)
+	Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error)
+	QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error)
+	TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error)
+	AddOrder(ctx context.Context, req OrderRequest) (AddOrderResult, error)
+	CancelOrder(ctx context.Context, txid string) (CancelResult, error)
+	CancelAllOrders(ctx context.Context) (CancelResult, error)
+	CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error)
+	EditOrder(ctx context.Context, req EditOrderRequest) (EditOrderResult, error)
+	DepositMethods(ctx context.Context, asset string) (DepositMethods, error)
+	DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error)
+	DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error)
+	WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error)
+	Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error)
+	WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error)
+	WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error)
+	WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error)
+	EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error)
+	EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error)
+	EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error)
+	EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error)
+	EarnAllocations(ctx context.Context) (EarnAllocations, error)
+	AddExport(ctx context.Context, req ExportRequest) (AddExportResult, error)
+	ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error)
+	RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error)
+	RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error)
+	CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error)
+	AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error)
+	GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error)
 }
 
 // Time a parsed response from the "/public/Time" API endpoint
 type Time struct {
 	Errors    []error
+	Warnings  []KrakenError
+	Raw       []byte
 	Timestamp time.Time
 }
 
 // SystemStatus a parsed response from the "/public/SystemStatus" API endpoint
 type SystemStatus struct {
-	Errors    []error
-	Status    string
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Status   SystemStatusValue
+	// RawStatus the status as returned by the API, useful when Status is
+	// SystemStatusValueUnknown
+	RawStatus string
 	Timestamp time.Time
 }
 
 // Assets a parsed response from the "/public/Assets" API endpoint
 type Assets struct {
-	Errors []error
-	Assets map[string]Asset
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Assets   map[string]Asset
 }
 
 // Asset a single parsed asset from the "/public/Assets" API endpoint
@@ -50,12 +98,16 @@ type Asset struct {
 
 // AssetPairs a parsed response from the "/public/AssetPairs" API endpoint
 type AssetPairs struct {
-	Errors []error
-	Pairs  map[string]AssetPair
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Pairs    map[string]AssetPair
 }
 
-// AssetPair a single parsed asset pair from the "/public/AssetPairs" API endpoint
+// AssetPair a single parsed asset pair from the "/public/AssetPairs" API endpoint,
+// only the fields included in the Info shape requested are populated
 type AssetPair struct {
+	Info              AssetPairInfo
 	AltName           string
 	WebSocketName     string
 	AssetClassBase    string
@@ -73,19 +125,28 @@ type AssetPair struct {
 	FeeVolumeCurrency string
 	MarginCalls       int
 	MarginStop        int
-	OrderMin          float32
+	OrderMin          decimal.Decimal
+	CostMin           decimal.Decimal
+	TickSize          decimal.Decimal
+	Status            AssetPairStatus
+	// LongPositionLimit and ShortPositionLimit are the maximum long and
+	// short margin positions in the base asset
+	LongPositionLimit  int
+	ShortPositionLimit int
 }
 
 // Fee a single parsed fee from the from the "/public/AssetPairs" API endpoint
 type Fee struct {
 	Volume     int
-	Percentage float32
+	Percentage decimal.Decimal
 }
 
 // Tickers a parsed response from the "/public/Ticker" API endpoint
 type Tickers struct {
-	Errors []error
-	Result map[string]Ticker
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Result   map[string]Ticker
 }
 
 // Ticker a single parsed ticker from the "/public/Ticker" API endpoint
@@ -112,6 +173,8 @@ type AskBid struct {
 	Price     decimal.Decimal
 	Volume    decimal.Decimal
 	Timestamp time.Time
+	// WholeLotVolume is only populated for ticker asks and bids
+	WholeLotVolume decimal.Decimal
 }
 
 // Close a single parsed Close value from the "/public/Ticker" API endpoint
@@ -122,12 +185,15 @@ type Close struct {
 
 // OHLCs a parsed response from the "/public/OHLC" API endpoint
 type OHLCs struct {
-	Errors []error
-	Result map[string][]OHLC
-	LastID uint64
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Result   map[string][]OHLC
+	LastID   uint64
 }
 
-// OHLC a single parsed OHLC value from the "/public/OHLC" API endpoint
+// OHLC a single parsed OHLC value from the "/public/OHLC" API endpoint,
+// Synthetic is set on the flat candles inserted by FillOHLCGaps
 type OHLC struct {
 	Time                       time.Time
 	Open                       decimal.Decimal
@@ -137,20 +203,165 @@ type OHLC struct {
 	Volume                     decimal.Decimal
 	VolumeWeightedAveragePrice decimal.Decimal
 	Count                      uint64
+	Synthetic                  bool
+}
+
+// OHLCUpdate a parsed message from the "ohlc-N" websocket channel, the candle
+// is in progress until EndTime and its Time is the start of the interval
+type OHLCUpdate struct {
+	Pair    string
+	OHLC    OHLC
+	EndTime time.Time
+}
+
+// OHLCGap a gap between consecutive updates of a pair's OHLC subscription,
+// no update was received for the candles ending after PreviousEndTime and
+// before EndTime. The API sends no update for a candle without trades so a
+// gap is not always a missed update. An EndTime before PreviousEndTime is an
+// update received out of order
+type OHLCGap struct {
+	Pair            string
+	Interval        OHLCInterval
+	PreviousEndTime time.Time
+	EndTime         time.Time
+}
+
+// TradeUpdate a single trade parsed from the "trade" websocket channel
+type TradeUpdate struct {
+	Pair  string
+	Trade RecentTrade
+}
+
+// SpreadUpdate a single spread parsed from the "spread" websocket channel
+type SpreadUpdate struct {
+	Pair   string
+	Spread Spread
+}
+
+// BookUpdate a parsed message from the "book-N" websocket channel, either a
+// snapshot of the book to the subscribed depth or the levels changed since
+// the previous message. A level with a zero volume has been removed
+type BookUpdate struct {
+	Pair     string
+	Depth    int
+	Snapshot bool
+	Asks     []AskBid
+	Bids     []AskBid
+	// Checksum is the CRC32 of the top ten levels once an update has been
+	// applied, it is zero for snapshots
+	Checksum uint32
+}
+
+// OrderUpdate a parsed order from the "openOrders" websocket channel, the
+// order includes every field received for it since subscribing as updates
+// only include the fields which have changed
+type OrderUpdate struct {
+	Type           OrderUpdateType
+	Order          Order
+	PreviousStatus OrderStatus
+}
+
+// OrderUpdateType the type of an OrderUpdate
+type OrderUpdateType byte
+
+// String return a string value of the order update type
+func (t OrderUpdateType) String() string {
+	switch t {
+	case OrderUpdateTypeNew:
+		return "new"
+	case OrderUpdateTypeStatus:
+		return "status"
+	case OrderUpdateTypeChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// OrderUpdateTypeNew enum representing the first update of an order,
+	// including the orders open when subscribing
+	OrderUpdateTypeNew = iota
+	// OrderUpdateTypeStatus enum representing an order moving from
+	// PreviousStatus to the status of the order
+	OrderUpdateTypeStatus
+	// OrderUpdateTypeChanged enum representing a change to an order other
+	// than its status, such as its executed volume
+	OrderUpdateTypeChanged
+	// OrderUpdateTypeUnknown enum representing an unknown update type
+	OrderUpdateTypeUnknown
+)
+
+// WSBackpressure the behaviour of a websocket subscription when its buffer
+// is full
+type WSBackpressure byte
+
+// String return a string value of the backpressure behaviour
+func (b WSBackpressure) String() string {
+	switch b {
+	case WSBackpressureBlock:
+		return "block"
+	case WSBackpressureDrop:
+		return "drop"
+	case WSBackpressureDropOldest:
+		return "drop-oldest"
+	default:
+		return "unknown"
+	}
 }
 
-// OrderBook a parsed response from the "/public/OrderBook" API endpoint
+const (
+	// WSBackpressureBlock stop reading from the connection until the buffer
+	// has space, delaying the messages of every subscription
+	WSBackpressureBlock = iota
+	// WSBackpressureDrop drop the newest message, reporting ErrWSDropped to
+	// the error handler
+	WSBackpressureDrop
+	// WSBackpressureDropOldest drop the oldest buffered message to make space
+	// for the newest, reporting ErrWSDropped to the error handler
+	WSBackpressureDropOldest
+)
+
+// WSVersion the version of the websocket API protocol
+type WSVersion byte
+
+// String return a string value of the protocol version
+func (v WSVersion) String() string {
+	switch v {
+	case WSVersion1:
+		return "v1"
+	case WSVersion2:
+		return "v2"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// WSVersion1 the protocol of positional array channel messages
+	WSVersion1 = iota
+	// WSVersion2 the protocol of JSON object messages, only public channels
+	// are supported
+	WSVersion2
+)
+
+// OrderBook a parsed response from the "/public/Depth" API endpoint, asks are
+// in ascending and bids in descending price order
 type OrderBook struct {
-	Errors []error
-	Asks   map[string][]AskBid
-	Bids   map[string][]AskBid
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Asks     map[string][]AskBid
+	Bids     map[string][]AskBid
 }
 
 // RecentTrades a parsed response from the "/public/Trades" API endpoint
 type RecentTrades struct {
-	Errors []error
-	Trades map[string][]RecentTrade
-	LastID uint64
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Trades   map[string][]RecentTrade
+	LastID   uint64
 }
 
 // RecentTrade a single parsed trade value from the "/public/Trade" API endpoint
@@ -161,13 +372,17 @@ type RecentTrade struct {
 	Action        OrderAction
 	Type          OrderType
 	Miscellaneous string
+	// TradeID is zero for responses that predate trade IDs
+	TradeID uint64
 }
 
 // RecentSpreads a parsed respones from the "/public/Spread" API endpoint
 type RecentSpreads struct {
-	Errors  []error
-	Spreads map[string][]Spread
-	LastID  uint64
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Spreads  map[string][]Spread
+	LastID   uint64
 }
 
 // Spread a single parsed spread value from the "/public/Spread" API endpoint
@@ -177,6 +392,723 @@ type Spread struct {
 	Ask       decimal.Decimal
 }
 
+// TradeBalance a parsed response from the "/private/TradeBalance" API endpoint
+type TradeBalance struct {
+	Errors            []error
+	Warnings          []KrakenError
+	Raw               []byte
+	EquivalentBalance decimal.Decimal
+	TradeBalance      decimal.Decimal
+	MarginUsed        decimal.Decimal
+	UnrealizedPnL     decimal.Decimal
+	CostBasis         decimal.Decimal
+	FloatingValuation decimal.Decimal
+	Equity            decimal.Decimal
+	FreeMargin        decimal.Decimal
+	MarginLevel       decimal.Decimal
+	UnexecutedValue   decimal.Decimal
+}
+
+// OpenOrders a parsed response from the "/private/OpenOrders" API endpoint
+type OpenOrders struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Orders   map[string]Order
+}
+
+// ClosedOrders a parsed response from the "/private/ClosedOrders" API endpoint
+type ClosedOrders struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Orders   map[string]Order
+	Count    uint64
+}
+
+// ClosedOrdersQuery optional parameters used in closed orders queries
+type ClosedOrdersQuery struct {
+	Trades    bool
+	UserRef   *int32
+	Start     HistoryBound
+	End       HistoryBound
+	Offset    uint64
+	CloseTime CloseTime
+}
+
+// OrdersInfo a parsed response from the "/private/QueryOrders" API endpoint
+type OrdersInfo struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Orders   map[string]Order
+}
+
+// Order a single parsed order from the "/private/OpenOrders",
+// "/private/ClosedOrders" and "/private/QueryOrders" API endpoints
+type Order struct {
+	TransactionID  string
+	ReferenceID    string
+	UserReference  int32
+	Status         OrderStatus
+	OpenTime       time.Time
+	StartTime      time.Time
+	ExpireTime     time.Time
+	CloseTime      time.Time
+	CloseReason    string
+	Description    OrderDescription
+	Volume         decimal.Decimal
+	VolumeExecuted decimal.Decimal
+	Cost           decimal.Decimal
+	Fee            decimal.Decimal
+	AveragePrice   decimal.Decimal
+	StopPrice      decimal.Decimal
+	LimitPrice     decimal.Decimal
+	Miscellaneous  string
+	Flags          []string
+	Trades         []string
+}
+
+// OrderDescription a parsed description of an order from the
+// "/private/OpenOrders", "/private/ClosedOrders" and "/private/QueryOrders"
+// API endpoints
+type OrderDescription struct {
+	Pair           string
+	Action         OrderAction
+	Type           OrderType
+	Price          decimal.Decimal
+	SecondaryPrice decimal.Decimal
+	Leverage       string
+	Order          string
+	Close          string
+}
+
+// TradesHistory a parsed response from the "/private/TradesHistory" API
+// endpoint
+type TradesHistory struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Trades   map[string]TradeHistoryEntry
+	Count    uint64
+}
+
+// TradesHistoryQuery optional parameters used in trades history queries
+type TradesHistoryQuery struct {
+	Type   TradesHistoryType
+	Trades bool
+	Start  HistoryBound
+	End    HistoryBound
+	Offset uint64
+}
+
+// TradeHistoryEntry a single parsed trade from the "/private/TradesHistory"
+// API endpoint
+type TradeHistoryEntry struct {
+	TransactionID         string
+	OrderTransactionID    string
+	PositionTransactionID string
+	Pair                  string
+	Time                  time.Time
+	Action                OrderAction
+	Type                  OrderType
+	Price                 decimal.Decimal
+	Cost                  decimal.Decimal
+	Fee                   decimal.Decimal
+	Volume                decimal.Decimal
+	Margin                decimal.Decimal
+	Miscellaneous         string
+}
+
+// OpenPositions a parsed response from the "/private/OpenPositions" API
+// endpoint
+type OpenPositions struct {
+	Errors    []error
+	Warnings  []KrakenError
+	Raw       []byte
+	Positions map[string]Position
+}
+
+// Position a single parsed position from the "/private/OpenPositions" API
+// endpoint, Value and NetPnL are only populated when the positions are queried
+// with calculations
+type Position struct {
+	TransactionID      string
+	OrderTransactionID string
+	Status             string
+	Pair               string
+	Time               time.Time
+	Action             OrderAction
+	Type               OrderType
+	Cost               decimal.Decimal
+	Fee                decimal.Decimal
+	Volume             decimal.Decimal
+	VolumeClosed       decimal.Decimal
+	Margin             decimal.Decimal
+	Value              decimal.Decimal
+	NetPnL             decimal.Decimal
+	Terms              string
+	RolloverTime       time.Time
+	Miscellaneous      string
+	Flags              []string
+}
+
+// Ledgers a parsed response from the "/private/Ledgers" API endpoint
+type Ledgers struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Entries  map[string]LedgerEntry
+	Count    uint64
+}
+
+// LedgersQuery optional parameters used in ledgers queries
+type LedgersQuery struct {
+	Assets     []string
+	AssetClass string
+	Type       LedgerType
+	Start      HistoryBound
+	End        HistoryBound
+	Offset     uint64
+}
+
+// LedgersInfo a parsed response from the "/private/QueryLedgers" API endpoint
+type LedgersInfo struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Entries  map[string]LedgerEntry
+}
+
+// LedgerEntry a single parsed ledger entry from the "/private/Ledgers" and
+// "/private/QueryLedgers" API endpoints
+type LedgerEntry struct {
+	ID          string
+	ReferenceID string
+	Time        time.Time
+	Type        LedgerType
+	SubType     string
+	AssetClass  string
+	Asset       string
+	Amount      decimal.Decimal
+	Fee         decimal.Decimal
+	Balance     decimal.Decimal
+}
+
+// TradeVolume a parsed response from the "/private/TradeVolume" API endpoint
+type TradeVolume struct {
+	Errors    []error
+	Warnings  []KrakenError
+	Raw       []byte
+	Currency  string
+	Volume    decimal.Decimal
+	FeesTaker map[string]TradeVolumeFee
+	FeesMaker map[string]TradeVolumeFee
+}
+
+// TradeVolumeFee a single parsed pair fee from the "/private/TradeVolume" API
+// endpoint, Next is nil when the account is in the highest fee tier
+type TradeVolumeFee struct {
+	Current Fee
+	Next    *Fee
+	Minimum decimal.Decimal
+	Maximum decimal.Decimal
+}
+
+// OrderRequest parameters used to place an order with the "/private/AddOrder"
+// API endpoint, zero prices, times and leverage are not sent. Prices and
+// volume are sent exactly as given unless AssetPair is set, in which case
+// prices are rounded to the nearest tick and volume down to the lot precision
+// of the pair, unless ExactValues is also set
+type OrderRequest struct {
+	Pair           string
+	Action         OrderAction
+	Type           OrderType
+	Volume         decimal.Decimal
+	Price          decimal.Decimal
+	SecondaryPrice decimal.Decimal
+	Leverage       string
+	Flags          []string
+	StartTime      time.Time
+	ExpireTime     time.Time
+	UserReference  *int32
+	ValidateOnly   bool
+	AssetPair      *AssetPair
+	ExactValues    bool
+}
+
+// round return the order request with its prices and volume rounded to its
+// AssetPair, unless it has none or ExactValues is set
+func (r OrderRequest) round() OrderRequest {
+	if r.AssetPair == nil || r.ExactValues {
+		return r
+	}
+
+	r.Volume = r.AssetPair.RoundVolume(r.Volume, RoundingModeFloor)
+	if !r.Price.IsZero() {
+		r.Price = r.AssetPair.RoundPrice(r.Price, RoundingModeNearest)
+	}
+
+	if !r.SecondaryPrice.IsZero() {
+		r.SecondaryPrice = r.AssetPair.RoundPrice(r.SecondaryPrice, RoundingModeNearest)
+	}
+
+	return r
+}
+
+// validate check the order request has the parameters required by its order
+// type
+func (r OrderRequest) validate() error {
+	if r.Pair == "" {
+		return fmt.Errorf("pair is required")
+	}
+
+	if r.Action != OrderActionBuy && r.Action != OrderActionSell {
+		return fmt.Errorf("unsupported order action %s", r.Action)
+	}
+
+	if r.Type >= OrderTypeUnknown {
+		return fmt.Errorf("unsupported order type %s", r.Type)
+	}
+
+	if !r.Volume.IsPositive() {
+		return fmt.Errorf("volume must be positive")
+	}
+
+	switch r.Type {
+	case OrderTypeMarket, OrderTypeSettlePosition:
+	default:
+		if !r.Price.IsPositive() {
+			return fmt.Errorf("price is required for %s orders", r.Type)
+		}
+	}
+
+	switch r.Type {
+	case OrderTypeStopLossLimit, OrderTypeTakeProfitLimit:
+		if !r.SecondaryPrice.IsPositive() {
+			return fmt.Errorf("secondary price is required for %s orders", r.Type)
+		}
+	}
+
+	return nil
+}
+
+// prepare round and validate the order request before it is sent by either
+// AddOrder, checking it against its AssetPair with ValidateOrder when
+// validateOrders is set
+func (r OrderRequest) prepare(validateOrders bool) (OrderRequest, error) {
+	r = r.round()
+	if err := r.validate(); err != nil {
+		return r, err
+	}
+
+	if validateOrders && r.AssetPair != nil {
+		if errs := ValidateOrder(r, *r.AssetPair); len(errs) != 0 {
+			return r, errors.Join(errs...)
+		}
+	}
+
+	return r, nil
+}
+
+// AddOrderResult a parsed response from the "/private/AddOrder" API endpoint
+type AddOrderResult struct {
+	Errors           []error
+	Warnings         []KrakenError
+	Raw              []byte
+	Description      string
+	CloseDescription string
+	TransactionIDs   []string
+}
+
+// EditOrderRequest parameters used to amend an open order with the
+// "/private/EditOrder" API endpoint, the order is identified by either its
+// transaction ID or user reference ID, zero values are left unchanged
+type EditOrderRequest struct {
+	TransactionID  string
+	Pair           string
+	Volume         decimal.Decimal
+	Price          decimal.Decimal
+	SecondaryPrice decimal.Decimal
+	Flags          []string
+	UserReference  *int32
+	ValidateOnly   bool
+}
+
+// EditOrderResult a parsed response from the "/private/EditOrder" API
+// endpoint. A successful edit replaces the original order with a new one, in
+// which case OriginalDeprecated is set and the order must be tracked by
+// TransactionID from then on
+type EditOrderResult struct {
+	Errors                []error
+	Warnings              []KrakenError
+	Raw                   []byte
+	Status                string
+	ErrorMessage          string
+	TransactionID         string
+	OriginalTransactionID string
+	OriginalDeprecated    bool
+	Volume                decimal.Decimal
+	Price                 decimal.Decimal
+	SecondaryPrice        decimal.Decimal
+	OrdersCancelled       uint64
+	Description           string
+}
+
+// CancelResult a parsed response from the "/private/CancelOrder" and
+// "/private/CancelAll" API endpoints
+type CancelResult struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Count    uint64
+	Pending  bool
+}
+
+// CancelAllOrdersAfterResult a parsed response from the
+// "/private/CancelAllOrdersAfter" API endpoint, TriggerTime is zero when the
+// switch is disabled
+type CancelAllOrdersAfterResult struct {
+	Errors      []error
+	Warnings    []KrakenError
+	Raw         []byte
+	CurrentTime time.Time
+	TriggerTime time.Time
+}
+
+// DepositMethods a parsed response from the "/private/DepositMethods" API
+// endpoint
+type DepositMethods struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Methods  []DepositMethod
+}
+
+// DepositMethod a single parsed deposit method from the
+// "/private/DepositMethods" API endpoint, Limit is nil when there is no limit
+// on the amount that can be deposited
+type DepositMethod struct {
+	Name            string
+	Limit           *decimal.Decimal
+	Fee             decimal.Decimal
+	AddressSetupFee decimal.Decimal
+	GenerateAddress bool
+	Minimum         decimal.Decimal
+}
+
+// DepositAddresses a parsed response from the "/private/DepositAddresses" API
+// endpoint
+type DepositAddresses struct {
+	Errors    []error
+	Warnings  []KrakenError
+	Raw       []byte
+	Addresses []DepositAddress
+}
+
+// DepositAddress a single parsed deposit address from the
+// "/private/DepositAddresses" API endpoint, ExpireTime is zero for addresses
+// which do not expire
+type DepositAddress struct {
+	Address    string
+	ExpireTime time.Time
+	New        bool
+	Tag        string
+}
+
+// DepositStatus a parsed response from the "/private/DepositStatus" API
+// endpoint
+type DepositStatus struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Deposits []FundingTransaction
+}
+
+// WithdrawInfo a parsed response from the "/private/WithdrawInfo" API endpoint
+type WithdrawInfo struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Method   string
+	Limit    decimal.Decimal
+	Amount   decimal.Decimal
+	Fee      decimal.Decimal
+}
+
+// WithdrawResult a parsed response from the "/private/Withdraw" API endpoint
+type WithdrawResult struct {
+	Errors      []error
+	Warnings    []KrakenError
+	Raw         []byte
+	ReferenceID string
+}
+
+// WithdrawStatus a parsed response from the "/private/WithdrawStatus" API
+// endpoint
+type WithdrawStatus struct {
+	Errors      []error
+	Warnings    []KrakenError
+	Raw         []byte
+	Withdrawals []FundingTransaction
+}
+
+// WithdrawCancelResult a parsed response from the "/private/WithdrawCancel"
+// API endpoint
+type WithdrawCancelResult struct {
+	Errors    []error
+	Warnings  []KrakenError
+	Raw       []byte
+	Cancelled bool
+}
+
+// WalletTransferResult a parsed response from the "/private/WalletTransfer"
+// API endpoint
+type WalletTransferResult struct {
+	Errors      []error
+	Warnings    []KrakenError
+	Raw         []byte
+	ReferenceID string
+}
+
+// EarnStrategies a parsed response from the "/private/Earn/Strategies" API
+// endpoint, NextCursor is empty once the last page has been returned
+type EarnStrategies struct {
+	Errors     []error
+	Warnings   []KrakenError
+	Raw        []byte
+	Strategies []EarnStrategy
+	NextCursor string
+}
+
+// EarnStrategy a single parsed strategy from the "/private/Earn/Strategies"
+// API endpoint, periods are reported by Kraken in seconds
+type EarnStrategy struct {
+	ID                string
+	Asset             string
+	LockType          EarnLockType
+	APRLow            decimal.Decimal
+	APRHigh           decimal.Decimal
+	BondingPeriod     time.Duration
+	UnbondingPeriod   time.Duration
+	MinimumAllocation decimal.Decimal
+	AllocationFee     decimal.Decimal
+	DeallocationFee   decimal.Decimal
+	CanAllocate       bool
+	CanDeallocate     bool
+}
+
+// EarnAllocationResult a parsed response from the "/private/Earn/Allocate"
+// and "/private/Earn/Deallocate" API endpoints
+type EarnAllocationResult struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Accepted bool
+}
+
+// EarnAllocationStatus a parsed response from the
+// "/private/Earn/AllocateStatus" API endpoint
+type EarnAllocationStatus struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Pending  bool
+}
+
+// EarnAllocations a parsed response from the "/private/Earn/Allocations" API
+// endpoint, totals are reported in the converted asset
+type EarnAllocations struct {
+	Errors         []error
+	Warnings       []KrakenError
+	Raw            []byte
+	ConvertedAsset string
+	TotalAllocated decimal.Decimal
+	TotalRewarded  decimal.Decimal
+	Allocations    []EarnAllocation
+}
+
+// EarnAllocation a single parsed allocation from the
+// "/private/Earn/Allocations" API endpoint, amounts are in the native asset
+type EarnAllocation struct {
+	StrategyID string
+	Asset      string
+	Allocated  decimal.Decimal
+	Pending    decimal.Decimal
+	Rewarded   decimal.Decimal
+}
+
+// ExportRequest the parameters of a report export to be requested from the
+// "/private/AddExport" API endpoint, all fields are exported when Fields is
+// empty
+type ExportRequest struct {
+	Report      ExportReport
+	Format      ExportFormat
+	Description string
+	Fields      []string
+	Start       time.Time
+	End         time.Time
+}
+
+// AddExportResult a parsed response from the "/private/AddExport" API
+// endpoint
+type AddExportResult struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	ID       string
+}
+
+// ExportStatus a parsed response from the "/private/ExportStatus" API endpoint
+type ExportStatus struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Exports  []Export
+}
+
+// Export a single parsed report export from the "/private/ExportStatus" API
+// endpoint
+type Export struct {
+	ID            string
+	Description   string
+	Format        ExportFormat
+	Report        ExportReport
+	Status        string
+	Fields        []string
+	CreatedTime   time.Time
+	ExpireTime    time.Time
+	StartTime     time.Time
+	CompletedTime time.Time
+	DataStartTime time.Time
+	DataEndTime   time.Time
+	AssetClass    string
+	Asset         string
+}
+
+// RemoveExportResult a parsed response from the "/private/RemoveExport" API
+// endpoint
+type RemoveExportResult struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Removed  bool
+}
+
+// CreateSubaccountResult a parsed response from the "/private/CreateSubaccount"
+// API endpoint
+type CreateSubaccountResult struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Created  bool
+}
+
+// AccountTransferResult a parsed response from the "/private/AccountTransfer"
+// API endpoint
+type AccountTransferResult struct {
+	Errors     []error
+	Warnings   []KrakenError
+	Raw        []byte
+	TransferID string
+	Status     string
+}
+
+// WebSocketsToken a parsed response from the "/private/GetWebSocketsToken"
+// API endpoint, the token must be used to connect within Expires
+type WebSocketsToken struct {
+	Errors   []error
+	Warnings []KrakenError
+	Raw      []byte
+	Token    string
+	Expires  time.Duration
+}
+
+// FundingTransaction a single parsed deposit or withdrawal from the
+// "/private/DepositStatus" and "/private/WithdrawStatus" API endpoints
+type FundingTransaction struct {
+	Method         string
+	AssetClass     string
+	Asset          string
+	ReferenceID    string
+	TransactionID  string
+	Info           string
+	Amount         decimal.Decimal
+	Fee            decimal.Decimal
+	Time           time.Time
+	Status         string
+	StatusProperty string
+}
+
+// ExportReport the type of report to export
+type ExportReport string
+
+const (
+	// ExportReportTrades export of the trade history
+	ExportReportTrades = ExportReport("trades")
+	// ExportReportLedgers export of the ledger entries
+	ExportReportLedgers = ExportReport("ledgers")
+)
+
+// ExportFormat the file format of a report export
+type ExportFormat string
+
+const (
+	// ExportFormatCSV comma separated values
+	ExportFormatCSV = ExportFormat("CSV")
+	// ExportFormatTSV tab separated values
+	ExportFormatTSV = ExportFormat("TSV")
+)
+
+// ExportRemoval how a report export is removed, a queued or processing export
+// is cancelled and a processed export is deleted
+type ExportRemoval string
+
+const (
+	// ExportRemovalCancel cancel a queued or processing export
+	ExportRemovalCancel = ExportRemoval("cancel")
+	// ExportRemovalDelete delete a processed export
+	ExportRemovalDelete = ExportRemoval("delete")
+)
+
+// EarnLockType the lock type of an earn strategy
+type EarnLockType string
+
+const (
+	// EarnLockTypeFlex funds can be deallocated at any time
+	EarnLockTypeFlex = EarnLockType("flex")
+	// EarnLockTypeBonded funds are subject to bonding and unbonding periods
+	EarnLockTypeBonded = EarnLockType("bonded")
+	// EarnLockTypeTimed funds are locked for a fixed period
+	EarnLockTypeTimed = EarnLockType("timed")
+	// EarnLockTypeInstant funds can be deallocated instantly
+	EarnLockTypeInstant = EarnLockType("instant")
+)
+
+// Wallet a Kraken wallet funds can be transferred between
+type Wallet byte
+
+// String return a string value of the wallet
+func (w Wallet) String() string {
+	switch w {
+	case WalletSpot:
+		return "Spot Wallet"
+	case WalletFutures:
+		return "Futures Wallet"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// WalletSpot enum representing the spot wallet
+	WalletSpot = iota
+	// WalletFutures enum representing the futures wallet
+	WalletFutures
+	// WalletUnknown enum representing an unknown wallet
+	WalletUnknown
+)
+
 // OrderAction an action of a trade, either buy or sell
 type OrderAction byte
 
@@ -201,7 +1133,7 @@ const (
 	OrderActionUnknown
 )
 
-// OrderType a type of trade, either market or limit
+// OrderType a type of order, such as market or limit
 type OrderType byte
 
 // String return a string value of the order type
@@ -211,6 +1143,16 @@ func (t OrderType) String() string {
 		return "market"
 	case OrderTypeLimit:
 		return "limit"
+	case OrderTypeStopLoss:
+		return "stop-loss"
+	case OrderTypeTakeProfit:
+		return "take-profit"
+	case OrderTypeStopLossLimit:
+		return "stop-loss-limit"
+	case OrderTypeTakeProfitLimit:
+		return "take-profit-limit"
+	case OrderTypeSettlePosition:
+		return "settle-position"
 	default:
 		return "unknown"
 	}
@@ -221,10 +1163,71 @@ const (
 	OrderTypeMarket = iota
 	// OrderTypeLimit enum representing a limit order
 	OrderTypeLimit
+	// OrderTypeStopLoss enum representing a stop loss order
+	OrderTypeStopLoss
+	// OrderTypeTakeProfit enum representing a take profit order
+	OrderTypeTakeProfit
+	// OrderTypeStopLossLimit enum representing a stop loss limit order
+	OrderTypeStopLossLimit
+	// OrderTypeTakeProfitLimit enum representing a take profit limit order
+	OrderTypeTakeProfitLimit
+	// OrderTypeSettlePosition enum representing a settle position order
+	OrderTypeSettlePosition
 	// OrderTypeUnknown enum representing an unknown order action
 	OrderTypeUnknown
 )
 
+const (
+	// OrderFlagPostOnly order flag for a post only limit order
+	OrderFlagPostOnly = "post"
+	// OrderFlagFeeInBase order flag to prefer fees in the base currency
+	OrderFlagFeeInBase = "fcib"
+	// OrderFlagFeeInQuote order flag to prefer fees in the quote currency
+	OrderFlagFeeInQuote = "fciq"
+	// OrderFlagNoMarketPriceProtection order flag to disable market price
+	// protection for market orders
+	OrderFlagNoMarketPriceProtection = "nompp"
+	// OrderFlagVolumeInQuote order flag for order volume expressed in the quote
+	// currency
+	OrderFlagVolumeInQuote = "viqc"
+)
+
+// OrderStatus the status of an order
+type OrderStatus byte
+
+// String return a string value of the order status
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderStatusPending:
+		return "pending"
+	case OrderStatusOpen:
+		return "open"
+	case OrderStatusClosed:
+		return "closed"
+	case OrderStatusCanceled:
+		return "canceled"
+	case OrderStatusExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// OrderStatusPending enum representing an order pending book entry
+	OrderStatusPending = iota
+	// OrderStatusOpen enum representing an open order
+	OrderStatusOpen
+	// OrderStatusClosed enum representing a closed order
+	OrderStatusClosed
+	// OrderStatusCanceled enum representing a canceled order
+	OrderStatusCanceled
+	// OrderStatusExpired enum representing an expired order
+	OrderStatusExpired
+	// OrderStatusUnknown enum representing an unknown order status
+	OrderStatusUnknown
+)
+
 // AssetPairInfo info values used in asset pair queries
 type AssetPairInfo string
 
@@ -239,6 +1242,174 @@ const (
 	AssetPairInfoMargin = AssetPairInfo("margin")
 )
 
+// SystemStatusValue the trading status of the exchange
+type SystemStatusValue byte
+
+// String return a string value of the system status
+func (s SystemStatusValue) String() string {
+	switch s {
+	case SystemStatusValueOnline:
+		return "online"
+	case SystemStatusValueMaintenance:
+		return "maintenance"
+	case SystemStatusValueCancelOnly:
+		return "cancel_only"
+	case SystemStatusValuePostOnly:
+		return "post_only"
+	default:
+		return "unknown"
+	}
+}
+
+// CanTrade return whether new orders can be placed, in post only mode only
+// post only limit orders are accepted
+func (s SystemStatusValue) CanTrade() bool {
+	return s == SystemStatusValueOnline || s == SystemStatusValuePostOnly
+}
+
+// CanCancel return whether open orders can be cancelled
+func (s SystemStatusValue) CanCancel() bool {
+	return s == SystemStatusValueOnline || s == SystemStatusValueCancelOnly || s == SystemStatusValuePostOnly
+}
+
+const (
+	// SystemStatusValueOnline enum representing the exchange operating normally
+	SystemStatusValueOnline = iota
+	// SystemStatusValueMaintenance enum representing the exchange being offline
+	// for maintenance
+	SystemStatusValueMaintenance
+	// SystemStatusValueCancelOnly enum representing the exchange only accepting
+	// order cancellations
+	SystemStatusValueCancelOnly
+	// SystemStatusValuePostOnly enum representing the exchange only accepting
+	// post only limit orders and cancellations
+	SystemStatusValuePostOnly
+	// SystemStatusValueUnknown enum representing an unknown system status
+	SystemStatusValueUnknown
+)
+
+// AssetPairStatus the trading status of an asset pair
+type AssetPairStatus byte
+
+// String return a string value of the asset pair status
+func (s AssetPairStatus) String() string {
+	switch s {
+	case AssetPairStatusOnline:
+		return "online"
+	case AssetPairStatusCancelOnly:
+		return "cancel_only"
+	case AssetPairStatusPostOnly:
+		return "post_only"
+	case AssetPairStatusReduceOnly:
+		return "reduce_only"
+	case AssetPairStatusLimitOnly:
+		return "limit_only"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// AssetPairStatusOnline enum representing a pair open for trading
+	AssetPairStatusOnline = iota
+	// AssetPairStatusCancelOnly enum representing a pair only accepting order
+	// cancellations
+	AssetPairStatusCancelOnly
+	// AssetPairStatusPostOnly enum representing a pair only accepting post only
+	// limit orders
+	AssetPairStatusPostOnly
+	// AssetPairStatusReduceOnly enum representing a pair only accepting orders
+	// that reduce positions
+	AssetPairStatusReduceOnly
+	// AssetPairStatusLimitOnly enum representing a pair only accepting limit
+	// orders
+	AssetPairStatusLimitOnly
+	// AssetPairStatusUnknown enum representing an unknown or omitted asset pair
+	// status
+	AssetPairStatusUnknown
+)
+
+// CloseTime which order time to use when filtering closed orders queries
+type CloseTime string
+
+const (
+	// CloseTimeOpen filter closed orders by their open time
+	CloseTimeOpen = CloseTime("open")
+	// CloseTimeClose filter closed orders by their close time
+	CloseTimeClose = CloseTime("close")
+	// CloseTimeBoth filter closed orders by both their open and close time
+	CloseTimeBoth = CloseTime("both")
+)
+
+// TradesHistoryType type values used in trades history queries
+type TradesHistoryType string
+
+const (
+	// TradesHistoryTypeAll "type" value used in trades history queries
+	TradesHistoryTypeAll = TradesHistoryType("all")
+	// TradesHistoryTypeAnyPosition "type" value used in trades history queries
+	TradesHistoryTypeAnyPosition = TradesHistoryType("any position")
+	// TradesHistoryTypeClosedPosition "type" value used in trades history
+	// queries
+	TradesHistoryTypeClosedPosition = TradesHistoryType("closed position")
+	// TradesHistoryTypeClosingPosition "type" value used in trades history
+	// queries
+	TradesHistoryTypeClosingPosition = TradesHistoryType("closing position")
+	// TradesHistoryTypeNoPosition "type" value used in trades history queries
+	TradesHistoryTypeNoPosition = TradesHistoryType("no position")
+)
+
+// LedgerType type values of ledger entries and used in ledgers queries
+type LedgerType string
+
+const (
+	// LedgerTypeAll "type" value used in ledgers queries
+	LedgerTypeAll = LedgerType("all")
+	// LedgerTypeTrade ledger entry type of a trade
+	LedgerTypeTrade = LedgerType("trade")
+	// LedgerTypeDeposit ledger entry type of a deposit
+	LedgerTypeDeposit = LedgerType("deposit")
+	// LedgerTypeWithdrawal ledger entry type of a withdrawal
+	LedgerTypeWithdrawal = LedgerType("withdrawal")
+	// LedgerTypeTransfer ledger entry type of a transfer
+	LedgerTypeTransfer = LedgerType("transfer")
+	// LedgerTypeMargin ledger entry type of a margin trade
+	LedgerTypeMargin = LedgerType("margin")
+	// LedgerTypeRollover ledger entry type of a margin position rollover
+	LedgerTypeRollover = LedgerType("rollover")
+	// LedgerTypeSpend ledger entry type of a spend
+	LedgerTypeSpend = LedgerType("spend")
+	// LedgerTypeReceive ledger entry type of a receive
+	LedgerTypeReceive = LedgerType("receive")
+	// LedgerTypeSettled ledger entry type of a settled margin position
+	LedgerTypeSettled = LedgerType("settled")
+	// LedgerTypeAdjustment ledger entry type of an adjustment
+	LedgerTypeAdjustment = LedgerType("adjustment")
+	// LedgerTypeCredit ledger entry type of a credit
+	LedgerTypeCredit = LedgerType("credit")
+	// LedgerTypeStaking ledger entry type of a staking reward or transfer
+	LedgerTypeStaking = LedgerType("staking")
+	// LedgerTypeSale ledger entry type of a sale
+	LedgerTypeSale = LedgerType("sale")
+	// LedgerTypeDividend ledger entry type of a dividend
+	LedgerTypeDividend = LedgerType("dividend")
+)
+
+// HistoryBound a start or end bound used in history queries, the Kraken API
+// accepts either a unix timestamp or a transaction ID, an empty bound is not
+// sent
+type HistoryBound string
+
+// HistoryBoundTime create a history bound from a time
+func HistoryBoundTime(t time.Time) HistoryBound {
+	return HistoryBound(strconv.FormatInt(t.Unix(), 10))
+}
+
+// HistoryBoundTransactionID create a history bound from a transaction ID
+func HistoryBoundTransactionID(txid string) HistoryBound {
+	return HistoryBound(txid)
+}
+
 // OHLCInterval interval value in OHLC queries
 type OHLCInterval int
 
@@ -262,3 +1433,8 @@ const (
 	// OHLCInterval15Days interval values in OHLC queries
 	OHLCInterval15Days = OHLCInterval(21600)
 )
+
+// Duration the length of an interval
+func (i OHLCInterval) Duration() time.Duration {
+	return time.Duration(i) * time.Minute
+}
@@ -0,0 +1,128 @@
+package kraken_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+func TestClientImplementations(t *testing.T) {
+	client := reflect.TypeOf((*kraken.Client)(nil)).Elem()
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	tcs := []struct {
+		name string
+		impl reflect.Type
+		// helpers exported methods taking a context which are intentionally
+		// not part of the Client interface
+		helpers map[string]bool
+	}{
+		{
+			name: "HTTPClient",
+			impl: reflect.TypeOf(&kraken.HTTPClient{}),
+			helpers: map[string]bool{
+				"KeepAlive":                  true,
+				"CancelOrderByUserReference": true,
+			},
+		},
+		{
+			name: "InstrumentationClient",
+			impl: reflect.TypeOf(&kraken.InstrumentationClient{}),
+		},
+		{
+			name: "LoggingClient",
+			impl: reflect.TypeOf(&kraken.LoggingClient{}),
+		},
+		{
+			name: "CachingClient",
+			impl: reflect.TypeOf(&kraken.CachingClient{}),
+		},
+		{
+			name: "CoalescingClient",
+			impl: reflect.TypeOf(&kraken.CoalescingClient{}),
+		},
+		{
+			name: "RateLimitedClient",
+			impl: reflect.TypeOf(&kraken.RateLimitedClient{}),
+		},
+		{
+			name: "MockClient",
+			impl: reflect.TypeOf(&krakentest.MockClient{}),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < client.NumMethod(); i++ {
+				expected := client.Method(i)
+
+				actual, ok := tc.impl.MethodByName(expected.Name)
+				if !ok {
+					t.Errorf("%s not implemented", expected.Name)
+					continue
+				}
+
+				// drop the receiver so the signatures are comparable
+				in := make([]reflect.Type, actual.Type.NumIn()-1)
+				for j := range in {
+					in[j] = actual.Type.In(j + 1)
+				}
+
+				out := make([]reflect.Type, actual.Type.NumOut())
+				for j := range out {
+					out[j] = actual.Type.Out(j)
+				}
+
+				signature := reflect.FuncOf(in, out, actual.Type.IsVariadic())
+				if signature != expected.Type {
+					t.Errorf("%s EXPECTED: %s\nACTUAL: %s", expected.Name, expected.Type, signature)
+				}
+			}
+
+			for i := 0; i < tc.impl.NumMethod(); i++ {
+				method := tc.impl.Method(i)
+				if method.Type.NumIn() < 2 || method.Type.In(1) != contextType {
+					continue
+				}
+
+				if _, ok := client.MethodByName(method.Name); !ok && !tc.helpers[method.Name] {
+					t.Errorf("%s missing from the Client interface", method.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestSystemStatusValue(t *testing.T) {
+	tcs := []struct {
+		status    kraken.SystemStatusValue
+		name      string
+		canTrade  bool
+		canCancel bool
+	}{
+		{status: kraken.SystemStatusValueOnline, name: "online", canTrade: true, canCancel: true},
+		{status: kraken.SystemStatusValueMaintenance, name: "maintenance"},
+		{status: kraken.SystemStatusValueCancelOnly, name: "cancel_only", canCancel: true},
+		{status: kraken.SystemStatusValuePostOnly, name: "post_only", canTrade: true, canCancel: true},
+		{status: kraken.SystemStatusValueUnknown, name: "unknown"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.status.String() != tc.name {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.name, tc.status)
+			}
+
+			if tc.status.CanTrade() != tc.canTrade {
+				t.Errorf("EXPECTED: CanTrade %t\nACTUAL: %t", tc.canTrade, tc.status.CanTrade())
+			}
+
+			if tc.status.CanCancel() != tc.canCancel {
+				t.Errorf("EXPECTED: CanCancel %t\nACTUAL: %t", tc.canCancel, tc.status.CanCancel())
+			}
+		})
+	}
+}
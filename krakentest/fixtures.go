@@ -0,0 +1,222 @@
+package krakentest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// scrubbedHeaders request headers which are never written to a fixture file
+var scrubbedHeaders = []string{"API-Key", "API-Sign"}
+
+// volatileParams request body params which are removed before a request is
+// recorded or matched, the nonce differs on every run and the otp is a secret
+var volatileParams = []string{"nonce", "otp"}
+
+// Interaction a recorded request and the response it received
+type Interaction struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest a recorded request, the host is not recorded so fixtures can
+// be replayed against any host. The path includes the base path, such as
+// "/0/public/Time", so fixtures must be replayed with the base path they were
+// recorded with
+type FixtureRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// FixtureResponse a recorded response
+type FixtureResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Recorder a http.RoundTripper which sends requests with another transport
+// and writes every request and response to a fixture file, sensitive headers
+// and params are scrubbed from the recording
+type Recorder struct {
+	path      string
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder helper function for creating a new Recorder, requests are sent
+// with http.DefaultTransport when no transport is given. The fixture file is
+// replaced on the first recorded request
+func NewRecorder(path string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{
+		path:      path,
+		transport: transport,
+	}
+}
+
+// RoundTrip send a request and record it along with its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixtureReq, err := newFixtureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, Interaction{
+		Request: fixtureReq,
+		Response: FixtureResponse{
+			Status: res.StatusCode,
+			Header: res.Header.Clone(),
+			Body:   string(body),
+		},
+	})
+
+	data, err := json.MarshalIndent(r.interactions, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(r.path, data, 0644); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Replayer a http.RoundTripper which serves responses from a fixture file
+// written by a Recorder. Requests are matched on method, path, query and body,
+// identical requests are served the recorded responses in order
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer helper function for creating a new Replayer from a fixture file
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+
+	return &Replayer{
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}, nil
+}
+
+// RoundTrip serve the first unused recorded response matching the request, an
+// error is returned when there is none
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixtureReq, err := newFixtureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		recorded := interaction.Request
+		if r.used[i] || recorded.Method != fixtureReq.Method || recorded.Path != fixtureReq.Path || recorded.Query != fixtureReq.Query || recorded.Body != fixtureReq.Body {
+			continue
+		}
+		r.used[i] = true
+
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response for %s %s?%s", fixtureReq.Method, fixtureReq.Path, fixtureReq.Query)
+}
+
+// newFixtureRequest return the normalized and scrubbed form of a request, the
+// request body is read and replaced so it can still be sent
+func newFixtureRequest(req *http.Request) (FixtureRequest, error) {
+	query, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		return FixtureRequest{}, err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return FixtureRequest{}, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		return FixtureRequest{}, err
+	}
+
+	for _, param := range volatileParams {
+		params.Del(param)
+	}
+
+	header := req.Header.Clone()
+	for _, key := range scrubbedHeaders {
+		header.Del(key)
+	}
+
+	if len(header) == 0 {
+		header = nil
+	}
+
+	return FixtureRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  query.Encode(),
+		Header: header,
+		Body:   params.Encode(),
+	}, nil
+}
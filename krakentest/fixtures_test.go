@@ -0,0 +1,111 @@
+package krakentest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+func TestRecordReplay(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/0/public/Time":
+			w.Write([]byte(`{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"}}`))
+		case "/0/private/TradeBalance":
+			w.Write([]byte(`{"error":[],"result":{"eb":"1.5","tb":"1.5","m":"0","n":"0","c":"0","v":"0","e":"1.5","mf":"1.5"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	recorder, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithBaseURL(s.URL+"/0"),
+		kraken.HTTPClientWithHTTPClient(&http.Client{Transport: krakentest.NewRecorder(path, nil)}),
+		kraken.HTTPClientWithAPIKey("key"),
+		kraken.HTTPClientWithSecret("c2VjcmV0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTime, err := recorder.Time(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBalance, err := recorder.TradeBalance(context.Background(), "ZUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scrubbed := range []string{"API-Key", "API-Sign", "nonce"} {
+		if strings.Contains(string(data), scrubbed) {
+			t.Errorf("fixture contains %s", scrubbed)
+		}
+	}
+
+	replay, err := krakentest.NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fixture is replayed against another host with the same base path,
+	// as fixtures recorded from the live API are replayed in tests
+	replayer, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithBaseURL("http://kraken.invalid/0"),
+		kraken.HTTPClientWithHTTPClient(&http.Client{Transport: replay}),
+		kraken.HTTPClientWithAPIKey("key"),
+		kraken.HTTPClientWithSecret("c2VjcmV0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualTime, err := replayer.Time(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expectedTime, actualTime); diff != nil {
+		t.Error(diff)
+	}
+
+	actualBalance, err := replayer.TradeBalance(context.Background(), "ZUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expectedBalance, actualBalance); diff != nil {
+		t.Error(diff)
+	}
+
+	if !actualBalance.EquivalentBalance.Equal(decimal.RequireFromString("1.5")) {
+		t.Errorf("EXPECTED: 1.5\nACTUAL: %s", actualBalance.EquivalentBalance)
+	}
+
+	// each recorded response is served once
+	if _, err := replayer.Time(context.Background()); err == nil {
+		t.Error("expected an error for an unrecorded request")
+	}
+
+	if _, err := replayer.TradeBalance(context.Background(), "XXBT"); err == nil {
+		t.Error("expected an error for an unrecorded request")
+	}
+}
@@ -0,0 +1,1411 @@
+// Package krakentest provides helpers for testing code which depends on the
+// kraken package
+package krakentest
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// Call a call made to a MockClient, the arguments exclude the context
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// TB the subset of testing.TB used to report unexpected calls
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// MockClient a Client whose behaviour is set per method with the OnX and
+// ReturnX functions. Every call is recorded with its arguments, a method with
+// no behaviour set returns its zero values and, when the client is strict,
+// fails the test
+type MockClient struct {
+	mu     sync.Mutex
+	t      TB
+	strict bool
+	calls  []Call
+
+	onTime                 func(ctx context.Context) (kraken.Time, error)
+	onStatus               func(ctx context.Context) (kraken.SystemStatus, error)
+	onAssets               func(ctx context.Context) (kraken.Assets, error)
+	onAssetPairs           func(ctx context.Context, info kraken.AssetPairInfo, pairs ...string) (kraken.AssetPairs, error)
+	onTickers              func(ctx context.Context, pairs ...string) (kraken.Tickers, error)
+	onOHLC                 func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error)
+	onOrderBook            func(ctx context.Context, count uint, pairs ...string) (kraken.OrderBook, error)
+	onRecentTrades         func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentTrades, error)
+	onRecentSpreads        func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentSpreads, error)
+	onTradeBalance         func(ctx context.Context, asset string) (kraken.TradeBalance, error)
+	onOpenOrders           func(ctx context.Context, trades bool, userref *int32) (kraken.OpenOrders, error)
+	onClosedOrders         func(ctx context.Context, query kraken.ClosedOrdersQuery) (kraken.ClosedOrders, error)
+	onQueryOrders          func(ctx context.Context, trades bool, userref *int32, txids ...string) (kraken.OrdersInfo, error)
+	onTradesHistory        func(ctx context.Context, query kraken.TradesHistoryQuery) (kraken.TradesHistory, error)
+	onOpenPositions        func(ctx context.Context, docalcs bool, txids ...string) (kraken.OpenPositions, error)
+	onLedgers              func(ctx context.Context, query kraken.LedgersQuery) (kraken.Ledgers, error)
+	onQueryLedgers         func(ctx context.Context, ids ...string) (kraken.LedgersInfo, error)
+	onTradeVolume          func(ctx context.Context, pairs ...string) (kraken.TradeVolume, error)
+	onAddOrder             func(ctx context.Context, req kraken.OrderRequest) (kraken.AddOrderResult, error)
+	onCancelOrder          func(ctx context.Context, txid string) (kraken.CancelResult, error)
+	onCancelAllOrders      func(ctx context.Context) (kraken.CancelResult, error)
+	onCancelAllOrdersAfter func(ctx context.Context, timeout time.Duration) (kraken.CancelAllOrdersAfterResult, error)
+	onEditOrder            func(ctx context.Context, req kraken.EditOrderRequest) (kraken.EditOrderResult, error)
+	onDepositMethods       func(ctx context.Context, asset string) (kraken.DepositMethods, error)
+	onDepositAddresses     func(ctx context.Context, asset, method string, new bool) (kraken.DepositAddresses, error)
+	onDepositStatus        func(ctx context.Context, asset, method string) (kraken.DepositStatus, error)
+	onWithdrawInfo         func(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawInfo, error)
+	onWithdraw             func(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawResult, error)
+	onWithdrawStatus       func(ctx context.Context, asset, method string) (kraken.WithdrawStatus, error)
+	onWithdrawCancel       func(ctx context.Context, asset, refid string) (kraken.WithdrawCancelResult, error)
+	onWalletTransfer       func(ctx context.Context, asset string, from, to kraken.Wallet, amount decimal.Decimal) (kraken.WalletTransferResult, error)
+	onEarnStrategies       func(ctx context.Context, asset, cursor string) (kraken.EarnStrategies, error)
+	onEarnAllocate         func(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error)
+	onEarnDeallocate       func(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error)
+	onEarnAllocationStatus func(ctx context.Context, strategyID string) (kraken.EarnAllocationStatus, error)
+	onEarnAllocations      func(ctx context.Context) (kraken.EarnAllocations, error)
+	onAddExport            func(ctx context.Context, req kraken.ExportRequest) (kraken.AddExportResult, error)
+	onExportStatus         func(ctx context.Context, report kraken.ExportReport) (kraken.ExportStatus, error)
+	onRetrieveExport       func(ctx context.Context, id string) (io.ReadCloser, error)
+	onRemoveExport         func(ctx context.Context, id string, removal kraken.ExportRemoval) (kraken.RemoveExportResult, error)
+	onCreateSubaccount     func(ctx context.Context, username, email string) (kraken.CreateSubaccountResult, error)
+	onAccountTransfer      func(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (kraken.AccountTransferResult, error)
+	onGetWebSocketsToken   func(ctx context.Context) (kraken.WebSocketsToken, error)
+}
+
+var _ kraken.Client = (*MockClient)(nil)
+
+// NewMockClient helper function for creating a new mock client
+func NewMockClient(t TB, opts ...MockOption) (*MockClient, error) {
+	m := MockClient{
+		t: t,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&m); err != nil {
+			return nil, err
+		}
+	}
+
+	return &m, nil
+}
+
+// Calls return the calls made to the client in the order they were made
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo return the calls made to a method in the order they were made
+func (m *MockClient) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// record append a call, failing the test when the call is unexpected and the
+// client is strict, the caller must hold the lock
+func (m *MockClient) record(method string, unexpected bool, args ...interface{}) {
+	m.calls = append(m.calls, Call{
+		Method: method,
+		Args:   args,
+	})
+
+	if unexpected && m.strict {
+		m.t.Helper()
+		m.t.Errorf("unexpected call to %s with arguments %v", method, args)
+	}
+}
+
+// OnTime set the function called by Time
+func (m *MockClient) OnTime(fn func(ctx context.Context) (kraken.Time, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onTime = fn
+}
+
+// ReturnTime set the result returned by Time
+func (m *MockClient) ReturnTime(res kraken.Time, err error) {
+	m.OnTime(func(ctx context.Context) (kraken.Time, error) {
+		return res, err
+	})
+}
+
+// Time records the call and returns the result of the Time handler
+func (m *MockClient) Time(ctx context.Context) (kraken.Time, error) {
+	m.mu.Lock()
+	fn := m.onTime
+	m.record("Time", fn == nil)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.Time{}, nil
+	}
+
+	return fn(ctx)
+}
+
+// OnStatus set the function called by Status
+func (m *MockClient) OnStatus(fn func(ctx context.Context) (kraken.SystemStatus, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onStatus = fn
+}
+
+// ReturnStatus set the result returned by Status
+func (m *MockClient) ReturnStatus(res kraken.SystemStatus, err error) {
+	m.OnStatus(func(ctx context.Context) (kraken.SystemStatus, error) {
+		return res, err
+	})
+}
+
+// Status records the call and returns the result of the Status handler
+func (m *MockClient) Status(ctx context.Context) (kraken.SystemStatus, error) {
+	m.mu.Lock()
+	fn := m.onStatus
+	m.record("Status", fn == nil)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.SystemStatus{}, nil
+	}
+
+	return fn(ctx)
+}
+
+// OnAssets set the function called by Assets
+func (m *MockClient) OnAssets(fn func(ctx context.Context) (kraken.Assets, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onAssets = fn
+}
+
+// ReturnAssets set the result returned by Assets
+func (m *MockClient) ReturnAssets(res kraken.Assets, err error) {
+	m.OnAssets(func(ctx context.Context) (kraken.Assets, error) {
+		return res, err
+	})
+}
+
+// Assets records the call and returns the result of the Assets handler
+func (m *MockClient) Assets(ctx context.Context) (kraken.Assets, error) {
+	m.mu.Lock()
+	fn := m.onAssets
+	m.record("Assets", fn == nil)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.Assets{}, nil
+	}
+
+	return fn(ctx)
+}
+
+// OnAssetPairs set the function called by AssetPairs
+func (m *MockClient) OnAssetPairs(fn func(ctx context.Context, info kraken.AssetPairInfo, pairs ...string) (kraken.AssetPairs, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onAssetPairs = fn
+}
+
+// ReturnAssetPairs set the result returned by AssetPairs
+func (m *MockClient) ReturnAssetPairs(res kraken.AssetPairs, err error) {
+	m.OnAssetPairs(func(ctx context.Context, info kraken.AssetPairInfo, pairs ...string) (kraken.AssetPairs, error) {
+		return res, err
+	})
+}
+
+// AssetPairs records the call and returns the result of the AssetPairs handler
+func (m *MockClient) AssetPairs(ctx context.Context, info kraken.AssetPairInfo, pairs ...string) (kraken.AssetPairs, error) {
+	m.mu.Lock()
+	fn := m.onAssetPairs
+	m.record("AssetPairs", fn == nil, info, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.AssetPairs{}, nil
+	}
+
+	return fn(ctx, info, pairs...)
+}
+
+// OnTickers set the function called by Tickers
+func (m *MockClient) OnTickers(fn func(ctx context.Context, pairs ...string) (kraken.Tickers, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onTickers = fn
+}
+
+// ReturnTickers set the result returned by Tickers
+func (m *MockClient) ReturnTickers(res kraken.Tickers, err error) {
+	m.OnTickers(func(ctx context.Context, pairs ...string) (kraken.Tickers, error) {
+		return res, err
+	})
+}
+
+// Tickers records the call and returns the result of the Tickers handler
+func (m *MockClient) Tickers(ctx context.Context, pairs ...string) (kraken.Tickers, error) {
+	m.mu.Lock()
+	fn := m.onTickers
+	m.record("Tickers", fn == nil, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.Tickers{}, nil
+	}
+
+	return fn(ctx, pairs...)
+}
+
+// OnOHLC set the function called by OHLC
+func (m *MockClient) OnOHLC(fn func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onOHLC = fn
+}
+
+// ReturnOHLC set the result returned by OHLC
+func (m *MockClient) ReturnOHLC(res kraken.OHLCs, err error) {
+	m.OnOHLC(func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+		return res, err
+	})
+}
+
+// OHLC records the call and returns the result of the OHLC handler
+func (m *MockClient) OHLC(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+	m.mu.Lock()
+	fn := m.onOHLC
+	m.record("OHLC", fn == nil, interval, since, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.OHLCs{}, nil
+	}
+
+	return fn(ctx, interval, since, pairs...)
+}
+
+// OnOrderBook set the function called by OrderBook
+func (m *MockClient) OnOrderBook(fn func(ctx context.Context, count uint, pairs ...string) (kraken.OrderBook, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onOrderBook = fn
+}
+
+// ReturnOrderBook set the result returned by OrderBook
+func (m *MockClient) ReturnOrderBook(res kraken.OrderBook, err error) {
+	m.OnOrderBook(func(ctx context.Context, count uint, pairs ...string) (kraken.OrderBook, error) {
+		return res, err
+	})
+}
+
+// OrderBook records the call and returns the result of the OrderBook handler
+func (m *MockClient) OrderBook(ctx context.Context, count uint, pairs ...string) (kraken.OrderBook, error) {
+	m.mu.Lock()
+	fn := m.onOrderBook
+	m.record("OrderBook", fn == nil, count, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.OrderBook{}, nil
+	}
+
+	return fn(ctx, count, pairs...)
+}
+
+// OnRecentTrades set the function called by RecentTrades
+func (m *MockClient) OnRecentTrades(fn func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentTrades, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onRecentTrades = fn
+}
+
+// ReturnRecentTrades set the result returned by RecentTrades
+func (m *MockClient) ReturnRecentTrades(res kraken.RecentTrades, err error) {
+	m.OnRecentTrades(func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentTrades, error) {
+		return res, err
+	})
+}
+
+// RecentTrades records the call and returns the result of the RecentTrades
+// handler
+func (m *MockClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentTrades, error) {
+	m.mu.Lock()
+	fn := m.onRecentTrades
+	m.record("RecentTrades", fn == nil, since, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.RecentTrades{}, nil
+	}
+
+	return fn(ctx, since, pairs...)
+}
+
+// OnRecentSpreads set the function called by RecentSpreads
+func (m *MockClient) OnRecentSpreads(fn func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentSpreads, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onRecentSpreads = fn
+}
+
+// ReturnRecentSpreads set the result returned by RecentSpreads
+func (m *MockClient) ReturnRecentSpreads(res kraken.RecentSpreads, err error) {
+	m.OnRecentSpreads(func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentSpreads, error) {
+		return res, err
+	})
+}
+
+// RecentSpreads records the call and returns the result of the RecentSpreads
+// handler
+func (m *MockClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentSpreads, error) {
+	m.mu.Lock()
+	fn := m.onRecentSpreads
+	m.record("RecentSpreads", fn == nil, since, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.RecentSpreads{}, nil
+	}
+
+	return fn(ctx, since, pairs...)
+}
+
+// OnTradeBalance set the function called by TradeBalance
+func (m *MockClient) OnTradeBalance(fn func(ctx context.Context, asset string) (kraken.TradeBalance, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onTradeBalance = fn
+}
+
+// ReturnTradeBalance set the result returned by TradeBalance
+func (m *MockClient) ReturnTradeBalance(res kraken.TradeBalance, err error) {
+	m.OnTradeBalance(func(ctx context.Context, asset string) (kraken.TradeBalance, error) {
+		return res, err
+	})
+}
+
+// TradeBalance records the call and returns the result of the TradeBalance
+// handler
+func (m *MockClient) TradeBalance(ctx context.Context, asset string) (kraken.TradeBalance, error) {
+	m.mu.Lock()
+	fn := m.onTradeBalance
+	m.record("TradeBalance", fn == nil, asset)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.TradeBalance{}, nil
+	}
+
+	return fn(ctx, asset)
+}
+
+// OnOpenOrders set the function called by OpenOrders
+func (m *MockClient) OnOpenOrders(fn func(ctx context.Context, trades bool, userref *int32) (kraken.OpenOrders, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onOpenOrders = fn
+}
+
+// ReturnOpenOrders set the result returned by OpenOrders
+func (m *MockClient) ReturnOpenOrders(res kraken.OpenOrders, err error) {
+	m.OnOpenOrders(func(ctx context.Context, trades bool, userref *int32) (kraken.OpenOrders, error) {
+		return res, err
+	})
+}
+
+// OpenOrders records the call and returns the result of the OpenOrders handler
+func (m *MockClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (kraken.OpenOrders, error) {
+	m.mu.Lock()
+	fn := m.onOpenOrders
+	m.record("OpenOrders", fn == nil, trades, userref)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.OpenOrders{}, nil
+	}
+
+	return fn(ctx, trades, userref)
+}
+
+// OnClosedOrders set the function called by ClosedOrders
+func (m *MockClient) OnClosedOrders(fn func(ctx context.Context, query kraken.ClosedOrdersQuery) (kraken.ClosedOrders, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onClosedOrders = fn
+}
+
+// ReturnClosedOrders set the result returned by ClosedOrders
+func (m *MockClient) ReturnClosedOrders(res kraken.ClosedOrders, err error) {
+	m.OnClosedOrders(func(ctx context.Context, query kraken.ClosedOrdersQuery) (kraken.ClosedOrders, error) {
+		return res, err
+	})
+}
+
+// ClosedOrders records the call and returns the result of the ClosedOrders
+// handler
+func (m *MockClient) ClosedOrders(ctx context.Context, query kraken.ClosedOrdersQuery) (kraken.ClosedOrders, error) {
+	m.mu.Lock()
+	fn := m.onClosedOrders
+	m.record("ClosedOrders", fn == nil, query)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.ClosedOrders{}, nil
+	}
+
+	return fn(ctx, query)
+}
+
+// OnQueryOrders set the function called by QueryOrders
+func (m *MockClient) OnQueryOrders(fn func(ctx context.Context, trades bool, userref *int32, txids ...string) (kraken.OrdersInfo, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onQueryOrders = fn
+}
+
+// ReturnQueryOrders set the result returned by QueryOrders
+func (m *MockClient) ReturnQueryOrders(res kraken.OrdersInfo, err error) {
+	m.OnQueryOrders(func(ctx context.Context, trades bool, userref *int32, txids ...string) (kraken.OrdersInfo, error) {
+		return res, err
+	})
+}
+
+// QueryOrders records the call and returns the result of the QueryOrders
+// handler
+func (m *MockClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (kraken.OrdersInfo, error) {
+	m.mu.Lock()
+	fn := m.onQueryOrders
+	m.record("QueryOrders", fn == nil, trades, userref, txids)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.OrdersInfo{}, nil
+	}
+
+	return fn(ctx, trades, userref, txids...)
+}
+
+// OnTradesHistory set the function called by TradesHistory
+func (m *MockClient) OnTradesHistory(fn func(ctx context.Context, query kraken.TradesHistoryQuery) (kraken.TradesHistory, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onTradesHistory = fn
+}
+
+// ReturnTradesHistory set the result returned by TradesHistory
+func (m *MockClient) ReturnTradesHistory(res kraken.TradesHistory, err error) {
+	m.OnTradesHistory(func(ctx context.Context, query kraken.TradesHistoryQuery) (kraken.TradesHistory, error) {
+		return res, err
+	})
+}
+
+// TradesHistory records the call and returns the result of the TradesHistory
+// handler
+func (m *MockClient) TradesHistory(ctx context.Context, query kraken.TradesHistoryQuery) (kraken.TradesHistory, error) {
+	m.mu.Lock()
+	fn := m.onTradesHistory
+	m.record("TradesHistory", fn == nil, query)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.TradesHistory{}, nil
+	}
+
+	return fn(ctx, query)
+}
+
+// OnOpenPositions set the function called by OpenPositions
+func (m *MockClient) OnOpenPositions(fn func(ctx context.Context, docalcs bool, txids ...string) (kraken.OpenPositions, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onOpenPositions = fn
+}
+
+// ReturnOpenPositions set the result returned by OpenPositions
+func (m *MockClient) ReturnOpenPositions(res kraken.OpenPositions, err error) {
+	m.OnOpenPositions(func(ctx context.Context, docalcs bool, txids ...string) (kraken.OpenPositions, error) {
+		return res, err
+	})
+}
+
+// OpenPositions records the call and returns the result of the OpenPositions
+// handler
+func (m *MockClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (kraken.OpenPositions, error) {
+	m.mu.Lock()
+	fn := m.onOpenPositions
+	m.record("OpenPositions", fn == nil, docalcs, txids)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.OpenPositions{}, nil
+	}
+
+	return fn(ctx, docalcs, txids...)
+}
+
+// OnLedgers set the function called by Ledgers
+func (m *MockClient) OnLedgers(fn func(ctx context.Context, query kraken.LedgersQuery) (kraken.Ledgers, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onLedgers = fn
+}
+
+// ReturnLedgers set the result returned by Ledgers
+func (m *MockClient) ReturnLedgers(res kraken.Ledgers, err error) {
+	m.OnLedgers(func(ctx context.Context, query kraken.LedgersQuery) (kraken.Ledgers, error) {
+		return res, err
+	})
+}
+
+// Ledgers records the call and returns the result of the Ledgers handler
+func (m *MockClient) Ledgers(ctx context.Context, query kraken.LedgersQuery) (kraken.Ledgers, error) {
+	m.mu.Lock()
+	fn := m.onLedgers
+	m.record("Ledgers", fn == nil, query)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.Ledgers{}, nil
+	}
+
+	return fn(ctx, query)
+}
+
+// OnQueryLedgers set the function called by QueryLedgers
+func (m *MockClient) OnQueryLedgers(fn func(ctx context.Context, ids ...string) (kraken.LedgersInfo, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onQueryLedgers = fn
+}
+
+// ReturnQueryLedgers set the result returned by QueryLedgers
+func (m *MockClient) ReturnQueryLedgers(res kraken.LedgersInfo, err error) {
+	m.OnQueryLedgers(func(ctx context.Context, ids ...string) (kraken.LedgersInfo, error) {
+		return res, err
+	})
+}
+
+// QueryLedgers records the call and returns the result of the QueryLedgers
+// handler
+func (m *MockClient) QueryLedgers(ctx context.Context, ids ...string) (kraken.LedgersInfo, error) {
+	m.mu.Lock()
+	fn := m.onQueryLedgers
+	m.record("QueryLedgers", fn == nil, ids)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.LedgersInfo{}, nil
+	}
+
+	return fn(ctx, ids...)
+}
+
+// OnTradeVolume set the function called by TradeVolume
+func (m *MockClient) OnTradeVolume(fn func(ctx context.Context, pairs ...string) (kraken.TradeVolume, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onTradeVolume = fn
+}
+
+// ReturnTradeVolume set the result returned by TradeVolume
+func (m *MockClient) ReturnTradeVolume(res kraken.TradeVolume, err error) {
+	m.OnTradeVolume(func(ctx context.Context, pairs ...string) (kraken.TradeVolume, error) {
+		return res, err
+	})
+}
+
+// TradeVolume records the call and returns the result of the TradeVolume
+// handler
+func (m *MockClient) TradeVolume(ctx context.Context, pairs ...string) (kraken.TradeVolume, error) {
+	m.mu.Lock()
+	fn := m.onTradeVolume
+	m.record("TradeVolume", fn == nil, pairs)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.TradeVolume{}, nil
+	}
+
+	return fn(ctx, pairs...)
+}
+
+// OnAddOrder set the function called by AddOrder
+func (m *MockClient) OnAddOrder(fn func(ctx context.Context, req kraken.OrderRequest) (kraken.AddOrderResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onAddOrder = fn
+}
+
+// ReturnAddOrder set the result returned by AddOrder
+func (m *MockClient) ReturnAddOrder(res kraken.AddOrderResult, err error) {
+	m.OnAddOrder(func(ctx context.Context, req kraken.OrderRequest) (kraken.AddOrderResult, error) {
+		return res, err
+	})
+}
+
+// AddOrder records the call and returns the result of the AddOrder handler
+func (m *MockClient) AddOrder(ctx context.Context, req kraken.OrderRequest) (kraken.AddOrderResult, error) {
+	m.mu.Lock()
+	fn := m.onAddOrder
+	m.record("AddOrder", fn == nil, req)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.AddOrderResult{}, nil
+	}
+
+	return fn(ctx, req)
+}
+
+// OnCancelOrder set the function called by CancelOrder
+func (m *MockClient) OnCancelOrder(fn func(ctx context.Context, txid string) (kraken.CancelResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onCancelOrder = fn
+}
+
+// ReturnCancelOrder set the result returned by CancelOrder
+func (m *MockClient) ReturnCancelOrder(res kraken.CancelResult, err error) {
+	m.OnCancelOrder(func(ctx context.Context, txid string) (kraken.CancelResult, error) {
+		return res, err
+	})
+}
+
+// CancelOrder records the call and returns the result of the CancelOrder
+// handler
+func (m *MockClient) CancelOrder(ctx context.Context, txid string) (kraken.CancelResult, error) {
+	m.mu.Lock()
+	fn := m.onCancelOrder
+	m.record("CancelOrder", fn == nil, txid)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.CancelResult{}, nil
+	}
+
+	return fn(ctx, txid)
+}
+
+// OnCancelAllOrders set the function called by CancelAllOrders
+func (m *MockClient) OnCancelAllOrders(fn func(ctx context.Context) (kraken.CancelResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onCancelAllOrders = fn
+}
+
+// ReturnCancelAllOrders set the result returned by CancelAllOrders
+func (m *MockClient) ReturnCancelAllOrders(res kraken.CancelResult, err error) {
+	m.OnCancelAllOrders(func(ctx context.Context) (kraken.CancelResult, error) {
+		return res, err
+	})
+}
+
+// CancelAllOrders records the call and returns the result of the CancelAllOrders
+// handler
+func (m *MockClient) CancelAllOrders(ctx context.Context) (kraken.CancelResult, error) {
+	m.mu.Lock()
+	fn := m.onCancelAllOrders
+	m.record("CancelAllOrders", fn == nil)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.CancelResult{}, nil
+	}
+
+	return fn(ctx)
+}
+
+// OnCancelAllOrdersAfter set the function called by CancelAllOrdersAfter
+func (m *MockClient) OnCancelAllOrdersAfter(fn func(ctx context.Context, timeout time.Duration) (kraken.CancelAllOrdersAfterResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onCancelAllOrdersAfter = fn
+}
+
+// ReturnCancelAllOrdersAfter set the result returned by CancelAllOrdersAfter
+func (m *MockClient) ReturnCancelAllOrdersAfter(res kraken.CancelAllOrdersAfterResult, err error) {
+	m.OnCancelAllOrdersAfter(func(ctx context.Context, timeout time.Duration) (kraken.CancelAllOrdersAfterResult, error) {
+		return res, err
+	})
+}
+
+// CancelAllOrdersAfter records the call and returns the result of the CancelAllOrdersAfter
+// handler
+func (m *MockClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (kraken.CancelAllOrdersAfterResult, error) {
+	m.mu.Lock()
+	fn := m.onCancelAllOrdersAfter
+	m.record("CancelAllOrdersAfter", fn == nil, timeout)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.CancelAllOrdersAfterResult{}, nil
+	}
+
+	return fn(ctx, timeout)
+}
+
+// OnEditOrder set the function called by EditOrder
+func (m *MockClient) OnEditOrder(fn func(ctx context.Context, req kraken.EditOrderRequest) (kraken.EditOrderResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onEditOrder = fn
+}
+
+// ReturnEditOrder set the result returned by EditOrder
+func (m *MockClient) ReturnEditOrder(res kraken.EditOrderResult, err error) {
+	m.OnEditOrder(func(ctx context.Context, req kraken.EditOrderRequest) (kraken.EditOrderResult, error) {
+		return res, err
+	})
+}
+
+// EditOrder records the call and returns the result of the EditOrder handler
+func (m *MockClient) EditOrder(ctx context.Context, req kraken.EditOrderRequest) (kraken.EditOrderResult, error) {
+	m.mu.Lock()
+	fn := m.onEditOrder
+	m.record("EditOrder", fn == nil, req)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.EditOrderResult{}, nil
+	}
+
+	return fn(ctx, req)
+}
+
+// OnDepositMethods set the function called by DepositMethods
+func (m *MockClient) OnDepositMethods(fn func(ctx context.Context, asset string) (kraken.DepositMethods, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onDepositMethods = fn
+}
+
+// ReturnDepositMethods set the result returned by DepositMethods
+func (m *MockClient) ReturnDepositMethods(res kraken.DepositMethods, err error) {
+	m.OnDepositMethods(func(ctx context.Context, asset string) (kraken.DepositMethods, error) {
+		return res, err
+	})
+}
+
+// DepositMethods records the call and returns the result of the DepositMethods
+// handler
+func (m *MockClient) DepositMethods(ctx context.Context, asset string) (kraken.DepositMethods, error) {
+	m.mu.Lock()
+	fn := m.onDepositMethods
+	m.record("DepositMethods", fn == nil, asset)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.DepositMethods{}, nil
+	}
+
+	return fn(ctx, asset)
+}
+
+// OnDepositAddresses set the function called by DepositAddresses
+func (m *MockClient) OnDepositAddresses(fn func(ctx context.Context, asset, method string, new bool) (kraken.DepositAddresses, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onDepositAddresses = fn
+}
+
+// ReturnDepositAddresses set the result returned by DepositAddresses
+func (m *MockClient) ReturnDepositAddresses(res kraken.DepositAddresses, err error) {
+	m.OnDepositAddresses(func(ctx context.Context, asset, method string, new bool) (kraken.DepositAddresses, error) {
+		return res, err
+	})
+}
+
+// DepositAddresses records the call and returns the result of the DepositAddresses
+// handler
+func (m *MockClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (kraken.DepositAddresses, error) {
+	m.mu.Lock()
+	fn := m.onDepositAddresses
+	m.record("DepositAddresses", fn == nil, asset, method, new)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.DepositAddresses{}, nil
+	}
+
+	return fn(ctx, asset, method, new)
+}
+
+// OnDepositStatus set the function called by DepositStatus
+func (m *MockClient) OnDepositStatus(fn func(ctx context.Context, asset, method string) (kraken.DepositStatus, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onDepositStatus = fn
+}
+
+// ReturnDepositStatus set the result returned by DepositStatus
+func (m *MockClient) ReturnDepositStatus(res kraken.DepositStatus, err error) {
+	m.OnDepositStatus(func(ctx context.Context, asset, method string) (kraken.DepositStatus, error) {
+		return res, err
+	})
+}
+
+// DepositStatus records the call and returns the result of the DepositStatus
+// handler
+func (m *MockClient) DepositStatus(ctx context.Context, asset, method string) (kraken.DepositStatus, error) {
+	m.mu.Lock()
+	fn := m.onDepositStatus
+	m.record("DepositStatus", fn == nil, asset, method)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.DepositStatus{}, nil
+	}
+
+	return fn(ctx, asset, method)
+}
+
+// OnWithdrawInfo set the function called by WithdrawInfo
+func (m *MockClient) OnWithdrawInfo(fn func(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawInfo, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onWithdrawInfo = fn
+}
+
+// ReturnWithdrawInfo set the result returned by WithdrawInfo
+func (m *MockClient) ReturnWithdrawInfo(res kraken.WithdrawInfo, err error) {
+	m.OnWithdrawInfo(func(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawInfo, error) {
+		return res, err
+	})
+}
+
+// WithdrawInfo records the call and returns the result of the WithdrawInfo
+// handler
+func (m *MockClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawInfo, error) {
+	m.mu.Lock()
+	fn := m.onWithdrawInfo
+	m.record("WithdrawInfo", fn == nil, asset, key, amount)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.WithdrawInfo{}, nil
+	}
+
+	return fn(ctx, asset, key, amount)
+}
+
+// OnWithdraw set the function called by Withdraw
+func (m *MockClient) OnWithdraw(fn func(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onWithdraw = fn
+}
+
+// ReturnWithdraw set the result returned by Withdraw
+func (m *MockClient) ReturnWithdraw(res kraken.WithdrawResult, err error) {
+	m.OnWithdraw(func(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawResult, error) {
+		return res, err
+	})
+}
+
+// Withdraw records the call and returns the result of the Withdraw handler
+func (m *MockClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (kraken.WithdrawResult, error) {
+	m.mu.Lock()
+	fn := m.onWithdraw
+	m.record("Withdraw", fn == nil, asset, key, amount)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.WithdrawResult{}, nil
+	}
+
+	return fn(ctx, asset, key, amount)
+}
+
+// OnWithdrawStatus set the function called by WithdrawStatus
+func (m *MockClient) OnWithdrawStatus(fn func(ctx context.Context, asset, method string) (kraken.WithdrawStatus, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onWithdrawStatus = fn
+}
+
+// ReturnWithdrawStatus set the result returned by WithdrawStatus
+func (m *MockClient) ReturnWithdrawStatus(res kraken.WithdrawStatus, err error) {
+	m.OnWithdrawStatus(func(ctx context.Context, asset, method string) (kraken.WithdrawStatus, error) {
+		return res, err
+	})
+}
+
+// WithdrawStatus records the call and returns the result of the WithdrawStatus
+// handler
+func (m *MockClient) WithdrawStatus(ctx context.Context, asset, method string) (kraken.WithdrawStatus, error) {
+	m.mu.Lock()
+	fn := m.onWithdrawStatus
+	m.record("WithdrawStatus", fn == nil, asset, method)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.WithdrawStatus{}, nil
+	}
+
+	return fn(ctx, asset, method)
+}
+
+// OnWithdrawCancel set the function called by WithdrawCancel
+func (m *MockClient) OnWithdrawCancel(fn func(ctx context.Context, asset, refid string) (kraken.WithdrawCancelResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onWithdrawCancel = fn
+}
+
+// ReturnWithdrawCancel set the result returned by WithdrawCancel
+func (m *MockClient) ReturnWithdrawCancel(res kraken.WithdrawCancelResult, err error) {
+	m.OnWithdrawCancel(func(ctx context.Context, asset, refid string) (kraken.WithdrawCancelResult, error) {
+		return res, err
+	})
+}
+
+// WithdrawCancel records the call and returns the result of the WithdrawCancel
+// handler
+func (m *MockClient) WithdrawCancel(ctx context.Context, asset, refid string) (kraken.WithdrawCancelResult, error) {
+	m.mu.Lock()
+	fn := m.onWithdrawCancel
+	m.record("WithdrawCancel", fn == nil, asset, refid)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.WithdrawCancelResult{}, nil
+	}
+
+	return fn(ctx, asset, refid)
+}
+
+// OnWalletTransfer set the function called by WalletTransfer
+func (m *MockClient) OnWalletTransfer(fn func(ctx context.Context, asset string, from, to kraken.Wallet, amount decimal.Decimal) (kraken.WalletTransferResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onWalletTransfer = fn
+}
+
+// ReturnWalletTransfer set the result returned by WalletTransfer
+func (m *MockClient) ReturnWalletTransfer(res kraken.WalletTransferResult, err error) {
+	m.OnWalletTransfer(func(ctx context.Context, asset string, from, to kraken.Wallet, amount decimal.Decimal) (kraken.WalletTransferResult, error) {
+		return res, err
+	})
+}
+
+// WalletTransfer records the call and returns the result of the WalletTransfer
+// handler
+func (m *MockClient) WalletTransfer(ctx context.Context, asset string, from, to kraken.Wallet, amount decimal.Decimal) (kraken.WalletTransferResult, error) {
+	m.mu.Lock()
+	fn := m.onWalletTransfer
+	m.record("WalletTransfer", fn == nil, asset, from, to, amount)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.WalletTransferResult{}, nil
+	}
+
+	return fn(ctx, asset, from, to, amount)
+}
+
+// OnEarnStrategies set the function called by EarnStrategies
+func (m *MockClient) OnEarnStrategies(fn func(ctx context.Context, asset, cursor string) (kraken.EarnStrategies, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onEarnStrategies = fn
+}
+
+// ReturnEarnStrategies set the result returned by EarnStrategies
+func (m *MockClient) ReturnEarnStrategies(res kraken.EarnStrategies, err error) {
+	m.OnEarnStrategies(func(ctx context.Context, asset, cursor string) (kraken.EarnStrategies, error) {
+		return res, err
+	})
+}
+
+// EarnStrategies records the call and returns the result of the EarnStrategies
+// handler
+func (m *MockClient) EarnStrategies(ctx context.Context, asset, cursor string) (kraken.EarnStrategies, error) {
+	m.mu.Lock()
+	fn := m.onEarnStrategies
+	m.record("EarnStrategies", fn == nil, asset, cursor)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.EarnStrategies{}, nil
+	}
+
+	return fn(ctx, asset, cursor)
+}
+
+// OnEarnAllocate set the function called by EarnAllocate
+func (m *MockClient) OnEarnAllocate(fn func(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onEarnAllocate = fn
+}
+
+// ReturnEarnAllocate set the result returned by EarnAllocate
+func (m *MockClient) ReturnEarnAllocate(res kraken.EarnAllocationResult, err error) {
+	m.OnEarnAllocate(func(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error) {
+		return res, err
+	})
+}
+
+// EarnAllocate records the call and returns the result of the EarnAllocate
+// handler
+func (m *MockClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error) {
+	m.mu.Lock()
+	fn := m.onEarnAllocate
+	m.record("EarnAllocate", fn == nil, strategyID, amount)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.EarnAllocationResult{}, nil
+	}
+
+	return fn(ctx, strategyID, amount)
+}
+
+// OnEarnDeallocate set the function called by EarnDeallocate
+func (m *MockClient) OnEarnDeallocate(fn func(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onEarnDeallocate = fn
+}
+
+// ReturnEarnDeallocate set the result returned by EarnDeallocate
+func (m *MockClient) ReturnEarnDeallocate(res kraken.EarnAllocationResult, err error) {
+	m.OnEarnDeallocate(func(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error) {
+		return res, err
+	})
+}
+
+// EarnDeallocate records the call and returns the result of the EarnDeallocate
+// handler
+func (m *MockClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (kraken.EarnAllocationResult, error) {
+	m.mu.Lock()
+	fn := m.onEarnDeallocate
+	m.record("EarnDeallocate", fn == nil, strategyID, amount)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.EarnAllocationResult{}, nil
+	}
+
+	return fn(ctx, strategyID, amount)
+}
+
+// OnEarnAllocationStatus set the function called by EarnAllocationStatus
+func (m *MockClient) OnEarnAllocationStatus(fn func(ctx context.Context, strategyID string) (kraken.EarnAllocationStatus, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onEarnAllocationStatus = fn
+}
+
+// ReturnEarnAllocationStatus set the result returned by EarnAllocationStatus
+func (m *MockClient) ReturnEarnAllocationStatus(res kraken.EarnAllocationStatus, err error) {
+	m.OnEarnAllocationStatus(func(ctx context.Context, strategyID string) (kraken.EarnAllocationStatus, error) {
+		return res, err
+	})
+}
+
+// EarnAllocationStatus records the call and returns the result of the EarnAllocationStatus
+// handler
+func (m *MockClient) EarnAllocationStatus(ctx context.Context, strategyID string) (kraken.EarnAllocationStatus, error) {
+	m.mu.Lock()
+	fn := m.onEarnAllocationStatus
+	m.record("EarnAllocationStatus", fn == nil, strategyID)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.EarnAllocationStatus{}, nil
+	}
+
+	return fn(ctx, strategyID)
+}
+
+// OnEarnAllocations set the function called by EarnAllocations
+func (m *MockClient) OnEarnAllocations(fn func(ctx context.Context) (kraken.EarnAllocations, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onEarnAllocations = fn
+}
+
+// ReturnEarnAllocations set the result returned by EarnAllocations
+func (m *MockClient) ReturnEarnAllocations(res kraken.EarnAllocations, err error) {
+	m.OnEarnAllocations(func(ctx context.Context) (kraken.EarnAllocations, error) {
+		return res, err
+	})
+}
+
+// EarnAllocations records the call and returns the result of the EarnAllocations
+// handler
+func (m *MockClient) EarnAllocations(ctx context.Context) (kraken.EarnAllocations, error) {
+	m.mu.Lock()
+	fn := m.onEarnAllocations
+	m.record("EarnAllocations", fn == nil)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.EarnAllocations{}, nil
+	}
+
+	return fn(ctx)
+}
+
+// OnAddExport set the function called by AddExport
+func (m *MockClient) OnAddExport(fn func(ctx context.Context, req kraken.ExportRequest) (kraken.AddExportResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onAddExport = fn
+}
+
+// ReturnAddExport set the result returned by AddExport
+func (m *MockClient) ReturnAddExport(res kraken.AddExportResult, err error) {
+	m.OnAddExport(func(ctx context.Context, req kraken.ExportRequest) (kraken.AddExportResult, error) {
+		return res, err
+	})
+}
+
+// AddExport records the call and returns the result of the AddExport handler
+func (m *MockClient) AddExport(ctx context.Context, req kraken.ExportRequest) (kraken.AddExportResult, error) {
+	m.mu.Lock()
+	fn := m.onAddExport
+	m.record("AddExport", fn == nil, req)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.AddExportResult{}, nil
+	}
+
+	return fn(ctx, req)
+}
+
+// OnExportStatus set the function called by ExportStatus
+func (m *MockClient) OnExportStatus(fn func(ctx context.Context, report kraken.ExportReport) (kraken.ExportStatus, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onExportStatus = fn
+}
+
+// ReturnExportStatus set the result returned by ExportStatus
+func (m *MockClient) ReturnExportStatus(res kraken.ExportStatus, err error) {
+	m.OnExportStatus(func(ctx context.Context, report kraken.ExportReport) (kraken.ExportStatus, error) {
+		return res, err
+	})
+}
+
+// ExportStatus records the call and returns the result of the ExportStatus
+// handler
+func (m *MockClient) ExportStatus(ctx context.Context, report kraken.ExportReport) (kraken.ExportStatus, error) {
+	m.mu.Lock()
+	fn := m.onExportStatus
+	m.record("ExportStatus", fn == nil, report)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.ExportStatus{}, nil
+	}
+
+	return fn(ctx, report)
+}
+
+// OnRetrieveExport set the function called by RetrieveExport
+func (m *MockClient) OnRetrieveExport(fn func(ctx context.Context, id string) (io.ReadCloser, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onRetrieveExport = fn
+}
+
+// ReturnRetrieveExport set the result returned by RetrieveExport
+func (m *MockClient) ReturnRetrieveExport(res io.ReadCloser, err error) {
+	m.OnRetrieveExport(func(ctx context.Context, id string) (io.ReadCloser, error) {
+		return res, err
+	})
+}
+
+// RetrieveExport records the call and returns the result of the RetrieveExport
+// handler
+func (m *MockClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	fn := m.onRetrieveExport
+	m.record("RetrieveExport", fn == nil, id)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return nil, nil
+	}
+
+	return fn(ctx, id)
+}
+
+// OnRemoveExport set the function called by RemoveExport
+func (m *MockClient) OnRemoveExport(fn func(ctx context.Context, id string, removal kraken.ExportRemoval) (kraken.RemoveExportResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onRemoveExport = fn
+}
+
+// ReturnRemoveExport set the result returned by RemoveExport
+func (m *MockClient) ReturnRemoveExport(res kraken.RemoveExportResult, err error) {
+	m.OnRemoveExport(func(ctx context.Context, id string, removal kraken.ExportRemoval) (kraken.RemoveExportResult, error) {
+		return res, err
+	})
+}
+
+// RemoveExport records the call and returns the result of the RemoveExport
+// handler
+func (m *MockClient) RemoveExport(ctx context.Context, id string, removal kraken.ExportRemoval) (kraken.RemoveExportResult, error) {
+	m.mu.Lock()
+	fn := m.onRemoveExport
+	m.record("RemoveExport", fn == nil, id, removal)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.RemoveExportResult{}, nil
+	}
+
+	return fn(ctx, id, removal)
+}
+
+// OnCreateSubaccount set the function called by CreateSubaccount
+func (m *MockClient) OnCreateSubaccount(fn func(ctx context.Context, username, email string) (kraken.CreateSubaccountResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onCreateSubaccount = fn
+}
+
+// ReturnCreateSubaccount set the result returned by CreateSubaccount
+func (m *MockClient) ReturnCreateSubaccount(res kraken.CreateSubaccountResult, err error) {
+	m.OnCreateSubaccount(func(ctx context.Context, username, email string) (kraken.CreateSubaccountResult, error) {
+		return res, err
+	})
+}
+
+// CreateSubaccount records the call and returns the result of the CreateSubaccount
+// handler
+func (m *MockClient) CreateSubaccount(ctx context.Context, username, email string) (kraken.CreateSubaccountResult, error) {
+	m.mu.Lock()
+	fn := m.onCreateSubaccount
+	m.record("CreateSubaccount", fn == nil, username, email)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.CreateSubaccountResult{}, nil
+	}
+
+	return fn(ctx, username, email)
+}
+
+// OnAccountTransfer set the function called by AccountTransfer
+func (m *MockClient) OnAccountTransfer(fn func(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (kraken.AccountTransferResult, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onAccountTransfer = fn
+}
+
+// ReturnAccountTransfer set the result returned by AccountTransfer
+func (m *MockClient) ReturnAccountTransfer(res kraken.AccountTransferResult, err error) {
+	m.OnAccountTransfer(func(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (kraken.AccountTransferResult, error) {
+		return res, err
+	})
+}
+
+// AccountTransfer records the call and returns the result of the AccountTransfer
+// handler
+func (m *MockClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (kraken.AccountTransferResult, error) {
+	m.mu.Lock()
+	fn := m.onAccountTransfer
+	m.record("AccountTransfer", fn == nil, asset, amount, fromUser, toUser)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.AccountTransferResult{}, nil
+	}
+
+	return fn(ctx, asset, amount, fromUser, toUser)
+}
+
+// OnGetWebSocketsToken set the function called by GetWebSocketsToken
+func (m *MockClient) OnGetWebSocketsToken(fn func(ctx context.Context) (kraken.WebSocketsToken, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onGetWebSocketsToken = fn
+}
+
+// ReturnGetWebSocketsToken set the result returned by GetWebSocketsToken
+func (m *MockClient) ReturnGetWebSocketsToken(res kraken.WebSocketsToken, err error) {
+	m.OnGetWebSocketsToken(func(ctx context.Context) (kraken.WebSocketsToken, error) {
+		return res, err
+	})
+}
+
+// GetWebSocketsToken records the call and returns the result of the
+// GetWebSocketsToken handler
+func (m *MockClient) GetWebSocketsToken(ctx context.Context) (kraken.WebSocketsToken, error) {
+	m.mu.Lock()
+	fn := m.onGetWebSocketsToken
+	m.record("GetWebSocketsToken", fn == nil)
+	m.mu.Unlock()
+
+	if fn == nil {
+		return kraken.WebSocketsToken{}, nil
+	}
+
+	return fn(ctx)
+}
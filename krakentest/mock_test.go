@@ -0,0 +1,144 @@
+package krakentest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+// recorder records errors reported by a strict MockClient
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockClient(t *testing.T) {
+	errOHLC := errors.New("ohlc")
+	since := uint64(42)
+
+	tcs := []struct {
+		name          string
+		opts          []krakentest.MockOption
+		setup         func(m *krakentest.MockClient)
+		call          func(m *krakentest.MockClient) (interface{}, error)
+		expected      interface{}
+		expectedErr   error
+		expectedCalls []krakentest.Call
+		expectedFails int
+	}{
+		{
+			name: "On",
+			setup: func(m *krakentest.MockClient) {
+				m.OnOHLC(func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+					return kraken.OHLCs{LastID: *since}, nil
+				})
+			},
+			call: func(m *krakentest.MockClient) (interface{}, error) {
+				return m.OHLC(context.Background(), kraken.OHLCIntervalMinute, &since, "XBTUSD")
+			},
+			expected: kraken.OHLCs{LastID: 42},
+			expectedCalls: []krakentest.Call{
+				{Method: "OHLC", Args: []interface{}{kraken.OHLCIntervalMinute, &since, []string{"XBTUSD"}}},
+			},
+		},
+		{
+			name: "Return",
+			setup: func(m *krakentest.MockClient) {
+				m.ReturnOHLC(kraken.OHLCs{}, errOHLC)
+			},
+			call: func(m *krakentest.MockClient) (interface{}, error) {
+				return m.OHLC(context.Background(), kraken.OHLCIntervalMinute, nil)
+			},
+			expected:    kraken.OHLCs{},
+			expectedErr: errOHLC,
+			expectedCalls: []krakentest.Call{
+				{Method: "OHLC", Args: []interface{}{kraken.OHLCIntervalMinute, (*uint64)(nil), []string(nil)}},
+			},
+		},
+		{
+			name: "Unexpected",
+			call: func(m *krakentest.MockClient) (interface{}, error) {
+				return m.TradeBalance(context.Background(), "ZUSD")
+			},
+			expected: kraken.TradeBalance{},
+			expectedCalls: []krakentest.Call{
+				{Method: "TradeBalance", Args: []interface{}{"ZUSD"}},
+			},
+		},
+		{
+			name: "UnexpectedStrict",
+			opts: []krakentest.MockOption{
+				krakentest.MockWithStrict(),
+			},
+			call: func(m *krakentest.MockClient) (interface{}, error) {
+				return m.TradeBalance(context.Background(), "ZUSD")
+			},
+			expected: kraken.TradeBalance{},
+			expectedCalls: []krakentest.Call{
+				{Method: "TradeBalance", Args: []interface{}{"ZUSD"}},
+			},
+			expectedFails: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recorder{}
+			m, err := krakentest.NewMockClient(r, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			actual, err := tc.call(m)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expectedErr, err)
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+
+			if diff := deep.Equal(tc.expectedCalls, m.Calls()); diff != nil {
+				t.Error(diff)
+			}
+
+			if len(r.errors) != tc.expectedFails {
+				t.Errorf("EXPECTED: %d failures\nACTUAL: %v", tc.expectedFails, r.errors)
+			}
+		})
+	}
+}
+
+func TestMockClientCallsTo(t *testing.T) {
+	m, err := krakentest.NewMockClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.Tickers(context.Background(), "XBTUSD")
+	m.Time(context.Background())
+	m.Tickers(context.Background(), "ETHUSD")
+
+	expected := []krakentest.Call{
+		{Method: "Tickers", Args: []interface{}{[]string{"XBTUSD"}}},
+		{Method: "Tickers", Args: []interface{}{[]string{"ETHUSD"}}},
+	}
+
+	if diff := deep.Equal(expected, m.CallsTo("Tickers")); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,20 @@
+package krakentest
+
+import "fmt"
+
+// MockOption options used when creating a new MockClient
+type MockOption func(m *MockClient) error
+
+// MockWithStrict fail the test when a method is called without its behaviour
+// being set
+func MockWithStrict() MockOption {
+	return MockOption(func(m *MockClient) error {
+		if m.t == nil {
+			return fmt.Errorf("strict mock requires a test")
+		}
+
+		m.strict = true
+
+		return nil
+	})
+}
@@ -0,0 +1,174 @@
+package krakentest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oliread/kraken"
+)
+
+// defaultPayloads payloads served by the public endpoints of a Server unless
+// replaced, keyed by endpoint name
+var defaultPayloads = map[string]string{
+	"Time":         `{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"}}`,
+	"SystemStatus": `{"error":[],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z"}}`,
+	"Assets":       `{"error":[],"result":{"XXBT":{"aclass":"currency","altname":"XBT","decimals":10,"display_decimals":5},"ZUSD":{"aclass":"currency","altname":"USD","decimals":4,"display_decimals":2}}}`,
+	"AssetPairs":   `{"error":[],"result":{"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","aclass_base":"currency","base":"XXBT","aclass_quote":"currency","quote":"ZUSD","lot":"unit","pair_decimals":1,"lot_decimals":8,"lot_multiplier":1,"leverage_buy":[2,3,4,5],"leverage_sell":[2,3,4,5],"fees":[[0,0.26],[50000,0.24]],"fees_maker":[[0,0.16],[50000,0.14]],"fee_volume_currency":"ZUSD","margin_call":80,"margin_stop":40,"ordermin":0.0001}}}`,
+	"Ticker":       `{"error":[],"result":{"XXBTZUSD":{"a":["38659.60000","1","1.000"],"b":["38658.70000","1","1.000"],"c":["38658.90000","0.02120800"],"v":["3150.86186124","3404.34671000"],"p":["38609.60189","38601.37073"],"t":[24864,27336],"l":["38050.00000","38050.00000"],"h":["39290.00000","39290.00000"],"o":"38512.00000"}}}`,
+	"OHLC":         `{"error":[],"result":{"XXBTZUSD":[[1643714160,"38311.6","38343.7","38311.6","38343.7","38320.8","0.40716249",11]],"last":1643714160}}`,
+	"Depth":        `{"error":[],"result":{"XXBTZUSD":{"asks":[["37639.40000","0.002",1643832845]],"bids":[["37639.30000","3.488",1643832845]]}}}`,
+	"Trades":       `{"error":[],"result":{"XXBTZUSD":[["42428.00000","0.00109505",1644189769.9122,"b","l","",76472410]],"last":"1644191265969108820"}}`,
+	"Spread":       `{"error":[],"result":{"XXBTZUSD":[[1644356229,"44223.30000","44225.10000"]],"last":1644356229}}`,
+}
+
+// Fault a failure injected into the response to a request
+type Fault struct {
+	status int
+	body   string
+	delay  time.Duration
+}
+
+// FaultEService respond with an EService:Unavailable API error
+func FaultEService() Fault {
+	return Fault{
+		status: http.StatusOK,
+		body:   `{"error":["EService:Unavailable"]}`,
+	}
+}
+
+// FaultStatus respond with a HTTP status code and no body, such as the 520
+// returned by Kraken's CDN
+func FaultStatus(status int) Fault {
+	return Fault{
+		status: status,
+	}
+}
+
+// FaultMalformedJSON respond with a truncated JSON payload
+func FaultMalformedJSON() Fault {
+	return Fault{
+		status: http.StatusOK,
+		body:   `{"error":[],"result":{`,
+	}
+}
+
+// FaultDelay respond with the endpoint payload after a delay, or not at all if
+// the request is cancelled first
+func FaultDelay(delay time.Duration) Fault {
+	return Fault{
+		delay: delay,
+	}
+}
+
+// Server a fake Kraken API serving canned payloads from the public endpoints,
+// failures can be injected per endpoint
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	payloads map[string]string
+	faults   map[string][]Fault
+	requests map[string]int
+}
+
+// NewServer helper function for creating and starting a new Server, along with
+// a HTTPClient pointed at it. The server must be closed once done with
+func NewServer(opts ...ServerOption) (*Server, *kraken.HTTPClient, error) {
+	s := Server{
+		payloads: make(map[string]string),
+		faults:   make(map[string][]Fault),
+		requests: make(map[string]int),
+	}
+
+	for endpoint, payload := range defaultPayloads {
+		s.payloads[endpoint] = payload
+	}
+
+	cfg := serverConfig{}
+	for _, opt := range opts {
+		if err := opt(&s, &cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	c, err := kraken.NewHTTPClient(append(cfg.clientOpts, kraken.HTTPClientWithBaseURL(s.URL))...)
+	if err != nil {
+		s.Close()
+
+		return nil, nil, err
+	}
+
+	return &s, c, nil
+}
+
+// SetPayload replace the payload served by an endpoint, such as "Ticker"
+func (s *Server) SetPayload(endpoint, payload string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.payloads[endpoint] = payload
+}
+
+// Inject queue faults for an endpoint, each fault is used for a single request
+// in the order given after which the endpoint payload is served again
+func (s *Server) Inject(endpoint string, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[endpoint] = append(s.faults[endpoint], faults...)
+}
+
+// Requests return the number of requests made to an endpoint
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[endpoint]
+}
+
+// serve handle a request to a public endpoint
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/public/")
+
+	s.mu.Lock()
+	s.requests[endpoint]++
+	payload, ok := s.payloads[endpoint]
+
+	var fault *Fault
+	if faults := s.faults[endpoint]; len(faults) != 0 {
+		fault = &faults[0]
+		s.faults[endpoint] = faults[1:]
+	}
+	s.mu.Unlock()
+
+	if !ok || !strings.HasPrefix(r.URL.Path, "/public/") {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":["EGeneral:Unknown method"]}`))
+
+		return
+	}
+
+	if fault != nil && fault.delay != 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(fault.delay):
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	if fault != nil && fault.status != 0 {
+		w.WriteHeader(fault.status)
+		w.Write([]byte(fault.body))
+
+		return
+	}
+
+	w.Write([]byte(payload))
+}
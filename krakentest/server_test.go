@@ -0,0 +1,235 @@
+package krakentest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+func TestServer(t *testing.T) {
+	tcs := []struct {
+		name             string
+		endpoint         string
+		opts             []krakentest.ServerOption
+		faults           []krakentest.Fault
+		call             func(ctx context.Context, c *kraken.HTTPClient) ([]error, error)
+		timeout          time.Duration
+		expectedErr      error
+		expectedAPIErr   error
+		expectedRequests int
+	}{
+		{
+			name:     "Time",
+			endpoint: "Time",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "Status",
+			endpoint: "SystemStatus",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Status(ctx)
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "Assets",
+			endpoint: "Assets",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Assets(ctx)
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "AssetPairs",
+			endpoint: "AssetPairs",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.AssetPairs(ctx, "", "XBTUSD")
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "Ticker",
+			endpoint: "Ticker",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Tickers(ctx, "XBTUSD")
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "OHLC",
+			endpoint: "OHLC",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.OHLC(ctx, kraken.OHLCIntervalMinute, nil, "XBTUSD")
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "Depth",
+			endpoint: "Depth",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.OrderBook(ctx, 1, "XBTUSD")
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "Trades",
+			endpoint: "Trades",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.RecentTrades(ctx, nil, "XBTUSD")
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "Spread",
+			endpoint: "Spread",
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.RecentSpreads(ctx, nil, "XBTUSD")
+				return res.Errors, err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name:     "EService",
+			endpoint: "Time",
+			faults:   []krakentest.Fault{krakentest.FaultEService()},
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			expectedAPIErr:   kraken.ErrService,
+			expectedRequests: 1,
+		},
+		{
+			name:     "Status520",
+			endpoint: "Time",
+			faults:   []krakentest.Fault{krakentest.FaultStatus(520)},
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			expectedErr:      kraken.ErrServerError,
+			expectedRequests: 1,
+		},
+		{
+			name:     "MalformedJSON",
+			endpoint: "Time",
+			faults:   []krakentest.Fault{krakentest.FaultMalformedJSON()},
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			expectedErr:      kraken.ErrParse,
+			expectedRequests: 1,
+		},
+		{
+			name:     "Timeout",
+			endpoint: "Time",
+			faults:   []krakentest.Fault{krakentest.FaultDelay(time.Second)},
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			timeout:          10 * time.Millisecond,
+			expectedErr:      kraken.ErrNetwork,
+			expectedRequests: 1,
+		},
+		{
+			name:     "RetriedFaults",
+			endpoint: "Time",
+			opts: []krakentest.ServerOption{
+				krakentest.ServerWithClientOptions(kraken.HTTPClientWithRetry(3, time.Millisecond, time.Millisecond)),
+			},
+			faults: []krakentest.Fault{
+				krakentest.FaultStatus(520),
+				krakentest.FaultEService(),
+			},
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			expectedRequests: 3,
+		},
+		{
+			name:     "APIError",
+			endpoint: "Time",
+			opts: []krakentest.ServerOption{
+				krakentest.ServerWithPayload("Time", `{"error":["EGeneral:Internal error"]}`),
+			},
+			call: func(ctx context.Context, c *kraken.HTTPClient) ([]error, error) {
+				res, err := c.Time(ctx)
+				return res.Errors, err
+			},
+			expectedAPIErr:   kraken.ErrGeneral,
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s, c, err := krakentest.NewServer(tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			s.Inject(tc.endpoint, tc.faults...)
+
+			ctx := context.Background()
+			if tc.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			errs, err := tc.call(ctx, c)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expectedErr, err)
+			}
+
+			switch {
+			case tc.expectedAPIErr == nil && len(errs) != 0:
+				t.Errorf("unexpected result errors: %v", errs)
+			case tc.expectedAPIErr != nil && (len(errs) == 0 || !errors.Is(errs[0], tc.expectedAPIErr)):
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expectedAPIErr, errs)
+			}
+
+			if requests := s.Requests(tc.endpoint); requests != tc.expectedRequests {
+				t.Errorf("EXPECTED: %d requests\nACTUAL: %d", tc.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestServerUnknownEndpoint(t *testing.T) {
+	s, _, err := krakentest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	res, err := http.Get(s.URL + "/public/Unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("EXPECTED: %d\nACTUAL: %d", http.StatusNotFound, res.StatusCode)
+	}
+}
@@ -0,0 +1,39 @@
+package krakentest
+
+import (
+	"fmt"
+
+	"github.com/oliread/kraken"
+)
+
+// serverConfig configuration used only while creating a Server
+type serverConfig struct {
+	clientOpts []kraken.HTTPClientOption
+}
+
+// ServerOption options used when creating a new Server
+type ServerOption func(s *Server, cfg *serverConfig) error
+
+// ServerWithPayload replace the payload served by an endpoint, such as
+// "Ticker"
+func ServerWithPayload(endpoint, payload string) ServerOption {
+	return ServerOption(func(s *Server, cfg *serverConfig) error {
+		if endpoint == "" {
+			return fmt.Errorf("endpoint is required")
+		}
+
+		s.payloads[endpoint] = payload
+
+		return nil
+	})
+}
+
+// ServerWithClientOptions set options of the HTTPClient returned with the
+// server, the base url is always set to the server
+func ServerWithClientOptions(opts ...kraken.HTTPClientOption) ServerOption {
+	return ServerOption(func(s *Server, cfg *serverConfig) error {
+		cfg.clientOpts = append(cfg.clientOpts, opts...)
+
+		return nil
+	})
+}
@@ -0,0 +1,510 @@
+package krakentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/internal/websocket"
+)
+
+// WSFrame a frame written to a connection by a WSServer
+type WSFrame struct {
+	payload string
+	delay   time.Duration
+	drop    bool
+}
+
+// WSMessage write a payload
+func WSMessage(payload string) WSFrame {
+	return WSFrame{
+		payload: payload,
+	}
+}
+
+// WSMessageAfter write a payload after a delay, or not at all if the
+// connection is dropped first
+func WSMessageAfter(delay time.Duration, payload string) WSFrame {
+	return WSFrame{
+		payload: payload,
+		delay:   delay,
+	}
+}
+
+// WSDrop close the connection, any frames after it are not written
+func WSDrop() WSFrame {
+	return WSFrame{
+		drop: true,
+	}
+}
+
+// WSSubscription the subscription of a subscribe or unsubscribe request
+type WSSubscription struct {
+	Name     string `json:"name"`
+	Interval int    `json:"interval,omitempty"`
+	Depth    int    `json:"depth,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// WSRequest a request received by a WSServer, the pairs are those of a
+// subscription or the single pair of an order. The event is the method of a
+// v2 request, whose channel and symbols are given as those of a v1
+// subscription. The request is given as received by Raw for the fields of
+// other events
+type WSRequest struct {
+	Version      kraken.WSVersion
+	Event        string
+	ReqID        uint64
+	Pairs        []string
+	Token        string
+	Subscription WSSubscription
+	Raw          json.RawMessage
+}
+
+// UnmarshalJSON unmarshal a request, keeping the message as received
+func (r *WSRequest) UnmarshalJSON(data []byte) error {
+	req := struct {
+		Event        string          `json:"event"`
+		ReqID        uint64          `json:"reqid"`
+		Pair         json.RawMessage `json:"pair"`
+		Token        string          `json:"token"`
+		Subscription WSSubscription  `json:"subscription"`
+		Method       string          `json:"method"`
+		ReqIDV2      uint64          `json:"req_id"`
+		Params       struct {
+			Channel  string   `json:"channel"`
+			Symbol   []string `json:"symbol"`
+			Interval int      `json:"interval"`
+			Depth    int      `json:"depth"`
+			Token    string   `json:"token"`
+		} `json:"params"`
+	}{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+
+	r.Raw = append(json.RawMessage(nil), data...)
+
+	if req.Event == "" && req.Method != "" {
+		r.Version = kraken.WSVersion2
+		r.Event = req.Method
+		r.ReqID = req.ReqIDV2
+		r.Pairs = req.Params.Symbol
+		r.Token = req.Params.Token
+		r.Subscription = WSSubscription{
+			Name:     req.Params.Channel,
+			Interval: req.Params.Interval,
+			Depth:    req.Params.Depth,
+		}
+
+		return nil
+	}
+
+	r.Version = kraken.WSVersion1
+	r.Event = req.Event
+	r.ReqID = req.ReqID
+	r.Token = req.Token
+	r.Subscription = req.Subscription
+	r.Pairs = nil
+
+	switch {
+	case len(req.Pair) == 0 || string(req.Pair) == "null":
+	case strings.HasPrefix(string(req.Pair), "["):
+		if err := json.Unmarshal(req.Pair, &r.Pairs); err != nil {
+			return err
+		}
+	default:
+		pair := ""
+		if err := json.Unmarshal(req.Pair, &pair); err != nil {
+			return err
+		}
+		r.Pairs = []string{pair}
+	}
+
+	return nil
+}
+
+// ChannelName the name of the channel subscribed to by a request, the
+// interval is part of the name of OHLC channels as is the depth of v1 book
+// channels
+func (r WSRequest) ChannelName() string {
+	switch {
+	case r.Subscription.Name == "ohlc":
+		return fmt.Sprintf("ohlc-%d", max(r.Subscription.Interval, 1))
+	case r.Subscription.Name == "book" && r.Version == kraken.WSVersion1:
+		return fmt.Sprintf("book-%d", max(r.Subscription.Depth, 10))
+	}
+
+	return r.Subscription.Name
+}
+
+// WSHandler return the frames written in reply to a request
+type WSHandler func(req WSRequest) []WSFrame
+
+// wsStatus a subscriptionStatus event
+type wsStatus struct {
+	ChannelID    uint64         `json:"channelID,omitempty"`
+	ChannelName  string         `json:"channelName,omitempty"`
+	ErrorMessage string         `json:"errorMessage,omitempty"`
+	Event        string         `json:"event"`
+	Pair         string         `json:"pair,omitempty"`
+	ReqID        uint64         `json:"reqid"`
+	Status       string         `json:"status"`
+	Subscription WSSubscription `json:"subscription"`
+}
+
+// wsV2Status the reply to a v2 subscribe or unsubscribe request
+type wsV2Status struct {
+	Error   string          `json:"error,omitempty"`
+	Method  string          `json:"method"`
+	ReqID   uint64          `json:"req_id"`
+	Result  *wsV2StatusPair `json:"result,omitempty"`
+	Success bool            `json:"success"`
+	Symbol  string          `json:"symbol,omitempty"`
+}
+
+// wsV2StatusPair the channel and symbol of a successful v2 request
+type wsV2StatusPair struct {
+	Channel  string `json:"channel"`
+	Symbol   string `json:"symbol"`
+	Interval int    `json:"interval,omitempty"`
+}
+
+// WSSubscriptionStatus the subscriptionStatus event of a request for each of
+// its pairs, or a single event for a private channel. The error message is
+// only given for an "error" status, the reply to a v2 request is given for
+// each pair instead
+func WSSubscriptionStatus(req WSRequest, status, errorMessage string) []WSFrame {
+	return subscriptionStatus(req, status, errorMessage, 0)
+}
+
+// subscriptionStatus the subscriptionStatus events of a request, with the
+// channel id of a subscription when given
+func subscriptionStatus(req WSRequest, status, errorMessage string, channelID uint64) []WSFrame {
+	sub := req.Subscription
+	sub.Token = ""
+
+	pairs := req.Pairs
+	if len(pairs) == 0 {
+		pairs = []string{""}
+	}
+
+	frames := []WSFrame{}
+	for _, pair := range pairs {
+		if req.Version == kraken.WSVersion2 {
+			frames = append(frames, v2SubscriptionStatus(req, pair, status, errorMessage))
+			continue
+		}
+
+		event := wsStatus{
+			ChannelID:    channelID,
+			ErrorMessage: errorMessage,
+			Event:        "subscriptionStatus",
+			Pair:         pair,
+			ReqID:        req.ReqID,
+			Status:       status,
+			Subscription: sub,
+		}
+
+		if status != "error" {
+			event.ChannelName = req.ChannelName()
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			panic(err)
+		}
+
+		frames = append(frames, WSMessage(string(payload)))
+	}
+
+	return frames
+}
+
+// v2SubscriptionStatus the reply to a v2 request for a pair
+func v2SubscriptionStatus(req WSRequest, pair, status, errorMessage string) WSFrame {
+	event := wsV2Status{
+		Method: req.Event,
+		ReqID:  req.ReqID,
+	}
+
+	if status == "error" {
+		event.Error = errorMessage
+		event.Symbol = pair
+	} else {
+		event.Success = true
+		event.Result = &wsV2StatusPair{
+			Channel: req.Subscription.Name,
+			Symbol:  pair,
+		}
+
+		if req.Subscription.Name == "ohlc" {
+			event.Result.Interval = max(req.Subscription.Interval, 1)
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+
+	return WSMessage(string(payload))
+}
+
+// wsChannelKey a channel subscribed to for a pair, the pair is empty for
+// private channels
+type wsChannelKey struct {
+	name string
+	pair string
+}
+
+// wsServerConn a connection accepted by a WSServer, closed is closed once the
+// connection has been dropped
+type wsServerConn struct {
+	*websocket.Conn
+
+	once   sync.Once
+	closed chan struct{}
+}
+
+// close drop the connection
+func (c *wsServerConn) close() {
+	c.once.Do(func() {
+		close(c.closed)
+		c.Close()
+	})
+}
+
+// WSServer a fake Kraken websocket API. Subscribe and unsubscribe requests are
+// acknowledged for each pair, after which any frames scripted for the
+// channel and pair are written, in the protocol version of the request. The
+// reply to an event can be replaced by a handler and connections can be
+// dropped to exercise the end of a connection and connecting again
+type WSServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	handlers    map[string]WSHandler
+	scripts     map[wsChannelKey][][]WSFrame
+	conns       map[*wsServerConn]struct{}
+	requests    []WSRequest
+	connections int
+	channelID   uint64
+}
+
+// NewWSServer helper function for creating and starting a new WSServer, along
+// with a WSClient pointed at it. The server must be closed once done with
+func NewWSServer(opts ...WSServerOption) (*WSServer, *kraken.WSClient, error) {
+	s := WSServer{
+		handlers: make(map[string]WSHandler),
+		scripts:  make(map[wsChannelKey][][]WSFrame),
+		conns:    make(map[*wsServerConn]struct{}),
+	}
+
+	s.handlers["subscribe"] = s.Subscribe
+	s.handlers["unsubscribe"] = s.Unsubscribe
+	s.handlers["ping"] = func(req WSRequest) []WSFrame {
+		if req.Version == kraken.WSVersion2 {
+			return []WSFrame{WSMessage(fmt.Sprintf(`{"method":"pong","req_id":%d}`, req.ReqID))}
+		}
+
+		return []WSFrame{WSMessage(fmt.Sprintf(`{"event":"pong","reqid":%d}`, req.ReqID))}
+	}
+
+	cfg := wsServerConfig{}
+	for _, opt := range opts {
+		if err := opt(&s, &cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	c, err := kraken.NewWSClient(append(cfg.clientOpts, kraken.WSClientWithURL(s.WSURL()))...)
+	if err != nil {
+		s.Close()
+
+		return nil, nil, err
+	}
+
+	return &s, c, nil
+}
+
+// WSURL the websocket url of the server
+func (s *WSServer) WSURL() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+// Handle replace the reply to an event, such as "subscribe" or "addOrder".
+// Events without a handler are replied to with an error event
+func (s *WSServer) Handle(event string, handler WSHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[event] = handler
+}
+
+// Script queue frames for a channel and pair, such as "ticker" and "XBT/USD",
+// written after the next subscription to them is acknowledged. Each script is
+// used for a single subscription in the order given, the pair is empty for
+// private channels
+func (s *WSServer) Script(channelName, pair string, frames ...WSFrame) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := wsChannelKey{name: channelName, pair: pair}
+	s.scripts[key] = append(s.scripts[key], frames)
+}
+
+// Subscribe the default reply to a subscribe request, acknowledging each pair
+// followed by the next frames scripted for it
+func (s *WSServer) Subscribe(req WSRequest) []WSFrame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channelID++
+
+	frames := subscriptionStatus(req, "subscribed", "", s.channelID)
+
+	pairs := req.Pairs
+	if len(pairs) == 0 {
+		pairs = []string{""}
+	}
+
+	for _, pair := range pairs {
+		key := wsChannelKey{name: req.ChannelName(), pair: pair}
+		if scripts := s.scripts[key]; len(scripts) != 0 {
+			frames = append(frames, scripts[0]...)
+			s.scripts[key] = scripts[1:]
+		}
+	}
+
+	return frames
+}
+
+// Unsubscribe the default reply to an unsubscribe request, acknowledging each
+// pair
+func (s *WSServer) Unsubscribe(req WSRequest) []WSFrame {
+	return WSSubscriptionStatus(req, "unsubscribed", "")
+}
+
+// Send write frames to every open connection
+func (s *WSServer) Send(frames ...WSFrame) {
+	s.mu.Lock()
+	conns := make([]*wsServerConn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.write(frames)
+	}
+}
+
+// Drop close every open connection
+func (s *WSServer) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.close()
+	}
+}
+
+// Requests return the requests received across all connections
+func (s *WSServer) Requests() []WSRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]WSRequest(nil), s.requests...)
+}
+
+// Connections return the number of connections accepted
+func (s *WSServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connections
+}
+
+// serve handle a connection, replying to each request in turn
+func (s *WSServer) serve(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	conn := &wsServerConn{Conn: ws, closed: make(chan struct{})}
+	s.mu.Lock()
+	s.conns[conn] = struct{}{}
+	s.connections++
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		conn.close()
+	}()
+
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		req := WSRequest{}
+		if err := json.Unmarshal(msg, &req); err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		handler, ok := s.handlers[req.Event]
+		s.mu.Unlock()
+
+		frames := []WSFrame{WSMessage(fmt.Sprintf(`{"errorMessage":"Unsupported event","event":"error","reqid":%d}`, req.ReqID))}
+		if req.Version == kraken.WSVersion2 {
+			frames = []WSFrame{WSMessage(fmt.Sprintf(`{"error":"Unsupported method","method":%q,"req_id":%d,"success":false}`, req.Event, req.ReqID))}
+		}
+		if ok {
+			frames = handler(req)
+		}
+
+		if !conn.write(frames) {
+			return
+		}
+	}
+}
+
+// write frames to the connection, false once it has been dropped
+func (c *wsServerConn) write(frames []WSFrame) bool {
+	for _, frame := range frames {
+		if frame.delay != 0 {
+			select {
+			case <-c.closed:
+				return false
+			case <-time.After(frame.delay):
+			}
+		}
+
+		if frame.drop {
+			c.close()
+
+			return false
+		}
+
+		if err := c.WriteMessage([]byte(frame.payload)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
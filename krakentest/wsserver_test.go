@@ -0,0 +1,249 @@
+package krakentest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+// wsTicker a ticker channel message for a pair
+func wsTicker(pair string) string {
+	return `[340,{"a":["38659.60000",2,"2.50000000"],"b":["38658.70000",1,"1.00000000"],"c":["38658.90000","0.02120800"],"v":["3150.86186124","3404.34671000"],"p":["38609.60189","38601.37073"],"t":[24864,27336],"l":["38132.50000","38132.50000"],"h":["38988.00000","38988.00000"],"o":["38466.40000","38401.10000"]},"ticker","` + pair + `"]`
+}
+
+// receiveTicker the pair of the next ticker of a subscription
+func receiveTicker(ctx context.Context, t *testing.T, sub *kraken.Subscription[kraken.Ticker]) string {
+	select {
+	case ticker, ok := <-sub.C:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+
+		return ticker.Pair
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	return ""
+}
+
+func TestWSServer(t *testing.T) {
+	s, c, err := krakentest.NewWSServer(
+		krakentest.WSServerWithScript("ticker", "XBT/USD", krakentest.WSMessage(wsTicker("XBT/USD"))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s.Script("ticker", "ETH/USD", krakentest.WSMessageAfter(10*time.Millisecond, wsTicker("ETH/USD")))
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD", "ETH/USD"}, kraken.SubscribeWithPairChannels())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pair := range []string{"XBT/USD", "ETH/USD"} {
+		select {
+		case ticker := <-tickers.Pair(pair):
+			if ticker.Pair != pair {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", pair, ticker.Pair)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	s.Send(krakentest.WSMessage(wsTicker("XBT/USD")))
+	select {
+	case ticker := <-tickers.Pair("XBT/USD"):
+		if ticker.Pair != "XBT/USD" {
+			t.Errorf("EXPECTED: %v\nACTUAL: %v", "XBT/USD", ticker.Pair)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	if err := tickers.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		Event        string
+		Pairs        []string
+		Subscription krakentest.WSSubscription
+	}{
+		{Event: "subscribe", Pairs: []string{"XBT/USD", "ETH/USD"}, Subscription: krakentest.WSSubscription{Name: "ticker"}},
+		{Event: "unsubscribe", Pairs: []string{"XBT/USD", "ETH/USD"}, Subscription: krakentest.WSSubscription{Name: "ticker"}},
+	}
+
+	requests := s.Requests()
+	if len(requests) != len(expected) {
+		t.Fatalf("EXPECTED: %v\nACTUAL: %v", len(expected), len(requests))
+	}
+
+	for i, req := range requests {
+		if diff := deep.Equal(expected[i].Event, req.Event); diff != nil {
+			t.Error(diff)
+		}
+
+		if diff := deep.Equal(expected[i].Pairs, req.Pairs); diff != nil {
+			t.Error(diff)
+		}
+
+		if diff := deep.Equal(expected[i].Subscription, req.Subscription); diff != nil {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestWSServerChannelName(t *testing.T) {
+	tcs := []struct {
+		name         string
+		subscription krakentest.WSSubscription
+		expected     string
+	}{
+		{
+			name:         "Ticker",
+			subscription: krakentest.WSSubscription{Name: "ticker"},
+			expected:     "ticker",
+		},
+		{
+			name:         "OHLCDefault",
+			subscription: krakentest.WSSubscription{Name: "ohlc"},
+			expected:     "ohlc-1",
+		},
+		{
+			name:         "OHLC",
+			subscription: krakentest.WSSubscription{Name: "ohlc", Interval: 60},
+			expected:     "ohlc-60",
+		},
+		{
+			name:         "BookDefault",
+			subscription: krakentest.WSSubscription{Name: "book"},
+			expected:     "book-10",
+		},
+		{
+			name:         "Book",
+			subscription: krakentest.WSSubscription{Name: "book", Depth: 25},
+			expected:     "book-25",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := krakentest.WSRequest{Subscription: tc.subscription}
+			if actual := req.ChannelName(); actual != tc.expected {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWSServerHandle(t *testing.T) {
+	s, c, err := krakentest.NewWSServer(
+		krakentest.WSServerWithHandler("subscribe", func(req krakentest.WSRequest) []krakentest.WSFrame {
+			return krakentest.WSSubscriptionStatus(req, "error", "Currency pair not supported")
+		}),
+		krakentest.WSServerWithClientOptions(kraken.WSClientWithErrorHandler(func(error) {})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := c.SubscribeTicker(ctx, []string{"ABC/USD"}); !errors.Is(err, kraken.ErrSubscription) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrSubscription, err)
+	}
+
+	s.Handle("subscribe", s.Subscribe)
+	if _, err := c.SubscribeTicker(ctx, []string{"XBT/USD"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWSServerDrop(t *testing.T) {
+	s, c, err := krakentest.NewWSServer(
+		krakentest.WSServerWithScript("ticker", "XBT/USD", krakentest.WSMessage(wsTicker("XBT/USD")), krakentest.WSDrop()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pair := receiveTicker(ctx, t, tickers); pair != "XBT/USD" {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", "XBT/USD", pair)
+	}
+
+	// the subscription ends once its connection is dropped
+	for range tickers.C {
+	}
+
+	tickers, err = c.SubscribeTicker(ctx, []string{"ETH/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := s.Connections(); actual != 2 {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", 2, actual)
+	}
+
+	s.Drop()
+	for range tickers.C {
+	}
+}
+
+func TestNewWSServer(t *testing.T) {
+	tcs := []struct {
+		name string
+		opt  krakentest.WSServerOption
+	}{
+		{
+			name: "HandlerEvent",
+			opt:  krakentest.WSServerWithHandler("", func(req krakentest.WSRequest) []krakentest.WSFrame { return nil }),
+		},
+		{
+			name: "Handler",
+			opt:  krakentest.WSServerWithHandler("subscribe", nil),
+		},
+		{
+			name: "ScriptChannelName",
+			opt:  krakentest.WSServerWithScript("", "XBT/USD"),
+		},
+		{
+			name: "ClientOptions",
+			opt:  krakentest.WSServerWithClientOptions(kraken.WSClientWithBufferSize(-1)),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if s, _, err := krakentest.NewWSServer(tc.opt); err == nil {
+				s.Close()
+				t.Error("EXPECTED: error\nACTUAL: nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package krakentest
+
+import (
+	"fmt"
+
+	"github.com/oliread/kraken"
+)
+
+// wsServerConfig configuration used only while creating a WSServer
+type wsServerConfig struct {
+	clientOpts []kraken.WSClientOption
+}
+
+// WSServerOption options used when creating a new WSServer
+type WSServerOption func(s *WSServer, cfg *wsServerConfig) error
+
+// WSServerWithHandler replace the reply to an event, such as "subscribe" or
+// "addOrder"
+func WSServerWithHandler(event string, handler WSHandler) WSServerOption {
+	return WSServerOption(func(s *WSServer, cfg *wsServerConfig) error {
+		if event == "" {
+			return fmt.Errorf("event is required")
+		}
+
+		if handler == nil {
+			return fmt.Errorf("handler is required")
+		}
+
+		s.handlers[event] = handler
+
+		return nil
+	})
+}
+
+// WSServerWithScript queue frames for a channel and pair as Script
+func WSServerWithScript(channelName, pair string, frames ...WSFrame) WSServerOption {
+	return WSServerOption(func(s *WSServer, cfg *wsServerConfig) error {
+		if channelName == "" {
+			return fmt.Errorf("channel name is required")
+		}
+
+		key := wsChannelKey{name: channelName, pair: pair}
+		s.scripts[key] = append(s.scripts[key], frames)
+
+		return nil
+	})
+}
+
+// WSServerWithClientOptions set options of the WSClient returned with the
+// server, the url is always set to the server
+func WSServerWithClientOptions(opts ...kraken.WSClientOption) WSServerOption {
+	return WSServerOption(func(s *WSServer, cfg *wsServerConfig) error {
+		cfg.clientOpts = append(cfg.clientOpts, opts...)
+
+		return nil
+	})
+}
@@ -0,0 +1,590 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxLoggedErrorLength errors longer than this are truncated when logged
+const maxLoggedErrorLength = 256
+
+// LoggingClient handles structured logging of calls to client functions, the
+// arguments and duration of each call are logged along with any error
+type LoggingClient struct {
+	inner        Client
+	logger       *slog.Logger
+	successLevel slog.Level
+	failureLevel slog.Level
+}
+
+var _ Client = (*LoggingClient)(nil)
+
+// NewLoggingClient helper function for creating a new logging client, calls
+// are logged at debug level when successful and warn level when failed unless
+// otherwise given
+func NewLoggingClient(inner Client, logger *slog.Logger, opts ...LoggingOption) (*LoggingClient, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+
+	c := LoggingClient{
+		inner:        inner,
+		logger:       logger,
+		successLevel: slog.LevelDebug,
+		failureLevel: slog.LevelWarn,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+// log log a call to a client function, pointer arguments are logged by value
+func (c *LoggingClient) log(ctx context.Context, operation string, start time.Time, err error, args ...slog.Attr) {
+	level := c.successLevel
+	if err != nil {
+		level = c.failureLevel
+	}
+
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	argAttrs := make([]interface{}, len(args))
+	for i, arg := range args {
+		argAttrs[i] = slog.Any(arg.Key, dereference(arg.Value.Any()))
+	}
+
+	attrs := []interface{}{
+		slog.String("operation", operation),
+		slog.Group("args", argAttrs...),
+		slog.Duration("duration", time.Since(start)),
+	}
+
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > maxLoggedErrorLength {
+			msg = msg[:maxLoggedErrorLength] + "..."
+		}
+
+		attrs = append(attrs, slog.String("error", msg))
+	}
+
+	c.logger.Log(ctx, level, "kraken client call", attrs...)
+}
+
+// dereference return the value a pointer points to, nil is returned for a nil
+// pointer
+func dereference(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return v
+	}
+
+	if rv.IsNil() {
+		return nil
+	}
+
+	return rv.Elem().Interface()
+}
+
+// Time handles logging for client Time function
+func (c *LoggingClient) Time(ctx context.Context) (Time, error) {
+	start := time.Now()
+	v, err := c.inner.Time(ctx)
+	c.log(ctx, "Time", start, err)
+
+	return v, err
+}
+
+// Status handles logging for client Status function
+func (c *LoggingClient) Status(ctx context.Context) (SystemStatus, error) {
+	start := time.Now()
+	v, err := c.inner.Status(ctx)
+	c.log(ctx, "Status", start, err)
+
+	return v, err
+}
+
+// Assets handles logging for client Assets function
+func (c *LoggingClient) Assets(ctx context.Context) (Assets, error) {
+	start := time.Now()
+	v, err := c.inner.Assets(ctx)
+	c.log(ctx, "Assets", start, err)
+
+	return v, err
+}
+
+// AssetPairs handles logging for client AssetPairs function
+func (c *LoggingClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
+	start := time.Now()
+	v, err := c.inner.AssetPairs(ctx, info, pairs...)
+	c.log(ctx, "AssetPairs", start, err,
+		slog.Any("info", info),
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// Tickers handles logging for client Tickers function
+func (c *LoggingClient) Tickers(ctx context.Context, pairs ...string) (Tickers, error) {
+	start := time.Now()
+	v, err := c.inner.Tickers(ctx, pairs...)
+	c.log(ctx, "Tickers", start, err,
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// OHLC handles logging for client OHLC function
+func (c *LoggingClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
+	start := time.Now()
+	v, err := c.inner.OHLC(ctx, interval, since, pairs...)
+	c.log(ctx, "OHLC", start, err,
+		slog.Any("interval", interval),
+		slog.Any("since", since),
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// OrderBook handles logging for client OrderBook function
+func (c *LoggingClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
+	start := time.Now()
+	v, err := c.inner.OrderBook(ctx, count, pairs...)
+	c.log(ctx, "OrderBook", start, err,
+		slog.Any("count", count),
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// RecentTrades handles logging for client RecentTrades function
+func (c *LoggingClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
+	start := time.Now()
+	v, err := c.inner.RecentTrades(ctx, since, pairs...)
+	c.log(ctx, "RecentTrades", start, err,
+		slog.Any("since", since),
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// RecentSpreads handles logging for client RecentSpreads function
+func (c *LoggingClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
+	start := time.Now()
+	v, err := c.inner.RecentSpreads(ctx, since, pairs...)
+	c.log(ctx, "RecentSpreads", start, err,
+		slog.Any("since", since),
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// TradeBalance handles logging for client TradeBalance function
+func (c *LoggingClient) TradeBalance(ctx context.Context, asset string) (TradeBalance, error) {
+	start := time.Now()
+	v, err := c.inner.TradeBalance(ctx, asset)
+	c.log(ctx, "TradeBalance", start, err,
+		slog.Any("asset", asset),
+	)
+
+	return v, err
+}
+
+// OpenOrders handles logging for client OpenOrders function
+func (c *LoggingClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error) {
+	start := time.Now()
+	v, err := c.inner.OpenOrders(ctx, trades, userref)
+	c.log(ctx, "OpenOrders", start, err,
+		slog.Any("trades", trades),
+		slog.Any("userref", userref),
+	)
+
+	return v, err
+}
+
+// ClosedOrders handles logging for client ClosedOrders function
+func (c *LoggingClient) ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error) {
+	start := time.Now()
+	v, err := c.inner.ClosedOrders(ctx, query)
+	c.log(ctx, "ClosedOrders", start, err,
+		slog.Any("query", query),
+	)
+
+	return v, err
+}
+
+// QueryOrders handles logging for client QueryOrders function
+func (c *LoggingClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error) {
+	start := time.Now()
+	v, err := c.inner.QueryOrders(ctx, trades, userref, txids...)
+	c.log(ctx, "QueryOrders", start, err,
+		slog.Any("trades", trades),
+		slog.Any("userref", userref),
+		slog.Any("txids", txids),
+	)
+
+	return v, err
+}
+
+// TradesHistory handles logging for client TradesHistory function
+func (c *LoggingClient) TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error) {
+	start := time.Now()
+	v, err := c.inner.TradesHistory(ctx, query)
+	c.log(ctx, "TradesHistory", start, err,
+		slog.Any("query", query),
+	)
+
+	return v, err
+}
+
+// OpenPositions handles logging for client OpenPositions function
+func (c *LoggingClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error) {
+	start := time.Now()
+	v, err := c.inner.OpenPositions(ctx, docalcs, txids...)
+	c.log(ctx, "OpenPositions", start, err,
+		slog.Any("docalcs", docalcs),
+		slog.Any("txids", txids),
+	)
+
+	return v, err
+}
+
+// Ledgers handles logging for client Ledgers function
+func (c *LoggingClient) Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error) {
+	start := time.Now()
+	v, err := c.inner.Ledgers(ctx, query)
+	c.log(ctx, "Ledgers", start, err,
+		slog.Any("query", query),
+	)
+
+	return v, err
+}
+
+// QueryLedgers handles logging for client QueryLedgers function
+func (c *LoggingClient) QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error) {
+	start := time.Now()
+	v, err := c.inner.QueryLedgers(ctx, ids...)
+	c.log(ctx, "QueryLedgers", start, err,
+		slog.Any("ids", ids),
+	)
+
+	return v, err
+}
+
+// TradeVolume handles logging for client TradeVolume function
+func (c *LoggingClient) TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error) {
+	start := time.Now()
+	v, err := c.inner.TradeVolume(ctx, pairs...)
+	c.log(ctx, "TradeVolume", start, err,
+		slog.Any("pairs", pairs),
+	)
+
+	return v, err
+}
+
+// AddOrder handles logging for client AddOrder function
+func (c *LoggingClient) AddOrder(ctx context.Context, req OrderRequest) (AddOrderResult, error) {
+	start := time.Now()
+	v, err := c.inner.AddOrder(ctx, req)
+	c.log(ctx, "AddOrder", start, err,
+		slog.Any("req", req),
+	)
+
+	return v, err
+}
+
+// CancelOrder handles logging for client CancelOrder function
+func (c *LoggingClient) CancelOrder(ctx context.Context, txid string) (CancelResult, error) {
+	start := time.Now()
+	v, err := c.inner.CancelOrder(ctx, txid)
+	c.log(ctx, "CancelOrder", start, err,
+		slog.Any("txid", txid),
+	)
+
+	return v, err
+}
+
+// CancelAllOrders handles logging for client CancelAllOrders function
+func (c *LoggingClient) CancelAllOrders(ctx context.Context) (CancelResult, error) {
+	start := time.Now()
+	v, err := c.inner.CancelAllOrders(ctx)
+	c.log(ctx, "CancelAllOrders", start, err)
+
+	return v, err
+}
+
+// CancelAllOrdersAfter handles logging for client CancelAllOrdersAfter function
+func (c *LoggingClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error) {
+	start := time.Now()
+	v, err := c.inner.CancelAllOrdersAfter(ctx, timeout)
+	c.log(ctx, "CancelAllOrdersAfter", start, err,
+		slog.Any("timeout", timeout),
+	)
+
+	return v, err
+}
+
+// EditOrder handles logging for client EditOrder function
+func (c *LoggingClient) EditOrder(ctx context.Context, req EditOrderRequest) (EditOrderResult, error) {
+	start := time.Now()
+	v, err := c.inner.EditOrder(ctx, req)
+	c.log(ctx, "EditOrder", start, err,
+		slog.Any("req", req),
+	)
+
+	return v, err
+}
+
+// DepositMethods handles logging for client DepositMethods function
+func (c *LoggingClient) DepositMethods(ctx context.Context, asset string) (DepositMethods, error) {
+	start := time.Now()
+	v, err := c.inner.DepositMethods(ctx, asset)
+	c.log(ctx, "DepositMethods", start, err,
+		slog.Any("asset", asset),
+	)
+
+	return v, err
+}
+
+// DepositAddresses handles logging for client DepositAddresses function
+func (c *LoggingClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error) {
+	start := time.Now()
+	v, err := c.inner.DepositAddresses(ctx, asset, method, new)
+	c.log(ctx, "DepositAddresses", start, err,
+		slog.Any("asset", asset),
+		slog.Any("method", method),
+		slog.Any("new", new),
+	)
+
+	return v, err
+}
+
+// DepositStatus handles logging for client DepositStatus function
+func (c *LoggingClient) DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error) {
+	start := time.Now()
+	v, err := c.inner.DepositStatus(ctx, asset, method)
+	c.log(ctx, "DepositStatus", start, err,
+		slog.Any("asset", asset),
+		slog.Any("method", method),
+	)
+
+	return v, err
+}
+
+// WithdrawInfo handles logging for client WithdrawInfo function
+func (c *LoggingClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error) {
+	start := time.Now()
+	v, err := c.inner.WithdrawInfo(ctx, asset, key, amount)
+	c.log(ctx, "WithdrawInfo", start, err,
+		slog.Any("asset", asset),
+		slog.Any("key", key),
+		slog.Any("amount", amount),
+	)
+
+	return v, err
+}
+
+// Withdraw handles logging for client Withdraw function
+func (c *LoggingClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error) {
+	start := time.Now()
+	v, err := c.inner.Withdraw(ctx, asset, key, amount)
+	c.log(ctx, "Withdraw", start, err,
+		slog.Any("asset", asset),
+		slog.Any("key", key),
+		slog.Any("amount", amount),
+	)
+
+	return v, err
+}
+
+// WithdrawStatus handles logging for client WithdrawStatus function
+func (c *LoggingClient) WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error) {
+	start := time.Now()
+	v, err := c.inner.WithdrawStatus(ctx, asset, method)
+	c.log(ctx, "WithdrawStatus", start, err,
+		slog.Any("asset", asset),
+		slog.Any("method", method),
+	)
+
+	return v, err
+}
+
+// WithdrawCancel handles logging for client WithdrawCancel function
+func (c *LoggingClient) WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error) {
+	start := time.Now()
+	v, err := c.inner.WithdrawCancel(ctx, asset, refid)
+	c.log(ctx, "WithdrawCancel", start, err,
+		slog.Any("asset", asset),
+		slog.Any("refid", refid),
+	)
+
+	return v, err
+}
+
+// WalletTransfer handles logging for client WalletTransfer function
+func (c *LoggingClient) WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error) {
+	start := time.Now()
+	v, err := c.inner.WalletTransfer(ctx, asset, from, to, amount)
+	c.log(ctx, "WalletTransfer", start, err,
+		slog.Any("asset", asset),
+		slog.Any("from", from),
+		slog.Any("to", to),
+		slog.Any("amount", amount),
+	)
+
+	return v, err
+}
+
+// EarnStrategies handles logging for client EarnStrategies function
+func (c *LoggingClient) EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error) {
+	start := time.Now()
+	v, err := c.inner.EarnStrategies(ctx, asset, cursor)
+	c.log(ctx, "EarnStrategies", start, err,
+		slog.Any("asset", asset),
+		slog.Any("cursor", cursor),
+	)
+
+	return v, err
+}
+
+// EarnAllocate handles logging for client EarnAllocate function
+func (c *LoggingClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	start := time.Now()
+	v, err := c.inner.EarnAllocate(ctx, strategyID, amount)
+	c.log(ctx, "EarnAllocate", start, err,
+		slog.Any("strategyID", strategyID),
+		slog.Any("amount", amount),
+	)
+
+	return v, err
+}
+
+// EarnDeallocate handles logging for client EarnDeallocate function
+func (c *LoggingClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	start := time.Now()
+	v, err := c.inner.EarnDeallocate(ctx, strategyID, amount)
+	c.log(ctx, "EarnDeallocate", start, err,
+		slog.Any("strategyID", strategyID),
+		slog.Any("amount", amount),
+	)
+
+	return v, err
+}
+
+// EarnAllocationStatus handles logging for client EarnAllocationStatus function
+func (c *LoggingClient) EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error) {
+	start := time.Now()
+	v, err := c.inner.EarnAllocationStatus(ctx, strategyID)
+	c.log(ctx, "EarnAllocationStatus", start, err,
+		slog.Any("strategyID", strategyID),
+	)
+
+	return v, err
+}
+
+// EarnAllocations handles logging for client EarnAllocations function
+func (c *LoggingClient) EarnAllocations(ctx context.Context) (EarnAllocations, error) {
+	start := time.Now()
+	v, err := c.inner.EarnAllocations(ctx)
+	c.log(ctx, "EarnAllocations", start, err)
+
+	return v, err
+}
+
+// AddExport handles logging for client AddExport function
+func (c *LoggingClient) AddExport(ctx context.Context, req ExportRequest) (AddExportResult, error) {
+	start := time.Now()
+	v, err := c.inner.AddExport(ctx, req)
+	c.log(ctx, "AddExport", start, err,
+		slog.Any("req", req),
+	)
+
+	return v, err
+}
+
+// ExportStatus handles logging for client ExportStatus function
+func (c *LoggingClient) ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error) {
+	start := time.Now()
+	v, err := c.inner.ExportStatus(ctx, report)
+	c.log(ctx, "ExportStatus", start, err,
+		slog.Any("report", report),
+	)
+
+	return v, err
+}
+
+// RetrieveExport handles logging for client RetrieveExport function
+func (c *LoggingClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	start := time.Now()
+	v, err := c.inner.RetrieveExport(ctx, id)
+	c.log(ctx, "RetrieveExport", start, err,
+		slog.Any("id", id),
+	)
+
+	return v, err
+}
+
+// RemoveExport handles logging for client RemoveExport function
+func (c *LoggingClient) RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error) {
+	start := time.Now()
+	v, err := c.inner.RemoveExport(ctx, id, removal)
+	c.log(ctx, "RemoveExport", start, err,
+		slog.Any("id", id),
+		slog.Any("removal", removal),
+	)
+
+	return v, err
+}
+
+// CreateSubaccount handles logging for client CreateSubaccount function
+func (c *LoggingClient) CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error) {
+	start := time.Now()
+	v, err := c.inner.CreateSubaccount(ctx, username, email)
+	c.log(ctx, "CreateSubaccount", start, err,
+		slog.Any("username", username),
+		slog.Any("email", email),
+	)
+
+	return v, err
+}
+
+// AccountTransfer handles logging for client AccountTransfer function
+func (c *LoggingClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error) {
+	start := time.Now()
+	v, err := c.inner.AccountTransfer(ctx, asset, amount, fromUser, toUser)
+	c.log(ctx, "AccountTransfer", start, err,
+		slog.Any("asset", asset),
+		slog.Any("amount", amount),
+		slog.Any("fromUser", fromUser),
+		slog.Any("toUser", toUser),
+	)
+
+	return v, err
+}
+
+// GetWebSocketsToken handles logging for client GetWebSocketsToken function
+func (c *LoggingClient) GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error) {
+	start := time.Now()
+	v, err := c.inner.GetWebSocketsToken(ctx)
+	c.log(ctx, "GetWebSocketsToken", start, err)
+
+	return v, err
+}
@@ -0,0 +1,172 @@
+package kraken_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+func TestLoggingClient(t *testing.T) {
+	since := uint64(1616663618)
+
+	tcs := []struct {
+		name     string
+		body     string
+		call     func(c *kraken.LoggingClient)
+		expected map[string]interface{}
+	}{
+		{
+			name: "Success",
+			body: `{"error":[],"result":{}}`,
+			call: func(c *kraken.LoggingClient) {
+				c.OHLC(context.Background(), kraken.OHLCIntervalMinute, &since, "XBTUSD")
+			},
+			expected: map[string]interface{}{
+				"level":     "DEBUG",
+				"operation": "OHLC",
+				"args": map[string]interface{}{
+					"interval": float64(1),
+					"since":    float64(1616663618),
+					"pairs":    []interface{}{"XBTUSD"},
+				},
+			},
+		},
+		{
+			name: "Failure",
+			body: `{"error":`,
+			call: func(c *kraken.LoggingClient) {
+				c.RecentTrades(context.Background(), nil, "XBTUSD")
+			},
+			expected: map[string]interface{}{
+				"level":     "WARN",
+				"operation": "RecentTrades",
+				"args": map[string]interface{}{
+					"since": nil,
+					"pairs": []interface{}{"XBTUSD"},
+				},
+				"error": `Trades pair=XBTUSD: parse error:unexpected end of JSON input: payload: {"error":`,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.body))
+			}))
+			defer s.Close()
+
+			inner, err := kraken.NewHTTPClient(kraken.HTTPClientWithBaseURL(s.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf := bytes.Buffer{}
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			c, err := kraken.NewLoggingClient(inner, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.call(c)
+
+			entry := map[string]interface{}{}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := entry["duration"]; !ok {
+				t.Error("duration not logged")
+			}
+
+			delete(entry, "time")
+			delete(entry, "msg")
+			delete(entry, "duration")
+
+			if diff := deep.Equal(tc.expected, entry); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestLoggingClientLevels(t *testing.T) {
+	inner, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	c, err := kraken.NewLoggingClient(inner, logger, kraken.LoggingWithFailureLevel(slog.LevelError))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Time(context.Background())
+
+	if !strings.Contains(buf.String(), "level=ERROR") {
+		t.Errorf("failure not logged at error level: %s", buf.String())
+	}
+}
+
+func TestLoggingClientRedaction(t *testing.T) {
+	key := "redaction-test-api-key"
+	secret := base64.StdEncoding.EncodeToString([]byte("redaction-test-secret"))
+
+	var signatures []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get("API-Sign"))
+		w.Write([]byte(`{"error":["EAPI:Invalid key"]}`))
+	}))
+	defer s.Close()
+
+	inner, err := kraken.NewHTTPClient(
+		kraken.HTTPClientWithBaseURL(s.URL),
+		kraken.HTTPClientWithAPIKey(key),
+		kraken.HTTPClientWithSecret(secret),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c, err := kraken.NewLoggingClient(inner, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c.TradeBalance(ctx, "ZUSD")
+	c.CancelOrder(ctx, "OYVGEW-VYV5B-UUEXSK")
+	c.AddOrder(ctx, kraken.OrderRequest{
+		Pair:   "XBTUSD",
+		Action: kraken.OrderActionBuy,
+		Type:   kraken.OrderTypeMarket,
+		Volume: decimal.New(1, 0),
+	})
+
+	if len(signatures) == 0 {
+		t.Fatal("no signed requests made")
+	}
+
+	for _, sensitive := range append([]string{key, secret, "redaction-test-secret"}, signatures...) {
+		if strings.Contains(buf.String(), sensitive) {
+			t.Errorf("log contains %q", sensitive)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package kraken
+
+import "log/slog"
+
+// LoggingOption options used when creating a new LoggingClient
+type LoggingOption func(c *LoggingClient) error
+
+// LoggingWithSuccessLevel set the level successful calls are logged at
+func LoggingWithSuccessLevel(level slog.Level) LoggingOption {
+	return LoggingOption(func(c *LoggingClient) error {
+		c.successLevel = level
+
+		return nil
+	})
+}
+
+// LoggingWithFailureLevel set the level failed calls are logged at
+func LoggingWithFailureLevel(level slog.Level) LoggingOption {
+	return LoggingOption(func(c *LoggingClient) error {
+		c.failureLevel = level
+
+		return nil
+	})
+}
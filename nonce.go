@@ -0,0 +1,91 @@
+package kraken
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// NonceGenerator generates the nonces of private requests, Kraken rejects a
+// nonce which is not greater than the last nonce used with an API key
+type NonceGenerator interface {
+	Next() uint64
+}
+
+// ClockNonceGenerator a NonceGenerator following the unix time in nanoseconds,
+// a nonce is always greater than the last even when called concurrently or
+// when the clock has not advanced
+type ClockNonceGenerator struct {
+	last uint64
+}
+
+// NewClockNonceGenerator helper function for creating a new
+// ClockNonceGenerator
+func NewClockNonceGenerator() *ClockNonceGenerator {
+	return &ClockNonceGenerator{}
+}
+
+// Next return the next nonce
+func (g *ClockNonceGenerator) Next() uint64 {
+	for {
+		last := atomic.LoadUint64(&g.last)
+
+		next := uint64(time.Now().UnixNano())
+		if next <= last {
+			next = last + 1
+		}
+
+		if atomic.CompareAndSwapUint64(&g.last, last, next) {
+			return next
+		}
+	}
+}
+
+// NonceStore persists the last nonce used so that nonces continue to increase
+// across restarts of a process
+type NonceStore interface {
+	Load() (uint64, error)
+	Save(nonce uint64) error
+}
+
+// FileNonceStore a NonceStore persisting the last nonce to a file
+type FileNonceStore struct {
+	path string
+}
+
+// NewFileNonceStore helper function for creating a new FileNonceStore, the
+// file is created on the first save
+func NewFileNonceStore(path string) *FileNonceStore {
+	return &FileNonceStore{
+		path: path,
+	}
+}
+
+// Load return the last saved nonce, zero is returned when no nonce has been
+// saved yet
+func (s *FileNonceStore) Load() (uint64, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// Save persist a nonce, the file is replaced atomically so a crash cannot
+// leave a partially written nonce
+func (s *FileNonceStore) Save(nonce uint64) error {
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatUint(nonce, 10)), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
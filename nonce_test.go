@@ -0,0 +1,73 @@
+package kraken_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/oliread/kraken"
+)
+
+func TestClockNonceGenerator(t *testing.T) {
+	const (
+		goroutines = 8
+		perRoutine = 1000
+	)
+
+	g := kraken.NewClockNonceGenerator()
+
+	nonces := make([][]uint64, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			nonces[i] = make([]uint64, perRoutine)
+			for j := range nonces[i] {
+				nonces[i][j] = g.Next()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool, goroutines*perRoutine)
+	for _, routineNonces := range nonces {
+		for j, nonce := range routineNonces {
+			if seen[nonce] {
+				t.Fatalf("nonce %d generated more than once", nonce)
+			}
+			seen[nonce] = true
+
+			if j > 0 && nonce <= routineNonces[j-1] {
+				t.Fatalf("nonce %d not greater than previous nonce %d", nonce, routineNonces[j-1])
+			}
+		}
+	}
+}
+
+func TestFileNonceStore(t *testing.T) {
+	s := kraken.NewFileNonceStore(filepath.Join(t.TempDir(), "nonce"))
+
+	nonce, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if nonce != 0 {
+		t.Fatalf("EXPECTED: 0\nACTUAL: %d", nonce)
+	}
+
+	if err := s.Save(1616492376594); err != nil {
+		t.Fatal(err)
+	}
+
+	nonce, err = s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if nonce != 1616492376594 {
+		t.Fatalf("EXPECTED: 1616492376594\nACTUAL: %d", nonce)
+	}
+}
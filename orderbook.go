@@ -0,0 +1,221 @@
+package kraken
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// basisPoints the number of basis points in a whole
+var basisPoints = decimal.NewFromInt(10000)
+
+// Fill the result of walking the levels of a book to fill a volume, the
+// Slippage is how much worse the average price is than the best price
+type Fill struct {
+	Volume       decimal.Decimal
+	Cost         decimal.Decimal
+	AveragePrice decimal.Decimal
+	BestPrice    decimal.Decimal
+	Slippage     decimal.Decimal
+	SlippageBPS  decimal.Decimal
+	Levels       int
+}
+
+// BestBid return the highest bid of a pair, false is returned when the pair
+// has no bids
+func (r OrderBook) BestBid(pair string) (AskBid, bool) {
+	return bestOrderBookLevel(r.Bids[pair], bidBefore)
+}
+
+// BestAsk return the lowest ask of a pair, false is returned when the pair
+// has no asks
+func (r OrderBook) BestAsk(pair string) (AskBid, bool) {
+	return bestOrderBookLevel(r.Asks[pair], askBefore)
+}
+
+// Mid return the price halfway between the best bid and best ask of a pair,
+// false is returned when either side is empty
+func (r OrderBook) Mid(pair string) (decimal.Decimal, bool) {
+	bid, ok := r.BestBid(pair)
+	if !ok {
+		return decimal.Zero, false
+	}
+
+	ask, ok := r.BestAsk(pair)
+	if !ok {
+		return decimal.Zero, false
+	}
+
+	return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), true
+}
+
+// SpreadBPS return the difference between the best ask and best bid of a pair
+// in basis points of the mid price, negative for a crossed book. False is
+// returned when either side is empty or the mid price is zero
+func (r OrderBook) SpreadBPS(pair string) (decimal.Decimal, bool) {
+	mid, ok := r.Mid(pair)
+	if !ok || mid.IsZero() {
+		return decimal.Zero, false
+	}
+
+	bid, _ := r.BestBid(pair)
+	ask, _ := r.BestAsk(pair)
+
+	return ask.Price.Sub(bid.Price).Div(mid).Mul(basisPoints), true
+}
+
+// CostToBuy return the fill of buying a volume of a pair by walking its asks
+// from the lowest, ErrInsufficientDepth is returned when the asks do not
+// cover the volume
+func (r OrderBook) CostToBuy(pair string, volume decimal.Decimal) (Fill, error) {
+	return fillOrderBook(r.Asks[pair], volume, askBefore)
+}
+
+// CostToSell return the fill of selling a volume of a pair by walking its
+// bids from the highest, ErrInsufficientDepth is returned when the bids do
+// not cover the volume
+func (r OrderBook) CostToSell(pair string, volume decimal.Decimal) (Fill, error) {
+	return fillOrderBook(r.Bids[pair], volume, bidBefore)
+}
+
+// Validate check the asks of each pair are in ascending and the bids in
+// descending price order, as they are when parsed, and the best bid of each
+// pair is below its best ask. The error of the first pair in name order which
+// fails is returned, matching ErrUnsortedBook or ErrCrossedBook
+func (r OrderBook) Validate() error {
+	pairs := make([]string, 0, len(r.Asks)+len(r.Bids))
+	for pair := range r.Asks {
+		pairs = append(pairs, pair)
+	}
+	for pair := range r.Bids {
+		if _, ok := r.Asks[pair]; !ok {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Strings(pairs)
+
+	for _, pair := range pairs {
+		if err := validateOrderBookSide(pair, "asks", r.Asks[pair], askBefore); err != nil {
+			return err
+		}
+
+		if err := validateOrderBookSide(pair, "bids", r.Bids[pair], bidBefore); err != nil {
+			return err
+		}
+
+		if err := crossedOrderBook(pair, r.Asks[pair], r.Bids[pair]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// askBefore whether an ask at price a is before one at price b
+func askBefore(a, b decimal.Decimal) bool {
+	return a.LessThan(b)
+}
+
+// bidBefore whether a bid at price a is before one at price b
+func bidBefore(a, b decimal.Decimal) bool {
+	return a.GreaterThan(b)
+}
+
+// sortOrderBookLevels sort the levels of a side best first, levels at the
+// same price are in timestamp order
+func sortOrderBookLevels(levels []AskBid, before func(a, b decimal.Decimal) bool) {
+	sort.SliceStable(levels, func(i, j int) bool {
+		if !levels[i].Price.Equal(levels[j].Price) {
+			return before(levels[i].Price, levels[j].Price)
+		}
+
+		return levels[i].Timestamp.Before(levels[j].Timestamp)
+	})
+}
+
+// validateOrderBookSide check the levels of a side are best first
+func validateOrderBookSide(pair, side string, levels []AskBid, before func(a, b decimal.Decimal) bool) error {
+	for i := 1; i < len(levels); i++ {
+		if before(levels[i].Price, levels[i-1].Price) {
+			return fmt.Errorf("%w:%s %s price %s is after %s", ErrUnsortedBook, pair, side, levels[i].Price, levels[i-1].Price)
+		}
+	}
+
+	return nil
+}
+
+// crossedOrderBook check the best bid of sorted sides is below the best ask
+func crossedOrderBook(pair string, asks, bids []AskBid) error {
+	if len(asks) == 0 || len(bids) == 0 {
+		return nil
+	}
+
+	if !bids[0].Price.LessThan(asks[0].Price) {
+		return fmt.Errorf("%w:%s best bid %s is not below best ask %s", ErrCrossedBook, pair, bids[0].Price, asks[0].Price)
+	}
+
+	return nil
+}
+
+// bestOrderBookLevel the level of a side which is before all others, the
+// levels are not assumed to be sorted
+func bestOrderBookLevel(levels []AskBid, before func(a, b decimal.Decimal) bool) (AskBid, bool) {
+	if len(levels) == 0 {
+		return AskBid{}, false
+	}
+
+	best := levels[0]
+	for _, level := range levels[1:] {
+		if before(level.Price, best.Price) {
+			best = level
+		}
+	}
+
+	return best, true
+}
+
+// fillOrderBook walk a copy of the levels of a side sorted best first until
+// the volume is filled
+func fillOrderBook(levels []AskBid, volume decimal.Decimal, before func(a, b decimal.Decimal) bool) (Fill, error) {
+	if !volume.IsPositive() {
+		return Fill{}, fmt.Errorf("volume must be positive")
+	}
+
+	sorted := append([]AskBid(nil), levels...)
+	sort.SliceStable(sorted, func(i, j int) bool { return before(sorted[i].Price, sorted[j].Price) })
+
+	fill := Fill{Volume: volume}
+	remaining := volume
+	for _, level := range sorted {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		if !level.Volume.IsPositive() {
+			continue
+		}
+
+		if fill.Levels == 0 {
+			fill.BestPrice = level.Price
+		}
+
+		filled := decimal.Min(remaining, level.Volume)
+		fill.Cost = fill.Cost.Add(filled.Mul(level.Price))
+		fill.Levels++
+		remaining = remaining.Sub(filled)
+	}
+
+	if remaining.IsPositive() {
+		return Fill{}, fmt.Errorf("%w:%s of %s unfilled", ErrInsufficientDepth, remaining, volume)
+	}
+
+	fill.AveragePrice = fill.Cost.Div(volume)
+	fill.Slippage = fill.AveragePrice.Sub(fill.BestPrice).Abs()
+
+	if !fill.BestPrice.IsZero() {
+		fill.SlippageBPS = fill.Slippage.Div(fill.BestPrice).Mul(basisPoints)
+	}
+
+	return fill, nil
+}
@@ -0,0 +1,209 @@
+package kraken_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// analyticsBook a book for XXBTZUSD with levels out of order
+func analyticsBook() kraken.OrderBook {
+	return kraken.OrderBook{
+		Asks: map[string][]kraken.AskBid{
+			"XXBTZUSD": {askBid("102", "2", 1), askBid("101", "1", 1), askBid("104", "3", 1)},
+		},
+		Bids: map[string][]kraken.AskBid{
+			"XXBTZUSD": {askBid("98", "2", 1), askBid("99", "1", 1), askBid("95", "4", 1)},
+		},
+	}
+}
+
+func TestOrderBookBestAskBid(t *testing.T) {
+	book := analyticsBook()
+
+	bid, ok := book.BestBid("XXBTZUSD")
+	if !ok || !bid.Price.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("EXPECTED: 99 true\nACTUAL: %s %v", bid.Price, ok)
+	}
+
+	ask, ok := book.BestAsk("XXBTZUSD")
+	if !ok || !ask.Price.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("EXPECTED: 101 true\nACTUAL: %s %v", ask.Price, ok)
+	}
+
+	mid, ok := book.Mid("XXBTZUSD")
+	if !ok || !mid.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("EXPECTED: 100 true\nACTUAL: %s %v", mid, ok)
+	}
+
+	spread, ok := book.SpreadBPS("XXBTZUSD")
+	if !ok || !spread.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("EXPECTED: 200 true\nACTUAL: %s %v", spread, ok)
+	}
+
+	book.Asks["XXBTZUSD"] = nil
+	if _, ok := book.BestAsk("XXBTZUSD"); ok {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+
+	if _, ok := book.Mid("XXBTZUSD"); ok {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+
+	if _, ok := book.SpreadBPS("XXBTZUSD"); ok {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+
+	if _, ok := book.BestBid("XETHZUSD"); ok {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+}
+
+func TestOrderBookCost(t *testing.T) {
+	tcs := []struct {
+		name     string
+		sell     bool
+		volume   string
+		expected kraken.Fill
+	}{
+		{
+			name:   "BuyWithinLevel",
+			volume: "0.5",
+			expected: kraken.Fill{
+				Volume:       decimal.RequireFromString("0.5"),
+				Cost:         decimal.RequireFromString("50.5"),
+				AveragePrice: decimal.RequireFromString("101"),
+				BestPrice:    decimal.RequireFromString("101"),
+				Slippage:     decimal.RequireFromString("0"),
+				SlippageBPS:  decimal.RequireFromString("0"),
+				Levels:       1,
+			},
+		},
+		{
+			name:   "BuyExactlyConsumingLevels",
+			volume: "3",
+			expected: kraken.Fill{
+				Volume:       decimal.RequireFromString("3"),
+				Cost:         decimal.RequireFromString("305"),
+				AveragePrice: decimal.RequireFromString("101.6666666666666667"),
+				BestPrice:    decimal.RequireFromString("101"),
+				Slippage:     decimal.RequireFromString("0.6666666666666667"),
+				SlippageBPS:  decimal.RequireFromString("66.006600660066"),
+				Levels:       2,
+			},
+		},
+		{
+			name:   "BuyWholeBook",
+			volume: "6",
+			expected: kraken.Fill{
+				Volume:       decimal.RequireFromString("6"),
+				Cost:         decimal.RequireFromString("617"),
+				AveragePrice: decimal.RequireFromString("102.8333333333333333"),
+				BestPrice:    decimal.RequireFromString("101"),
+				Slippage:     decimal.RequireFromString("1.8333333333333333"),
+				SlippageBPS:  decimal.RequireFromString("181.518151815182"),
+				Levels:       3,
+			},
+		},
+		{
+			name:   "SellAcrossLevels",
+			sell:   true,
+			volume: "2",
+			expected: kraken.Fill{
+				Volume:       decimal.RequireFromString("2"),
+				Cost:         decimal.RequireFromString("197"),
+				AveragePrice: decimal.RequireFromString("98.5"),
+				BestPrice:    decimal.RequireFromString("99"),
+				Slippage:     decimal.RequireFromString("0.5"),
+				SlippageBPS:  decimal.RequireFromString("50.505050505051"),
+				Levels:       2,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			book := analyticsBook()
+			cost := book.CostToBuy
+			if tc.sell {
+				cost = book.CostToSell
+			}
+
+			actual, err := cost("XXBTZUSD", decimal.RequireFromString(tc.volume))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestOrderBookCostErrors(t *testing.T) {
+	book := analyticsBook()
+
+	if _, err := book.CostToBuy("XXBTZUSD", decimal.RequireFromString("6.0001")); !errors.Is(err, kraken.ErrInsufficientDepth) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrInsufficientDepth, err)
+	}
+
+	if _, err := book.CostToSell("XETHZUSD", decimal.NewFromInt(1)); !errors.Is(err, kraken.ErrInsufficientDepth) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrInsufficientDepth, err)
+	}
+
+	if _, err := book.CostToBuy("XXBTZUSD", decimal.Zero); err == nil {
+		t.Error("EXPECTED: volume must be positive\nACTUAL: <nil>")
+	}
+}
+
+func TestOrderBookValidate(t *testing.T) {
+	tcs := []struct {
+		name     string
+		asks     []kraken.AskBid
+		bids     []kraken.AskBid
+		expected error
+	}{
+		{
+			name: "Valid",
+			asks: []kraken.AskBid{askBid("101", "1", 1), askBid("101", "2", 2), askBid("102", "1", 1)},
+			bids: []kraken.AskBid{askBid("99", "1", 1), askBid("98", "1", 1)},
+		},
+		{
+			name: "OneSide",
+			asks: []kraken.AskBid{askBid("101", "1", 1)},
+		},
+		{
+			name:     "UnsortedAsks",
+			asks:     []kraken.AskBid{askBid("102", "1", 1), askBid("101", "1", 1)},
+			expected: kraken.ErrUnsortedBook,
+		},
+		{
+			name:     "UnsortedBids",
+			bids:     []kraken.AskBid{askBid("98", "1", 1), askBid("99", "1", 1)},
+			expected: kraken.ErrUnsortedBook,
+		},
+		{
+			name:     "Crossed",
+			asks:     []kraken.AskBid{askBid("101", "1", 1)},
+			bids:     []kraken.AskBid{askBid("101", "1", 1)},
+			expected: kraken.ErrCrossedBook,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			book := kraken.OrderBook{
+				Asks: map[string][]kraken.AskBid{"XXBTZUSD": tc.asks},
+				Bids: map[string][]kraken.AskBid{"XXBTZUSD": tc.bids},
+			}
+
+			if err := book.Validate(); !errors.Is(err, tc.expected) || (err == nil) != (tc.expected == nil) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expected, err)
+			}
+		})
+	}
+}
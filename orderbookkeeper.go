@@ -0,0 +1,390 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// orderBookEventBufferSize the number of events buffered before further
+	// events are dropped
+	orderBookEventBufferSize = 64
+	// orderBookChecksumLevels the number of levels of each side included in
+	// a book checksum
+	orderBookChecksumLevels = 10
+	// orderBookUnsubscribeTimeout the time allowed to unsubscribe once Run
+	// has returned
+	orderBookUnsubscribeTimeout = 5 * time.Second
+)
+
+// OrderBookEventType the type of an OrderBookEvent
+type OrderBookEventType byte
+
+// String return a string value of the order book event type
+func (t OrderBookEventType) String() string {
+	switch t {
+	case OrderBookEventChecksumMismatch:
+		return "checksum mismatch"
+	case OrderBookEventResynced:
+		return "resynced"
+	case OrderBookEventResyncFailed:
+		return "resync failed"
+	case OrderBookEventGapDetected:
+		return "gap detected"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// OrderBookEventChecksumMismatch enum representing a book which no longer
+	// matches the checksum given by the API, it is discarded until resynced
+	OrderBookEventChecksumMismatch = iota
+	// OrderBookEventResynced enum representing a book rebuilt from a new
+	// snapshot after a checksum mismatch or gap
+	OrderBookEventResynced
+	// OrderBookEventResyncFailed enum representing a book which could not be
+	// resubscribed to after a checksum mismatch or gap
+	OrderBookEventResyncFailed
+	// OrderBookEventGapDetected enum representing a book which an update
+	// could not have followed, as updates were missed or received out of
+	// order. It is discarded until resynced
+	OrderBookEventGapDetected
+	// OrderBookEventUnknown enum representing an unknown event
+	OrderBookEventUnknown
+)
+
+// OrderBookEvent a change in the state of a pair's book maintained by an
+// OrderBookKeeper
+type OrderBookEvent struct {
+	Pair string
+	Type OrderBookEventType
+	Err  error
+}
+
+// OrderBookKeeper maintain a local order book for each pair from the
+// websocket book channel. The book of a pair is validated against the
+// checksum of each update and resynced from a new snapshot on a mismatch
+type OrderBookKeeper struct {
+	client *WSClient
+	depth  int
+	pairs  []string
+	events chan OrderBookEvent
+	resync chan string
+
+	mu        sync.RWMutex
+	books     map[string]*localOrderBook
+	resyncing map[string]bool
+}
+
+// localOrderBook the levels of a pair's book, asks are sorted by ascending
+// price and bids by descending price
+type localOrderBook struct {
+	asks []AskBid
+	bids []AskBid
+}
+
+// NewOrderBookKeeper helper function for creating a new OrderBookKeeper,
+// the books of pairs are kept to a depth of 10, 25, 100, 500 or 1000 levels
+func NewOrderBookKeeper(client *WSClient, depth int, pairs ...string) (*OrderBookKeeper, error) {
+	if err := validateBookDepth(depth); err != nil {
+		return nil, err
+	}
+
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("at least one pair is required")
+	}
+
+	// v2 checksums are of levels at the precision of the pair, which is
+	// lost by the JSON numbers of its book channel
+	if client.version != WSVersion1 {
+		return nil, fmt.Errorf("book checksums are not supported by websocket %s", client.version)
+	}
+
+	return &OrderBookKeeper{
+		client:    client,
+		depth:     depth,
+		pairs:     pairs,
+		events:    make(chan OrderBookEvent, orderBookEventBufferSize),
+		resync:    make(chan string, len(pairs)),
+		books:     map[string]*localOrderBook{},
+		resyncing: map[string]bool{},
+	}, nil
+}
+
+// Events return the channel events are delivered on, events are dropped
+// while its buffer is full
+func (k *OrderBookKeeper) Events() <-chan OrderBookEvent {
+	return k.events
+}
+
+// Run subscribe to the book channel of the pairs and keep their books until
+// ctx is done or the connection ends, resubscribing to a pair whenever its
+// book does not match a checksum
+func (k *OrderBookKeeper) Run(ctx context.Context) error {
+	conn, err := k.client.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := wsSubscriptionOptions{Name: "book", Depth: k.depth}
+	sub := &wsSubscription{
+		handle: func(pair string, data []json.RawMessage) error {
+			update, err := k.client.parser.parseWSBook(k.depth, pair, data)
+			if err != nil {
+				return err
+			}
+
+			return k.Apply(update)
+		},
+		close: func() {},
+	}
+
+	if err := k.client.subscribe(ctx, conn, options, k.pairs, sub); err != nil {
+		return err
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), orderBookUnsubscribeTimeout)
+		defer cancel()
+
+		k.client.unsubscribe(ctx, conn, options, k.pairs)
+		k.client.release(sub)
+	}()
+
+	for {
+		select {
+		case pair := <-k.resync:
+			err := k.client.unsubscribe(ctx, conn, options, []string{pair})
+			if err == nil {
+				err = k.client.subscribe(ctx, conn, options, []string{pair}, sub)
+			}
+
+			if err != nil {
+				k.emit(OrderBookEvent{Pair: pair, Type: OrderBookEventResyncFailed, Err: err})
+				return err
+			}
+		case <-conn.done:
+			return conn.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Apply apply a snapshot or update to the book of its pair, updates received
+// before a snapshot are ignored. An ErrWSGap is returned when the update
+// could not have followed the book, either deleting a level within the book
+// which it does not have or with a level older than the book's, and an
+// ErrChecksum when the updated book does not match the checksum of the
+// update. The book is then discarded and the pair resynced by Run
+func (k *OrderBookKeeper) Apply(update BookUpdate) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if update.Snapshot {
+		book := &localOrderBook{
+			asks: append([]AskBid{}, update.Asks...),
+			bids: append([]AskBid{}, update.Bids...),
+		}
+		sort.SliceStable(book.asks, func(i, j int) bool { return book.asks[i].Price.LessThan(book.asks[j].Price) })
+		sort.SliceStable(book.bids, func(i, j int) bool { return book.bids[i].Price.GreaterThan(book.bids[j].Price) })
+		book.truncate(k.depth)
+
+		k.books[update.Pair] = book
+		if k.resyncing[update.Pair] {
+			delete(k.resyncing, update.Pair)
+			k.emit(OrderBookEvent{Pair: update.Pair, Type: OrderBookEventResynced})
+		}
+
+		return nil
+	}
+
+	book, ok := k.books[update.Pair]
+	if !ok {
+		return nil
+	}
+
+	for _, ask := range update.Asks {
+		if gap := orderBookGap(book.asks, ask, decimal.Decimal.LessThan); gap != "" {
+			return k.discard(update.Pair, OrderBookEventGapDetected, fmt.Errorf("%w: %s book ask %s %s", ErrWSGap, update.Pair, ask.Price, gap))
+		}
+
+		book.asks = applyOrderBookLevel(book.asks, ask, decimal.Decimal.LessThan)
+	}
+
+	for _, bid := range update.Bids {
+		if gap := orderBookGap(book.bids, bid, decimal.Decimal.GreaterThan); gap != "" {
+			return k.discard(update.Pair, OrderBookEventGapDetected, fmt.Errorf("%w: %s book bid %s %s", ErrWSGap, update.Pair, bid.Price, gap))
+		}
+
+		book.bids = applyOrderBookLevel(book.bids, bid, decimal.Decimal.GreaterThan)
+	}
+
+	book.truncate(k.depth)
+
+	if checksum := book.checksum(); checksum != update.Checksum {
+		return k.discard(update.Pair, OrderBookEventChecksumMismatch, fmt.Errorf("%w: %s book expected %d, calculated %d", ErrChecksum, update.Pair, update.Checksum, checksum))
+	}
+
+	return nil
+}
+
+// discard discard the book of a pair until it has been resynced by Run, an
+// event of the reason is emitted and its error returned
+func (k *OrderBookKeeper) discard(pair string, reason OrderBookEventType, err error) error {
+	delete(k.books, pair)
+	k.resyncing[pair] = true
+	k.emit(OrderBookEvent{Pair: pair, Type: reason, Err: err})
+
+	select {
+	case k.resync <- pair:
+	default:
+	}
+
+	return err
+}
+
+// BestBid return the highest bid of a pair, false is returned when the pair
+// has no book or no bids
+func (k *OrderBookKeeper) BestBid(pair string) (AskBid, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	book, ok := k.books[pair]
+	if !ok || len(book.bids) == 0 {
+		return AskBid{}, false
+	}
+
+	return book.bids[0], true
+}
+
+// BestAsk return the lowest ask of a pair, false is returned when the pair
+// has no book or no asks
+func (k *OrderBookKeeper) BestAsk(pair string) (AskBid, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	book, ok := k.books[pair]
+	if !ok || len(book.asks) == 0 {
+		return AskBid{}, false
+	}
+
+	return book.asks[0], true
+}
+
+// Snapshot return a copy of the book of every pair, pairs awaiting a
+// snapshot are not included
+func (k *OrderBookKeeper) Snapshot() OrderBook {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	snapshot := OrderBook{
+		Asks: make(map[string][]AskBid, len(k.books)),
+		Bids: make(map[string][]AskBid, len(k.books)),
+	}
+
+	for pair, book := range k.books {
+		snapshot.Asks[pair] = append([]AskBid{}, book.asks...)
+		snapshot.Bids[pair] = append([]AskBid{}, book.bids...)
+	}
+
+	return snapshot
+}
+
+// emit deliver an event unless the buffer is full
+func (k *OrderBookKeeper) emit(event OrderBookEvent) {
+	select {
+	case k.events <- event:
+	default:
+	}
+}
+
+// applyOrderBookLevel insert, replace or remove a level of a side sorted by
+// before, a level with a zero volume is removed
+func applyOrderBookLevel(levels []AskBid, level AskBid, before func(a, b decimal.Decimal) bool) []AskBid {
+	i := sort.Search(len(levels), func(i int) bool { return !before(levels[i].Price, level.Price) })
+	if i < len(levels) && levels[i].Price.Equal(level.Price) {
+		if level.Volume.IsZero() {
+			return append(levels[:i], levels[i+1:]...)
+		}
+
+		levels[i] = level
+		return levels
+	}
+
+	if level.Volume.IsZero() {
+		return levels
+	}
+
+	levels = append(levels, AskBid{})
+	copy(levels[i+1:], levels[i:])
+	levels[i] = level
+
+	return levels
+}
+
+// orderBookGap return why a level of an update could not have followed a
+// side sorted by before, or an empty string when it could. Levels beyond
+// the worst of a side may have been truncated so are not checked
+func orderBookGap(levels []AskBid, level AskBid, before func(a, b decimal.Decimal) bool) string {
+	i := sort.Search(len(levels), func(i int) bool { return !before(levels[i].Price, level.Price) })
+	if i < len(levels) && levels[i].Price.Equal(level.Price) {
+		if !level.Timestamp.IsZero() && level.Timestamp.Before(levels[i].Timestamp) {
+			return "is older than the book"
+		}
+
+		return ""
+	}
+
+	if level.Volume.IsZero() && i < len(levels) {
+		return "is deleted but not in the book"
+	}
+
+	return ""
+}
+
+// truncate remove the levels of each side beyond depth
+func (b *localOrderBook) truncate(depth int) {
+	if len(b.asks) > depth {
+		b.asks = b.asks[:depth]
+	}
+
+	if len(b.bids) > depth {
+		b.bids = b.bids[:depth]
+	}
+}
+
+// checksum calculate the CRC32 of the top ten asks followed by the top ten
+// bids, the price and volume of each level are concatenated with their
+// decimal point and leading zeros removed
+func (b *localOrderBook) checksum() uint32 {
+	var sb strings.Builder
+	for _, levels := range [][]AskBid{b.asks, b.bids} {
+		for i := 0; i < len(levels) && i < orderBookChecksumLevels; i++ {
+			sb.WriteString(checksumDecimal(levels[i].Price))
+			sb.WriteString(checksumDecimal(levels[i].Volume))
+		}
+	}
+
+	return crc32.ChecksumIEEE([]byte(sb.String()))
+}
+
+// checksumDecimal format a decimal as given by the API with its decimal
+// point and leading zeros removed, relying on the exponent being kept
+func checksumDecimal(d decimal.Decimal) string {
+	s := d.Coefficient().String()
+	if exp := d.Exponent(); exp > 0 {
+		s += strings.Repeat("0", int(exp))
+	}
+
+	return strings.TrimLeft(s, "0")
+}
@@ -0,0 +1,259 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// bookLevels levels of a book side given as [price, volume] strings
+func bookLevels(levels ...[2]string) []kraken.AskBid {
+	parsed := make([]kraken.AskBid, 0, len(levels))
+	for _, level := range levels {
+		parsed = append(parsed, kraken.AskBid{
+			Price:  decimal.RequireFromString(level[0]),
+			Volume: decimal.RequireFromString(level[1]),
+		})
+	}
+
+	return parsed
+}
+
+// bookChecksum the checksum of the top ten asks and bids of a book given as
+// [price, volume] strings, as documented by the API
+func bookChecksum(asks, bids [][2]string) uint32 {
+	var sb strings.Builder
+	for _, levels := range [][][2]string{asks, bids} {
+		for i := 0; i < len(levels) && i < 10; i++ {
+			for _, v := range levels[i] {
+				sb.WriteString(strings.TrimLeft(strings.ReplaceAll(v, ".", ""), "0"))
+			}
+		}
+	}
+
+	return crc32.ChecksumIEEE([]byte(sb.String()))
+}
+
+func TestOrderBookKeeperApply(t *testing.T) {
+	client, err := kraken.NewWSClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := kraken.NewOrderBookKeeper(client, 10, "XBT/USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// updates before a snapshot are ignored
+	if err := k.Apply(kraken.BookUpdate{Pair: "XBT/USD", Asks: bookLevels([2]string{"5541.30000", "1.00000000"})}); err != nil {
+		t.Fatal(err)
+	}
+
+	asks := [][2]string{}
+	bids := [][2]string{}
+	for i := 0; i < 11; i++ {
+		asks = append(asks, [2]string{fmt.Sprintf("%d.10000", 5542+i), "0.50000000"})
+		bids = append(bids, [2]string{fmt.Sprintf("%d.10000", 5540-i), "1.25000000"})
+	}
+
+	// levels out of order and beyond the depth
+	if err := k.Apply(kraken.BookUpdate{Pair: "XBT/USD", Depth: 10, Snapshot: true, Asks: bookLevels(append(asks[1:], asks[0])...), Bids: bookLevels(bids...)}); err != nil {
+		t.Fatal(err)
+	}
+	asks, bids = asks[:10], bids[:10]
+
+	updateAsks := [][2]string{{"5542.10000", "0.00000000"}, {"5541.90000", "0.02000000"}, {"5600.00000", "1.00000000"}}
+	updateBids := [][2]string{{"5539.10000", "3.00000000"}, {"5535.10000", "0.00000000"}, {"0.05000", "10.00000000"}}
+
+	asks = append([][2]string{updateAsks[1]}, asks[1:]...)
+	bids = append([][2]string{bids[0], updateBids[0]}, append(append(bids[2:5], bids[6:]...), updateBids[2])...)
+	if err := k.Apply(kraken.BookUpdate{Pair: "XBT/USD", Depth: 10, Asks: bookLevels(updateAsks...), Bids: bookLevels(updateBids...), Checksum: bookChecksum(asks, bids)}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := kraken.OrderBook{
+		Asks: map[string][]kraken.AskBid{"XBT/USD": bookLevels(asks...)},
+		Bids: map[string][]kraken.AskBid{"XBT/USD": bookLevels(bids...)},
+	}
+	if diff := deep.Equal(expected, k.Snapshot()); diff != nil {
+		t.Error(diff)
+	}
+
+	if bid, ok := k.BestBid("XBT/USD"); !ok || !bid.Price.Equal(decimal.RequireFromString("5540.1")) {
+		t.Errorf("EXPECTED: 5540.1\nACTUAL: %s %t", bid.Price, ok)
+	}
+
+	if ask, ok := k.BestAsk("XBT/USD"); !ok || !ask.Price.Equal(decimal.RequireFromString("5541.9")) {
+		t.Errorf("EXPECTED: 5541.9\nACTUAL: %s %t", ask.Price, ok)
+	}
+
+	err = k.Apply(kraken.BookUpdate{Pair: "XBT/USD", Depth: 10, Asks: bookLevels([2]string{"5541.90000", "0.03000000"}), Checksum: 1})
+	if !errors.Is(err, kraken.ErrChecksum) {
+		t.Fatalf("EXPECTED: %v\nACTUAL: %v", kraken.ErrChecksum, err)
+	}
+
+	if _, ok := k.BestAsk("XBT/USD"); ok {
+		t.Error("EXPECTED: book discarded\nACTUAL: best ask returned")
+	}
+
+	select {
+	case event := <-k.Events():
+		if event.Pair != "XBT/USD" || event.Type != kraken.OrderBookEventChecksumMismatch || !errors.Is(event.Err, kraken.ErrChecksum) {
+			t.Errorf("EXPECTED: checksum mismatch event\nACTUAL: %+v", event)
+		}
+	default:
+		t.Error("EXPECTED: checksum mismatch event\nACTUAL: no event")
+	}
+}
+
+func TestOrderBookKeeperGap(t *testing.T) {
+	client, err := kraken.NewWSClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := kraken.BookUpdate{
+		Pair:     "XBT/USD",
+		Depth:    10,
+		Snapshot: true,
+		Asks: []kraken.AskBid{
+			{Price: decimal.RequireFromString("5541.3"), Volume: decimal.RequireFromString("2.507"), Timestamp: time.Unix(1534614248, 0)},
+			{Price: decimal.RequireFromString("5542.5"), Volume: decimal.RequireFromString("0.4"), Timestamp: time.Unix(1534614248, 0)},
+		},
+		Bids: []kraken.AskBid{
+			{Price: decimal.RequireFromString("5541.2"), Volume: decimal.RequireFromString("1.529"), Timestamp: time.Unix(1534614248, 0)},
+		},
+	}
+
+	tcs := []struct {
+		name   string
+		update kraken.BookUpdate
+	}{
+		{
+			name: "out of order",
+			update: kraken.BookUpdate{Pair: "XBT/USD", Depth: 10, Asks: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5541.3"), Volume: decimal.RequireFromString("1"), Timestamp: time.Unix(1534614247, 0)},
+			}},
+		},
+		{
+			name: "missing",
+			update: kraken.BookUpdate{Pair: "XBT/USD", Depth: 10, Asks: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5542.1"), Volume: decimal.Zero, Timestamp: time.Unix(1534614249, 0)},
+			}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := kraken.NewOrderBookKeeper(client, 10, "XBT/USD")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := k.Apply(snapshot); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := k.Apply(tc.update); !errors.Is(err, kraken.ErrWSGap) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", kraken.ErrWSGap, err)
+			}
+
+			if _, ok := k.BestAsk("XBT/USD"); ok {
+				t.Error("EXPECTED: book discarded\nACTUAL: best ask returned")
+			}
+
+			if event := <-k.Events(); event.Type != kraken.OrderBookEventGapDetected || !errors.Is(event.Err, kraken.ErrWSGap) {
+				t.Errorf("EXPECTED: gap detected event\nACTUAL: %+v", event)
+			}
+
+			if err := k.Apply(snapshot); err != nil {
+				t.Fatal(err)
+			}
+
+			if event := <-k.Events(); event.Type != kraken.OrderBookEventResynced {
+				t.Errorf("EXPECTED: resynced event\nACTUAL: %+v", event)
+			}
+		})
+	}
+
+	// a newer level and the deletion of a level beyond the book are not gaps
+	k, err := kraken.NewOrderBookKeeper(client, 10, "XBT/USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := k.Apply(snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	update := kraken.BookUpdate{Pair: "XBT/USD", Depth: 10, Asks: []kraken.AskBid{
+		{Price: decimal.RequireFromString("5541.3"), Volume: decimal.RequireFromString("1"), Timestamp: time.Unix(1534614249, 0)},
+		{Price: decimal.RequireFromString("5550"), Volume: decimal.Zero, Timestamp: time.Unix(1534614249, 0)},
+	}, Checksum: bookChecksum([][2]string{{"5541.3", "1"}, {"5542.5", "0.4"}}, [][2]string{{"5541.2", "1.529"}})}
+	if err := k.Apply(update); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOrderBookKeeperRun(t *testing.T) {
+	snapshot := `[0,{"as":[["5541.30000","2.50700000","1534614248.123678"]],"bs":[["5541.20000","1.52900000","1534614248.765567"]]},"book-10","XBT/USD"]`
+	resynced := `[0,{"as":[["5541.40000","1.00000000","1534614248.123678"]],"bs":[["5541.20000","1.52900000","1534614248.765567"]]},"book-10","XBT/USD"]`
+
+	s, c := newWSTestServer(t,
+		wsScript("book-10", "XBT/USD", snapshot, `[1234,{"a":[["5541.30000","2.00000000","1534614335.345903"]],"c":"1"},"book-10","XBT/USD"]`),
+		// the book is resubscribed to once the checksum does not match
+		wsScript("book-10", "XBT/USD", resynced),
+	)
+	defer s.Close()
+	defer c.Close()
+
+	k, err := kraken.NewOrderBookKeeper(c, 10, "XBT/USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- k.Run(ctx) }()
+
+	for _, expected := range []kraken.OrderBookEventType{kraken.OrderBookEventChecksumMismatch, kraken.OrderBookEventResynced} {
+		select {
+		case event := <-k.Events():
+			if event.Type != expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", expected, event.Type)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	if ask, ok := k.BestAsk("XBT/USD"); !ok || !ask.Price.Equal(decimal.RequireFromString("5541.4")) {
+		t.Errorf("EXPECTED: 5541.4\nACTUAL: %s %t", ask.Price, ok)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, err)
+	}
+}
+
+func TestNewOrderBookKeeper(t *testing.T) {
+	if _, err := kraken.NewOrderBookKeeper(nil, 20, "XBT/USD"); err == nil {
+		t.Error("EXPECTED: unsupported depth error\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewOrderBookKeeper(nil, 10); err == nil {
+		t.Error("EXPECTED: pair required error\nACTUAL: <nil>")
+	}
+}
@@ -0,0 +1,99 @@
+package kraken
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ValidateOrder check an order request against the metadata of its pair
+// before it is sent, returning every violation found, each matching
+// ErrOrderValidation. The volume must meet OrderMin and the notional value of
+// orders with a price must meet CostMin, with the volume taken as the
+// notional value when OrderFlagVolumeInQuote is set. Prices must be aligned
+// to the tick size, or PairPrecision without one, and volume to LotPrecision.
+// Leverage must be one offered for the action, and the status of the pair
+// must accept the order: cancel_only pairs accept none, post_only pairs only
+// post only limit orders, limit_only pairs only limit orders and reduce_only
+// pairs only leveraged or settle position orders
+func ValidateOrder(req OrderRequest, pair AssetPair) []error {
+	var errs []error
+	violation := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w:%s", ErrOrderValidation, fmt.Sprintf(format, args...)))
+	}
+
+	volumeInQuote := containsString(req.Flags, OrderFlagVolumeInQuote)
+	if !volumeInQuote && pair.OrderMin.IsPositive() && req.Volume.LessThan(pair.OrderMin) {
+		violation("volume %s is below the minimum %s", req.Volume, pair.OrderMin)
+	}
+
+	notional := req.Volume.Mul(req.Price)
+	if volumeInQuote {
+		notional = req.Volume
+	}
+
+	if pair.CostMin.IsPositive() && !notional.IsZero() && notional.LessThan(pair.CostMin) {
+		violation("cost %s is below the minimum %s", notional, pair.CostMin)
+	}
+
+	for _, price := range []struct {
+		name  string
+		value decimal.Decimal
+	}{
+		{name: "price", value: req.Price},
+		{name: "secondary price", value: req.SecondaryPrice},
+	} {
+		if !price.value.IsZero() && !pair.RoundPrice(price.value, RoundingModeFloor).Equal(price.value) {
+			violation("%s %s is not aligned to the pair's tick size", price.name, price.value)
+		}
+	}
+
+	if !volumeInQuote && !pair.RoundVolume(req.Volume, RoundingModeFloor).Equal(req.Volume) {
+		violation("volume %s has more than %d decimal places", req.Volume, pair.LotPrecision)
+	}
+
+	leveraged := req.Leverage != "" && req.Leverage != "none"
+	if leveraged {
+		allowed := pair.LeverageBuy
+		if req.Action == OrderActionSell {
+			allowed = pair.LeverageSell
+		}
+
+		leverage, err := strconv.Atoi(strings.SplitN(req.Leverage, ":", 2)[0])
+		if err != nil || !containsInt(allowed, leverage) {
+			violation("leverage %s is not offered for %s orders", req.Leverage, req.Action)
+		}
+	}
+
+	switch pair.Status {
+	case AssetPairStatusCancelOnly:
+		violation("pair is %s", pair.Status)
+	case AssetPairStatusPostOnly:
+		if req.Type != OrderTypeLimit || !containsString(req.Flags, OrderFlagPostOnly) {
+			violation("pair is %s and only accepts post only limit orders", pair.Status)
+		}
+	case AssetPairStatusLimitOnly:
+		if req.Type != OrderTypeLimit {
+			violation("pair is %s and only accepts limit orders", pair.Status)
+		}
+	case AssetPairStatusReduceOnly:
+		if !leveraged && req.Type != OrderTypeSettlePosition {
+			violation("pair is %s and only accepts orders reducing positions", pair.Status)
+		}
+	}
+
+	return errs
+}
+
+// containsInt whether s contains v
+func containsInt(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,167 @@
+package kraken_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// validationPair a pair with the metadata of XXBTZUSD
+func validationPair(status kraken.AssetPairStatus) kraken.AssetPair {
+	return kraken.AssetPair{
+		PairPrecision: 1,
+		LotPrecision:  8,
+		LeverageBuy:   []int{2, 3},
+		LeverageSell:  []int{2},
+		OrderMin:      decimal.RequireFromString("0.0001"),
+		CostMin:       decimal.RequireFromString("0.5"),
+		TickSize:      decimal.RequireFromString("0.1"),
+		Status:        status,
+	}
+}
+
+// limitOrder a limit order for XBTUSD
+func limitOrder(action kraken.OrderAction, volume, price string, flags ...string) kraken.OrderRequest {
+	return kraken.OrderRequest{
+		Pair:   "XBTUSD",
+		Action: action,
+		Type:   kraken.OrderTypeLimit,
+		Volume: decimal.RequireFromString(volume),
+		Price:  decimal.RequireFromString(price),
+		Flags:  flags,
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	tcs := []struct {
+		name     string
+		req      kraken.OrderRequest
+		pair     kraken.AssetPair
+		expected []string
+	}{
+		{
+			name: "Valid",
+			req:  limitOrder(kraken.OrderActionBuy, "0.0001", "38000.1"),
+			pair: validationPair(kraken.AssetPairStatusOnline),
+		},
+		{
+			name: "ValidMarket",
+			req: kraken.OrderRequest{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionSell,
+				Type:   kraken.OrderTypeMarket,
+				Volume: decimal.RequireFromString("0.0001"),
+			},
+			pair: validationPair(kraken.AssetPairStatusOnline),
+		},
+		{
+			name: "EveryViolation",
+			req: kraken.OrderRequest{
+				Pair:           "XBTUSD",
+				Action:         kraken.OrderActionSell,
+				Type:           kraken.OrderTypeStopLossLimit,
+				Volume:         decimal.RequireFromString("0.000012345"),
+				Price:          decimal.RequireFromString("38000.15"),
+				SecondaryPrice: decimal.RequireFromString("37000.01"),
+				Leverage:       "3:1",
+			},
+			pair: validationPair(kraken.AssetPairStatusLimitOnly),
+			expected: []string{
+				"order validation error:volume 0.000012345 is below the minimum 0.0001",
+				"order validation error:cost 0.46911185175 is below the minimum 0.5",
+				"order validation error:price 38000.15 is not aligned to the pair's tick size",
+				"order validation error:secondary price 37000.01 is not aligned to the pair's tick size",
+				"order validation error:volume 0.000012345 has more than 8 decimal places",
+				"order validation error:leverage 3:1 is not offered for sell orders",
+				"order validation error:pair is limit_only and only accepts limit orders",
+			},
+		},
+		{
+			name: "VolumeInQuote",
+			req:  limitOrder(kraken.OrderActionBuy, "0.4", "38000", kraken.OrderFlagVolumeInQuote),
+			pair: validationPair(kraken.AssetPairStatusOnline),
+			expected: []string{
+				"order validation error:cost 0.4 is below the minimum 0.5",
+			},
+		},
+		{
+			name: "Leverage",
+			req:  kraken.OrderRequest{Pair: "XBTUSD", Action: kraken.OrderActionBuy, Type: kraken.OrderTypeLimit, Volume: decimal.NewFromInt(1), Price: decimal.NewFromInt(38000), Leverage: "3"},
+			pair: validationPair(kraken.AssetPairStatusOnline),
+		},
+		{
+			name: "CancelOnly",
+			req:  limitOrder(kraken.OrderActionBuy, "1", "38000"),
+			pair: validationPair(kraken.AssetPairStatusCancelOnly),
+			expected: []string{
+				"order validation error:pair is cancel_only",
+			},
+		},
+		{
+			name: "PostOnly",
+			req:  limitOrder(kraken.OrderActionBuy, "1", "38000"),
+			pair: validationPair(kraken.AssetPairStatusPostOnly),
+			expected: []string{
+				"order validation error:pair is post_only and only accepts post only limit orders",
+			},
+		},
+		{
+			name: "PostOnlyWithFlag",
+			req:  limitOrder(kraken.OrderActionBuy, "1", "38000", kraken.OrderFlagPostOnly),
+			pair: validationPair(kraken.AssetPairStatusPostOnly),
+		},
+		{
+			name: "ReduceOnly",
+			req:  limitOrder(kraken.OrderActionSell, "1", "38000"),
+			pair: validationPair(kraken.AssetPairStatusReduceOnly),
+			expected: []string{
+				"order validation error:pair is reduce_only and only accepts orders reducing positions",
+			},
+		},
+		{
+			name: "ReduceOnlyNoLeverage",
+			req: func() kraken.OrderRequest {
+				req := limitOrder(kraken.OrderActionSell, "1", "38000")
+				req.Leverage = "none"
+				return req
+			}(),
+			pair: validationPair(kraken.AssetPairStatusReduceOnly),
+			expected: []string{
+				"order validation error:pair is reduce_only and only accepts orders reducing positions",
+			},
+		},
+		{
+			name: "ReduceOnlyLeveraged",
+			req: func() kraken.OrderRequest {
+				req := limitOrder(kraken.OrderActionSell, "1", "38000")
+				req.Leverage = "2:1"
+				return req
+			}(),
+			pair: validationPair(kraken.AssetPairStatusReduceOnly),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := []string{}
+			for _, err := range kraken.ValidateOrder(tc.req, tc.pair) {
+				if !errors.Is(err, kraken.ErrOrderValidation) {
+					t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrOrderValidation, err)
+				}
+
+				actual = append(actual, err.Error())
+			}
+
+			if tc.expected == nil {
+				tc.expected = []string{}
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WaitForOrder poll the API for an order until it is closed, canceled or
+// expired, returning the order with the IDs of its fills in Trades. An order
+// not yet returned by the API, as happens shortly after it is placed, is
+// retried until the not found timeout has passed since the first request and
+// then fails with ErrUnknownOrder, whether the API omits it or responds with
+// EOrder:Unknown order. Once found, an order missing from a response is
+// retried until the context ends. Other errors are returned as they are, and
+// when the context ends its error is returned with the last order found
+func WaitForOrder(ctx context.Context, client Client, txid string, opts ...WaitForOrderOption) (Order, error) {
+	if client == nil {
+		return Order{}, fmt.Errorf("client is required")
+	}
+
+	if txid == "" {
+		return Order{}, fmt.Errorf("txid is required")
+	}
+
+	cfg := waitForOrderConfig{
+		interval:   time.Second,
+		multiplier: 1,
+		notFound:   5 * time.Second,
+		clock:      systemClock{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return Order{}, err
+		}
+	}
+
+	start := cfg.clock.Now()
+	interval := cfg.interval
+	var last Order
+	found := false
+	for {
+		res, err := client.QueryOrders(ctx, true, nil, txid)
+		if err == nil && len(res.Errors) != 0 {
+			err = res.Errors[0]
+		}
+
+		if err != nil && !errors.Is(err, ErrUnknownOrder) {
+			return last, err
+		}
+
+		order, ok := res.Orders[txid]
+		switch {
+		case err != nil || !ok:
+			if !found && cfg.clock.Now().Sub(start) >= cfg.notFound {
+				return last, fmt.Errorf("%w:%s", ErrUnknownOrder, txid)
+			}
+		case !found || order.Status != last.Status || !order.VolumeExecuted.Equal(last.VolumeExecuted):
+			last, found = order, true
+			if cfg.onUpdate != nil {
+				cfg.onUpdate(order)
+			}
+		}
+
+		if found && orderDone(last.Status) {
+			return last, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-cfg.clock.After(interval):
+		}
+
+		if cfg.multiplier > 1 {
+			if interval = time.Duration(float64(interval) * cfg.multiplier); interval > cfg.maxInterval {
+				interval = cfg.maxInterval
+			}
+		}
+	}
+}
+
+// orderDone whether an order status is terminal
+func orderDone(status OrderStatus) bool {
+	return status == OrderStatusClosed || status == OrderStatusCanceled || status == OrderStatusExpired
+}
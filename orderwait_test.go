@@ -0,0 +1,259 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+// waitOrder an order with a status and executed volume
+func waitOrder(status kraken.OrderStatus, executed string, trades ...string) kraken.Order {
+	return kraken.Order{
+		TransactionID:  "OQCLML-BW3P3-BUCMWZ",
+		Status:         status,
+		Volume:         decimal.RequireFromString("1"),
+		VolumeExecuted: decimal.RequireFromString(executed),
+		Trades:         trades,
+	}
+}
+
+// orderResponses a QueryOrders handler returning a response and error for
+// each call, the last response is repeated and calls without an error return
+// nil
+func orderResponses(responses []kraken.OrdersInfo, errs []error) func(ctx context.Context, trades bool, userref *int32, txids ...string) (kraken.OrdersInfo, error) {
+	calls := 0
+
+	return func(ctx context.Context, trades bool, userref *int32, txids ...string) (kraken.OrdersInfo, error) {
+		res := responses[len(responses)-1]
+		if calls < len(responses) {
+			res = responses[calls]
+		}
+
+		var err error
+		if calls < len(errs) {
+			err = errs[calls]
+		}
+		calls++
+
+		return res, err
+	}
+}
+
+func TestWaitForOrder(t *testing.T) {
+	txid := "OQCLML-BW3P3-BUCMWZ"
+	found := func(order kraken.Order) kraken.OrdersInfo {
+		return kraken.OrdersInfo{Orders: map[string]kraken.Order{txid: order}}
+	}
+
+	unknown := kraken.OrdersInfo{}
+	if err := (&kraken.Parser{}).Parse([]byte(`{"error":["EOrder:Unknown order"]}`), &unknown); err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name      string
+		responses []kraken.OrdersInfo
+		errs      []error
+		opts      []kraken.WaitForOrderOption
+		expected  kraken.Order
+		updates   []kraken.Order
+		waits     []time.Duration
+		err       error
+	}{
+		{
+			name: "Closed",
+			responses: []kraken.OrdersInfo{
+				{Orders: map[string]kraken.Order{}},
+				unknown,
+				found(waitOrder(kraken.OrderStatusPending, "0")),
+				found(waitOrder(kraken.OrderStatusOpen, "0")),
+				found(waitOrder(kraken.OrderStatusOpen, "0")),
+				found(waitOrder(kraken.OrderStatusOpen, "0.5", "TA")),
+				found(waitOrder(kraken.OrderStatusClosed, "1", "TA", "TB")),
+			},
+			opts:     []kraken.WaitForOrderOption{kraken.WaitForOrderWithBackoff(2, 4*time.Second)},
+			expected: waitOrder(kraken.OrderStatusClosed, "1", "TA", "TB"),
+			updates: []kraken.Order{
+				waitOrder(kraken.OrderStatusPending, "0"),
+				waitOrder(kraken.OrderStatusOpen, "0"),
+				waitOrder(kraken.OrderStatusOpen, "0.5", "TA"),
+				waitOrder(kraken.OrderStatusClosed, "1", "TA", "TB"),
+			},
+			waits: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second},
+		},
+		{
+			name:      "Canceled",
+			responses: []kraken.OrdersInfo{found(waitOrder(kraken.OrderStatusCanceled, "0"))},
+			expected:  waitOrder(kraken.OrderStatusCanceled, "0"),
+			updates:   []kraken.Order{waitOrder(kraken.OrderStatusCanceled, "0")},
+		},
+		{
+			name:      "NotFound",
+			responses: []kraken.OrdersInfo{{Orders: map[string]kraken.Order{}}},
+			opts:      []kraken.WaitForOrderOption{kraken.WaitForOrderWithNotFoundTimeout(3 * time.Second)},
+			waits:     []time.Duration{time.Second, time.Second, time.Second},
+			err:       kraken.ErrUnknownOrder,
+		},
+		{
+			name:      "Unknown",
+			responses: []kraken.OrdersInfo{unknown},
+			opts:      []kraken.WaitForOrderOption{kraken.WaitForOrderWithNotFoundTimeout(0)},
+			err:       kraken.ErrUnknownOrder,
+		},
+		{
+			// clients failing on API errors return EOrder:Unknown order as
+			// the error of the call
+			name: "UnknownCallError",
+			responses: []kraken.OrdersInfo{
+				{},
+				found(waitOrder(kraken.OrderStatusClosed, "1", "TA")),
+			},
+			errs:     []error{unknown.Errors[0]},
+			expected: waitOrder(kraken.OrderStatusClosed, "1", "TA"),
+			updates:  []kraken.Order{waitOrder(kraken.OrderStatusClosed, "1", "TA")},
+			waits:    []time.Duration{time.Second},
+		},
+		{
+			name:      "UnknownCallErrorTimeout",
+			responses: []kraken.OrdersInfo{{}},
+			errs:      []error{unknown.Errors[0]},
+			opts:      []kraken.WaitForOrderOption{kraken.WaitForOrderWithNotFoundTimeout(0)},
+			err:       kraken.ErrUnknownOrder,
+		},
+		{
+			// an order missing from responses once found is not unknown
+			name: "Dropped",
+			responses: []kraken.OrdersInfo{
+				found(waitOrder(kraken.OrderStatusOpen, "0")),
+				{Orders: map[string]kraken.Order{}},
+				unknown,
+				{Orders: map[string]kraken.Order{}},
+				found(waitOrder(kraken.OrderStatusClosed, "1", "TA")),
+			},
+			opts:     []kraken.WaitForOrderOption{kraken.WaitForOrderWithNotFoundTimeout(time.Second)},
+			expected: waitOrder(kraken.OrderStatusClosed, "1", "TA"),
+			updates: []kraken.Order{
+				waitOrder(kraken.OrderStatusOpen, "0"),
+				waitOrder(kraken.OrderStatusClosed, "1", "TA"),
+			},
+			waits: []time.Duration{time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			name: "Error",
+			responses: []kraken.OrdersInfo{
+				found(waitOrder(kraken.OrderStatusOpen, "0")),
+				{Errors: []error{kraken.ErrInvalidKey}},
+			},
+			expected: waitOrder(kraken.OrderStatusOpen, "0"),
+			updates:  []kraken.Order{waitOrder(kraken.OrderStatusOpen, "0")},
+			waits:    []time.Duration{time.Second},
+			err:      kraken.ErrInvalidKey,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := krakentest.NewMockClient(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.OnQueryOrders(orderResponses(tc.responses, tc.errs))
+
+			clock := &fakeClock{now: time.Unix(1643714160, 0)}
+			updates := []kraken.Order{}
+			opts := append([]kraken.WaitForOrderOption{
+				kraken.WaitForOrderWithClock(clock),
+				kraken.WaitForOrderWithUpdates(func(order kraken.Order) { updates = append(updates, order) }),
+			}, tc.opts...)
+
+			order, err := kraken.WaitForOrder(context.Background(), m, txid, opts...)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+
+			if diff := deep.Equal(order, tc.expected); diff != nil {
+				t.Error(diff)
+			}
+
+			if tc.updates == nil {
+				tc.updates = []kraken.Order{}
+			}
+
+			if diff := deep.Equal(updates, tc.updates); diff != nil {
+				t.Error(diff)
+			}
+
+			if diff := deep.Equal(clock.waits, tc.waits); diff != nil {
+				t.Error(diff)
+			}
+
+			call := m.CallsTo("QueryOrders")[0]
+			if diff := deep.Equal(call.Args, []interface{}{true, (*int32)(nil), []string{txid}}); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestWaitForOrderContext(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := waitOrder(kraken.OrderStatusOpen, "0")
+	m.ReturnQueryOrders(kraken.OrdersInfo{Orders: map[string]kraken.Order{open.TransactionID: open}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	order, err := kraken.WaitForOrder(ctx, m, open.TransactionID, kraken.WaitForOrderWithClock(&fakeClock{block: true}))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, err)
+	}
+
+	if diff := deep.Equal(order, open); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestWaitForOrderOptions(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name string
+		opt  kraken.WaitForOrderOption
+	}{
+		{name: "Interval", opt: kraken.WaitForOrderWithInterval(0)},
+		{name: "Multiplier", opt: kraken.WaitForOrderWithBackoff(0.5, time.Second)},
+		{name: "Max", opt: kraken.WaitForOrderWithBackoff(2, 0)},
+		{name: "NotFound", opt: kraken.WaitForOrderWithNotFoundTimeout(-time.Second)},
+		{name: "Updates", opt: kraken.WaitForOrderWithUpdates(nil)},
+		{name: "Clock", opt: kraken.WaitForOrderWithClock(nil)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kraken.WaitForOrder(context.Background(), m, "OQCLML-BW3P3-BUCMWZ", tc.opt); err == nil {
+				t.Error("EXPECTED: error\nACTUAL: <nil>")
+			}
+		})
+	}
+
+	if _, err := kraken.WaitForOrder(context.Background(), nil, "OQCLML-BW3P3-BUCMWZ"); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.WaitForOrder(context.Background(), m, ""); err == nil {
+		t.Error("EXPECTED: txid is required\nACTUAL: <nil>")
+	}
+}
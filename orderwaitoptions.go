@@ -0,0 +1,95 @@
+package kraken
+
+import (
+	"fmt"
+	"time"
+)
+
+// waitForOrderConfig the configuration of a WaitForOrder call
+type waitForOrderConfig struct {
+	interval    time.Duration
+	multiplier  float64
+	maxInterval time.Duration
+	notFound    time.Duration
+	onUpdate    func(order Order)
+	clock       Clock
+}
+
+// WaitForOrderOption options used when calling WaitForOrder
+type WaitForOrderOption func(cfg *waitForOrderConfig) error
+
+// WaitForOrderWithInterval set the delay between the first requests for the
+// order, one second by default
+func WaitForOrderWithInterval(interval time.Duration) WaitForOrderOption {
+	return WaitForOrderOption(func(cfg *waitForOrderConfig) error {
+		if interval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+
+		cfg.interval = interval
+
+		return nil
+	})
+}
+
+// WaitForOrderWithBackoff multiply the delay between requests for the order
+// after each request, up to a maximum delay. The delay is constant by default
+func WaitForOrderWithBackoff(multiplier float64, max time.Duration) WaitForOrderOption {
+	return WaitForOrderOption(func(cfg *waitForOrderConfig) error {
+		if multiplier < 1 {
+			return fmt.Errorf("multiplier must be at least one")
+		}
+
+		if max <= 0 {
+			return fmt.Errorf("max must be positive")
+		}
+
+		cfg.multiplier = multiplier
+		cfg.maxInterval = max
+
+		return nil
+	})
+}
+
+// WaitForOrderWithNotFoundTimeout set how long an order not returned by the
+// API is retried for before it is treated as unknown, five seconds by default
+func WaitForOrderWithNotFoundTimeout(timeout time.Duration) WaitForOrderOption {
+	return WaitForOrderOption(func(cfg *waitForOrderConfig) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative")
+		}
+
+		cfg.notFound = timeout
+
+		return nil
+	})
+}
+
+// WaitForOrderWithUpdates set a function called with the order each time its
+// status or executed volume changes, including when it is first found and when
+// it reaches a terminal status
+func WaitForOrderWithUpdates(fn func(order Order)) WaitForOrderOption {
+	return WaitForOrderOption(func(cfg *waitForOrderConfig) error {
+		if fn == nil {
+			return fmt.Errorf("update function is required")
+		}
+
+		cfg.onUpdate = fn
+
+		return nil
+	})
+}
+
+// WaitForOrderWithClock set the clock used to wait between requests and to
+// decide when an order not found is unknown
+func WaitForOrderWithClock(clock Clock) WaitForOrderOption {
+	return WaitForOrderOption(func(cfg *waitForOrderConfig) error {
+		if clock == nil {
+			return fmt.Errorf("clock is required")
+		}
+
+		cfg.clock = clock
+
+		return nil
+	})
+}
@@ -0,0 +1,391 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OHLCIterator pages through the OHLC of a pair from a start time by passing
+// the LastID of each response as the since of the next request. The last
+// candle of each page may still be in progress and is sent again by the next
+// page, so it is held back until a newer candle confirms it and returned once
+// with its latest values. Iteration stops once a response has no newer
+// candles. Any Client can be used so the requests are made through its rate
+// limiting and retries
+type OHLCIterator struct {
+	client   Client
+	interval OHLCInterval
+	pair     string
+	from     time.Time
+	since    uint64
+
+	batch   []OHLC
+	pending *OHLC
+	last    time.Time
+	err     error
+	done    bool
+}
+
+// NewOHLCIterator helper function for creating a new OHLCIterator, the first
+// request is made by Next
+func NewOHLCIterator(client Client, interval OHLCInterval, from time.Time, pair string) (*OHLCIterator, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+
+	if pair == "" {
+		return nil, fmt.Errorf("pair is required")
+	}
+
+	var since uint64
+	if from.Unix() > 0 {
+		since = uint64(from.Unix())
+	}
+
+	return &OHLCIterator{
+		client:   client,
+		interval: interval,
+		pair:     pair,
+		from:     from,
+		since:    since,
+	}, nil
+}
+
+// Next request the next page of candles, false once caught up, the context
+// is cancelled or a request fails
+func (it *OHLCIterator) Next(ctx context.Context) bool {
+	it.batch = nil
+
+	// a page whose only new candle is held back has no batch, so the next
+	// page is requested until there is one or iteration is done
+	for len(it.batch) == 0 && !it.done {
+		if err := it.page(ctx); err != nil {
+			return it.stop(err)
+		}
+	}
+
+	return len(it.batch) != 0
+}
+
+// page request the page of candles from the since of the iterator, adding
+// the candles confirmed by it to the batch
+func (it *OHLCIterator) page(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	since := it.since
+	res, err := it.client.OHLC(ctx, it.interval, &since, it.pair)
+	if err != nil {
+		return err
+	}
+
+	if len(res.Errors) != 0 {
+		return res.Errors[0]
+	}
+
+	if len(res.Result) > 1 {
+		return fmt.Errorf("%w:ohlc of %d pairs returned for %s", ErrParse, len(res.Result), it.pair)
+	}
+
+	newer := false
+	for _, ohlcs := range res.Result {
+		for _, ohlc := range ohlcs {
+			if ohlc.Time.Before(it.from) || (!it.last.IsZero() && !ohlc.Time.After(it.last)) {
+				continue
+			}
+
+			ohlc := ohlc
+			switch {
+			case it.pending == nil:
+				newer = true
+			case ohlc.Time.Equal(it.pending.Time):
+				// the candle held back from the previous page, sent again
+				// with its latest values
+			case ohlc.Time.After(it.pending.Time):
+				newer = true
+				it.batch = append(it.batch, *it.pending)
+				it.last = it.pending.Time
+			default:
+				continue
+			}
+
+			it.pending = &ohlc
+		}
+	}
+
+	if !newer || res.LastID <= it.since {
+		it.done = true
+		if it.pending != nil {
+			it.batch = append(it.batch, *it.pending)
+			it.last = it.pending.Time
+			it.pending = nil
+		}
+	}
+	it.since = res.LastID
+
+	return nil
+}
+
+// Batch the candles of the page requested by the last call to Next, oldest
+// first
+func (it *OHLCIterator) Batch() []OHLC {
+	return it.batch
+}
+
+// Err the error which stopped iteration, nil once caught up
+func (it *OHLCIterator) Err() error {
+	return it.err
+}
+
+// stop end iteration with an error
+func (it *OHLCIterator) stop(err error) bool {
+	it.err = err
+	it.done = true
+
+	return false
+}
+
+// BackfillOHLC return the candles of a pair in [from, to) oldest first,
+// paging through them with an OHLCIterator and waiting a delay between
+// requests to stay under the public rate limits. A BackfillGapError is
+// returned alongside the candles when any are missing, as the API only keeps
+// the latest 720 candles of each interval, and candles after the latest
+// available are not missing
+func BackfillOHLC(ctx context.Context, client Client, pair string, interval OHLCInterval, from, to time.Time, opts ...BackfillOption) ([]OHLC, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	cfg := backfillConfig{
+		delay: time.Second,
+		clock: systemClock{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	it, err := NewOHLCIterator(client, interval, from, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := []OHLC{}
+	var latest time.Time
+	for it.Next(ctx) {
+		for _, candle := range it.Batch() {
+			latest = candle.Time
+			if candle.Time.Before(to) {
+				candles = append(candles, candle)
+			}
+		}
+
+		if it.pending != nil && !it.pending.Time.Before(to) {
+			latest = it.pending.Time
+		}
+
+		if !latest.Before(to) || it.done {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-cfg.clock.After(cfg.delay):
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	end := to
+	if latest.IsZero() {
+		if now := cfg.clock.Now(); now.Before(end) {
+			end = now
+		}
+	} else if available := latest.Add(interval.Duration()); available.Before(end) {
+		end = available
+	}
+
+	if gaps := ohlcGaps(candles, interval, from, end); len(gaps) != 0 {
+		return candles, &BackfillGapError{Pair: pair, Interval: interval, Gaps: gaps}
+	}
+
+	return candles, nil
+}
+
+// tradeKey identify a trade by its id, or by its time, price and volume for
+// responses that predate trade ids
+type tradeKey struct {
+	id     uint64
+	time   int64
+	price  string
+	volume string
+}
+
+// newTradeKey the key identifying a trade
+func newTradeKey(trade RecentTrade) tradeKey {
+	if trade.TradeID != 0 {
+		return tradeKey{id: trade.TradeID}
+	}
+
+	return tradeKey{time: trade.Time.UnixNano(), price: trade.Price.String(), volume: trade.Volume.String()}
+}
+
+// TradesIterator pages through the trades of a pair by passing the LastID of
+// each response as the since of the next request, waiting a delay between
+// requests. Trades returned again at the boundary of two pages are removed by
+// trade id, or by time, price and volume without one, and iteration stops
+// once a response has no new trades or its cursor has passed the time of the
+// first request
+type TradesIterator struct {
+	client Client
+	pair   string
+	since  uint64
+	delay  time.Duration
+	clock  Clock
+
+	batch     []RecentTrade
+	seen      map[tradeKey]struct{}
+	until     time.Time
+	requested bool
+	err       error
+	done      bool
+}
+
+// NewTradesIterator helper function for creating a new TradesIterator from
+// the start of the pair's trades, or the cursor or time given by options. The
+// first request is made by Next
+func NewTradesIterator(client Client, pair string, opts ...TradesIteratorOption) (*TradesIterator, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+
+	if pair == "" {
+		return nil, fmt.Errorf("pair is required")
+	}
+
+	it := TradesIterator{
+		client: client,
+		pair:   pair,
+		delay:  time.Second,
+		clock:  systemClock{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(&it); err != nil {
+			return nil, err
+		}
+	}
+
+	return &it, nil
+}
+
+// Next request the next page of trades, false once caught up, the context is
+// cancelled or a request fails
+func (it *TradesIterator) Next(ctx context.Context) bool {
+	it.batch = nil
+	if it.done {
+		return false
+	}
+
+	if it.requested {
+		select {
+		case <-ctx.Done():
+			return it.stop(ctx.Err())
+		case <-it.clock.After(it.delay):
+		}
+	} else {
+		if err := ctx.Err(); err != nil {
+			return it.stop(err)
+		}
+
+		it.until = it.clock.Now()
+		it.requested = true
+	}
+
+	since := it.since
+	res, err := it.client.RecentTrades(ctx, &since, it.pair)
+	if err != nil {
+		return it.stop(err)
+	}
+
+	if len(res.Errors) != 0 {
+		return it.stop(res.Errors[0])
+	}
+
+	if len(res.Trades) > 1 {
+		return it.stop(fmt.Errorf("%w:trades of %d pairs returned for %s", ErrParse, len(res.Trades), it.pair))
+	}
+
+	seen := make(map[tradeKey]struct{})
+	for _, trades := range res.Trades {
+		for _, trade := range trades {
+			key := newTradeKey(trade)
+			seen[key] = struct{}{}
+
+			if _, ok := it.seen[key]; ok {
+				continue
+			}
+
+			it.batch = append(it.batch, trade)
+		}
+	}
+	it.seen = seen
+
+	if len(it.batch) == 0 || res.LastID <= it.since || res.LastID >= uint64(it.until.UnixNano()) {
+		it.done = true
+	}
+
+	if res.LastID > it.since {
+		it.since = res.LastID
+	}
+
+	return len(it.batch) != 0
+}
+
+// Batch the trades of the page requested by the last call to Next, oldest
+// first
+func (it *TradesIterator) Batch() []RecentTrade {
+	return it.batch
+}
+
+// Cursor the since of the next request, which can be used to resume
+// iteration with TradesIteratorWithCursor
+func (it *TradesIterator) Cursor() uint64 {
+	return it.since
+}
+
+// Err the error which stopped iteration, nil once caught up
+func (it *TradesIterator) Err() error {
+	return it.err
+}
+
+// Each call fn with each page of trades until caught up, returning the first
+// error of a request or fn
+func (it *TradesIterator) Each(ctx context.Context, fn func(trades []RecentTrade) error) error {
+	for it.Next(ctx) {
+		if err := fn(it.Batch()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// stop end iteration with an error
+func (it *TradesIterator) stop(err error) bool {
+	it.err = err
+	it.done = true
+
+	return false
+}
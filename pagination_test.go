@@ -0,0 +1,570 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+// ohlcPages an OHLC handler serving candles a minute apart from start, at
+// most size candles from since and the candle at since are returned per page
+// with the time of the last as the LastID
+func ohlcPages(start int64, count, size int) func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+	return func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+		ohlcs := []kraken.OHLC{}
+		for i := 0; i < count && len(ohlcs) < size; i++ {
+			t := start + int64(i)*60
+			if since != nil && t < int64(*since) {
+				continue
+			}
+
+			ohlcs = append(ohlcs, kraken.OHLC{Time: time.Unix(t, 0).UTC(), Count: uint64(i)})
+		}
+
+		res := kraken.OHLCs{Result: map[string][]kraken.OHLC{"XXBTZUSD": ohlcs}, LastID: *since}
+		if len(ohlcs) != 0 {
+			res.LastID = uint64(ohlcs[len(ohlcs)-1].Time.Unix())
+		}
+
+		return res, nil
+	}
+}
+
+func TestOHLCIterator(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.OnOHLC(ohlcPages(1643714160, 10, 4))
+
+	it, err := kraken.NewOHLCIterator(m, kraken.OHLCIntervalMinute, time.Unix(1643714220, 0), "XBTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	batches := [][]uint64{}
+	for it.Next(ctx) {
+		counts := []uint64{}
+		for _, ohlc := range it.Batch() {
+			counts = append(counts, ohlc.Count)
+		}
+		batches = append(batches, counts)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the last candle of each page is held back until the next page
+	expected := [][]uint64{{1, 2, 3}, {4, 5, 6}, {7, 8}, {9}}
+	if diff := deep.Equal(expected, batches); diff != nil {
+		t.Error(diff)
+	}
+
+	since := []uint64{}
+	for _, call := range m.CallsTo("OHLC") {
+		since = append(since, *call.Args[1].(*uint64))
+	}
+
+	expectedSince := []uint64{1643714220, 1643714400, 1643714580, 1643714700}
+	if diff := deep.Equal(expectedSince, since); diff != nil {
+		t.Error(diff)
+	}
+
+	if it.Next(ctx) {
+		t.Error("EXPECTED: false once caught up\nACTUAL: true")
+	}
+}
+
+func TestOHLCIteratorInProgress(t *testing.T) {
+	start := time.Unix(1643714160, 0).UTC()
+	minute := func(n int) time.Time { return start.Add(time.Duration(n) * time.Minute) }
+	page := func(last time.Time, ohlcs ...kraken.OHLC) kraken.OHLCs {
+		return kraken.OHLCs{Result: map[string][]kraken.OHLC{"XXBTZUSD": ohlcs}, LastID: uint64(last.Unix())}
+	}
+
+	tcs := []struct {
+		name     string
+		pages    map[int64]kraken.OHLCs
+		expected [][]uint64
+		calls    int
+	}{
+		{
+			// the last candle of each page is in progress and the LastID is
+			// the time of the candle before it, so it is sent again by the
+			// next page with updated values
+			name: "Boundary",
+			pages: map[int64]kraken.OHLCs{
+				minute(0).Unix(): page(minute(1), kraken.OHLC{Time: minute(0), Count: 0}, kraken.OHLC{Time: minute(1), Count: 1}, kraken.OHLC{Time: minute(2), Count: 2}),
+				minute(1).Unix(): page(minute(2), kraken.OHLC{Time: minute(1), Count: 1}, kraken.OHLC{Time: minute(2), Count: 12}, kraken.OHLC{Time: minute(3), Count: 3}),
+				minute(2).Unix(): page(minute(2), kraken.OHLC{Time: minute(2), Count: 12}, kraken.OHLC{Time: minute(3), Count: 13}),
+			},
+			expected: [][]uint64{{0, 1}, {12}, {13}},
+			calls:    3,
+		},
+		{
+			// the only candle of the first page is held back, so the next
+			// page is requested by the same call to Next
+			name: "SingleCandle",
+			pages: map[int64]kraken.OHLCs{
+				minute(0).Unix():     page(minute(0).Add(time.Second), kraken.OHLC{Time: minute(0), Count: 0}),
+				minute(0).Unix() + 1: page(minute(0).Add(time.Second), kraken.OHLC{Time: minute(0), Count: 10}),
+			},
+			expected: [][]uint64{{10}},
+			calls:    2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := krakentest.NewMockClient(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.OnOHLC(func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+				return tc.pages[int64(*since)], nil
+			})
+
+			it, err := kraken.NewOHLCIterator(m, kraken.OHLCIntervalMinute, minute(0), "XBTUSD")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			batches := [][]uint64{}
+			for it.Next(context.Background()) {
+				counts := []uint64{}
+				for _, ohlc := range it.Batch() {
+					counts = append(counts, ohlc.Count)
+				}
+				batches = append(batches, counts)
+			}
+
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			// the held back candles are returned with the values of the later
+			// page
+			if diff := deep.Equal(tc.expected, batches); diff != nil {
+				t.Error(diff)
+			}
+
+			if calls := len(m.CallsTo("OHLC")); calls != tc.calls {
+				t.Errorf("EXPECTED: %d\nACTUAL: %d", tc.calls, calls)
+			}
+		})
+	}
+}
+
+func TestOHLCIteratorErrors(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.OnOHLC(func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+		return kraken.OHLCs{Errors: []error{kraken.ErrServiceUnavailable}}, nil
+	})
+
+	it, err := kraken.NewOHLCIterator(m, kraken.OHLCIntervalMinute, time.Time{}, "XBTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if it.Next(context.Background()) {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+
+	if err := it.Err(); !errors.Is(err, kraken.ErrServiceUnavailable) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrServiceUnavailable, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	it, err = kraken.NewOHLCIterator(m, kraken.OHLCIntervalMinute, time.Time{}, "XBTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if it.Next(ctx) || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, it.Err())
+	}
+
+	if _, err := kraken.NewOHLCIterator(nil, kraken.OHLCIntervalMinute, time.Time{}, "XBTUSD"); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewOHLCIterator(m, kraken.OHLCIntervalMinute, time.Time{}, ""); err == nil {
+		t.Error("EXPECTED: pair is required\nACTUAL: <nil>")
+	}
+}
+
+// tradePages a RecentTrades handler serving trades a second apart from
+// start, at most size trades from since and the trade at since are returned
+// per page with the time of the last as the LastID. Trades after the first
+// count with ids have none
+func tradePages(start time.Time, count, withIDs, size int) func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentTrades, error) {
+	return func(ctx context.Context, since *uint64, pairs ...string) (kraken.RecentTrades, error) {
+		trades := []kraken.RecentTrade{}
+		for i := 0; i < count && len(trades) < size; i++ {
+			trade := kraken.RecentTrade{
+				Price:  decimal.NewFromInt(int64(100 + i)),
+				Volume: decimal.NewFromInt(1),
+				Time:   start.Add(time.Duration(i) * time.Second),
+			}
+			if uint64(trade.Time.UnixNano()) < *since {
+				continue
+			}
+
+			if i < withIDs {
+				trade.TradeID = uint64(i + 1)
+			}
+
+			trades = append(trades, trade)
+		}
+
+		res := kraken.RecentTrades{Trades: map[string][]kraken.RecentTrade{"XXBTZUSD": trades}, LastID: *since}
+		if len(trades) != 0 {
+			res.LastID = uint64(trades[len(trades)-1].Time.UnixNano())
+		}
+
+		return res, nil
+	}
+}
+
+func TestTradesIterator(t *testing.T) {
+	start := time.Unix(1644189769, 0)
+	tcs := []struct {
+		name     string
+		withIDs  int
+		now      time.Time
+		opts     []kraken.TradesIteratorOption
+		expected [][]int64
+		requests int
+	}{
+		{
+			name:     "TradeIDs",
+			withIDs:  10,
+			now:      start.Add(time.Hour),
+			expected: [][]int64{{100, 101, 102}, {103, 104}, {105, 106}, {107, 108}, {109}},
+			requests: 6,
+		},
+		{
+			name:     "TimePriceVolume",
+			withIDs:  4,
+			now:      start.Add(time.Hour),
+			expected: [][]int64{{100, 101, 102}, {103, 104}, {105, 106}, {107, 108}, {109}},
+			requests: 6,
+		},
+		{
+			name:     "CaughtUp",
+			withIDs:  10,
+			now:      start.Add(3 * time.Second),
+			expected: [][]int64{{100, 101, 102}, {103, 104}},
+			requests: 2,
+		},
+		{
+			name:     "From",
+			withIDs:  10,
+			now:      start.Add(time.Hour),
+			opts:     []kraken.TradesIteratorOption{kraken.TradesIteratorFrom(start.Add(7 * time.Second))},
+			expected: [][]int64{{107, 108, 109}},
+			requests: 2,
+		},
+		{
+			name:     "Cursor",
+			withIDs:  10,
+			now:      start.Add(time.Hour),
+			opts:     []kraken.TradesIteratorOption{kraken.TradesIteratorWithCursor(uint64(start.Add(8 * time.Second).UnixNano()))},
+			expected: [][]int64{{108, 109}},
+			requests: 2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := krakentest.NewMockClient(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.OnRecentTrades(tradePages(start, 10, tc.withIDs, 3))
+
+			clock := &fakeClock{now: tc.now}
+			it, err := kraken.NewTradesIterator(m, "XBTUSD", append(tc.opts, kraken.TradesIteratorWithDelay(2*time.Second), kraken.TradesIteratorWithClock(clock))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := [][]int64{}
+			err = it.Each(context.Background(), func(trades []kraken.RecentTrade) error {
+				prices := []int64{}
+				for _, trade := range trades {
+					prices = append(prices, trade.Price.IntPart())
+				}
+				actual = append(actual, prices)
+
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+
+			if requests := len(m.CallsTo("RecentTrades")); requests != tc.requests {
+				t.Errorf("EXPECTED: %d\nACTUAL: %d", tc.requests, requests)
+			}
+
+			// requests after the first wait for the delay
+			for _, wait := range clock.waits {
+				if wait != 2*time.Second {
+					t.Errorf("EXPECTED: %v\nACTUAL: %v", 2*time.Second, wait)
+				}
+			}
+
+			if len(clock.waits) != tc.requests-1 {
+				t.Errorf("EXPECTED: %d\nACTUAL: %d", tc.requests-1, len(clock.waits))
+			}
+		})
+	}
+}
+
+func TestTradesIteratorErrors(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.OnRecentTrades(tradePages(time.Unix(1644189769, 0), 10, 10, 3))
+
+	it, err := kraken.NewTradesIterator(m, "XBTUSD", kraken.TradesIteratorWithClock(&fakeClock{now: time.Now(), block: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// the context is cancelled while waiting for the second page
+	expected := errors.New("stop")
+	err = it.Each(ctx, func(trades []kraken.RecentTrade) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.DeadlineExceeded, err)
+	}
+
+	it, err = kraken.NewTradesIterator(m, "XBTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := it.Each(context.Background(), func(trades []kraken.RecentTrade) error { return expected }); !errors.Is(err, expected) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", expected, err)
+	}
+
+	for _, opts := range [][]kraken.TradesIteratorOption{
+		{kraken.TradesIteratorWithDelay(-time.Second)},
+		{kraken.TradesIteratorWithClock(nil)},
+		{kraken.TradesIteratorFrom(time.Unix(-1, 0))},
+	} {
+		if _, err := kraken.NewTradesIterator(m, "XBTUSD", opts...); err == nil {
+			t.Error("EXPECTED: option error\nACTUAL: <nil>")
+		}
+	}
+
+	if _, err := kraken.NewTradesIterator(nil, "XBTUSD"); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+}
+
+func TestBackfillOHLC(t *testing.T) {
+	start := time.Unix(1643714160, 0).UTC()
+	minute := func(n int) time.Time { return start.Add(time.Duration(n) * time.Minute) }
+
+	tcs := []struct {
+		name     string
+		handler  func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error)
+		from     time.Time
+		to       time.Time
+		expected []uint64
+		gaps     []kraken.TimeRange
+		waits    int
+	}{
+		{
+			name:     "Window",
+			handler:  ohlcPages(start.Unix(), 10, 4),
+			from:     minute(0),
+			to:       minute(5),
+			expected: []uint64{0, 1, 2, 3, 4},
+			waits:    1,
+		},
+		{
+			name:     "UnalignedWindow",
+			handler:  ohlcPages(start.Unix(), 10, 4),
+			from:     minute(1).Add(-time.Second),
+			to:       minute(3).Add(time.Second),
+			expected: []uint64{1, 2, 3},
+		},
+		{
+			name:     "Unavailable",
+			handler:  ohlcPages(start.Unix(), 10, 4),
+			from:     minute(-3),
+			to:       minute(2),
+			expected: []uint64{0, 1},
+			gaps:     []kraken.TimeRange{{From: minute(-3), To: minute(0)}},
+		},
+		{
+			name: "Missing",
+			handler: func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+				ohlcs := []kraken.OHLC{
+					{Time: minute(0), Count: 0},
+					{Time: minute(1), Count: 1},
+					{Time: minute(4), Count: 4},
+				}
+
+				return kraken.OHLCs{Result: map[string][]kraken.OHLC{"XXBTZUSD": ohlcs}, LastID: uint64(minute(4).Unix())}, nil
+			},
+			from:     minute(0),
+			to:       minute(10),
+			expected: []uint64{0, 1, 4},
+			gaps:     []kraken.TimeRange{{From: minute(2), To: minute(4)}},
+			waits:    1,
+		},
+		{
+			// the only candle of the first page is held back by the iterator
+			// and returned with the next page
+			name: "SingleCandle",
+			handler: func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+				ohlcs := []kraken.OHLC{{Time: minute(0), Count: 0}}
+
+				return kraken.OHLCs{Result: map[string][]kraken.OHLC{"XXBTZUSD": ohlcs}, LastID: uint64(minute(0).Unix()) + 1}, nil
+			},
+			from:     minute(0),
+			to:       minute(1),
+			expected: []uint64{0},
+		},
+		{
+			name: "Empty",
+			handler: func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+				return kraken.OHLCs{Result: map[string][]kraken.OHLC{"XXBTZUSD": {}}, LastID: *since}, nil
+			},
+			from:     minute(0),
+			to:       minute(60),
+			expected: []uint64{},
+			gaps:     []kraken.TimeRange{{From: minute(0), To: minute(10)}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := krakentest.NewMockClient(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.OnOHLC(tc.handler)
+
+			clock := &fakeClock{now: minute(10)}
+			candles, err := kraken.BackfillOHLC(context.Background(), m, "XBTUSD", kraken.OHLCIntervalMinute, tc.from, tc.to, kraken.BackfillWithDelay(2*time.Second), kraken.BackfillWithClock(clock))
+
+			gapErr := &kraken.BackfillGapError{}
+			switch {
+			case tc.gaps == nil && err != nil:
+				t.Fatal(err)
+			case tc.gaps != nil && !errors.As(err, &gapErr):
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", kraken.ErrMissingOHLC, err)
+			case tc.gaps != nil:
+				if diff := deep.Equal(tc.gaps, gapErr.Gaps); diff != nil {
+					t.Error(diff)
+				}
+
+				if !errors.Is(err, kraken.ErrMissingOHLC) {
+					t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrMissingOHLC, err)
+				}
+			}
+
+			actual := []uint64{}
+			for _, candle := range candles {
+				actual = append(actual, candle.Count)
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+
+			if len(clock.waits) != tc.waits {
+				t.Errorf("EXPECTED: %d\nACTUAL: %d", tc.waits, len(clock.waits))
+			}
+		})
+	}
+}
+
+func TestBackfillGapError(t *testing.T) {
+	from := time.Unix(1643714160, 0).UTC()
+	tcs := []struct {
+		name     string
+		gaps     []kraken.TimeRange
+		expected string
+	}{
+		{
+			name:     "Gaps",
+			gaps:     []kraken.TimeRange{{From: from, To: from.Add(10 * time.Minute)}, {From: from.Add(time.Hour), To: from.Add(2 * time.Hour)}},
+			expected: "missing ohlc: 2 gaps in the 1m0s ohlc of XBTUSD, the first from 2022-02-01T11:16:00Z to 2022-02-01T11:26:00Z",
+		},
+		{name: "NoGaps", expected: "missing ohlc: no gaps in the 1m0s ohlc of XBTUSD"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := &kraken.BackfillGapError{Pair: "XBTUSD", Interval: kraken.OHLCIntervalMinute, Gaps: tc.gaps}
+			if err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestBackfillOHLCErrors(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.OnOHLC(func(ctx context.Context, interval kraken.OHLCInterval, since *uint64, pairs ...string) (kraken.OHLCs, error) {
+		return kraken.OHLCs{Errors: []error{kraken.ErrServiceUnavailable}}, nil
+	})
+
+	from := time.Unix(1643714160, 0)
+	if _, err := kraken.BackfillOHLC(context.Background(), m, "XBTUSD", kraken.OHLCIntervalMinute, from, from.Add(time.Hour)); !errors.Is(err, kraken.ErrServiceUnavailable) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrServiceUnavailable, err)
+	}
+
+	tcs := []struct {
+		name     string
+		interval kraken.OHLCInterval
+		to       time.Time
+		opts     []kraken.BackfillOption
+		expected string
+	}{
+		{name: "Interval", interval: 0, to: from.Add(time.Hour), expected: "interval must be positive"},
+		{name: "Range", interval: kraken.OHLCIntervalMinute, to: from, expected: "from must be before to"},
+		{name: "Delay", interval: kraken.OHLCIntervalMinute, to: from.Add(time.Hour), opts: []kraken.BackfillOption{kraken.BackfillWithDelay(-time.Second)}, expected: "delay must not be negative"},
+		{name: "Clock", interval: kraken.OHLCIntervalMinute, to: from.Add(time.Hour), opts: []kraken.BackfillOption{kraken.BackfillWithClock(nil)}, expected: "clock is required"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kraken.BackfillOHLC(context.Background(), m, "XBTUSD", tc.interval, from, tc.to, tc.opts...); err == nil || err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %v", tc.expected, err)
+			}
+		})
+	}
+}
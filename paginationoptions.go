@@ -0,0 +1,97 @@
+package kraken
+
+import (
+	"fmt"
+	"time"
+)
+
+// TradesIteratorOption options used when creating a new TradesIterator
+type TradesIteratorOption func(it *TradesIterator) error
+
+// TradesIteratorWithCursor start from the nanosecond since cursor of an
+// earlier response or iterator
+func TradesIteratorWithCursor(since uint64) TradesIteratorOption {
+	return TradesIteratorOption(func(it *TradesIterator) error {
+		it.since = since
+
+		return nil
+	})
+}
+
+// TradesIteratorFrom start from the trades made at a time
+func TradesIteratorFrom(from time.Time) TradesIteratorOption {
+	return TradesIteratorOption(func(it *TradesIterator) error {
+		if from.UnixNano() < 0 {
+			return fmt.Errorf("from must not be before the unix epoch")
+		}
+
+		it.since = uint64(from.UnixNano())
+
+		return nil
+	})
+}
+
+// TradesIteratorWithDelay set the delay between requests for pages, one
+// second by default
+func TradesIteratorWithDelay(delay time.Duration) TradesIteratorOption {
+	return TradesIteratorOption(func(it *TradesIterator) error {
+		if delay < 0 {
+			return fmt.Errorf("delay must not be negative")
+		}
+
+		it.delay = delay
+
+		return nil
+	})
+}
+
+// TradesIteratorWithClock set the clock used to wait between requests and
+// to decide when iteration has caught up
+func TradesIteratorWithClock(clock Clock) TradesIteratorOption {
+	return TradesIteratorOption(func(it *TradesIterator) error {
+		if clock == nil {
+			return fmt.Errorf("clock is required")
+		}
+
+		it.clock = clock
+
+		return nil
+	})
+}
+
+// backfillConfig the configuration of a BackfillOHLC call
+type backfillConfig struct {
+	delay time.Duration
+	clock Clock
+}
+
+// BackfillOption options used when calling BackfillOHLC
+type BackfillOption func(cfg *backfillConfig) error
+
+// BackfillWithDelay set the delay between requests for pages, one second by
+// default
+func BackfillWithDelay(delay time.Duration) BackfillOption {
+	return BackfillOption(func(cfg *backfillConfig) error {
+		if delay < 0 {
+			return fmt.Errorf("delay must not be negative")
+		}
+
+		cfg.delay = delay
+
+		return nil
+	})
+}
+
+// BackfillWithClock set the clock used to wait between requests and to decide
+// which candles are yet to be made when none are returned
+func BackfillWithClock(clock Clock) BackfillOption {
+	return BackfillOption(func(cfg *backfillConfig) error {
+		if clock == nil {
+			return fmt.Errorf("clock is required")
+		}
+
+		cfg.clock = clock
+
+		return nil
+	})
+}
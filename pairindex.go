@@ -0,0 +1,143 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// pairAssets the Kraken names of the base and quote assets of a pair
+type pairAssets struct {
+	base  string
+	quote string
+}
+
+// PairIndex look up the pairs of an AssetPairs result by their name, such as
+// "XXBTZUSD", alternative name, such as "XBTUSD", websocket name, such as
+// "XBT/USD", or base and quote assets. It is safe for concurrent use and can
+// be refreshed from the API
+type PairIndex struct {
+	mu     sync.RWMutex
+	pairs  map[string]AssetPair
+	names  map[string]string
+	assets map[pairAssets]string
+}
+
+// NewPairIndex helper function for creating a new PairIndex of the pairs of an
+// AssetPairs result, the first error of the result is returned
+func NewPairIndex(pairs AssetPairs) (*PairIndex, error) {
+	index := PairIndex{}
+	if err := index.set(pairs); err != nil {
+		return nil, err
+	}
+
+	return &index, nil
+}
+
+// Refresh replace the pairs of the index with those requested from the API,
+// the index is left as it is when the request fails
+func (x *PairIndex) Refresh(ctx context.Context, client Client) error {
+	if client == nil {
+		return fmt.Errorf("client is required")
+	}
+
+	res, err := client.AssetPairs(ctx, AssetPairInfoInfo)
+	if err != nil {
+		return err
+	}
+
+	return x.set(res)
+}
+
+// Pair return the pair with a name, alternative name or websocket name, false
+// is returned when there is none
+func (x *PairIndex) Pair(name string) (AssetPair, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	pair, ok := x.pairs[x.names[name]]
+
+	return pair, ok
+}
+
+// Name return the name used by the REST API of the pair with a name,
+// alternative name or websocket name, such as "XXBTZUSD" for "XBT/USD" from a
+// websocket message. False is returned when there is none
+func (x *PairIndex) Name(name string) (string, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	canonical, ok := x.names[name]
+
+	return canonical, ok
+}
+
+// WebSocketName return the websocket name of the pair with a name,
+// alternative name or websocket name, such as "XBT/USD" for "XXBTZUSD".
+// False is returned when there is none or it has no websocket name
+func (x *PairIndex) WebSocketName(name string) (string, bool) {
+	pair, ok := x.Pair(name)
+	if !ok || pair.WebSocketName == "" {
+		return "", false
+	}
+
+	return pair.WebSocketName, true
+}
+
+// ByAssets return the name and pair of base and quote assets, given by their
+// common, Kraken or alternative names such as "BTC" and "USD". False is
+// returned when there is none
+func (x *PairIndex) ByAssets(base, quote string) (string, AssetPair, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	name, ok := x.assets[pairAssets{base: ToKrakenAsset(base), quote: ToKrakenAsset(quote)}]
+	if !ok {
+		return "", AssetPair{}, false
+	}
+
+	return name, x.pairs[name], true
+}
+
+// set replace the pairs of the index with those of a result. Pairs with a
+// suffix, such as the dark pool "XXBTZUSD.d", are not found by their assets
+// when there is a pair of the assets without one
+func (x *PairIndex) set(pairs AssetPairs) error {
+	if len(pairs.Errors) != 0 {
+		return pairs.Errors[0]
+	}
+
+	index := make(map[string]AssetPair, len(pairs.Pairs))
+	names := make(map[string]string, 3*len(pairs.Pairs))
+	assets := make(map[pairAssets]string, len(pairs.Pairs))
+	for name, pair := range pairs.Pairs {
+		index[name] = pair
+		for _, alias := range []string{pair.AltName, pair.WebSocketName} {
+			if alias != "" {
+				if _, ok := names[alias]; !ok {
+					names[alias] = name
+				}
+			}
+		}
+
+		key := pairAssets{base: pair.Base, quote: pair.Quote}
+		if existing, ok := assets[key]; !ok || (strings.Contains(existing, ".") && !strings.Contains(name, ".")) {
+			assets[key] = name
+		}
+	}
+
+	// a name of a pair takes precedence over an alias of another
+	for name := range index {
+		names[name] = name
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.pairs = index
+	x.names = names
+	x.assets = assets
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+)
+
+// indexPairs an AssetPairs result with a dark pool pair
+func indexPairs() kraken.AssetPairs {
+	return kraken.AssetPairs{
+		Pairs: map[string]kraken.AssetPair{
+			"XXBTZUSD":   {AltName: "XBTUSD", WebSocketName: "XBT/USD", Base: "XXBT", Quote: "ZUSD"},
+			"XXBTZUSD.d": {AltName: "XBTUSD.d", Base: "XXBT", Quote: "ZUSD"},
+			"XETHZUSD":   {AltName: "ETHUSD", WebSocketName: "ETH/USD", Base: "XETH", Quote: "ZUSD"},
+			"MATICUSD":   {AltName: "MATICUSD", WebSocketName: "MATIC/USD", Base: "MATIC", Quote: "ZUSD"},
+		},
+	}
+}
+
+func TestPairIndex(t *testing.T) {
+	index, err := kraken.NewPairIndex(indexPairs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		input    string
+		name     string
+		wsname   string
+		wsnameOK bool
+		notFound bool
+	}{
+		{input: "XXBTZUSD", name: "XXBTZUSD", wsname: "XBT/USD", wsnameOK: true},
+		{input: "XBTUSD", name: "XXBTZUSD", wsname: "XBT/USD", wsnameOK: true},
+		{input: "XBT/USD", name: "XXBTZUSD", wsname: "XBT/USD", wsnameOK: true},
+		{input: "XBTUSD.d", name: "XXBTZUSD.d"},
+		{input: "MATIC/USD", name: "MATICUSD", wsname: "MATIC/USD", wsnameOK: true},
+		{input: "BTC/USD", notFound: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			name, ok := index.Name(tc.input)
+			if ok == tc.notFound || name != tc.name {
+				t.Errorf("EXPECTED: %v %v\nACTUAL: %v %v", tc.name, !tc.notFound, name, ok)
+			}
+
+			pair, ok := index.Pair(tc.input)
+			if ok == tc.notFound {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", !tc.notFound, ok)
+			}
+
+			if ok && pair.AltName != indexPairs().Pairs[tc.name].AltName {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", indexPairs().Pairs[tc.name].AltName, pair.AltName)
+			}
+
+			wsname, ok := index.WebSocketName(tc.input)
+			if ok != tc.wsnameOK || wsname != tc.wsname {
+				t.Errorf("EXPECTED: %v %v\nACTUAL: %v %v", tc.wsname, tc.wsnameOK, wsname, ok)
+			}
+		})
+	}
+
+	for _, assets := range [][2]string{{"BTC", "USD"}, {"XBT", "USD"}, {"XXBT", "ZUSD"}} {
+		if name, _, ok := index.ByAssets(assets[0], assets[1]); !ok || name != "XXBTZUSD" {
+			t.Errorf("EXPECTED: XXBTZUSD true\nACTUAL: %v %v", name, ok)
+		}
+	}
+
+	if name, pair, ok := index.ByAssets("MATIC", "USD"); !ok || name != "MATICUSD" || pair.WebSocketName != "MATIC/USD" {
+		t.Errorf("EXPECTED: MATICUSD true\nACTUAL: %v %v", name, ok)
+	}
+
+	if _, _, ok := index.ByAssets("USD", "BTC"); ok {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+}
+
+func TestPairIndexRefresh(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	m.OnAssetPairs(func(ctx context.Context, info kraken.AssetPairInfo, pairs ...string) (kraken.AssetPairs, error) {
+		calls++
+		if calls == 2 {
+			return kraken.AssetPairs{Errors: []error{kraken.ErrServiceUnavailable}}, nil
+		}
+
+		return indexPairs(), nil
+	})
+
+	index, err := kraken.NewPairIndex(kraken.AssetPairs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := index.Name("XBT/USD"); ok {
+		t.Error("EXPECTED: false\nACTUAL: true")
+	}
+
+	if err := index.Refresh(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, ok := index.Name("XBT/USD"); !ok || name != "XXBTZUSD" {
+		t.Errorf("EXPECTED: XXBTZUSD true\nACTUAL: %v %v", name, ok)
+	}
+
+	// a failed refresh leaves the index as it is
+	if err := index.Refresh(context.Background(), m); !errors.Is(err, kraken.ErrServiceUnavailable) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrServiceUnavailable, err)
+	}
+
+	if name, ok := index.Name("XBT/USD"); !ok || name != "XXBTZUSD" {
+		t.Errorf("EXPECTED: XXBTZUSD true\nACTUAL: %v %v", name, ok)
+	}
+
+	if info := m.CallsTo("AssetPairs")[0].Args[0]; info != kraken.AssetPairInfoInfo {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.AssetPairInfoInfo, info)
+	}
+
+	if err := index.Refresh(context.Background(), nil); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewPairIndex(kraken.AssetPairs{Errors: []error{kraken.ErrServiceUnavailable}}); !errors.Is(err, kraken.ErrServiceUnavailable) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrServiceUnavailable, err)
+	}
+}
+
+func TestPairIndexConcurrent(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.OnAssetPairs(func(ctx context.Context, info kraken.AssetPairInfo, pairs ...string) (kraken.AssetPairs, error) {
+		return indexPairs(), nil
+	})
+
+	index, err := kraken.NewPairIndex(indexPairs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := index.Refresh(context.Background(), m); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, ok := index.Name("XBT/USD"); !ok {
+				t.Error("EXPECTED: true\nACTUAL: false")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,9 +1,11 @@
 package kraken
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/big"
+	"io"
 	"reflect"
 	"strconv"
 	"strings"
@@ -14,7 +16,11 @@ import (
 
 // Parser handles parsing of response payloads from the Kraken API
 // to a structured data type
-type Parser struct{}
+type Parser struct {
+	// Strict fail to parse payloads with fields which are not mapped to the
+	// result, such as fields added to the API since the result was written
+	Strict bool
+}
 
 // Parse parse a payload
 func (p *Parser) Parse(payload []byte, v interface{}) error {
@@ -22,6 +28,91 @@ func (p *Parser) Parse(payload []byte, v interface{}) error {
 		return fmt.Errorf("%w: cannot parse to nil pointer", ErrParse)
 	}
 
+	return p.parseResultless(payload, func(payload []byte) error {
+		return p.parse(payload, v)
+	})
+}
+
+// Parseable a result type which can be parsed from a payload, implemented by
+// every result type returned by the Kraken API
+type Parseable[T any] interface {
+	parseInto(p *Parser, payload []byte, parsed *T) error
+}
+
+// ParseAs parse a payload into a T, unlike Parse the result type is checked at
+// compile time
+func ParseAs[T Parseable[T]](p *Parser, payload []byte) (T, error) {
+	var v T
+	err := p.parseResultless(payload, func(payload []byte) error {
+		return v.parseInto(p, payload, &v)
+	})
+
+	return v, err
+}
+
+// parseResultless parse a payload, on failure error responses with an empty
+// result are parsed again without the result
+func (p *Parser) parseResultless(payload []byte, parse func(payload []byte) error) error {
+	err := parse(payload)
+	if errors.Is(err, ErrParse) {
+		// error responses can carry an empty result of the wrong shape, such
+		// as {} where an array is expected, so parse the errors alone
+		if errorsOnly, ok := p.resultlessErrors(payload); ok {
+			return parse(errorsOnly)
+		}
+	}
+
+	return err
+}
+
+// unmarshal unmarshal a payload into a response, when strict fields of the
+// payload which the response does not have and trailing data are rejected
+func (p *Parser) unmarshal(payload []byte, v interface{}) error {
+	if !p.Strict {
+		return json.Unmarshal(payload, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after response")
+	}
+
+	return nil
+}
+
+// resultlessErrors return a payload of only the errors of an error response
+// with an empty, null or missing result
+func (p *Parser) resultlessErrors(payload []byte) ([]byte, bool) {
+	msg := struct {
+		Errors []string        `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}{}
+	if err := json.Unmarshal(payload, &msg); err != nil || len(msg.Errors) == 0 {
+		return nil, false
+	}
+
+	switch string(bytes.Join(bytes.Fields(msg.Result), nil)) {
+	case "", "null", "{}", "[]":
+	default:
+		return nil, false
+	}
+
+	errorsOnly, err := json.Marshal(struct {
+		Errors []string `json:"error"`
+	}{Errors: msg.Errors})
+	if err != nil {
+		return nil, false
+	}
+
+	return errorsOnly, true
+}
+
+func (p *Parser) parse(payload []byte, v interface{}) error {
 	switch t := v.(type) {
 	case *Time:
 		return p.parsePublicTime(payload, t)
@@ -41,534 +132,2670 @@ func (p *Parser) Parse(payload []byte, v interface{}) error {
 		return p.parseRecentTrades(payload, t)
 	case *RecentSpreads:
 		return p.parseRecentSpreads(payload, t)
+	case *TradeBalance:
+		return p.parseTradeBalance(payload, t)
+	case *OpenOrders:
+		return p.parseOpenOrders(payload, t)
+	case *ClosedOrders:
+		return p.parseClosedOrders(payload, t)
+	case *OrdersInfo:
+		return p.parseOrdersInfo(payload, t)
+	case *TradesHistory:
+		return p.parseTradesHistory(payload, t)
+	case *OpenPositions:
+		return p.parseOpenPositions(payload, t)
+	case *Ledgers:
+		return p.parseLedgers(payload, t)
+	case *LedgersInfo:
+		return p.parseLedgersInfo(payload, t)
+	case *TradeVolume:
+		return p.parseTradeVolume(payload, t)
+	case *AddOrderResult:
+		return p.parseAddOrderResult(payload, t)
+	case *EditOrderResult:
+		return p.parseEditOrderResult(payload, t)
+	case *CancelResult:
+		return p.parseCancelResult(payload, t)
+	case *CancelAllOrdersAfterResult:
+		return p.parseCancelAllOrdersAfterResult(payload, t)
+	case *DepositMethods:
+		return p.parseDepositMethods(payload, t)
+	case *DepositAddresses:
+		return p.parseDepositAddresses(payload, t)
+	case *DepositStatus:
+		return p.parseDepositStatus(payload, t)
+	case *WithdrawInfo:
+		return p.parseWithdrawInfo(payload, t)
+	case *WithdrawResult:
+		return p.parseWithdrawResult(payload, t)
+	case *WithdrawStatus:
+		return p.parseWithdrawStatus(payload, t)
+	case *WithdrawCancelResult:
+		return p.parseWithdrawCancelResult(payload, t)
+	case *WalletTransferResult:
+		return p.parseWalletTransferResult(payload, t)
+	case *EarnStrategies:
+		return p.parseEarnStrategies(payload, t)
+	case *EarnAllocationResult:
+		return p.parseEarnAllocationResult(payload, t)
+	case *EarnAllocationStatus:
+		return p.parseEarnAllocationStatus(payload, t)
+	case *EarnAllocations:
+		return p.parseEarnAllocations(payload, t)
+	case *AddExportResult:
+		return p.parseAddExportResult(payload, t)
+	case *ExportStatus:
+		return p.parseExportStatus(payload, t)
+	case *RemoveExportResult:
+		return p.parseRemoveExportResult(payload, t)
+	case *CreateSubaccountResult:
+		return p.parseCreateSubaccountResult(payload, t)
+	case *AccountTransferResult:
+		return p.parseAccountTransferResult(payload, t)
+	case *WebSocketsToken:
+		return p.parseWebSocketsToken(payload, t)
 	default:
 		return fmt.Errorf("%w: unsupported type %s", ErrParse, reflect.TypeOf(v).String())
 	}
 }
 
-func (p *Parser) parsePublicTime(payload []byte, parsed *Time) error {
-	msg := responsePublicTime{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (Time) parseInto(p *Parser, payload []byte, parsed *Time) error {
+	return p.parsePublicTime(payload, parsed)
+}
+
+func (SystemStatus) parseInto(p *Parser, payload []byte, parsed *SystemStatus) error {
+	return p.parseSystemStatus(payload, parsed)
+}
+
+func (Assets) parseInto(p *Parser, payload []byte, parsed *Assets) error {
+	return p.parseAssets(payload, parsed)
+}
+
+func (AssetPairs) parseInto(p *Parser, payload []byte, parsed *AssetPairs) error {
+	return p.parseAssetPairs(payload, parsed)
+}
+
+func (Tickers) parseInto(p *Parser, payload []byte, parsed *Tickers) error {
+	return p.parseTickers(payload, parsed)
+}
+
+func (OHLCs) parseInto(p *Parser, payload []byte, parsed *OHLCs) error {
+	return p.parseOHLCs(payload, parsed)
+}
+
+func (OrderBook) parseInto(p *Parser, payload []byte, parsed *OrderBook) error {
+	return p.parseOrderBook(payload, parsed)
+}
+
+func (RecentTrades) parseInto(p *Parser, payload []byte, parsed *RecentTrades) error {
+	return p.parseRecentTrades(payload, parsed)
+}
+
+func (RecentSpreads) parseInto(p *Parser, payload []byte, parsed *RecentSpreads) error {
+	return p.parseRecentSpreads(payload, parsed)
+}
+
+func (TradeBalance) parseInto(p *Parser, payload []byte, parsed *TradeBalance) error {
+	return p.parseTradeBalance(payload, parsed)
+}
+
+func (OpenOrders) parseInto(p *Parser, payload []byte, parsed *OpenOrders) error {
+	return p.parseOpenOrders(payload, parsed)
+}
+
+func (ClosedOrders) parseInto(p *Parser, payload []byte, parsed *ClosedOrders) error {
+	return p.parseClosedOrders(payload, parsed)
+}
+
+func (OrdersInfo) parseInto(p *Parser, payload []byte, parsed *OrdersInfo) error {
+	return p.parseOrdersInfo(payload, parsed)
+}
+
+func (TradesHistory) parseInto(p *Parser, payload []byte, parsed *TradesHistory) error {
+	return p.parseTradesHistory(payload, parsed)
+}
+
+func (OpenPositions) parseInto(p *Parser, payload []byte, parsed *OpenPositions) error {
+	return p.parseOpenPositions(payload, parsed)
+}
+
+func (Ledgers) parseInto(p *Parser, payload []byte, parsed *Ledgers) error {
+	return p.parseLedgers(payload, parsed)
+}
+
+func (LedgersInfo) parseInto(p *Parser, payload []byte, parsed *LedgersInfo) error {
+	return p.parseLedgersInfo(payload, parsed)
+}
+
+func (TradeVolume) parseInto(p *Parser, payload []byte, parsed *TradeVolume) error {
+	return p.parseTradeVolume(payload, parsed)
+}
+
+func (AddOrderResult) parseInto(p *Parser, payload []byte, parsed *AddOrderResult) error {
+	return p.parseAddOrderResult(payload, parsed)
+}
+
+func (EditOrderResult) parseInto(p *Parser, payload []byte, parsed *EditOrderResult) error {
+	return p.parseEditOrderResult(payload, parsed)
+}
+
+func (CancelResult) parseInto(p *Parser, payload []byte, parsed *CancelResult) error {
+	return p.parseCancelResult(payload, parsed)
+}
+
+func (CancelAllOrdersAfterResult) parseInto(p *Parser, payload []byte, parsed *CancelAllOrdersAfterResult) error {
+	return p.parseCancelAllOrdersAfterResult(payload, parsed)
+}
+
+func (DepositMethods) parseInto(p *Parser, payload []byte, parsed *DepositMethods) error {
+	return p.parseDepositMethods(payload, parsed)
+}
+
+func (DepositAddresses) parseInto(p *Parser, payload []byte, parsed *DepositAddresses) error {
+	return p.parseDepositAddresses(payload, parsed)
+}
+
+func (DepositStatus) parseInto(p *Parser, payload []byte, parsed *DepositStatus) error {
+	return p.parseDepositStatus(payload, parsed)
+}
+
+func (WithdrawInfo) parseInto(p *Parser, payload []byte, parsed *WithdrawInfo) error {
+	return p.parseWithdrawInfo(payload, parsed)
+}
+
+func (WithdrawResult) parseInto(p *Parser, payload []byte, parsed *WithdrawResult) error {
+	return p.parseWithdrawResult(payload, parsed)
+}
+
+func (WithdrawStatus) parseInto(p *Parser, payload []byte, parsed *WithdrawStatus) error {
+	return p.parseWithdrawStatus(payload, parsed)
+}
+
+func (WithdrawCancelResult) parseInto(p *Parser, payload []byte, parsed *WithdrawCancelResult) error {
+	return p.parseWithdrawCancelResult(payload, parsed)
+}
+
+func (WalletTransferResult) parseInto(p *Parser, payload []byte, parsed *WalletTransferResult) error {
+	return p.parseWalletTransferResult(payload, parsed)
+}
+
+func (EarnStrategies) parseInto(p *Parser, payload []byte, parsed *EarnStrategies) error {
+	return p.parseEarnStrategies(payload, parsed)
+}
+
+func (EarnAllocationResult) parseInto(p *Parser, payload []byte, parsed *EarnAllocationResult) error {
+	return p.parseEarnAllocationResult(payload, parsed)
+}
+
+func (EarnAllocationStatus) parseInto(p *Parser, payload []byte, parsed *EarnAllocationStatus) error {
+	return p.parseEarnAllocationStatus(payload, parsed)
+}
+
+func (EarnAllocations) parseInto(p *Parser, payload []byte, parsed *EarnAllocations) error {
+	return p.parseEarnAllocations(payload, parsed)
+}
+
+func (AddExportResult) parseInto(p *Parser, payload []byte, parsed *AddExportResult) error {
+	return p.parseAddExportResult(payload, parsed)
+}
+
+func (ExportStatus) parseInto(p *Parser, payload []byte, parsed *ExportStatus) error {
+	return p.parseExportStatus(payload, parsed)
+}
+
+func (RemoveExportResult) parseInto(p *Parser, payload []byte, parsed *RemoveExportResult) error {
+	return p.parseRemoveExportResult(payload, parsed)
+}
+
+func (CreateSubaccountResult) parseInto(p *Parser, payload []byte, parsed *CreateSubaccountResult) error {
+	return p.parseCreateSubaccountResult(payload, parsed)
+}
+
+func (AccountTransferResult) parseInto(p *Parser, payload []byte, parsed *AccountTransferResult) error {
+	return p.parseAccountTransferResult(payload, parsed)
+}
+
+func (WebSocketsToken) parseInto(p *Parser, payload []byte, parsed *WebSocketsToken) error {
+	return p.parseWebSocketsToken(payload, parsed)
+}
+
+func (p *Parser) parsePublicTime(payload []byte, parsed *Time) error {
+	msg := responsePublicTime{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	*parsed = Time{
+		Errors:    p.parseErrors(msg.Errors),
+		Warnings:  p.parseWarnings(msg.Errors),
+		Timestamp: time.Unix(msg.Result.UnixTimestamp, 0).UTC(),
+	}
+
+	return nil
+}
+
+func (p *Parser) parseSystemStatus(payload []byte, parsed *SystemStatus) error {
+	msg := responseSystemStatus{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	t, err := p.parseOptionalTime(msg.Result.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	*parsed = SystemStatus{
+		Errors:    p.parseErrors(msg.Errors),
+		Warnings:  p.parseWarnings(msg.Errors),
+		Status:    p.parseSystemStatusValue(msg.Result.Status),
+		RawStatus: msg.Result.Status,
+		Timestamp: t,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseSystemStatusValue(v string) SystemStatusValue {
+	switch v {
+	case "online":
+		return SystemStatusValueOnline
+	case "maintenance":
+		return SystemStatusValueMaintenance
+	case "cancel_only":
+		return SystemStatusValueCancelOnly
+	case "post_only":
+		return SystemStatusValuePostOnly
+	default:
+		return SystemStatusValueUnknown
+	}
+}
+
+func (p *Parser) parseAssets(payload []byte, parsed *Assets) error {
+	msg := responsePublicAssets{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	assets := make(map[string]Asset)
+	for name, asset := range msg.Result {
+		assets[name] = Asset{
+			Name:             name,
+			Class:            asset.Class,
+			AltName:          asset.AltName,
+			Precision:        asset.Decimals,
+			DisplayPrecision: asset.DisplayDecimals,
+		}
+	}
+
+	*parsed = Assets{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Assets:   assets,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseAssetPairs(payload []byte, parsed *AssetPairs) error {
+	msg := responsePublicAssetPairs{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	pairs := make(map[string]AssetPair)
+	for name, pair := range msg.Result {
+		info := p.parseAssetPairInfo(pair)
+
+		costMin, err := p.parseOptionalDecimal(pair.CostMin)
+		if err != nil {
+			return err
+		}
+
+		tickSize, err := p.parseOptionalDecimal(pair.TickSize)
+		if err != nil {
+			return err
+		}
+
+		orderMin, err := p.parseOptionalDecimal(pair.OrderMin.String())
+		if err != nil {
+			return err
+		}
+
+		feesTaker, err := p.parseFees(pair.Fees)
+		if err != nil {
+			return err
+		}
+
+		feesMaker, err := p.parseFees(pair.FeesMaker)
+		if err != nil {
+			return err
+		}
+
+		// margin responses name the stop out level "margin_level"
+		marginStop := pair.MarginStop
+		if info == AssetPairInfoMargin {
+			marginStop = pair.MarginLevel
+		}
+
+		pairs[name] = AssetPair{
+			Info:               info,
+			AltName:            pair.AltName,
+			WebSocketName:      pair.WSName,
+			AssetClassBase:     pair.AClassBase,
+			Base:               pair.Base,
+			AssetClassQuote:    pair.AClassQuote,
+			Quote:              pair.Quote,
+			Lot:                pair.Lot,
+			PairPrecision:      pair.PairDecimals,
+			LotPrecision:       pair.LotDecimals,
+			LotMultiplier:      pair.LotMultiplier,
+			LeverageBuy:        pair.LeverageBuy,
+			LeverageSell:       pair.LeverageSell,
+			FeesTaker:          feesTaker,
+			FeesMaker:          feesMaker,
+			FeeVolumeCurrency:  pair.FeeVolumeCurrency,
+			MarginCalls:        pair.MarginCalls,
+			MarginStop:         marginStop,
+			OrderMin:           orderMin,
+			CostMin:            costMin,
+			TickSize:           tickSize,
+			Status:             p.parseAssetPairStatus(pair.Status),
+			LongPositionLimit:  pair.LongPositionLimit,
+			ShortPositionLimit: pair.ShortPositionLimit,
+		}
+	}
+
+	*parsed = AssetPairs{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Pairs:    pairs,
+	}
+
+	return nil
+}
+
+// parseAssetPairInfo detect which "info" shape an asset pair was returned in
+// from the fields present, the full "info" shape is the only one to include
+// the alternate name
+func (p *Parser) parseAssetPairInfo(pair responsePublicAssetPairResultPair) AssetPairInfo {
+	switch {
+	case pair.AltName != "":
+		return AssetPairInfoInfo
+	case pair.LeverageBuy != nil || pair.LeverageSell != nil:
+		return AssetPairInfoLeverage
+	case pair.Fees != nil || pair.FeesMaker != nil || pair.FeeVolumeCurrency != "":
+		return AssetPairInfoFees
+	case pair.MarginCalls != 0 || pair.MarginLevel != 0:
+		return AssetPairInfoMargin
+	default:
+		return ""
+	}
+}
+
+func (p *Parser) parseAssetPairStatus(v string) AssetPairStatus {
+	switch v {
+	case "online":
+		return AssetPairStatusOnline
+	case "cancel_only":
+		return AssetPairStatusCancelOnly
+	case "post_only":
+		return AssetPairStatusPostOnly
+	case "reduce_only":
+		return AssetPairStatusReduceOnly
+	case "limit_only":
+		return AssetPairStatusLimitOnly
+	default:
+		return AssetPairStatusUnknown
+	}
+}
+
+// parseFees parse [volume, percentage] fee tiers
+func (p *Parser) parseFees(fees [][]json.Number) ([]Fee, error) {
+	if fees == nil {
+		return nil, nil
+	}
+
+	f := make([]Fee, len(fees))
+	for i, fee := range fees {
+		if len(fee) < 2 {
+			return nil, fmt.Errorf("%w:fee tier has %d values, expected at least 2", ErrParse, len(fee))
+		}
+
+		parsed, err := p.parseFee(fee[0].String(), fee[1].String())
+		if err != nil {
+			return nil, err
+		}
+
+		f[i] = parsed
+	}
+
+	return f, nil
+}
+
+func (p *Parser) parseTickers(payload []byte, parsed *Tickers) error {
+	msg := responsePublicTicker{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	tickers := make(map[string]Ticker, len(msg.Result))
+	for pair, ticker := range msg.Result {
+		t, err := p.parseTicker(pair, ticker)
+		if err != nil {
+			return err
+		}
+
+		tickers[pair] = t
+	}
+
+	*parsed = Tickers{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Result:   tickers,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseTicker(pair string, ticker responsePublicTickerInformation) (Ticker, error) {
+	for _, field := range []struct {
+		name   string
+		length int
+		min    int
+	}{
+		{name: "ask", length: len(ticker.Ask), min: 3},
+		{name: "bid", length: len(ticker.Bid), min: 3},
+		{name: "last close", length: len(ticker.LastClose), min: 2},
+		{name: "volume", length: len(ticker.Volume), min: 2},
+		{name: "volume weighted average price", length: len(ticker.VolumeWeightedAveragePrice), min: 2},
+		{name: "number of trades", length: len(ticker.NumberOfTrades), min: 2},
+		{name: "low", length: len(ticker.Low), min: 2},
+		{name: "high", length: len(ticker.High), min: 2},
+	} {
+		if field.length < field.min {
+			return Ticker{}, fmt.Errorf("%w:%s %s has %d values, expected at least %d", ErrParse, pair, field.name, field.length, field.min)
+		}
+	}
+
+	return Ticker{
+		Pair: pair,
+		Ask: AskBid{
+			Price:          decimal.Decimal(ticker.Ask[0]),
+			WholeLotVolume: decimal.Decimal(ticker.Ask[1]),
+			Volume:         decimal.Decimal(ticker.Ask[2]),
+		},
+		Bid: AskBid{
+			Price:          decimal.Decimal(ticker.Bid[0]),
+			WholeLotVolume: decimal.Decimal(ticker.Bid[1]),
+			Volume:         decimal.Decimal(ticker.Bid[2]),
+		},
+		LastClose: Close{
+			Price:  decimal.Decimal(ticker.LastClose[0]),
+			Volume: decimal.Decimal(ticker.LastClose[1]),
+		},
+		VolumeToday:                           decimal.Decimal(ticker.Volume[0]),
+		VolumeLast24Hours:                     decimal.Decimal(ticker.Volume[1]),
+		VolumeWeightedAveragePriceToday:       decimal.Decimal(ticker.VolumeWeightedAveragePrice[0]),
+		VolumeWeightedAveragePriceLast24Hours: decimal.Decimal(ticker.VolumeWeightedAveragePrice[1]),
+		NumberOfTradesToday:                   ticker.NumberOfTrades[0],
+		NumberOfTradesLast24Hours:             ticker.NumberOfTrades[1],
+		LowToday:                              decimal.Decimal(ticker.Low[0]),
+		LowLast24Hours:                        decimal.Decimal(ticker.Low[1]),
+		HighToday:                             decimal.Decimal(ticker.High[0]),
+		HighLast24Hours:                       decimal.Decimal(ticker.High[1]),
+		Open:                                  decimal.Decimal(ticker.Open),
+	}, nil
+}
+
+// parseWSTicker parse the data of a websocket ticker channel message
+func (p *Parser) parseWSTicker(pair string, data []json.RawMessage) (Ticker, error) {
+	if len(data) != 1 {
+		return Ticker{}, fmt.Errorf("%w:%s ticker has %d data values, expected 1", ErrParse, pair, len(data))
+	}
+
+	msg := responseWSTicker{}
+	if err := p.unmarshal(data[0], &msg); err != nil {
+		return Ticker{}, fmt.Errorf("%w:%s ticker: %s", ErrParse, pair, err)
+	}
+
+	if len(msg.Open) < 1 {
+		return Ticker{}, fmt.Errorf("%w:%s open has 0 values, expected at least 1", ErrParse, pair)
+	}
+	msg.responsePublicTickerInformation.Open = msg.Open[0]
+
+	return p.parseTicker(pair, msg.responsePublicTickerInformation)
+}
+
+// parseWSOHLC parse the data of a websocket OHLC channel message, the time
+// of the message is that of the last update so the candle's start is taken
+// from its end time instead
+func (p *Parser) parseWSOHLC(interval OHLCInterval, pair string, data []json.RawMessage) (OHLCUpdate, error) {
+	if len(data) != 1 {
+		return OHLCUpdate{}, fmt.Errorf("%w:%s ohlc has %d data values, expected 1", ErrParse, pair, len(data))
+	}
+
+	v := responseWSOHLCValue{}
+	if err := json.Unmarshal(data[0], &v); err != nil {
+		return OHLCUpdate{}, fmt.Errorf("%w:%s ohlc: %s", ErrParse, pair, err)
+	}
+
+	endTime := time.Time(v.EndTime)
+
+	return OHLCUpdate{
+		Pair: pair,
+		OHLC: OHLC{
+			Time:                       endTime.Add(-time.Duration(interval) * time.Minute),
+			Open:                       decimal.Decimal(v.Open),
+			High:                       decimal.Decimal(v.High),
+			Low:                        decimal.Decimal(v.Low),
+			Close:                      decimal.Decimal(v.Close),
+			VolumeWeightedAveragePrice: decimal.Decimal(v.VolumeWeightedAveragePrice),
+			Volume:                     decimal.Decimal(v.Volume),
+			Count:                      v.Count,
+		},
+		EndTime: endTime,
+	}, nil
+}
+
+// parseWSTrades parse the data of a websocket trade channel message, a
+// message may contain several trades which are returned in order
+func (p *Parser) parseWSTrades(pair string, data []json.RawMessage) ([]TradeUpdate, error) {
+	if len(data) != 1 {
+		return nil, fmt.Errorf("%w:%s trade has %d data values, expected 1", ErrParse, pair, len(data))
+	}
+
+	values := []responsePublicRecentTradeValue{}
+	if err := json.Unmarshal(data[0], &values); err != nil {
+		return nil, fmt.Errorf("%w:%s trade: %s", ErrParse, pair, err)
+	}
+
+	trades := make([]TradeUpdate, 0, len(values))
+	for _, value := range values {
+		trades = append(trades, TradeUpdate{Pair: pair, Trade: p.parseRecentTrade(value)})
+	}
+
+	return trades, nil
+}
+
+// parseWSSpread parse the data of a websocket spread channel message
+func (p *Parser) parseWSSpread(pair string, data []json.RawMessage) (SpreadUpdate, error) {
+	if len(data) != 1 {
+		return SpreadUpdate{}, fmt.Errorf("%w:%s spread has %d data values, expected 1", ErrParse, pair, len(data))
+	}
+
+	v := responseWSSpreadValue{}
+	if err := json.Unmarshal(data[0], &v); err != nil {
+		return SpreadUpdate{}, fmt.Errorf("%w:%s spread: %s", ErrParse, pair, err)
+	}
+
+	return SpreadUpdate{
+		Pair: pair,
+		Spread: Spread{
+			Timestamp: time.Time(v.Time),
+			Bid:       decimal.Decimal(v.Bid),
+			Ask:       decimal.Decimal(v.Ask),
+		},
+	}, nil
+}
+
+// parseWSBook parse the data of a websocket book channel message, the asks
+// and bids of an update may be given in separate data values. The exponent
+// of each price and volume is kept as given so the checksum of a book can be
+// calculated from them
+func (p *Parser) parseWSBook(depth int, pair string, data []json.RawMessage) (BookUpdate, error) {
+	if len(data) < 1 || len(data) > 2 {
+		return BookUpdate{}, fmt.Errorf("%w:%s book has %d data values, expected 1 or 2", ErrParse, pair, len(data))
+	}
+
+	update := BookUpdate{Pair: pair, Depth: depth}
+	for i, v := range data {
+		msg := responseWSBook{}
+		if err := p.unmarshal(v, &msg); err != nil {
+			return BookUpdate{}, fmt.Errorf("%w:%s book: %s", ErrParse, pair, err)
+		}
+
+		if msg.AskSnapshot != nil || msg.BidSnapshot != nil {
+			if len(data) != 1 {
+				return BookUpdate{}, fmt.Errorf("%w:%s book snapshot has %d data values, expected 1", ErrParse, pair, len(data))
+			}
+
+			update.Snapshot = true
+			update.Asks = p.parseWSBookLevels(msg.AskSnapshot)
+			update.Bids = p.parseWSBookLevels(msg.BidSnapshot)
+			continue
+		}
+
+		update.Asks = append(update.Asks, p.parseWSBookLevels(msg.Asks)...)
+		update.Bids = append(update.Bids, p.parseWSBookLevels(msg.Bids)...)
+
+		if msg.Checksum == "" {
+			continue
+		}
+
+		checksum, err := strconv.ParseUint(msg.Checksum, 10, 32)
+		if err != nil {
+			return BookUpdate{}, fmt.Errorf("%w:%s book checksum %d: %s", ErrParse, pair, i, err)
+		}
+
+		update.Checksum = uint32(checksum)
+	}
+
+	return update, nil
+}
+
+// parseWSBookLevels parse the levels of a websocket book channel message
+func (p *Parser) parseWSBookLevels(levels []responseWSBookLevel) []AskBid {
+	if levels == nil {
+		return nil
+	}
+
+	parsed := make([]AskBid, 0, len(levels))
+	for _, level := range levels {
+		parsed = append(parsed, AskBid{
+			Price:     decimal.Decimal(level.Price),
+			Volume:    decimal.Decimal(level.Volume),
+			Timestamp: time.Time(level.Time),
+		})
+	}
+
+	return parsed
+}
+
+// unmarshalWSV2 unmarshal the value of a v2 websocket channel message into
+// v, returning the type of the message such as "snapshot" or "update"
+func (p *Parser) unmarshalWSV2(channel, pair string, data []json.RawMessage, v interface{}) (string, error) {
+	if len(data) != 2 {
+		return "", fmt.Errorf("%w:%s %s has %d data values, expected 2", ErrParse, pair, channel, len(data))
+	}
+
+	if err := p.unmarshal(data[0], v); err != nil {
+		return "", fmt.Errorf("%w:%s %s: %s", ErrParse, pair, channel, err)
+	}
+
+	var typ string
+	if err := json.Unmarshal(data[1], &typ); err != nil {
+		return "", fmt.Errorf("%w:%s %s type: %s", ErrParse, pair, channel, err)
+	}
+
+	return typ, nil
+}
+
+// parseWSV2Ticker parse a value of a v2 websocket ticker channel message,
+// only the values of the last 24 hours are given so the open is that of 24
+// hours ago
+func (p *Parser) parseWSV2Ticker(pair string, data []json.RawMessage) (Ticker, error) {
+	v := responseWSV2Ticker{}
+	if _, err := p.unmarshalWSV2("ticker", pair, data, &v); err != nil {
+		return Ticker{}, err
+	}
+
+	return Ticker{
+		Pair: pair,
+		Ask: AskBid{
+			Price:  decimal.Decimal(v.Ask),
+			Volume: decimal.Decimal(v.AskQty),
+		},
+		Bid: AskBid{
+			Price:  decimal.Decimal(v.Bid),
+			Volume: decimal.Decimal(v.BidQty),
+		},
+		LastClose:                             Close{Price: decimal.Decimal(v.Last)},
+		VolumeLast24Hours:                     decimal.Decimal(v.Volume),
+		VolumeWeightedAveragePriceLast24Hours: decimal.Decimal(v.VWAP),
+		LowLast24Hours:                        decimal.Decimal(v.Low),
+		HighLast24Hours:                       decimal.Decimal(v.High),
+		Open:                                  decimal.Decimal(v.Last).Sub(decimal.Decimal(v.Change)),
+	}, nil
+}
+
+// parseWSV2Spread parse a value of a v2 websocket ticker channel message as
+// a spread, the ticker has no timestamp
+func (p *Parser) parseWSV2Spread(pair string, data []json.RawMessage) (SpreadUpdate, error) {
+	v := responseWSV2Ticker{}
+	if _, err := p.unmarshalWSV2("ticker", pair, data, &v); err != nil {
+		return SpreadUpdate{}, err
+	}
+
+	return SpreadUpdate{
+		Pair: pair,
+		Spread: Spread{
+			Bid: decimal.Decimal(v.Bid),
+			Ask: decimal.Decimal(v.Ask),
+		},
+	}, nil
+}
+
+// parseWSV2OHLC parse a value of a v2 websocket ohlc channel message
+func (p *Parser) parseWSV2OHLC(pair string, data []json.RawMessage) (OHLCUpdate, error) {
+	v := responseWSV2OHLC{}
+	if _, err := p.unmarshalWSV2("ohlc", pair, data, &v); err != nil {
+		return OHLCUpdate{}, err
+	}
+
+	return OHLCUpdate{
+		Pair: pair,
+		OHLC: OHLC{
+			Time:                       v.IntervalBegin,
+			Open:                       decimal.Decimal(v.Open),
+			High:                       decimal.Decimal(v.High),
+			Low:                        decimal.Decimal(v.Low),
+			Close:                      decimal.Decimal(v.Close),
+			VolumeWeightedAveragePrice: decimal.Decimal(v.VWAP),
+			Volume:                     decimal.Decimal(v.Volume),
+			Count:                      v.Trades,
+		},
+		EndTime: v.IntervalBegin.Add(time.Duration(v.Interval) * time.Minute),
+	}, nil
+}
+
+// parseWSV2Trades parse a value of a v2 websocket trade channel message,
+// each trade of a message is a value of its own
+func (p *Parser) parseWSV2Trades(pair string, data []json.RawMessage) ([]TradeUpdate, error) {
+	v := responseWSV2Trade{}
+	if _, err := p.unmarshalWSV2("trade", pair, data, &v); err != nil {
+		return nil, err
+	}
+
+	return []TradeUpdate{{
+		Pair: pair,
+		Trade: RecentTrade{
+			Price:   decimal.Decimal(v.Price),
+			Volume:  decimal.Decimal(v.Qty),
+			Time:    v.Timestamp,
+			Action:  p.parseOrderAction(v.Side),
+			Type:    p.parseOrderType(v.OrdType),
+			TradeID: v.TradeID,
+		},
+	}}, nil
+}
+
+// parseWSV2Book parse a value of a v2 websocket book channel message, the
+// levels of an update have the timestamp of the update
+func (p *Parser) parseWSV2Book(depth int, pair string, data []json.RawMessage) (BookUpdate, error) {
+	v := responseWSV2Book{}
+	typ, err := p.unmarshalWSV2("book", pair, data, &v)
+	if err != nil {
+		return BookUpdate{}, err
+	}
+
+	levels := func(levels []responseWSV2BookLevel) []AskBid {
+		if levels == nil {
+			return nil
+		}
+
+		parsed := make([]AskBid, 0, len(levels))
+		for _, level := range levels {
+			parsed = append(parsed, AskBid{
+				Price:     decimal.Decimal(level.Price),
+				Volume:    decimal.Decimal(level.Qty),
+				Timestamp: v.Timestamp,
+			})
+		}
+
+		return parsed
+	}
+
+	return BookUpdate{
+		Pair:     pair,
+		Depth:    depth,
+		Snapshot: typ == "snapshot",
+		Asks:     levels(v.Asks),
+		Bids:     levels(v.Bids),
+		Checksum: v.Checksum,
+	}, nil
+}
+
+// parseWSOwnTrades parse the data of a websocket ownTrades channel message,
+// each trade is an object keyed by its transaction id
+func (p *Parser) parseWSOwnTrades(data []json.RawMessage) ([]TradeHistoryEntry, error) {
+	if len(data) != 1 {
+		return nil, fmt.Errorf("%w:ownTrades has %d data values, expected 1", ErrParse, len(data))
+	}
+
+	values := []map[string]responseWSOwnTrade{}
+	if err := p.unmarshal(data[0], &values); err != nil {
+		return nil, fmt.Errorf("%w:ownTrades: %s", ErrParse, err)
+	}
+
+	trades := make([]TradeHistoryEntry, 0, len(values))
+	for _, value := range values {
+		for txid, trade := range value {
+			entry, err := p.parseTradeHistoryEntry(txid, trade.responsePrivateTrade)
+			if err != nil {
+				return nil, err
+			}
+
+			trades = append(trades, entry)
+		}
+	}
+
+	return trades, nil
+}
+
+// wsOrderState the fields received for an open order and its status
+type wsOrderState struct {
+	fields map[string]json.RawMessage
+	status OrderStatus
+}
+
+// parseWSOpenOrders parse the data of a websocket openOrders channel message,
+// the fields of each update are merged into the state of its order so a
+// complete order is returned. Orders are removed from the state once closed,
+// canceled or expired
+func (p *Parser) parseWSOpenOrders(orders map[string]*wsOrderState, data []json.RawMessage) ([]OrderUpdate, error) {
+	if len(data) != 1 {
+		return nil, fmt.Errorf("%w:openOrders has %d data values, expected 1", ErrParse, len(data))
+	}
+
+	values := []map[string]map[string]json.RawMessage{}
+	if err := json.Unmarshal(data[0], &values); err != nil {
+		return nil, fmt.Errorf("%w:openOrders: %s", ErrParse, err)
+	}
+
+	updates := make([]OrderUpdate, 0, len(values))
+	for _, value := range values {
+		for txid, fields := range value {
+			state, ok := orders[txid]
+			update := OrderUpdate{Type: OrderUpdateTypeNew}
+			if ok {
+				update.Type = OrderUpdateTypeChanged
+				update.PreviousStatus = state.status
+			} else {
+				state = &wsOrderState{fields: map[string]json.RawMessage{}}
+			}
+
+			for k, v := range fields {
+				state.fields[k] = v
+			}
+
+			order, err := p.parseWSOrder(txid, state.fields)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok && order.Status != state.status {
+				update.Type = OrderUpdateTypeStatus
+			}
+			update.Order = order
+
+			switch order.Status {
+			case OrderStatusClosed, OrderStatusCanceled, OrderStatusExpired:
+				delete(orders, txid)
+			default:
+				state.status = order.Status
+				orders[txid] = state
+			}
+
+			updates = append(updates, update)
+		}
+	}
+
+	return updates, nil
+}
+
+// parseWSOrder parse the fields received for a websocket order, unknown
+// fields are ignored as the order description differs to the REST API
+func (p *Parser) parseWSOrder(txid string, fields map[string]json.RawMessage) (Order, error) {
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return Order{}, fmt.Errorf("%w:%s order: %s", ErrParse, txid, err)
+	}
+
+	v := responseWSOrder{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return Order{}, fmt.Errorf("%w:%s order: %s", ErrParse, txid, err)
+	}
+
+	if v.AveragePrice != "" {
+		v.Price = v.AveragePrice
+	}
+
+	if v.CancelReason != "" {
+		v.Reason = v.CancelReason
+	}
+
+	return p.parseOrder(txid, v.responsePrivateOrder)
+}
+
+func (p *Parser) parseOHLCs(payload []byte, parsed *OHLCs) error {
+	msg := responsePublicOHLC{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	ohlcs := make(map[string][]OHLC)
+
+	for k, v := range msg.Result {
+		if k == "last" {
+			lastID, err := p.parseLastID(v)
+			if err != nil {
+				return err
+			}
+
+			parsed.LastID = lastID
+			continue
+		}
+
+		values := []responsePublicOHLCValue{}
+		if err := json.Unmarshal(v, &values); err != nil {
+			return fmt.Errorf("%w:%s ohlc: %s", ErrParse, k, err)
+		}
+
+		pairOHLCs := make([]OHLC, 0, len(values))
+		for _, value := range values {
+			pairOHLCs = append(pairOHLCs, p.parseOHLC(value))
+		}
+
+		ohlcs[k] = pairOHLCs
+	}
+
+	parsed.Errors = p.parseErrors(msg.Errors)
+
+	parsed.Warnings = p.parseWarnings(msg.Errors)
+	parsed.Result = ohlcs
+
+	return nil
+}
+
+func (p *Parser) parseOHLC(v responsePublicOHLCValue) OHLC {
+	return OHLC{
+		Time:                       time.Time(v.Time),
+		Open:                       decimal.Decimal(v.Open),
+		High:                       decimal.Decimal(v.High),
+		Low:                        decimal.Decimal(v.Low),
+		Close:                      decimal.Decimal(v.Close),
+		VolumeWeightedAveragePrice: decimal.Decimal(v.VolumeWeightedAveragePrice),
+		Volume:                     decimal.Decimal(v.Volume),
+		Count:                      v.Count,
+	}
+}
+
+// parseOrderBook parse a "/public/Depth" response, the asks of each pair are
+// sorted in ascending and the bids in descending price order with levels at
+// the same price in timestamp order. Errors only holds API errors, a crossed
+// book is reported by OrderBook.Validate
+func (p *Parser) parseOrderBook(payload []byte, parsed *OrderBook) error {
+	msg := responsePublicOrderBook{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	pairAsks := make(map[string][]AskBid)
+	pairBids := make(map[string][]AskBid)
+
+	for pair, askbids := range msg.Result {
+		asks := []AskBid{}
+		for _, ask := range askbids.Asks {
+			a, err := p.parseOrderBookEntry(pair, "ask", ask)
+			if err != nil {
+				return err
+			}
+
+			asks = append(asks, a)
+		}
+		pairAsks[pair] = asks
+
+		bids := []AskBid{}
+		for _, bid := range askbids.Bids {
+			b, err := p.parseOrderBookEntry(pair, "bid", bid)
+			if err != nil {
+				return err
+			}
+
+			bids = append(bids, b)
+		}
+		pairBids[pair] = bids
+
+		sortOrderBookLevels(asks, askBefore)
+		sortOrderBookLevels(bids, bidBefore)
+	}
+
+	*parsed = OrderBook{
+		Errors:   p.parseErrors(msg.Error),
+		Warnings: p.parseWarnings(msg.Error),
+		Asks:     pairAsks,
+		Bids:     pairBids,
+	}
+
+	return nil
+}
+
+// parseOrderBookEntry parse a single [price, volume, timestamp] ask or bid,
+// values may be either JSON strings or numbers
+func (p *Parser) parseOrderBookEntry(pair, field string, v []json.Number) (AskBid, error) {
+	if len(v) < 3 {
+		return AskBid{}, fmt.Errorf("%w:%s %s has %d values, expected at least 3", ErrParse, pair, field, len(v))
+	}
+
+	price, err := p.parsePairDecimal(pair, field+" price", v[0].String())
+	if err != nil {
+		return AskBid{}, err
+	}
+
+	volume, err := p.parsePairDecimal(pair, field+" volume", v[1].String())
+	if err != nil {
+		return AskBid{}, err
+	}
+
+	timestamp, err := p.parseTimestamp(v[2])
+	if err != nil {
+		return AskBid{}, err
+	}
+
+	return AskBid{
+		Price:     price,
+		Volume:    volume,
+		Timestamp: timestamp,
+	}, nil
+}
+
+func (p *Parser) parseRecentTrades(payload []byte, parsed *RecentTrades) error {
+	msg := responsePublicRecentTrades{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	trades := make(map[string][]RecentTrade)
+
+	for k, v := range msg.Result {
+		if k == "last" {
+			lastID, err := p.parseLastID(v)
+			if err != nil {
+				return err
+			}
+
+			parsed.LastID = lastID
+			continue
+		}
+
+		values := []responsePublicRecentTradeValue{}
+		if err := json.Unmarshal(v, &values); err != nil {
+			return fmt.Errorf("%w:%s trade: %s", ErrParse, k, err)
+		}
+
+		pairTrades := make([]RecentTrade, 0, len(values))
+		for _, value := range values {
+			pairTrades = append(pairTrades, p.parseRecentTrade(value))
+		}
+
+		trades[k] = pairTrades
+	}
+
+	parsed.Trades = trades
+	parsed.Errors = p.parseErrors(msg.Error)
+	parsed.Warnings = p.parseWarnings(msg.Error)
+
+	return nil
+}
+
+func (p *Parser) parseRecentTrade(v responsePublicRecentTradeValue) RecentTrade {
+	return RecentTrade{
+		Price:         decimal.Decimal(v.Price),
+		Volume:        decimal.Decimal(v.Volume),
+		Time:          time.Time(v.Time),
+		Action:        p.parseOrderAction(v.Action),
+		Type:          p.parseOrderType(v.Type),
+		Miscellaneous: v.Miscellaneous,
+		TradeID:       v.TradeID,
+	}
+}
+
+// parseOrderAction parse either the abbreviated or full name of an order
+// action
+func (p *Parser) parseOrderAction(v string) OrderAction {
+	switch v {
+	case "b", "buy":
+		return OrderActionBuy
+	case "s", "sell":
+		return OrderActionSell
+	default:
+		return OrderActionUnknown
+	}
+}
+
+// parseOrderType parse either the abbreviated or full name of an order type
+func (p *Parser) parseOrderType(v string) OrderType {
+	switch v {
+	case "m", "market":
+		return OrderTypeMarket
+	case "l", "limit":
+		return OrderTypeLimit
+	case "stop-loss":
+		return OrderTypeStopLoss
+	case "take-profit":
+		return OrderTypeTakeProfit
+	case "stop-loss-limit":
+		return OrderTypeStopLossLimit
+	case "take-profit-limit":
+		return OrderTypeTakeProfitLimit
+	case "settle-position":
+		return OrderTypeSettlePosition
+	default:
+		return OrderTypeUnknown
+	}
+}
+
+func (p *Parser) parseRecentSpreads(payload []byte, parsed *RecentSpreads) error {
+	msg := responsePublicRecentSpreads{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w: %s", ErrParse, err)
+	}
+
+	spreads := make(map[string][]Spread)
+	for k, v := range msg.Result {
+		if k == "last" {
+			lastID, err := p.parseLastID(v)
+			if err != nil {
+				return err
+			}
+
+			parsed.LastID = lastID
+			continue
+		}
+
+		values := []responsePublicRecentSpreadValue{}
+		if err := json.Unmarshal(v, &values); err != nil {
+			return fmt.Errorf("%w:%s spread: %s", ErrParse, k, err)
+		}
+
+		pairSpreads := make([]Spread, 0, len(values))
+		for _, value := range values {
+			pairSpreads = append(pairSpreads, p.parseRecentSpread(value))
+		}
+
+		spreads[k] = pairSpreads
+	}
+
+	parsed.Spreads = spreads
+	parsed.Errors = p.parseErrors(msg.Error)
+	parsed.Warnings = p.parseWarnings(msg.Error)
+
+	return nil
+}
+
+func (p *Parser) parseRecentSpread(v responsePublicRecentSpreadValue) Spread {
+	return Spread{
+		Timestamp: time.Time(v.Time),
+		Bid:       decimal.Decimal(v.Bid),
+		Ask:       decimal.Decimal(v.Ask),
+	}
+}
+
+// parseLastID parse the integer "last" cursor of a paginated public result,
+// given as either a JSON number or string
+func (p *Parser) parseLastID(v json.RawMessage) (uint64, error) {
+	var n json.Number
+	if err := json.Unmarshal(v, &n); err != nil {
+		return 0, fmt.Errorf("%w:last: %s", ErrParse, err)
+	}
+
+	lastID, err := strconv.ParseUint(n.String(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	return lastID, nil
+}
+
+// parsePairDecimal parse a decimal string, an ErrParse naming the pair and
+// field is returned otherwise
+func (p *Parser) parsePairDecimal(pair, field, v string) (decimal.Decimal, error) {
+	d, err := parseDecimal(v)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("%w:%s %s: %s", ErrParse, pair, field, err)
+	}
+
+	return d, nil
+}
+
+// parseDecimal parse a decimal, plain values of up to 18 digits are parsed
+// without the intermediate allocations of decimal.NewFromString, which anything
+// else falls back to
+func parseDecimal[T string | []byte](v T) (decimal.Decimal, error) {
+	i, neg := 0, false
+	if len(v) > 0 && (v[0] == '-' || v[0] == '+') {
+		i, neg = 1, v[0] == '-'
+	}
+
+	var value int64
+	var exp int32
+	digits, point := 0, false
+	for ; i < len(v); i++ {
+		switch c := v[i]; {
+		case c >= '0' && c <= '9' && digits < 18:
+			value = value*10 + int64(c-'0')
+			digits++
+			if point {
+				exp--
+			}
+		case c == '.' && !point:
+			point = true
+		default:
+			return decimal.NewFromString(string(v))
+		}
+	}
+
+	if digits == 0 {
+		return decimal.NewFromString(string(v))
+	}
+
+	if neg {
+		value = -value
+	}
+
+	return decimal.New(value, exp), nil
+}
+
+func (p *Parser) parseTradeBalance(payload []byte, parsed *TradeBalance) error {
+	msg := responsePrivateTradeBalance{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	equivalentBalance, err := p.parseOptionalDecimal(msg.Result.EquivalentBalance)
+	if err != nil {
+		return err
+	}
+
+	tradeBalance, err := p.parseOptionalDecimal(msg.Result.TradeBalance)
+	if err != nil {
+		return err
+	}
+
+	marginUsed, err := p.parseOptionalDecimal(msg.Result.MarginUsed)
+	if err != nil {
+		return err
+	}
+
+	unrealizedPnL, err := p.parseOptionalDecimal(msg.Result.UnrealizedPnL)
+	if err != nil {
+		return err
+	}
+
+	costBasis, err := p.parseOptionalDecimal(msg.Result.CostBasis)
+	if err != nil {
+		return err
+	}
+
+	floatingValuation, err := p.parseOptionalDecimal(msg.Result.FloatingValuation)
+	if err != nil {
+		return err
+	}
+
+	equity, err := p.parseOptionalDecimal(msg.Result.Equity)
+	if err != nil {
+		return err
+	}
+
+	freeMargin, err := p.parseOptionalDecimal(msg.Result.FreeMargin)
+	if err != nil {
+		return err
+	}
+
+	marginLevel, err := p.parseOptionalDecimal(msg.Result.MarginLevel)
+	if err != nil {
+		return err
+	}
+
+	unexecutedValue, err := p.parseOptionalDecimal(msg.Result.UnexecutedValue)
+	if err != nil {
+		return err
+	}
+
+	*parsed = TradeBalance{
+		Errors:            p.parseErrors(msg.Errors),
+		Warnings:          p.parseWarnings(msg.Errors),
+		EquivalentBalance: equivalentBalance,
+		TradeBalance:      tradeBalance,
+		MarginUsed:        marginUsed,
+		UnrealizedPnL:     unrealizedPnL,
+		CostBasis:         costBasis,
+		FloatingValuation: floatingValuation,
+		Equity:            equity,
+		FreeMargin:        freeMargin,
+		MarginLevel:       marginLevel,
+		UnexecutedValue:   unexecutedValue,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseOpenOrders(payload []byte, parsed *OpenOrders) error {
+	msg := responsePrivateOpenOrders{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	orders, err := p.parseOrders(msg.Result.Open)
+	if err != nil {
+		return err
+	}
+
+	*parsed = OpenOrders{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Orders:   orders,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseClosedOrders(payload []byte, parsed *ClosedOrders) error {
+	msg := responsePrivateClosedOrders{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	orders, err := p.parseOrders(msg.Result.Closed)
+	if err != nil {
+		return err
+	}
+
+	*parsed = ClosedOrders{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Orders:   orders,
+		Count:    msg.Result.Count,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseOrdersInfo(payload []byte, parsed *OrdersInfo) error {
+	msg := responsePrivateQueryOrders{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	orders, err := p.parseOrders(msg.Result)
+	if err != nil {
+		return err
+	}
+
+	*parsed = OrdersInfo{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Orders:   orders,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseOrders(v map[string]responsePrivateOrder) (map[string]Order, error) {
+	orders := make(map[string]Order, len(v))
+	for txid, order := range v {
+		o, err := p.parseOrder(txid, order)
+		if err != nil {
+			return nil, err
+		}
+
+		orders[txid] = o
+	}
+
+	return orders, nil
+}
+
+func (p *Parser) parseOrder(txid string, order responsePrivateOrder) (Order, error) {
+	openTime, err := p.parseTimestamp(order.OpenTime)
+	if err != nil {
+		return Order{}, err
+	}
+
+	startTime, err := p.parseTimestamp(order.StartTime)
+	if err != nil {
+		return Order{}, err
+	}
+
+	expireTime, err := p.parseTimestamp(order.ExpireTime)
+	if err != nil {
+		return Order{}, err
+	}
+
+	closeTime, err := p.parseTimestamp(order.CloseTime)
+	if err != nil {
+		return Order{}, err
+	}
+
+	description, err := p.parseOrderDescription(order.Description)
+	if err != nil {
+		return Order{}, err
+	}
+
+	volume, err := p.parseOptionalDecimal(order.Volume)
+	if err != nil {
+		return Order{}, err
+	}
+
+	volumeExecuted, err := p.parseOptionalDecimal(order.VolumeExecuted)
+	if err != nil {
+		return Order{}, err
+	}
+
+	cost, err := p.parseOptionalDecimal(order.Cost)
+	if err != nil {
+		return Order{}, err
+	}
+
+	fee, err := p.parseOptionalDecimal(order.Fee)
+	if err != nil {
+		return Order{}, err
+	}
+
+	averagePrice, err := p.parseOptionalDecimal(order.Price)
+	if err != nil {
+		return Order{}, err
+	}
+
+	stopPrice, err := p.parseOptionalDecimal(order.StopPrice)
+	if err != nil {
+		return Order{}, err
+	}
+
+	limitPrice, err := p.parseOptionalDecimal(order.LimitPrice)
+	if err != nil {
+		return Order{}, err
+	}
+
+	return Order{
+		TransactionID:  txid,
+		ReferenceID:    order.RefID,
+		UserReference:  order.UserRef,
+		Status:         p.parseOrderStatus(order.Status),
+		OpenTime:       openTime,
+		StartTime:      startTime,
+		ExpireTime:     expireTime,
+		CloseTime:      closeTime,
+		CloseReason:    order.Reason,
+		Description:    description,
+		Volume:         volume,
+		VolumeExecuted: volumeExecuted,
+		Cost:           cost,
+		Fee:            fee,
+		AveragePrice:   averagePrice,
+		StopPrice:      stopPrice,
+		LimitPrice:     limitPrice,
+		Miscellaneous:  order.Misc,
+		Flags:          p.parseList(order.OrderFlags),
+		Trades:         order.Trades,
+	}, nil
+}
+
+func (p *Parser) parseOrderDescription(description responsePrivateOrderDescription) (OrderDescription, error) {
+	price, err := p.parseOptionalDecimal(description.Price)
+	if err != nil {
+		return OrderDescription{}, err
+	}
+
+	secondaryPrice, err := p.parseOptionalDecimal(description.Price2)
+	if err != nil {
+		return OrderDescription{}, err
+	}
+
+	return OrderDescription{
+		Pair:           description.Pair,
+		Action:         p.parseOrderAction(description.Type),
+		Type:           p.parseOrderType(description.OrderType),
+		Price:          price,
+		SecondaryPrice: secondaryPrice,
+		Leverage:       description.Leverage,
+		Order:          description.Order,
+		Close:          description.Close,
+	}, nil
+}
+
+func (p *Parser) parseTradesHistory(payload []byte, parsed *TradesHistory) error {
+	msg := responsePrivateTradesHistory{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	trades := make(map[string]TradeHistoryEntry, len(msg.Result.Trades))
+	for txid, trade := range msg.Result.Trades {
+		t, err := p.parseTradeHistoryEntry(txid, trade)
+		if err != nil {
+			return err
+		}
+
+		trades[txid] = t
+	}
+
+	*parsed = TradesHistory{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Trades:   trades,
+		Count:    msg.Result.Count,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseTradeHistoryEntry(txid string, trade responsePrivateTrade) (TradeHistoryEntry, error) {
+	tradeTime, err := p.parseTimestamp(trade.Time)
+	if err != nil {
+		return TradeHistoryEntry{}, err
+	}
+
+	price, err := p.parseOptionalDecimal(trade.Price)
+	if err != nil {
+		return TradeHistoryEntry{}, err
+	}
+
+	cost, err := p.parseOptionalDecimal(trade.Cost)
+	if err != nil {
+		return TradeHistoryEntry{}, err
+	}
+
+	fee, err := p.parseOptionalDecimal(trade.Fee)
+	if err != nil {
+		return TradeHistoryEntry{}, err
+	}
+
+	volume, err := p.parseOptionalDecimal(trade.Volume)
+	if err != nil {
+		return TradeHistoryEntry{}, err
+	}
+
+	margin, err := p.parseOptionalDecimal(trade.Margin)
+	if err != nil {
+		return TradeHistoryEntry{}, err
+	}
+
+	return TradeHistoryEntry{
+		TransactionID:         txid,
+		OrderTransactionID:    trade.OrderTxID,
+		PositionTransactionID: trade.PositionTxID,
+		Pair:                  trade.Pair,
+		Time:                  tradeTime,
+		Action:                p.parseOrderAction(trade.Type),
+		Type:                  p.parseOrderType(trade.OrderType),
+		Price:                 price,
+		Cost:                  cost,
+		Fee:                   fee,
+		Volume:                volume,
+		Margin:                margin,
+		Miscellaneous:         trade.Misc,
+	}, nil
+}
+
+func (p *Parser) parseOpenPositions(payload []byte, parsed *OpenPositions) error {
+	msg := responsePrivateOpenPositions{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	positions := make(map[string]Position, len(msg.Result))
+	for txid, position := range msg.Result {
+		pos, err := p.parsePosition(txid, position)
+		if err != nil {
+			return err
+		}
+
+		positions[txid] = pos
+	}
+
+	*parsed = OpenPositions{
+		Errors:    p.parseErrors(msg.Errors),
+		Warnings:  p.parseWarnings(msg.Errors),
+		Positions: positions,
+	}
+
+	return nil
+}
+
+func (p *Parser) parsePosition(txid string, position responsePrivatePosition) (Position, error) {
+	positionTime, err := p.parseTimestamp(position.Time)
+	if err != nil {
+		return Position{}, err
+	}
+
+	cost, err := p.parseOptionalDecimal(position.Cost)
+	if err != nil {
+		return Position{}, err
+	}
+
+	fee, err := p.parseOptionalDecimal(position.Fee)
+	if err != nil {
+		return Position{}, err
+	}
+
+	volume, err := p.parseOptionalDecimal(position.Volume)
+	if err != nil {
+		return Position{}, err
+	}
+
+	volumeClosed, err := p.parseOptionalDecimal(position.VolumeClosed)
+	if err != nil {
+		return Position{}, err
+	}
+
+	margin, err := p.parseOptionalDecimal(position.Margin)
+	if err != nil {
+		return Position{}, err
+	}
+
+	value, err := p.parseOptionalDecimal(position.Value)
+	if err != nil {
+		return Position{}, err
+	}
+
+	net, err := p.parseOptionalDecimal(position.Net)
+	if err != nil {
+		return Position{}, err
+	}
+
+	rolloverTime, err := p.parseTimestamp(position.RolloverTime)
+	if err != nil {
+		return Position{}, err
+	}
+
+	return Position{
+		TransactionID:      txid,
+		OrderTransactionID: position.OrderTxID,
+		Status:             position.Status,
+		Pair:               position.Pair,
+		Time:               positionTime,
+		Action:             p.parseOrderAction(position.Type),
+		Type:               p.parseOrderType(position.OrderType),
+		Cost:               cost,
+		Fee:                fee,
+		Volume:             volume,
+		VolumeClosed:       volumeClosed,
+		Margin:             margin,
+		Value:              value,
+		NetPnL:             net,
+		Terms:              position.Terms,
+		RolloverTime:       rolloverTime,
+		Miscellaneous:      position.Misc,
+		Flags:              p.parseList(position.OrderFlags),
+	}, nil
+}
+
+func (p *Parser) parseLedgers(payload []byte, parsed *Ledgers) error {
+	msg := responsePrivateLedgers{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	entries, err := p.parseLedgerEntries(msg.Result.Ledger)
+	if err != nil {
+		return err
+	}
+
+	*parsed = Ledgers{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Entries:  entries,
+		Count:    msg.Result.Count,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseLedgersInfo(payload []byte, parsed *LedgersInfo) error {
+	msg := responsePrivateQueryLedgers{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	entries, err := p.parseLedgerEntries(msg.Result)
+	if err != nil {
+		return err
+	}
+
+	*parsed = LedgersInfo{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Entries:  entries,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseLedgerEntries(v map[string]responsePrivateLedgerEntry) (map[string]LedgerEntry, error) {
+	entries := make(map[string]LedgerEntry, len(v))
+	for id, entry := range v {
+		e, err := p.parseLedgerEntry(id, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[id] = e
+	}
+
+	return entries, nil
+}
+
+func (p *Parser) parseLedgerEntry(id string, entry responsePrivateLedgerEntry) (LedgerEntry, error) {
+	entryTime, err := p.parseTimestamp(entry.Time)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+
+	amount, err := p.parseOptionalDecimal(entry.Amount)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+
+	fee, err := p.parseOptionalDecimal(entry.Fee)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+
+	balance, err := p.parseOptionalDecimal(entry.Balance)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+
+	return LedgerEntry{
+		ID:          id,
+		ReferenceID: entry.RefID,
+		Time:        entryTime,
+		Type:        LedgerType(entry.Type),
+		SubType:     entry.SubType,
+		AssetClass:  entry.AssetClass,
+		Asset:       entry.Asset,
+		Amount:      amount,
+		Fee:         fee,
+		Balance:     balance,
+	}, nil
+}
+
+func (p *Parser) parseTradeVolume(payload []byte, parsed *TradeVolume) error {
+	msg := responsePrivateTradeVolume{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	volume, err := p.parseOptionalDecimal(msg.Result.Volume)
+	if err != nil {
+		return err
+	}
+
+	feesTaker, err := p.parseTradeVolumeFees(msg.Result.Fees)
+	if err != nil {
+		return err
+	}
+
+	feesMaker, err := p.parseTradeVolumeFees(msg.Result.FeesMaker)
+	if err != nil {
+		return err
+	}
+
+	*parsed = TradeVolume{
+		Errors:    p.parseErrors(msg.Errors),
+		Warnings:  p.parseWarnings(msg.Errors),
+		Currency:  msg.Result.Currency,
+		Volume:    volume,
+		FeesTaker: feesTaker,
+		FeesMaker: feesMaker,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseTradeVolumeFees(v map[string]responsePrivateTradeVolumeFee) (map[string]TradeVolumeFee, error) {
+	fees := make(map[string]TradeVolumeFee, len(v))
+	for pair, fee := range v {
+		current, err := p.parseFee(fee.TierVolume, fee.Fee)
+		if err != nil {
+			return nil, err
+		}
+
+		minimum, err := decimal.NewFromString(fee.MinFee)
+		if err != nil {
+			return nil, fmt.Errorf("%w:%s", ErrParse, err)
+		}
+
+		maximum, err := decimal.NewFromString(fee.MaxFee)
+		if err != nil {
+			return nil, fmt.Errorf("%w:%s", ErrParse, err)
+		}
+
+		f := TradeVolumeFee{
+			Current: current,
+			Minimum: minimum,
+			Maximum: maximum,
+		}
+
+		if fee.NextFee != nil && fee.NextVolume != nil {
+			next, err := p.parseFee(*fee.NextVolume, *fee.NextFee)
+			if err != nil {
+				return nil, err
+			}
+
+			f.Next = &next
+		}
+
+		fees[pair] = f
+	}
+
+	return fees, nil
+}
+
+// parseFee parse a fee tier from string values of the tier volume and the
+// percentage fee
+func (p *Parser) parseFee(volume, percentage string) (Fee, error) {
+	v, err := strconv.ParseFloat(volume, 64)
+	if err != nil {
+		return Fee{}, fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	pc, err := decimal.NewFromString(percentage)
+	if err != nil {
+		return Fee{}, fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	return Fee{
+		Volume:     int(v),
+		Percentage: pc,
+	}, nil
+}
+
+func (p *Parser) parseAddOrderResult(payload []byte, parsed *AddOrderResult) error {
+	msg := responsePrivateAddOrder{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	*parsed = AddOrderResult{
+		Errors:           p.parseErrors(msg.Errors),
+		Warnings:         p.parseWarnings(msg.Errors),
+		Description:      msg.Result.Description.Order,
+		CloseDescription: msg.Result.Description.Close,
+		TransactionIDs:   msg.Result.TxIDs,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseEditOrderResult(payload []byte, parsed *EditOrderResult) error {
+	msg := responsePrivateEditOrder{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	volume, err := p.parseOptionalDecimal(msg.Result.Volume)
+	if err != nil {
+		return err
+	}
+
+	price, err := p.parseOptionalDecimal(msg.Result.Price)
+	if err != nil {
+		return err
+	}
+
+	secondaryPrice, err := p.parseOptionalDecimal(msg.Result.Price2)
+	if err != nil {
+		return err
+	}
+
+	replaced := msg.Result.Status == "ok" &&
+		msg.Result.TxID != "" &&
+		msg.Result.TxID != msg.Result.OriginalTxID
+
+	*parsed = EditOrderResult{
+		Errors:                p.parseErrors(msg.Errors),
+		Warnings:              p.parseWarnings(msg.Errors),
+		Status:                msg.Result.Status,
+		ErrorMessage:          msg.Result.ErrorMessage,
+		TransactionID:         msg.Result.TxID,
+		OriginalTransactionID: msg.Result.OriginalTxID,
+		OriginalDeprecated:    replaced,
+		Volume:                volume,
+		Price:                 price,
+		SecondaryPrice:        secondaryPrice,
+		OrdersCancelled:       msg.Result.OrdersCancelled,
+		Description:           msg.Result.Description.Order,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseCancelResult(payload []byte, parsed *CancelResult) error {
+	msg := responsePrivateCancel{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	*parsed = CancelResult{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Count:    msg.Result.Count,
+		Pending:  msg.Result.Pending,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseCancelAllOrdersAfterResult(payload []byte, parsed *CancelAllOrdersAfterResult) error {
+	msg := responsePrivateCancelAllOrdersAfter{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	currentTime, err := p.parseOptionalTime(msg.Result.CurrentTime)
+	if err != nil {
+		return err
+	}
+
+	triggerTime, err := p.parseOptionalTime(msg.Result.TriggerTime)
+	if err != nil {
+		return err
+	}
+
+	*parsed = CancelAllOrdersAfterResult{
+		Errors:      p.parseErrors(msg.Errors),
+		Warnings:    p.parseWarnings(msg.Errors),
+		CurrentTime: currentTime,
+		TriggerTime: triggerTime,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseDepositMethods(payload []byte, parsed *DepositMethods) error {
+	msg := responsePrivateDepositMethods{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	methods := make([]DepositMethod, len(msg.Result))
+	for i, method := range msg.Result {
+		m, err := p.parseDepositMethod(method)
+		if err != nil {
+			return err
+		}
+
+		methods[i] = m
+	}
+
+	*parsed = DepositMethods{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Methods:  methods,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseDepositMethod(method responsePrivateDepositMethod) (DepositMethod, error) {
+	fee, err := p.parseOptionalDecimal(method.Fee)
+	if err != nil {
+		return DepositMethod{}, err
+	}
+
+	addressSetupFee, err := p.parseOptionalDecimal(method.AddressSetupFee)
+	if err != nil {
+		return DepositMethod{}, err
+	}
+
+	minimum, err := p.parseOptionalDecimal(method.Minimum)
+	if err != nil {
+		return DepositMethod{}, err
+	}
+
+	m := DepositMethod{
+		Name:            method.Method,
+		Fee:             fee,
+		AddressSetupFee: addressSetupFee,
+		GenerateAddress: method.GenAddress,
+		Minimum:         minimum,
+	}
+
+	// the limit is false when there is no limit, otherwise the limit amount
+	switch limit := method.Limit.(type) {
+	case string:
+		l, err := decimal.NewFromString(limit)
+		if err != nil {
+			return DepositMethod{}, fmt.Errorf("%w:%s", ErrParse, err)
+		}
+
+		m.Limit = &l
+	case float64:
+		l := decimal.NewFromFloat(limit)
+		m.Limit = &l
+	}
+
+	return m, nil
+}
+
+func (p *Parser) parseDepositAddresses(payload []byte, parsed *DepositAddresses) error {
+	msg := responsePrivateDepositAddresses{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	*parsed = Time{
+	addresses := make([]DepositAddress, len(msg.Result))
+	for i, address := range msg.Result {
+		expireTime, err := p.parseTimestamp(address.ExpireTime)
+		if err != nil {
+			return err
+		}
+
+		addresses[i] = DepositAddress{
+			Address:    address.Address,
+			ExpireTime: expireTime,
+			New:        address.New,
+			Tag:        address.Tag,
+		}
+	}
+
+	*parsed = DepositAddresses{
 		Errors:    p.parseErrors(msg.Errors),
-		Timestamp: time.Unix(msg.Result.UnixTimestamp, 0),
+		Warnings:  p.parseWarnings(msg.Errors),
+		Addresses: addresses,
 	}
 
 	return nil
 }
 
-func (p *Parser) parseSystemStatus(payload []byte, parsed *SystemStatus) error {
-	msg := responseSystemStatus{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseDepositStatus(payload []byte, parsed *DepositStatus) error {
+	msg := responsePrivateFundingStatus{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	t, err := time.Parse(time.RFC3339, msg.Result.Timestamp)
+	deposits, err := p.parseFundingTransactions(msg.Result)
 	if err != nil {
-		return fmt.Errorf("%w:%s", ErrParse, err)
+		return err
 	}
 
-	*parsed = SystemStatus{
-		Errors:    p.parseErrors(msg.Errors),
-		Status:    msg.Result.Status,
-		Timestamp: t.UTC(),
+	*parsed = DepositStatus{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Deposits: deposits,
 	}
 
 	return nil
 }
 
-func (p *Parser) parseAssets(payload []byte, parsed *Assets) error {
-	msg := responsePublicAssets{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseWithdrawInfo(payload []byte, parsed *WithdrawInfo) error {
+	msg := responsePrivateWithdrawInfo{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	assets := make(map[string]Asset)
-	for name, asset := range msg.Result {
-		assets[name] = Asset{
-			Name:             name,
-			Class:            asset.Class,
-			AltName:          asset.AltName,
-			Precision:        asset.Decimals,
-			DisplayPrecision: asset.DisplayDecimals,
-		}
+	limit, err := p.parseOptionalDecimal(msg.Result.Limit)
+	if err != nil {
+		return err
 	}
 
-	*parsed = Assets{
-		Errors: p.parseErrors(msg.Errors),
-		Assets: assets,
+	amount, err := p.parseOptionalDecimal(msg.Result.Amount)
+	if err != nil {
+		return err
+	}
+
+	fee, err := p.parseOptionalDecimal(msg.Result.Fee)
+	if err != nil {
+		return err
+	}
+
+	*parsed = WithdrawInfo{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Method:   msg.Result.Method,
+		Limit:    limit,
+		Amount:   amount,
+		Fee:      fee,
 	}
 
 	return nil
 }
 
-func (p *Parser) parseAssetPairs(payload []byte, parsed *AssetPairs) error {
-	msg := responsePublicAssetPairs{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseWithdrawResult(payload []byte, parsed *WithdrawResult) error {
+	msg := responsePrivateWithdraw{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	pairs := make(map[string]AssetPair)
-	for name, pair := range msg.Result {
-		pairs[name] = AssetPair{
-			AltName:           pair.AltName,
-			WebSocketName:     pair.WSName,
-			AssetClassBase:    pair.AClassBase,
-			Base:              pair.Base,
-			AssetClassQuote:   pair.AClassQuote,
-			Quote:             pair.Quote,
-			Lot:               pair.Lot,
-			PairPrecision:     pair.PairDecimals,
-			LotPrecision:      pair.LotDecimals,
-			LotMultiplier:     pair.LotMultiplier,
-			LeverageBuy:       pair.LeverageBuy,
-			LeverageSell:      pair.LeverageSell,
-			FeesTaker:         p.parseFees(pair.Fees),
-			FeesMaker:         p.parseFees(pair.FeesMaker),
-			FeeVolumeCurrency: pair.FeeVolumeCurrency,
-			MarginCalls:       pair.MarginCalls,
-			MarginStop:        pair.MarginStop,
-			OrderMin:          pair.OrderMin,
-		}
+	*parsed = WithdrawResult{
+		Errors:      p.parseErrors(msg.Errors),
+		Warnings:    p.parseWarnings(msg.Errors),
+		ReferenceID: msg.Result.RefID,
 	}
 
-	*parsed = AssetPairs{
-		Errors: p.parseErrors(msg.Errors),
-		Pairs:  pairs,
+	return nil
+}
+
+func (p *Parser) parseWithdrawStatus(payload []byte, parsed *WithdrawStatus) error {
+	msg := responsePrivateFundingStatus{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	withdrawals, err := p.parseFundingTransactions(msg.Result)
+	if err != nil {
+		return err
+	}
+
+	*parsed = WithdrawStatus{
+		Errors:      p.parseErrors(msg.Errors),
+		Warnings:    p.parseWarnings(msg.Errors),
+		Withdrawals: withdrawals,
 	}
 
 	return nil
 }
 
-func (p *Parser) parseFees(fees [][]float32) []Fee {
-	f := make([]Fee, len(fees))
-	for i, fee := range fees {
-		f[i] = Fee{
-			Volume:     int(fee[0]),
-			Percentage: fee[1],
-		}
+func (p *Parser) parseWithdrawCancelResult(payload []byte, parsed *WithdrawCancelResult) error {
+	msg := responsePrivateWithdrawCancel{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	*parsed = WithdrawCancelResult{
+		Errors:    p.parseErrors(msg.Errors),
+		Warnings:  p.parseWarnings(msg.Errors),
+		Cancelled: msg.Result,
+	}
+
+	return nil
+}
+
+func (p *Parser) parseWalletTransferResult(payload []byte, parsed *WalletTransferResult) error {
+	msg := responsePrivateWalletTransfer{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	*parsed = WalletTransferResult{
+		Errors:      p.parseErrors(msg.Errors),
+		Warnings:    p.parseWarnings(msg.Errors),
+		ReferenceID: msg.Result.RefID,
 	}
 
-	return f
+	return nil
 }
 
-func (p *Parser) parseTickers(payload []byte, parsed *Tickers) error {
-	msg := responsePublicTicker{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseEarnStrategies(payload []byte, parsed *EarnStrategies) error {
+	msg := responsePrivateEarnStrategies{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	tickers := make(map[string]Ticker, len(msg.Result))
-	for pair, ticker := range msg.Result {
-		t, err := p.parseTicker(pair, ticker)
+	strategies := make([]EarnStrategy, len(msg.Result.Items))
+	for i, item := range msg.Result.Items {
+		strategy, err := p.parseEarnStrategy(item)
 		if err != nil {
 			return err
 		}
 
-		tickers[pair] = t
+		strategies[i] = strategy
 	}
 
-	*parsed = Tickers{
-		Errors: p.parseErrors(msg.Errors),
-		Result: tickers,
+	*parsed = EarnStrategies{
+		Errors:     p.parseErrors(msg.Errors),
+		Warnings:   p.parseWarnings(msg.Errors),
+		Strategies: strategies,
+		NextCursor: msg.Result.NextCursor,
 	}
 
 	return nil
 }
 
-func (p *Parser) parseTicker(pair string, ticker responsePublicTickerInformation) (Ticker, error) {
-	ask, err := p.parseAskBid(ticker.Ask[0], ticker.Ask[2], nil)
+func (p *Parser) parseEarnStrategy(strategy responsePrivateEarnStrategy) (EarnStrategy, error) {
+	aprLow, err := p.parseOptionalDecimal(strategy.APREstimate.Low)
 	if err != nil {
-		return Ticker{}, err
+		return EarnStrategy{}, err
 	}
 
-	bid, err := p.parseAskBid(ticker.Bid[0], ticker.Bid[2], nil)
+	aprHigh, err := p.parseOptionalDecimal(strategy.APREstimate.High)
 	if err != nil {
-		return Ticker{}, err
+		return EarnStrategy{}, err
 	}
 
-	close, err := p.parseClose(ticker.LastClose[0], ticker.LastClose[1])
+	minimum, err := p.parseOptionalDecimal(strategy.UserMinAllocation)
 	if err != nil {
-		return Ticker{}, err
+		return EarnStrategy{}, err
 	}
 
-	volumeToday, err := decimal.NewFromString(ticker.Volume[0])
+	allocationFee, err := p.parseOptionalDecimal(strategy.AllocationFee.String())
 	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return EarnStrategy{}, err
 	}
 
-	volumeLast24Hours, err := decimal.NewFromString(ticker.Volume[1])
+	deallocationFee, err := p.parseOptionalDecimal(strategy.DeallocationFee.String())
 	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return EarnStrategy{}, err
 	}
 
-	volumeWeightedAveragePriceToday, err := decimal.NewFromString(ticker.VolumeWeightedAveragePrice[0])
-	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
-	}
+	return EarnStrategy{
+		ID:                strategy.ID,
+		Asset:             strategy.Asset,
+		LockType:          EarnLockType(strategy.LockType.Type),
+		APRLow:            aprLow,
+		APRHigh:           aprHigh,
+		BondingPeriod:     time.Duration(strategy.LockType.BondingPeriod) * time.Second,
+		UnbondingPeriod:   time.Duration(strategy.LockType.UnbondingPeriod) * time.Second,
+		MinimumAllocation: minimum,
+		AllocationFee:     allocationFee,
+		DeallocationFee:   deallocationFee,
+		CanAllocate:       strategy.CanAllocate,
+		CanDeallocate:     strategy.CanDeallocate,
+	}, nil
+}
 
-	volumeWeightedAveragePriceLast24Hours, err := decimal.NewFromString(ticker.VolumeWeightedAveragePrice[1])
-	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+func (p *Parser) parseEarnAllocationResult(payload []byte, parsed *EarnAllocationResult) error {
+	msg := responsePrivateEarnAllocation{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	lowToday, err := decimal.NewFromString(ticker.Low[0])
-	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+	*parsed = EarnAllocationResult{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Accepted: msg.Result,
 	}
 
-	lowLast24Hours, err := decimal.NewFromString(ticker.Low[1])
-	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+	return nil
+}
+
+func (p *Parser) parseEarnAllocationStatus(payload []byte, parsed *EarnAllocationStatus) error {
+	msg := responsePrivateEarnAllocationStatus{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	highToday, err := decimal.NewFromString(ticker.High[0])
-	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+	*parsed = EarnAllocationStatus{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Pending:  msg.Result.Pending,
 	}
 
-	highLast24Hours, err := decimal.NewFromString(ticker.High[1])
-	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+	return nil
+}
+
+func (p *Parser) parseEarnAllocations(payload []byte, parsed *EarnAllocations) error {
+	msg := responsePrivateEarnAllocations{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	open, err := decimal.NewFromString(ticker.Open)
+	totalAllocated, err := p.parseOptionalDecimal(msg.Result.TotalAllocated)
 	if err != nil {
-		return Ticker{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return err
 	}
 
-	return Ticker{
-		Pair:                                  pair,
-		Ask:                                   ask,
-		Bid:                                   bid,
-		LastClose:                             close,
-		VolumeToday:                           volumeToday,
-		VolumeLast24Hours:                     volumeLast24Hours,
-		VolumeWeightedAveragePriceToday:       volumeWeightedAveragePriceToday,
-		VolumeWeightedAveragePriceLast24Hours: volumeWeightedAveragePriceLast24Hours,
-		NumberOfTradesToday:                   ticker.NumberOfTrades[0],
-		NumberOfTradesLast24Hours:             ticker.NumberOfTrades[1],
-		LowToday:                              lowToday,
-		LowLast24Hours:                        lowLast24Hours,
-		HighToday:                             highToday,
-		HighLast24Hours:                       highLast24Hours,
-		Open:                                  open,
-	}, nil
-}
-
-func (p *Parser) parseAskBid(price, volume string, timestamp *int64) (AskBid, error) {
-	priceDecimal, err := decimal.NewFromString(price)
+	totalRewarded, err := p.parseOptionalDecimal(msg.Result.TotalRewarded)
 	if err != nil {
-		return AskBid{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return err
 	}
 
-	volumeDecimal, err := decimal.NewFromString(volume)
-	if err != nil {
-		return AskBid{}, fmt.Errorf("%w:%s", ErrParse, err)
+	allocations := make([]EarnAllocation, len(msg.Result.Items))
+	for i, item := range msg.Result.Items {
+		allocation, err := p.parseEarnAllocation(item)
+		if err != nil {
+			return err
+		}
+
+		allocations[i] = allocation
 	}
 
-	if timestamp == nil {
-		return AskBid{
-			Price:  priceDecimal,
-			Volume: volumeDecimal,
-		}, nil
+	*parsed = EarnAllocations{
+		Errors:         p.parseErrors(msg.Errors),
+		Warnings:       p.parseWarnings(msg.Errors),
+		ConvertedAsset: msg.Result.ConvertedAsset,
+		TotalAllocated: totalAllocated,
+		TotalRewarded:  totalRewarded,
+		Allocations:    allocations,
 	}
 
-	return AskBid{
-		Price:     priceDecimal,
-		Volume:    volumeDecimal,
-		Timestamp: time.Unix(*timestamp, 0).UTC(),
-	}, nil
+	return nil
 }
 
-func (p *Parser) parseClose(price, volume string) (Close, error) {
-	priceDecimal, err := decimal.NewFromString(price)
+func (p *Parser) parseEarnAllocation(item responsePrivateEarnAllocationItem) (EarnAllocation, error) {
+	allocated, err := p.parseOptionalDecimal(item.AmountAllocated.Total.Native)
 	if err != nil {
-		return Close{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return EarnAllocation{}, err
+	}
+
+	pending := decimal.Decimal{}
+	if item.AmountAllocated.Pending != nil {
+		pending, err = p.parseOptionalDecimal(item.AmountAllocated.Pending.Native)
+		if err != nil {
+			return EarnAllocation{}, err
+		}
 	}
 
-	volumeDecimal, err := decimal.NewFromString(volume)
+	rewarded, err := p.parseOptionalDecimal(item.TotalRewarded.Native)
 	if err != nil {
-		return Close{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return EarnAllocation{}, err
 	}
 
-	return Close{
-		Price:  priceDecimal,
-		Volume: volumeDecimal,
+	return EarnAllocation{
+		StrategyID: item.StrategyID,
+		Asset:      item.NativeAsset,
+		Allocated:  allocated,
+		Pending:    pending,
+		Rewarded:   rewarded,
 	}, nil
 }
 
-func (p *Parser) parseOHLCs(payload []byte, parsed *OHLCs) error {
-	msg := responsePublicOHLC{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseAddExportResult(payload []byte, parsed *AddExportResult) error {
+	msg := responsePrivateAddExport{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	ohlcs := make(map[string][]OHLC)
+	*parsed = AddExportResult{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		ID:       msg.Result.ID,
+	}
 
-	for k, v := range msg.Result {
-		if k == "last" {
-			last := new(big.Rat)
-			last.SetFloat64(v.(float64))
-			parsed.LastID = last.Num().Uint64()
-			continue
-		}
+	return nil
+}
 
-		pairOHLCs := []OHLC{}
-		for _, ohlcValue := range v.([]interface{}) {
-			ohlc, err := p.parseOHLC(ohlcValue.([]interface{}))
-			if err != nil {
-				return err
-			}
+func (p *Parser) parseExportStatus(payload []byte, parsed *ExportStatus) error {
+	msg := responsePrivateExportStatus{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
 
-			pairOHLCs = append(pairOHLCs, ohlc)
+	exports := make([]Export, len(msg.Result))
+	for i, export := range msg.Result {
+		e, err := p.parseExport(export)
+		if err != nil {
+			return err
 		}
 
-		ohlcs[k] = pairOHLCs
+		exports[i] = e
 	}
 
-	parsed.Result = ohlcs
+	*parsed = ExportStatus{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Exports:  exports,
+	}
 
 	return nil
 }
 
-func (p *Parser) parseOHLC(v []interface{}) (OHLC, error) {
-	open, err := decimal.NewFromString(v[1].(string))
+func (p *Parser) parseExport(export responsePrivateExport) (Export, error) {
+	createdTime, err := p.parseTimestamp(export.CreatedTime)
 	if err != nil {
-		return OHLC{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return Export{}, err
 	}
 
-	high, err := decimal.NewFromString(v[2].(string))
+	expireTime, err := p.parseTimestamp(export.ExpireTime)
 	if err != nil {
-		return OHLC{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return Export{}, err
 	}
 
-	low, err := decimal.NewFromString(v[3].(string))
+	startTime, err := p.parseTimestamp(export.StartTime)
 	if err != nil {
-		return OHLC{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return Export{}, err
 	}
 
-	close, err := decimal.NewFromString(v[4].(string))
+	completedTime, err := p.parseTimestamp(export.CompletedTime)
 	if err != nil {
-		return OHLC{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return Export{}, err
 	}
 
-	volumeWeightedAveragePrice, err := decimal.NewFromString(v[5].(string))
+	dataStartTime, err := p.parseTimestamp(export.DataStartTime)
 	if err != nil {
-		return OHLC{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return Export{}, err
 	}
 
-	volume, err := decimal.NewFromString(v[6].(string))
+	dataEndTime, err := p.parseTimestamp(export.DataEndTime)
 	if err != nil {
-		return OHLC{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return Export{}, err
 	}
 
-	return OHLC{
-		Time:                       time.Unix((&big.Rat{}).SetFloat64(v[0].(float64)).Num().Int64(), 0).UTC(),
-		Open:                       open,
-		High:                       high,
-		Low:                        low,
-		Close:                      close,
-		VolumeWeightedAveragePrice: volumeWeightedAveragePrice,
-		Volume:                     volume,
-		Count:                      (&big.Rat{}).SetFloat64(v[7].(float64)).Num().Uint64(),
+	fields := p.parseList(export.Fields)
+	if export.Fields == "all" {
+		fields = nil
+	}
+
+	return Export{
+		ID:            export.ID,
+		Description:   export.Description,
+		Format:        ExportFormat(export.Format),
+		Report:        ExportReport(export.Report),
+		Status:        export.Status,
+		Fields:        fields,
+		CreatedTime:   createdTime,
+		ExpireTime:    expireTime,
+		StartTime:     startTime,
+		CompletedTime: completedTime,
+		DataStartTime: dataStartTime,
+		DataEndTime:   dataEndTime,
+		AssetClass:    export.AssetClass,
+		Asset:         export.Asset,
 	}, nil
 }
 
-func (p *Parser) parseOrderBook(payload []byte, parsed *OrderBook) error {
-	msg := responsePublicOrderBook{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseRemoveExportResult(payload []byte, parsed *RemoveExportResult) error {
+	msg := responsePrivateRemoveExport{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	pairAsks := make(map[string][]AskBid)
-	pairBids := make(map[string][]AskBid)
+	*parsed = RemoveExportResult{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Removed:  msg.Result.Delete || msg.Result.Cancel,
+	}
 
-	for pair, askbids := range msg.Result {
-		asks := []AskBid{}
-		for _, ask := range askbids.Asks {
-			price := decimal.NewFromFloat(ask[0].(float64))
-			volume := decimal.NewFromFloat(ask[1].(float64))
-			timestamp := decimal.NewFromFloat(ask[2].(float64)).IntPart()
-
-			a := AskBid{
-				Price:     price,
-				Volume:    volume,
-				Timestamp: time.Unix(timestamp, 0),
-			}
+	return nil
+}
 
-			asks = append(asks, a)
-		}
-		pairAsks[pair] = asks
+func (p *Parser) parseCreateSubaccountResult(payload []byte, parsed *CreateSubaccountResult) error {
+	msg := responsePrivateCreateSubaccount{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
 
-		bids := []AskBid{}
-		for _, bid := range askbids.Bids {
-			price := decimal.NewFromFloat(bid[0].(float64))
-			volume := decimal.NewFromFloat(bid[1].(float64))
-			timestamp := decimal.NewFromFloat(bid[2].(float64)).IntPart()
-
-			b := AskBid{
-				Price:     price,
-				Volume:    volume,
-				Timestamp: time.Unix(timestamp, 0),
-			}
+	*parsed = CreateSubaccountResult{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Created:  msg.Result,
+	}
 
-			bids = append(bids, b)
-		}
-		pairBids[pair] = bids
+	return nil
+}
+
+func (p *Parser) parseAccountTransferResult(payload []byte, parsed *AccountTransferResult) error {
+	msg := responsePrivateAccountTransfer{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	*parsed = OrderBook{
-		Errors: p.parseErrors(msg.Error),
-		Asks:   pairAsks,
-		Bids:   pairBids,
+	*parsed = AccountTransferResult{
+		Errors:     p.parseErrors(msg.Errors),
+		Warnings:   p.parseWarnings(msg.Errors),
+		TransferID: msg.Result.TransferID,
+		Status:     msg.Result.Status,
 	}
 
 	return nil
 }
 
-func (p *Parser) parseRecentTrades(payload []byte, parsed *RecentTrades) error {
-	msg := responsePublicRecentTrades{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
+func (p *Parser) parseWebSocketsToken(payload []byte, parsed *WebSocketsToken) error {
+	msg := responsePrivateGetWebSocketsToken{}
+	if err := p.unmarshal(payload, &msg); err != nil {
 		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	trades := make(map[string][]RecentTrade)
+	*parsed = WebSocketsToken{
+		Errors:   p.parseErrors(msg.Errors),
+		Warnings: p.parseWarnings(msg.Errors),
+		Token:    msg.Result.Token,
+		Expires:  time.Duration(msg.Result.Expires) * time.Second,
+	}
 
-	for k, v := range msg.Result {
-		if k == "last" {
-			lastID, err := strconv.ParseUint(v.(string), 10, 64)
-			if err != nil {
-				return fmt.Errorf("%w:%s", ErrParse, err)
-			}
+	return nil
+}
 
-			parsed.LastID = lastID
-			continue
+func (p *Parser) parseFundingTransactions(v []responsePrivateFundingTransaction) ([]FundingTransaction, error) {
+	transactions := make([]FundingTransaction, len(v))
+	for i, transaction := range v {
+		amount, err := p.parseOptionalDecimal(transaction.Amount)
+		if err != nil {
+			return nil, err
 		}
 
-		pairTrades := []RecentTrade{}
-		for _, tradeValue := range v.([]interface{}) {
-			trade, err := p.parseRecentTrade(tradeValue.([]interface{}))
-			if err != nil {
-				return err
-			}
+		fee, err := p.parseOptionalDecimal(transaction.Fee)
+		if err != nil {
+			return nil, err
+		}
 
-			pairTrades = append(pairTrades, trade)
+		transactionTime, err := p.parseTimestamp(transaction.Time)
+		if err != nil {
+			return nil, err
 		}
 
-		trades[k] = pairTrades
+		transactions[i] = FundingTransaction{
+			Method:         transaction.Method,
+			AssetClass:     transaction.AssetClass,
+			Asset:          transaction.Asset,
+			ReferenceID:    transaction.RefID,
+			TransactionID:  transaction.TxID,
+			Info:           transaction.Info,
+			Amount:         amount,
+			Fee:            fee,
+			Time:           transactionTime,
+			Status:         transaction.Status,
+			StatusProperty: transaction.StatusProp,
+		}
 	}
 
-	parsed.Trades = trades
-	parsed.Errors = p.parseErrors(msg.Error)
-
-	return nil
+	return transactions, nil
 }
 
-func (p *Parser) parseRecentTrade(v []interface{}) (RecentTrade, error) {
-	price, err := decimal.NewFromString(v[0].(string))
-	if err != nil {
-		return RecentTrade{}, fmt.Errorf("%w:%s", ErrParse, err)
+// parseOptionalTime parse an RFC3339 time to UTC, an omitted or "0" time is
+// parsed to a zero time
+func (p *Parser) parseOptionalTime(v string) (time.Time, error) {
+	if v == "" || v == "0" {
+		return time.Time{}, nil
 	}
 
-	volume, err := decimal.NewFromString(v[1].(string))
+	t, err := time.Parse(time.RFC3339, v)
 	if err != nil {
-		return RecentTrade{}, fmt.Errorf("%w:%s", ErrParse, err)
+		return time.Time{}, fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	orderTime := decimal.NewFromFloat(v[2].(float64))
-	if err != nil {
-		return RecentTrade{}, fmt.Errorf("%w:%s", ErrParse, err)
+	return t.UTC(), nil
+}
+
+func (p *Parser) parseOrderStatus(v string) OrderStatus {
+	switch v {
+	case "pending":
+		return OrderStatusPending
+	case "open":
+		return OrderStatusOpen
+	case "closed":
+		return OrderStatusClosed
+	case "canceled":
+		return OrderStatusCanceled
+	case "expired":
+		return OrderStatusExpired
+	default:
+		return OrderStatusUnknown
+	}
+}
+
+// parseTimestamp parse a unix timestamp with optional fractional seconds to a
+// UTC time, an omitted or zero timestamp is parsed to a zero time
+func (p *Parser) parseTimestamp(v json.Number) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
 	}
 
-	orderAction := v[3].(string)
-	orderType := v[4].(string)
-	misc := v[5].(string)
+	// whole second timestamps are the common case for OHLC and spread rows
+	if !strings.ContainsAny(v.String(), ".eE") {
+		if seconds, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			if seconds == 0 {
+				return time.Time{}, nil
+			}
 
-	trade := RecentTrade{
-		Price:  price,
-		Volume: volume,
-		// TODO get microseconds working properly
-		Time:          time.Unix(orderTime.IntPart(), 0),
-		Miscellaneous: misc,
+			return time.Unix(seconds, 0).UTC(), nil
+		}
 	}
 
-	switch orderAction {
-	case "b":
-		trade.Action = OrderActionBuy
-	case "s":
-		trade.Action = OrderActionSell
-	default:
-		trade.Action = OrderActionUnknown
+	d, err := decimal.NewFromString(v.String())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	switch orderType {
-	case "l":
-		trade.Type = OrderTypeLimit
-	case "m":
-		trade.Type = OrderTypeMarket
-	default:
-		trade.Type = OrderTypeUnknown
+	if d.IsZero() {
+		return time.Time{}, nil
 	}
 
-	return trade, nil
+	seconds := d.IntPart()
+	nanoseconds := d.Sub(decimal.New(seconds, 0)).Shift(9).IntPart()
+
+	return time.Unix(seconds, nanoseconds).UTC(), nil
 }
 
-func (p *Parser) parseRecentSpreads(payload []byte, parsed *RecentSpreads) error {
-	msg := responsePublicRecentSpreads{}
-	if err := json.Unmarshal(payload, &msg); err != nil {
-		return fmt.Errorf("%w: %s", ErrParse, err)
+// parseList parse a comma delimited list, an empty string is parsed to a nil
+// slice
+func (p *Parser) parseList(v string) []string {
+	if v == "" {
+		return nil
 	}
 
-	spreads := make(map[string][]Spread)
-	for k, v := range msg.Result {
-		if k == "last" {
-			last := new(big.Rat)
-			last.SetFloat64(v.(float64))
-			parsed.LastID = last.Num().Uint64()
-			continue
-		}
-
-		pairSpreads := []Spread{}
-		for _, spreadValue := range v.([]interface{}) {
-			spread, err := p.parseRecentSpread(spreadValue.([]interface{}))
-			if err != nil {
-				return err
-			}
-
-			pairSpreads = append(pairSpreads, spread)
-		}
+	return strings.Split(v, ",")
+}
 
-		spreads[k] = pairSpreads
+// parseOptionalDecimal parse a decimal value which may be omitted from the
+// payload, an omitted value is parsed to a zero decimal
+func (p *Parser) parseOptionalDecimal(v string) (decimal.Decimal, error) {
+	if v == "" {
+		return decimal.Decimal{}, nil
 	}
 
-	parsed.Spreads = spreads
-	parsed.Errors = p.parseErrors(msg.Error)
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("%w:%s", ErrParse, err)
+	}
 
-	return nil
+	return d, nil
 }
 
-func (p *Parser) parseRecentSpread(v []interface{}) (Spread, error) {
-	timestamp := decimal.NewFromFloat(v[0].(float64)).IntPart()
-
-	bid, err := decimal.NewFromString(v[1].(string))
-	if err != nil {
-		return Spread{}, fmt.Errorf("%w: %s", ErrParse, err)
+// parseErrorPayload parse the errors of a payload which is only expected to be
+// JSON on failure, the first error is returned and ErrAPIUnknown is returned
+// when the payload contains no errors
+func (p *Parser) parseErrorPayload(payload []byte) error {
+	msg := responseErrors{}
+	if err := p.unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
 	}
 
-	ask, err := decimal.NewFromString(v[2].(string))
-	if err != nil {
-		return Spread{}, fmt.Errorf("%w: %s", ErrParse, err)
+	errs := p.parseErrors(msg.Errors)
+	if len(errs) == 0 {
+		return ErrAPIUnknown
 	}
 
-	return Spread{
-		Timestamp: time.Unix(timestamp, 0),
-		Bid:       bid,
-		Ask:       ask,
-	}, nil
+	return errs[0]
+}
+
+// knownErrors sentinel errors for well known API error messages
+var knownErrors = map[string]error{
+	"EAPI:Rate limit exceeded":     ErrRateLimit,
+	"EGeneral:Too many requests":   ErrRateLimit,
+	"EOrder:Rate limit exceeded":   ErrRateLimit,
+	"EAPI:Invalid key":             ErrInvalidKey,
+	"EAPI:Invalid signature":       ErrInvalidSignature,
+	"EAPI:Invalid nonce":           ErrInvalidNonce,
+	"EQuery:Unknown asset pair":    ErrUnknownAssetPair,
+	"EOrder:Insufficient funds":    ErrInsufficientFunds,
+	"EOrder:Order minimum not met": ErrOrderMinimum,
+	"EOrder:Unknown order":         ErrUnknownOrder,
+	"EService:Unavailable":         ErrServiceUnavailable,
+}
+
+// categoryErrors errors for each API error category
+var categoryErrors = map[string]error{
+	"General": ErrGeneral,
+	"API":     ErrAPI,
+	"Query":   ErrQuery,
+	"Order":   ErrOrder,
+	"Trade":   ErrTrade,
+	"Funding": ErrFunding,
+	"Service": ErrService,
+	"Session": ErrSession,
 }
 
 func (p *Parser) parseErrors(errStrings []string) []error {
@@ -576,31 +2803,56 @@ func (p *Parser) parseErrors(errStrings []string) []error {
 		return nil
 	}
 
-	errs := make([]error, len(errStrings))
-	for i, errString := range errStrings {
-		errParts := strings.SplitN(errString, ":", 2)
-
-		switch errParts[0] {
-		case "EGeneral":
-			errs[i] = fmt.Errorf("%w:%s", ErrGeneral, errParts[1])
-		case "EAPI":
-			errs[i] = fmt.Errorf("%w:%s", ErrAPI, errParts[1])
-		case "EQuery":
-			errs[i] = fmt.Errorf("%w:%s", ErrQuery, errParts[1])
-		case "EOrder":
-			errs[i] = fmt.Errorf("%w:%s", ErrOrder, errParts[1])
-		case "ETrade":
-			errs[i] = fmt.Errorf("%w:%s", ErrTrade, errParts[1])
-		case "EFunding":
-			errs[i] = fmt.Errorf("%w:%s", ErrFunding, errParts[1])
-		case "EService":
-			errs[i] = fmt.Errorf("%w:%s", ErrService, errParts[1])
-		case "ESession":
-			errs[i] = fmt.Errorf("%w:%s", ErrSession, errParts[1])
-		default:
-			errs[i] = fmt.Errorf("%w:%s", ErrAPIUnknown, errString)
+	var errs []error
+	for _, errString := range errStrings {
+		if err := p.parseError(errString); err.Severity != ErrorSeverityWarning {
+			errs = append(errs, err)
 		}
 	}
 
 	return errs
 }
+
+// parseWarnings parse the warnings from the API error strings, warnings are
+// returned alongside a valid result and are not included in the errors
+func (p *Parser) parseWarnings(errStrings []string) []KrakenError {
+	var warnings []KrakenError
+	for _, errString := range errStrings {
+		if err := p.parseError(errString); err.Severity == ErrorSeverityWarning {
+			warnings = append(warnings, *err)
+		}
+	}
+
+	return warnings
+}
+
+// parseError parse an API error string into a KrakenError
+func (p *Parser) parseError(errString string) *KrakenError {
+	errParts := strings.SplitN(errString, ":", 2)
+	if len(errParts) == 2 && len(errParts[0]) > 1 {
+		severity := ErrorSeverity(ErrorSeverityUnknown)
+		switch errParts[0][0] {
+		case 'E':
+			severity = ErrorSeverityError
+		case 'W':
+			severity = ErrorSeverityWarning
+		}
+
+		category, ok := categoryErrors[errParts[0][1:]]
+		if ok && severity != ErrorSeverityUnknown {
+			return &KrakenError{
+				Severity: severity,
+				Category: errParts[0][1:],
+				Message:  errParts[1],
+				category: category,
+				known:    knownErrors[errString],
+			}
+		}
+	}
+
+	return &KrakenError{
+		Severity: ErrorSeverityUnknown,
+		Message:  errString,
+		category: ErrAPIUnknown,
+	}
+}
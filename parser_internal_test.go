@@ -0,0 +1,44 @@
+package kraken
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tcs := []string{
+		"0",
+		"38311.6",
+		"0.40716249",
+		"-0.5",
+		"+12",
+		".5",
+		"1.",
+		"000123.4500",
+		"123456789012345678",
+		"1234567890.12345678",
+		"12345678901234567890.123",
+		"-9223372036854775808",
+		"1e-8",
+		"",
+		".",
+		"-",
+		"1.2.3",
+		"abc",
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc, func(t *testing.T) {
+			expected, expectedErr := decimal.NewFromString(tc)
+			actual, err := parseDecimal(tc)
+			if (expectedErr == nil) != (err == nil) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", expectedErr, err)
+			}
+
+			if expected.Coefficient().Cmp(actual.Coefficient()) != 0 || expected.Exponent() != actual.Exponent() {
+				t.Errorf("EXPECTED: %se%d\nACTUAL: %se%d", expected.Coefficient(), expected.Exponent(), actual.Coefficient(), actual.Exponent())
+			}
+		})
+	}
+}
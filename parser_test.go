@@ -2,6 +2,9 @@ package kraken_test
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +32,103 @@ func TestParseNilPointer(t *testing.T) {
 	}
 }
 
+func TestParseAs(t *testing.T) {
+	tcs := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{
+			name:  "ValidPayload",
+			input: []byte(`{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"}}`),
+		},
+		{
+			name:  "ResultlessErrors",
+			input: []byte(`{"error":["EGeneral:Internal error"],"result":[]}`),
+		},
+		{
+			name:  "MalformedPayload",
+			input: []byte(`{"error":`),
+			err:   kraken.ErrParse,
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := kraken.Time{}
+			if err := p.Parse(tc.input, &expected); !errors.Is(err, tc.err) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+
+			msg, err := kraken.ParseAs[kraken.Time](&p, tc.input)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+
+			if diff := deep.Equal(expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	tcs := []struct {
+		name       string
+		input      []byte
+		msg        interface{}
+		err        error
+		defaultErr error
+	}{
+		{
+			name:  "MappedFields",
+			input: []byte(`{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"}}`),
+			msg:   &kraken.Time{},
+		},
+		{
+			name:  "UnknownResponseField",
+			input: []byte(`{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"},"version":2}`),
+			msg:   &kraken.Time{},
+			err:   kraken.ErrParse,
+		},
+		{
+			name:  "UnknownResultField",
+			input: []byte(`{"error":[],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z","region":"eu"}}`),
+			msg:   &kraken.SystemStatus{},
+			err:   kraken.ErrParse,
+		},
+		{
+			name:  "UnknownNestedField",
+			input: []byte(`{"error":[],"result":{"XXBT":{"aclass":"currency","altname":"XBT","decimals":10,"display_decimals":5,"margin_rate":"0.02"}}}`),
+			msg:   &kraken.Assets{},
+			err:   kraken.ErrParse,
+		},
+		{
+			name:       "TrailingData",
+			input:      []byte(`{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"}}{}`),
+			msg:        &kraken.Time{},
+			err:        kraken.ErrParse,
+			defaultErr: kraken.ErrParse,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			strict := kraken.Parser{Strict: true}
+			if err := strict.Parse(tc.input, tc.msg); !errors.Is(err, tc.err) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+
+			// payloads with unmapped fields are still parsed by default
+			p := kraken.Parser{}
+			if err := p.Parse(tc.input, tc.msg); !errors.Is(err, tc.defaultErr) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.defaultErr, err)
+			}
+		})
+	}
+}
+
 func TestParseTime(t *testing.T) {
 	tcs := []struct {
 		name     string
@@ -49,7 +149,7 @@ func TestParseTime(t *testing.T) {
 			`),
 			expected: kraken.Time{
 				Errors:    nil,
-				Timestamp: time.Unix(1643584726, 0),
+				Timestamp: time.Unix(1643584726, 0).UTC(),
 			},
 		},
 	}
@@ -90,7 +190,26 @@ func TestParseSystemStatus(t *testing.T) {
 			expected: kraken.SystemStatus{
 				Errors:    nil,
 				Timestamp: time.Unix(1643589875, 0).UTC(),
-				Status:    "online",
+				Status:    kraken.SystemStatusValueOnline,
+				RawStatus: "online",
+			},
+		},
+		{
+			name: "UnknownStatus",
+			input: []byte(`
+			{
+				"error":[],
+				"result":{
+					"status":"limit_only",
+					"timestamp":"2022-01-31T00:44:35Z"
+				}
+			}
+			`),
+			expected: kraken.SystemStatus{
+				Errors:    nil,
+				Timestamp: time.Unix(1643589875, 0).UTC(),
+				Status:    kraken.SystemStatusValueUnknown,
+				RawStatus: "limit_only",
 			},
 		},
 	}
@@ -303,7 +422,12 @@ func TestParseAssetPairs(t *testing.T) {
 						"fee_volume_currency": "ZUSD",
 						"margin_call": 80,
 						"margin_stop": 40,
-						"ordermin": 0.0001
+						"ordermin": "0.0001",
+						"costmin": "0.5",
+						"tick_size": "0.1",
+						"status": "post_only",
+						"long_position_limit": 270,
+						"short_position_limit": 180
 					}
 				}
 			}
@@ -311,6 +435,7 @@ func TestParseAssetPairs(t *testing.T) {
 			expected: kraken.AssetPairs{
 				Pairs: map[string]kraken.AssetPair{
 					"XXBTZUSD": {
+						Info:            kraken.AssetPairInfoInfo,
 						AltName:         "XBTUSD",
 						WebSocketName:   "XBT/USD",
 						AssetClassBase:  "currency",
@@ -324,36 +449,90 @@ func TestParseAssetPairs(t *testing.T) {
 						LeverageBuy:     []int{2, 3, 4, 5},
 						LeverageSell:    []int{2, 3, 4, 5},
 						FeesTaker: []kraken.Fee{
-							{Volume: 0, Percentage: 0.26},
-							{Volume: 50000, Percentage: 0.24},
-							{Volume: 100000, Percentage: 0.22},
-							{Volume: 250000, Percentage: 0.2},
-							{Volume: 500000, Percentage: 0.18},
-							{Volume: 1000000, Percentage: 0.16},
-							{Volume: 2500000, Percentage: 0.14},
-							{Volume: 5000000, Percentage: 0.12},
-							{Volume: 10000000, Percentage: 0.1},
+							{Volume: 0, Percentage: decimal.New(26, -2)},
+							{Volume: 50000, Percentage: decimal.New(24, -2)},
+							{Volume: 100000, Percentage: decimal.New(22, -2)},
+							{Volume: 250000, Percentage: decimal.New(2, -1)},
+							{Volume: 500000, Percentage: decimal.New(18, -2)},
+							{Volume: 1000000, Percentage: decimal.New(16, -2)},
+							{Volume: 2500000, Percentage: decimal.New(14, -2)},
+							{Volume: 5000000, Percentage: decimal.New(12, -2)},
+							{Volume: 10000000, Percentage: decimal.New(1, -1)},
 						},
 						FeesMaker: []kraken.Fee{
-							{Volume: 0, Percentage: 0.16},
-							{Volume: 50000, Percentage: 0.14},
-							{Volume: 100000, Percentage: 0.12},
-							{Volume: 250000, Percentage: 0.1},
-							{Volume: 500000, Percentage: 0.08},
-							{Volume: 1000000, Percentage: 0.06},
-							{Volume: 2500000, Percentage: 0.04},
-							{Volume: 5000000, Percentage: 0.02},
-							{Volume: 10000000, Percentage: 0},
+							{Volume: 0, Percentage: decimal.New(16, -2)},
+							{Volume: 50000, Percentage: decimal.New(14, -2)},
+							{Volume: 100000, Percentage: decimal.New(12, -2)},
+							{Volume: 250000, Percentage: decimal.New(1, -1)},
+							{Volume: 500000, Percentage: decimal.New(8, -2)},
+							{Volume: 1000000, Percentage: decimal.New(6, -2)},
+							{Volume: 2500000, Percentage: decimal.New(4, -2)},
+							{Volume: 5000000, Percentage: decimal.New(2, -2)},
+							{Volume: 10000000, Percentage: decimal.New(0, 0)},
 						},
-						FeeVolumeCurrency: "ZUSD",
-						MarginCalls:       80,
-						MarginStop:        40,
-						OrderMin:          0.0001,
+						FeeVolumeCurrency:  "ZUSD",
+						MarginCalls:        80,
+						MarginStop:         40,
+						OrderMin:           decimal.New(1, -4),
+						CostMin:            decimal.New(5, -1),
+						TickSize:           decimal.New(1, -1),
+						Status:             kraken.AssetPairStatusPostOnly,
+						LongPositionLimit:  270,
+						ShortPositionLimit: 180,
 					},
 				},
 			},
 			err: nil,
 		},
+		{
+			name:  "LeveragePayload",
+			input: []byte(`{"error":[],"result":{"XXBTZUSD":{"leverage_buy":[2,3,4,5],"leverage_sell":[2,3]}}}`),
+			expected: kraken.AssetPairs{
+				Pairs: map[string]kraken.AssetPair{
+					"XXBTZUSD": {
+						Info:         kraken.AssetPairInfoLeverage,
+						LeverageBuy:  []int{2, 3, 4, 5},
+						LeverageSell: []int{2, 3},
+						Status:       kraken.AssetPairStatusUnknown,
+					},
+				},
+			},
+		},
+		{
+			name:  "FeesPayload",
+			input: []byte(`{"error":[],"result":{"XXBTZUSD":{"fees":[[0,0.26],[50000,0.24]],"fees_maker":[[0,0.16],[50000,0.14]],"fee_volume_currency":"ZUSD"}}}`),
+			expected: kraken.AssetPairs{
+				Pairs: map[string]kraken.AssetPair{
+					"XXBTZUSD": {
+						Info: kraken.AssetPairInfoFees,
+						FeesTaker: []kraken.Fee{
+							{Volume: 0, Percentage: decimal.New(26, -2)},
+							{Volume: 50000, Percentage: decimal.New(24, -2)},
+						},
+						FeesMaker: []kraken.Fee{
+							{Volume: 0, Percentage: decimal.New(16, -2)},
+							{Volume: 50000, Percentage: decimal.New(14, -2)},
+						},
+						FeeVolumeCurrency: "ZUSD",
+						Status:            kraken.AssetPairStatusUnknown,
+					},
+				},
+			},
+		},
+		{
+			name:  "MarginPayload",
+			input: []byte(`{"error":[],"result":{"XXBTZUSD":{"margin_call":80,"margin_level":40}}}`),
+			expected: kraken.AssetPairs{
+				Pairs: map[string]kraken.AssetPair{
+					"XXBTZUSD": {
+						Info:        kraken.AssetPairInfoMargin,
+						MarginCalls: 80,
+						MarginStop:  40,
+						Status:      kraken.AssetPairStatusUnknown,
+					},
+				},
+			},
+		},
 	}
 
 	p := kraken.Parser{}
@@ -387,8 +566,8 @@ func TestParseTicker(t *testing.T) {
 					"XXBTZUSD": {
 						"a": [
 							"38659.6",
-							"1",
-							"1.000"
+							"2",
+							"2.500"
 						],
 						"b": [
 							"38658.7",
@@ -429,12 +608,14 @@ func TestParseTicker(t *testing.T) {
 					"XXBTZUSD": {
 						Pair: "XXBTZUSD",
 						Ask: kraken.AskBid{
-							Price:  decimal.New(386596, -1),
-							Volume: decimal.New(1, 0),
+							Price:          decimal.New(386596, -1),
+							Volume:         decimal.New(25, -1),
+							WholeLotVolume: decimal.New(2, 0),
 						},
 						Bid: kraken.AskBid{
-							Price:  decimal.New(386587, -1),
-							Volume: decimal.New(1, 0),
+							Price:          decimal.New(386587, -1),
+							Volume:         decimal.New(1, 0),
+							WholeLotVolume: decimal.New(1, 0),
 						},
 						LastClose: kraken.Close{
 							Price:  decimal.New(386589, -1),
@@ -519,6 +700,14 @@ func TestParseOHLC(t *testing.T) {
 				LastID: uint64(1643757240),
 			},
 		},
+		{
+			name:  "LargeLastID",
+			input: []byte(`{"error":[],"result":{"XXBTZUSD":[],"last":9007199254740993}}`),
+			expected: kraken.OHLCs{
+				Result: map[string][]kraken.OHLC{"XXBTZUSD": {}},
+				LastID: 9007199254740993,
+			},
+		},
 	}
 
 	p := kraken.Parser{}
@@ -561,7 +750,7 @@ func TestParseOrderBook(t *testing.T) {
 							[
 								37639.3,
 								3.488,
-								1643832845
+								1643832845.123
 							]
 						]
 					}
@@ -574,7 +763,7 @@ func TestParseOrderBook(t *testing.T) {
 						{
 							Price:     decimal.New(376394, -1),
 							Volume:    decimal.New(2, -3),
-							Timestamp: time.Unix(1643832845, 0),
+							Timestamp: time.Unix(1643832845, 0).UTC(),
 						},
 					},
 				},
@@ -583,10 +772,113 @@ func TestParseOrderBook(t *testing.T) {
 						{
 							Price:     decimal.New(376393, -1),
 							Volume:    decimal.New(3488, -3),
-							Timestamp: time.Unix(1643832845, 0),
+							Timestamp: time.Unix(1643832845, 123000000).UTC(),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "StringValues",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"XXBTZUSD": {
+						"asks": [
+							["37639.40000","0.00200000",1643832845]
+						],
+						"bids": [
+							["0.00001234567890123457","123456789.12345678",1643832845.5]
+						]
+					}
+				}
+			}
+			`),
+			expected: kraken.OrderBook{
+				Asks: map[string][]kraken.AskBid{
+					"XXBTZUSD": {
+						{
+							Price:     decimal.New(3763940000, -5),
+							Volume:    decimal.New(200000, -8),
+							Timestamp: time.Unix(1643832845, 0).UTC(),
 						},
 					},
 				},
+				Bids: map[string][]kraken.AskBid{
+					"XXBTZUSD": {
+						{
+							Price:     decimal.New(1234567890123457, -20),
+							Volume:    decimal.New(12345678912345678, -8),
+							Timestamp: time.Unix(1643832845, 500000000).UTC(),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Shuffled",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"XXBTZUSD": {
+						"asks": [
+							["101.0","1",1643832846],
+							["100.5","2",1643832845],
+							["101.0","3",1643832844]
+						],
+						"bids": [
+							["99.0","1",1643832845],
+							["99.5","2",1643832845],
+							["98.0","3",1643832845]
+						]
+					}
+				}
+			}
+			`),
+			expected: kraken.OrderBook{
+				Asks: map[string][]kraken.AskBid{
+					"XXBTZUSD": {
+						{Price: decimal.New(1005, -1), Volume: decimal.New(2, 0), Timestamp: time.Unix(1643832845, 0).UTC()},
+						{Price: decimal.New(1010, -1), Volume: decimal.New(3, 0), Timestamp: time.Unix(1643832844, 0).UTC()},
+						{Price: decimal.New(1010, -1), Volume: decimal.New(1, 0), Timestamp: time.Unix(1643832846, 0).UTC()},
+					},
+				},
+				Bids: map[string][]kraken.AskBid{
+					"XXBTZUSD": {
+						{Price: decimal.New(995, -1), Volume: decimal.New(2, 0), Timestamp: time.Unix(1643832845, 0).UTC()},
+						{Price: decimal.New(990, -1), Volume: decimal.New(1, 0), Timestamp: time.Unix(1643832845, 0).UTC()},
+						{Price: decimal.New(980, -1), Volume: decimal.New(3, 0), Timestamp: time.Unix(1643832845, 0).UTC()},
+					},
+				},
+			},
+		},
+		{
+			name: "Crossed",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"XXBTZUSD": {
+						"asks": [["100.0","1",1643832845]],
+						"bids": [["100.5","1",1643832845]]
+					}
+				}
+			}
+			`),
+			// a crossed book is not an API error, it is left to Validate
+			expected: kraken.OrderBook{
+				Asks: map[string][]kraken.AskBid{
+					"XXBTZUSD": {
+						{Price: decimal.New(1000, -1), Volume: decimal.New(1, 0), Timestamp: time.Unix(1643832845, 0).UTC()},
+					},
+				},
+				Bids: map[string][]kraken.AskBid{
+					"XXBTZUSD": {
+						{Price: decimal.New(1005, -1), Volume: decimal.New(1, 0), Timestamp: time.Unix(1643832845, 0).UTC()},
+					},
+				},
 			},
 		},
 	}
@@ -633,14 +925,14 @@ func TestParseRecentTrades(t *testing.T) {
 						{
 							Price:  decimal.New(42428, 0),
 							Volume: decimal.New(109505, -8),
-							Time:   time.Unix(1644189769, 0).UTC(),
+							Time:   time.Unix(1644189769, 912200000).UTC(),
 							Action: kraken.OrderActionBuy,
 							Type:   kraken.OrderTypeLimit,
 						},
 						{
 							Price:  decimal.New(424365, -1),
 							Volume: decimal.New(98631, -8),
-							Time:   time.Unix(1644189769, 0).UTC(),
+							Time:   time.Unix(1644189769, 913400000).UTC(),
 							Action: kraken.OrderActionBuy,
 							Type:   kraken.OrderTypeLimit,
 						},
@@ -649,6 +941,64 @@ func TestParseRecentTrades(t *testing.T) {
 				LastID: 1644191265969108820,
 			},
 		},
+		{
+			name: "TradeIDs",
+			input: []byte(`
+			{
+				"error":[],
+				"result":{
+					"XXBTZUSD":[
+						["42428.00000","0.00109505",1644189769.9122,"b","l","",76472410],
+						["42436.50000","0.00098631",1644189769.9134,"s","m","",76472411]
+					],
+					"last": "1644191265969108820"
+				}
+			}
+			`),
+			expected: kraken.RecentTrades{
+				Trades: map[string][]kraken.RecentTrade{
+					"XXBTZUSD": {
+						{
+							Price:   decimal.New(42428, 0),
+							Volume:  decimal.New(109505, -8),
+							Time:    time.Unix(1644189769, 912200000).UTC(),
+							Action:  kraken.OrderActionBuy,
+							Type:    kraken.OrderTypeLimit,
+							TradeID: 76472410,
+						},
+						{
+							Price:   decimal.New(424365, -1),
+							Volume:  decimal.New(98631, -8),
+							Time:    time.Unix(1644189769, 913400000).UTC(),
+							Action:  kraken.OrderActionSell,
+							Type:    kraken.OrderTypeMarket,
+							TradeID: 76472411,
+						},
+					},
+				},
+				LastID: 1644191265969108820,
+			},
+		},
+		{
+			name:  "TrailingValues",
+			input: []byte(`{"error":[],"result":{"XXBTZUSD":[["42428.00000","0.00109505",1644189769,"s","m","a\",b",76472410,[1,"]"]]],"last":"1644191265969108820"}}`),
+			expected: kraken.RecentTrades{
+				Trades: map[string][]kraken.RecentTrade{
+					"XXBTZUSD": {
+						{
+							Price:         decimal.New(42428, 0),
+							Volume:        decimal.New(109505, -8),
+							Time:          time.Unix(1644189769, 0).UTC(),
+							Action:        kraken.OrderActionSell,
+							Type:          kraken.OrderTypeMarket,
+							Miscellaneous: `a",b`,
+							TradeID:       76472410,
+						},
+					},
+				},
+				LastID: 1644191265969108820,
+			},
+		},
 	}
 
 	p := kraken.Parser{}
@@ -690,7 +1040,7 @@ func TestParseRecentSpread(t *testing.T) {
 				Spreads: map[string][]kraken.Spread{
 					"XXBTZUSD": {
 						{
-							Timestamp: time.Unix(1644356229, 0),
+							Timestamp: time.Unix(1644356229, 0).UTC(),
 							Bid:       decimal.New(442233, -1),
 							Ask:       decimal.New(442251, -1),
 						},
@@ -699,6 +1049,14 @@ func TestParseRecentSpread(t *testing.T) {
 				LastID: 1644356424,
 			},
 		},
+		{
+			name:  "LargeLastID",
+			input: []byte(`{"error":[],"result":{"XXBTZUSD":[],"last":18446744073709551615}}`),
+			expected: kraken.RecentSpreads{
+				Spreads: map[string][]kraken.Spread{"XXBTZUSD": {}},
+				LastID:  18446744073709551615,
+			},
+		},
 	}
 
 	p := kraken.Parser{}
@@ -716,42 +1074,407 @@ func TestParseRecentSpread(t *testing.T) {
 	}
 }
 
-func TestParseErrors(t *testing.T) {
-	input := []byte(`
-	{
-		"error":[
-			"EGeneral:test error",
-			"EAPI:test error",
-			"EQuery:test error",
-			"EOrder:test error",
-			"ETrade:test error",
-			"EFunding:test error",
-			"EService:test error",
-			"ESession:test error",
-			"unknown test error"
-		],
-		"result":{
-			"unixtime":1644358183,
-			"rfc1123":"Tue,  8 Feb 22 22:09:43 +0000"
-		}
+func TestParseTimesUTC(t *testing.T) {
+	local, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
 	}
-	`)
 
-	output := []error{
-		kraken.ErrGeneral,
-		kraken.ErrAPI,
-		kraken.ErrQuery,
-		kraken.ErrOrder,
-		kraken.ErrTrade,
-		kraken.ErrFunding,
-		kraken.ErrService,
-		kraken.ErrSession,
-		kraken.ErrAPIUnknown,
+	// equivalent to running with TZ=America/New_York
+	defer func(l *time.Location) { time.Local = l }(time.Local)
+	time.Local = local
+
+	tcs := []struct {
+		name  string
+		input string
+		msg   interface{}
+	}{
+		{
+			name:  "Time",
+			input: `{"error":[],"result":{"unixtime":1643584726,"rfc1123":"Sun, 30 Jan 22 23:18:46 +0000"}}`,
+			msg:   &kraken.Time{},
+		},
+		{
+			name:  "SystemStatus",
+			input: `{"error":[],"result":{"status":"online","timestamp":"2022-01-31T00:44:35Z"}}`,
+			msg:   &kraken.SystemStatus{},
+		},
+		{
+			name:  "OHLC",
+			input: `{"error":[],"result":{"XXBTZUSD":[[1643714160,"1","1","1","1","1","1",1]],"last":1643757240}}`,
+			msg:   &kraken.OHLCs{},
+		},
+		{
+			name:  "OrderBook",
+			input: `{"error":[],"result":{"XXBTZUSD":{"asks":[["1","1",1643832845]],"bids":[["1","1",1643832845.5]]}}}`,
+			msg:   &kraken.OrderBook{},
+		},
+		{
+			name:  "RecentTrades",
+			input: `{"error":[],"result":{"XXBTZUSD":[["1","1",1644189769.9122,"b","l","",1]],"last":"1644191265969108820"}}`,
+			msg:   &kraken.RecentTrades{},
+		},
+		{
+			name:  "RecentSpreads",
+			input: `{"error":[],"result":{"XXBTZUSD":[[1644356229,"1","1"]],"last":1644356424}}`,
+			msg:   &kraken.RecentSpreads{},
+		},
 	}
 
-	msg := kraken.Time{}
 	p := kraken.Parser{}
-	if err := p.Parse(input, &msg); err != nil {
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := p.Parse([]byte(tc.input), tc.msg); err != nil {
+				t.Fatal(err)
+			}
+
+			times := 0
+			walkTimes(reflect.ValueOf(tc.msg), func(v time.Time) {
+				times++
+				if v.Location() != time.UTC {
+					t.Errorf("EXPECTED: %s\nACTUAL: %s", time.UTC, v.Location())
+				}
+			})
+
+			if times == 0 {
+				t.Error("no times parsed")
+			}
+		})
+	}
+}
+
+// walkTimes call fn with every time.Time reachable from v
+func walkTimes(v reflect.Value, fn func(time.Time)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkTimes(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			fn(t)
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkTimes(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkTimes(v.Index(i), fn)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			walkTimes(v.MapIndex(k), fn)
+		}
+	}
+}
+
+func TestParseResultlessErrors(t *testing.T) {
+	results := []struct {
+		name string
+		msg  interface{}
+	}{
+		{name: "Time", msg: &kraken.Time{}},
+		{name: "SystemStatus", msg: &kraken.SystemStatus{}},
+		{name: "Assets", msg: &kraken.Assets{}},
+		{name: "AssetPairs", msg: &kraken.AssetPairs{}},
+		{name: "Tickers", msg: &kraken.Tickers{}},
+		{name: "OHLCs", msg: &kraken.OHLCs{}},
+		{name: "OrderBook", msg: &kraken.OrderBook{}},
+		{name: "RecentTrades", msg: &kraken.RecentTrades{}},
+		{name: "RecentSpreads", msg: &kraken.RecentSpreads{}},
+		{name: "TradeBalance", msg: &kraken.TradeBalance{}},
+		{name: "OpenOrders", msg: &kraken.OpenOrders{}},
+		{name: "ClosedOrders", msg: &kraken.ClosedOrders{}},
+		{name: "OrdersInfo", msg: &kraken.OrdersInfo{}},
+		{name: "TradesHistory", msg: &kraken.TradesHistory{}},
+		{name: "OpenPositions", msg: &kraken.OpenPositions{}},
+		{name: "Ledgers", msg: &kraken.Ledgers{}},
+		{name: "LedgersInfo", msg: &kraken.LedgersInfo{}},
+		{name: "TradeVolume", msg: &kraken.TradeVolume{}},
+		{name: "AddOrderResult", msg: &kraken.AddOrderResult{}},
+		{name: "EditOrderResult", msg: &kraken.EditOrderResult{}},
+		{name: "CancelResult", msg: &kraken.CancelResult{}},
+		{name: "CancelAllOrdersAfterResult", msg: &kraken.CancelAllOrdersAfterResult{}},
+		{name: "DepositMethods", msg: &kraken.DepositMethods{}},
+		{name: "DepositAddresses", msg: &kraken.DepositAddresses{}},
+		{name: "DepositStatus", msg: &kraken.DepositStatus{}},
+		{name: "WithdrawInfo", msg: &kraken.WithdrawInfo{}},
+		{name: "WithdrawResult", msg: &kraken.WithdrawResult{}},
+		{name: "WithdrawStatus", msg: &kraken.WithdrawStatus{}},
+		{name: "WithdrawCancelResult", msg: &kraken.WithdrawCancelResult{}},
+		{name: "WalletTransferResult", msg: &kraken.WalletTransferResult{}},
+		{name: "EarnStrategies", msg: &kraken.EarnStrategies{}},
+		{name: "EarnAllocationResult", msg: &kraken.EarnAllocationResult{}},
+		{name: "EarnAllocationStatus", msg: &kraken.EarnAllocationStatus{}},
+		{name: "EarnAllocations", msg: &kraken.EarnAllocations{}},
+		{name: "AddExportResult", msg: &kraken.AddExportResult{}},
+		{name: "ExportStatus", msg: &kraken.ExportStatus{}},
+		{name: "RemoveExportResult", msg: &kraken.RemoveExportResult{}},
+		{name: "CreateSubaccountResult", msg: &kraken.CreateSubaccountResult{}},
+		{name: "AccountTransferResult", msg: &kraken.AccountTransferResult{}},
+		{name: "WebSocketsToken", msg: &kraken.WebSocketsToken{}},
+	}
+
+	inputs := []struct {
+		name  string
+		input string
+	}{
+		{name: "EmptyResult", input: `{"error":["EQuery:Unknown asset pair"],"result":{}}`},
+		{name: "NullResult", input: `{"error":["EQuery:Unknown asset pair"],"result":null}`},
+		{name: "MissingResult", input: `{"error":["EQuery:Unknown asset pair"]}`},
+	}
+
+	p := kraken.Parser{}
+	for _, result := range results {
+		for _, input := range inputs {
+			t.Run(result.name+input.name, func(t *testing.T) {
+				msg := reflect.New(reflect.TypeOf(result.msg).Elem())
+				if err := p.Parse([]byte(input.input), msg.Interface()); err != nil {
+					t.Fatal(err)
+				}
+
+				errs := msg.Elem().FieldByName("Errors").Interface().([]error)
+				if len(errs) != 1 || !errors.Is(errs[0], kraken.ErrUnknownAssetPair) {
+					t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrUnknownAssetPair, errs)
+				}
+
+				// result maps are left empty rather than nil
+				for i := 0; i < msg.Elem().NumField(); i++ {
+					if field := msg.Elem().Field(i); field.Kind() == reflect.Map && field.IsNil() {
+						t.Errorf("%s is nil", msg.Elem().Type().Field(i).Name)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestParseNumbersAndStrings(t *testing.T) {
+	tcs := []struct {
+		name    string
+		strings string
+		numbers string
+		msg     func() interface{}
+	}{
+		{
+			name:    "Tickers",
+			strings: `{"error":[],"result":{"XXBTZUSD":{"a":["38659.6","2","2.500"],"b":["38658.7","1","1.000"],"c":["38658.9","0.021208"],"v":["3150.86186124","3404.34671"],"p":["38609.60189","38601.37073"],"t":[24864,27336],"l":["38132.5","38132.5"],"h":["38988.0","38988.0"],"o":"38466.4"}}}`,
+			numbers: `{"error":[],"result":{"XXBTZUSD":{"a":[38659.6,2,2.500],"b":[38658.7,1,1.000],"c":[38658.9,0.021208],"v":[3150.86186124,3404.34671],"p":[38609.60189,38601.37073],"t":[24864,27336],"l":[38132.5,38132.5],"h":[38988.0,38988.0],"o":38466.4}}}`,
+			msg:     func() interface{} { return &kraken.Tickers{} },
+		},
+		{
+			name:    "OHLC",
+			strings: `{"error":[],"result":{"XXBTZUSD":[["1643714160","38311.6","38343.7","38311.6","38343.7","38320.8","0.40716249",11]],"last":1643757240}}`,
+			numbers: `{"error":[],"result":{"XXBTZUSD":[[1643714160,38311.6,38343.7,38311.6,38343.7,38320.8,0.40716249,11]],"last":1643757240}}`,
+			msg:     func() interface{} { return &kraken.OHLCs{} },
+		},
+		{
+			name:    "OrderBook",
+			strings: `{"error":[],"result":{"XXBTZUSD":{"asks":[["38311.6","1.5","1643714160"]],"bids":[["38311.5","0.25","1643714160.123"]]}}}`,
+			numbers: `{"error":[],"result":{"XXBTZUSD":{"asks":[[38311.6,1.5,1643714160]],"bids":[[38311.5,0.25,1643714160.123]]}}}`,
+			msg:     func() interface{} { return &kraken.OrderBook{} },
+		},
+		{
+			name:    "RecentTrades",
+			strings: `{"error":[],"result":{"XXBTZUSD":[["38311.6","0.1","1643714160.1234","b","l","",42]],"last":"1643757240"}}`,
+			numbers: `{"error":[],"result":{"XXBTZUSD":[[38311.6,0.1,1643714160.1234,"b","l","",42]],"last":"1643757240"}}`,
+			msg:     func() interface{} { return &kraken.RecentTrades{} },
+		},
+		{
+			name:    "RecentSpreads",
+			strings: `{"error":[],"result":{"XXBTZUSD":[["1643714160","38311.6","38311.7"]],"last":1643757240}}`,
+			numbers: `{"error":[],"result":{"XXBTZUSD":[[1643714160,38311.6,38311.7]],"last":1643757240}}`,
+			msg:     func() interface{} { return &kraken.RecentSpreads{} },
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := tc.msg()
+			if err := p.Parse([]byte(tc.strings), expected); err != nil {
+				t.Fatal(err)
+			}
+
+			actual := tc.msg()
+			if err := p.Parse([]byte(tc.numbers), actual); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(expected, actual); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseMalformedArrays(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		msg      interface{}
+		expected string
+	}{
+		{
+			name:     "TickerShortAsk",
+			input:    `{"error":[],"result":{"XXBTZUSD":{"a":["1.0"],"b":["1.0","1","1.0"],"c":["1.0","1"],"v":["1","1"],"p":["1","1"],"t":[1,1],"l":["1","1"],"h":["1","1"],"o":"1"}}}`,
+			msg:      &kraken.Tickers{},
+			expected: "parse error:XXBTZUSD ask has 1 values, expected at least 3",
+		},
+		{
+			name:     "TickerMissingVolume",
+			input:    `{"error":[],"result":{"XXBTZUSD":{"a":["1.0","1","1.0"],"b":["1.0","1","1.0"],"c":["1.0","1"],"p":["1","1"],"t":[1,1],"l":["1","1"],"h":["1","1"],"o":"1"}}}`,
+			msg:      &kraken.Tickers{},
+			expected: "parse error:XXBTZUSD volume has 0 values, expected at least 2",
+		},
+		{
+			name:     "OHLCShort",
+			input:    `{"error":[],"result":{"XXBTZUSD":[[1643714160,"38311.6"]],"last":1643757240}}`,
+			msg:      &kraken.OHLCs{},
+			expected: "parse error:XXBTZUSD ohlc: 2 values, expected at least 8",
+		},
+		{
+			name:     "OHLCNotArray",
+			input:    `{"error":[],"result":{"XXBTZUSD":"invalid","last":1643757240}}`,
+			msg:      &kraken.OHLCs{},
+			expected: "parse error:XXBTZUSD ohlc: json: cannot unmarshal string",
+		},
+		{
+			name:     "OHLCInvalidLast",
+			input:    `{"error":[],"result":{"XXBTZUSD":[],"last":"invalid"}}`,
+			msg:      &kraken.OHLCs{},
+			expected: "parse error:last: json: cannot unmarshal string",
+		},
+		{
+			name:     "OHLCInvalidType",
+			input:    `{"error":[],"result":{"XXBTZUSD":[[1643714160,true,"1","1","1","1","1",1]]}}`,
+			msg:      &kraken.OHLCs{},
+			expected: "parse error:XXBTZUSD ohlc: json: cannot unmarshal bool",
+		},
+		{
+			name:     "OrderBookShortAsk",
+			input:    `{"error":[],"result":{"XXBTZUSD":{"asks":[[38311.6]],"bids":[]}}}`,
+			msg:      &kraken.OrderBook{},
+			expected: "parse error:XXBTZUSD ask has 1 values, expected at least 3",
+		},
+		{
+			name:     "OrderBookShortBid",
+			input:    `{"error":[],"result":{"XXBTZUSD":{"asks":[],"bids":[[38311.6,1]]}}}`,
+			msg:      &kraken.OrderBook{},
+			expected: "parse error:XXBTZUSD bid has 2 values, expected at least 3",
+		},
+		{
+			name:     "RecentTradeShort",
+			input:    `{"error":[],"result":{"XXBTZUSD":[["38311.6","1",1643714160.1234]],"last":"1643757240"}}`,
+			msg:      &kraken.RecentTrades{},
+			expected: "parse error:XXBTZUSD trade: 3 values, expected at least 6",
+		},
+		{
+			name:     "RecentTradesInvalidLast",
+			input:    `{"error":[],"result":{"XXBTZUSD":[],"last":true}}`,
+			msg:      &kraken.RecentTrades{},
+			expected: "parse error:last: json: cannot unmarshal bool",
+		},
+		{
+			name:     "RecentSpreadShort",
+			input:    `{"error":[],"result":{"XXBTZUSD":[[1643714160,"38311.6"]],"last":1643757240}}`,
+			msg:      &kraken.RecentSpreads{},
+			expected: "parse error:XXBTZUSD spread: 2 values, expected at least 3",
+		},
+		{
+			name:     "RecentSpreadInvalidTime",
+			input:    `{"error":[],"result":{"XXBTZUSD":[[true,"38311.6","38311.7"]],"last":1643757240}}`,
+			msg:      &kraken.RecentSpreads{},
+			expected: "parse error:XXBTZUSD spread: json: cannot unmarshal bool",
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := p.Parse([]byte(tc.input), tc.msg)
+			if !errors.Is(err, kraken.ErrParse) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", kraken.ErrParse, err)
+			}
+
+			if !strings.HasPrefix(err.Error(), tc.expected) {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, err)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		`{"error":[],"result":{"XXBTZUSD":{"a":["1.0","1","1.0"],"b":["1.0","1","1.0"],"c":["1.0","1"],"v":["1","1"],"p":["1","1"],"t":[1,1],"l":["1","1"],"h":["1","1"],"o":"1"}}}`,
+		`{"error":[],"result":{"XXBTZUSD":[[1643714160,"1","1","1","1","1","1",1]],"last":1643757240}}`,
+		`{"error":[],"result":{"XXBTZUSD":{"asks":[[1.0,1.0,1643714160]],"bids":[[1.0,1.0,1643714160]]}}}`,
+		`{"error":[],"result":{"XXBTZUSD":[["1","1",1643714160.1234,"b","m",""]],"last":"1643757240"}}`,
+		`{"error":[],"result":{"XXBTZUSD":[[1643714160,"1","1"]],"last":1643757240}}`,
+		`{"error":["EGeneral:Internal error","WGeneral:warning"]}`,
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	p := kraken.Parser{}
+	f.Fuzz(func(t *testing.T, input []byte) {
+		for _, msg := range []interface{}{
+			&kraken.Time{},
+			&kraken.SystemStatus{},
+			&kraken.Assets{},
+			&kraken.AssetPairs{},
+			&kraken.Tickers{},
+			&kraken.OHLCs{},
+			&kraken.OrderBook{},
+			&kraken.RecentTrades{},
+			&kraken.RecentSpreads{},
+		} {
+			p.Parse(input, msg)
+		}
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	input := []byte(`
+	{
+		"error":[
+			"EGeneral:test error",
+			"EAPI:test error",
+			"EQuery:test error",
+			"EOrder:test error",
+			"ETrade:test error",
+			"EFunding:test error",
+			"EService:test error",
+			"ESession:test error",
+			"unknown test error"
+		],
+		"result":{
+			"unixtime":1644358183,
+			"rfc1123":"Tue,  8 Feb 22 22:09:43 +0000"
+		}
+	}
+	`)
+
+	output := []error{
+		kraken.ErrGeneral,
+		kraken.ErrAPI,
+		kraken.ErrQuery,
+		kraken.ErrOrder,
+		kraken.ErrTrade,
+		kraken.ErrFunding,
+		kraken.ErrService,
+		kraken.ErrSession,
+		kraken.ErrAPIUnknown,
+	}
+
+	msg := kraken.Time{}
+	p := kraken.Parser{}
+	if err := p.Parse(input, &msg); err != nil {
 		t.Fatal(err)
 	}
 
@@ -761,3 +1484,2684 @@ func TestParseErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestParseKnownErrors(t *testing.T) {
+	tcs := []struct {
+		input    string
+		category error
+		sentinel error
+	}{
+		{input: "EAPI:Rate limit exceeded", category: kraken.ErrAPI, sentinel: kraken.ErrRateLimit},
+		{input: "EGeneral:Too many requests", category: kraken.ErrGeneral, sentinel: kraken.ErrRateLimit},
+		{input: "EOrder:Rate limit exceeded", category: kraken.ErrOrder, sentinel: kraken.ErrRateLimit},
+		{input: "EAPI:Invalid key", category: kraken.ErrAPI, sentinel: kraken.ErrInvalidKey},
+		{input: "EAPI:Invalid signature", category: kraken.ErrAPI, sentinel: kraken.ErrInvalidSignature},
+		{input: "EAPI:Invalid nonce", category: kraken.ErrAPI, sentinel: kraken.ErrInvalidNonce},
+		{input: "EQuery:Unknown asset pair", category: kraken.ErrQuery, sentinel: kraken.ErrUnknownAssetPair},
+		{input: "EOrder:Insufficient funds", category: kraken.ErrOrder, sentinel: kraken.ErrInsufficientFunds},
+		{input: "EOrder:Order minimum not met", category: kraken.ErrOrder, sentinel: kraken.ErrOrderMinimum},
+		{input: "EOrder:Unknown order", category: kraken.ErrOrder, sentinel: kraken.ErrUnknownOrder},
+		{input: "EService:Unavailable", category: kraken.ErrService, sentinel: kraken.ErrServiceUnavailable},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			msg := kraken.Time{}
+			if err := p.Parse([]byte(`{"error":["`+tc.input+`"]}`), &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(msg.Errors) != 1 {
+				t.Fatalf("EXPECTED: 1 error\nACTUAL: %v", msg.Errors)
+			}
+
+			err := msg.Errors[0]
+			if !errors.Is(err, tc.category) {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.category, err)
+			}
+
+			if !errors.Is(err, tc.sentinel) {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.sentinel, err)
+			}
+
+			if err.Error() != tc.input {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.input, err)
+			}
+		})
+	}
+}
+
+func TestParseKrakenError(t *testing.T) {
+	tcs := []struct {
+		input    string
+		expected kraken.KrakenError
+		category error
+	}{
+		{
+			input: "EOrder:Insufficient funds",
+			expected: kraken.KrakenError{
+				Severity: kraken.ErrorSeverityError,
+				Category: "Order",
+				Message:  "Insufficient funds",
+			},
+			category: kraken.ErrOrder,
+		},
+		{
+			input: "EGeneral:Invalid arguments:ordertype",
+			expected: kraken.KrakenError{
+				Severity: kraken.ErrorSeverityError,
+				Category: "General",
+				Message:  "Invalid arguments:ordertype",
+			},
+			category: kraken.ErrGeneral,
+		},
+		{
+			input: "EUnknown:category",
+			expected: kraken.KrakenError{
+				Severity: kraken.ErrorSeverityUnknown,
+				Message:  "EUnknown:category",
+			},
+			category: kraken.ErrAPIUnknown,
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			msg := kraken.Time{}
+			if err := p.Parse([]byte(`{"error":["`+tc.input+`"]}`), &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			var krakenErr *kraken.KrakenError
+			if len(msg.Errors) != 1 || !errors.As(msg.Errors[0], &krakenErr) {
+				t.Fatalf("EXPECTED: KrakenError\nACTUAL: %v", msg.Errors)
+			}
+
+			if krakenErr.Severity != tc.expected.Severity || krakenErr.Category != tc.expected.Category || krakenErr.Message != tc.expected.Message {
+				t.Errorf("EXPECTED: %s %s %s\nACTUAL: %s %s %s", tc.expected.Severity, tc.expected.Category, tc.expected.Message, krakenErr.Severity, krakenErr.Category, krakenErr.Message)
+			}
+
+			if !errors.Is(krakenErr, tc.category) {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.category, krakenErr)
+			}
+		})
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	input := []byte(`
+	{
+		"error":[
+			"WGeneral:Danger advisory",
+			"EGeneral:Temporary lockout"
+		],
+		"result":{
+			"status":"online",
+			"timestamp":"2022-01-31T00:44:35Z"
+		}
+	}
+	`)
+
+	msg := kraken.SystemStatus{}
+	p := kraken.Parser{}
+	if err := p.Parse(input, &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msg.Errors) != 1 || !errors.Is(msg.Errors[0], kraken.ErrGeneral) {
+		t.Errorf("EXPECTED: EGeneral:Temporary lockout\nACTUAL: %v", msg.Errors)
+	}
+
+	expected := kraken.SystemStatus{
+		Errors: msg.Errors,
+		Warnings: []kraken.KrakenError{
+			{
+				Severity: kraken.ErrorSeverityWarning,
+				Category: "General",
+				Message:  "Danger advisory",
+			},
+		},
+		Status:    kraken.SystemStatusValueOnline,
+		RawStatus: "online",
+		Timestamp: time.Date(2022, 1, 31, 0, 44, 35, 0, time.UTC),
+	}
+
+	if diff := deep.Equal(expected, msg); diff != nil {
+		t.Error(diff)
+	}
+
+	if !errors.Is(&msg.Warnings[0], kraken.ErrGeneral) {
+		t.Errorf("EXPECTED: %s\nACTUAL: %s", kraken.ErrGeneral, &msg.Warnings[0])
+	}
+}
+
+func TestParseWarningsOnly(t *testing.T) {
+	input := []byte(`
+	{
+		"error":["WGeneral:Danger advisory"],
+		"result":{
+			"XXBTZUSD":[
+				[1644356229,"44223.30000","44225.10000"]
+			],
+			"last":1644356424
+		}
+	}
+	`)
+
+	msg := kraken.RecentSpreads{}
+	p := kraken.Parser{}
+	if err := p.Parse(input, &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Errors != nil {
+		t.Errorf("EXPECTED: no errors\nACTUAL: %v", msg.Errors)
+	}
+
+	if len(msg.Warnings) != 1 || msg.Warnings[0].Error() != "WGeneral:Danger advisory" {
+		t.Errorf("EXPECTED: WGeneral:Danger advisory\nACTUAL: %v", msg.Warnings)
+	}
+
+	if len(msg.Spreads["XXBTZUSD"]) != 1 {
+		t.Errorf("EXPECTED: 1 spread\nACTUAL: %v", msg.Spreads)
+	}
+}
+
+func TestParseTradeBalance(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.TradeBalance
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"eb": "1101.3425",
+					"tb": "392.2264",
+					"m": "7.0354",
+					"n": "-10.0232",
+					"c": "21.1063",
+					"v": "31.1297",
+					"e": "382.2032",
+					"mf": "375.1678",
+					"ml": "5432.57",
+					"uv": "0.0000"
+				}
+			}
+			`),
+			expected: kraken.TradeBalance{
+				EquivalentBalance: decimal.New(11013425, -4),
+				TradeBalance:      decimal.New(3922264, -4),
+				MarginUsed:        decimal.New(70354, -4),
+				UnrealizedPnL:     decimal.New(-100232, -4),
+				CostBasis:         decimal.New(211063, -4),
+				FloatingValuation: decimal.New(311297, -4),
+				Equity:            decimal.New(3822032, -4),
+				FreeMargin:        decimal.New(3751678, -4),
+				MarginLevel:       decimal.New(543257, -2),
+				UnexecutedValue:   decimal.New(0, 0),
+			},
+		},
+		{
+			name: "NoOpenPositions",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"eb": "1101.3425",
+					"tb": "392.2264",
+					"m": "0.0000",
+					"n": "0.0000",
+					"c": "0.0000",
+					"v": "0.0000",
+					"e": "392.2264",
+					"mf": "392.2264"
+				}
+			}
+			`),
+			expected: kraken.TradeBalance{
+				EquivalentBalance: decimal.New(11013425, -4),
+				TradeBalance:      decimal.New(3922264, -4),
+				MarginUsed:        decimal.New(0, 0),
+				UnrealizedPnL:     decimal.New(0, 0),
+				CostBasis:         decimal.New(0, 0),
+				FloatingValuation: decimal.New(0, 0),
+				Equity:            decimal.New(3922264, -4),
+				FreeMargin:        decimal.New(3922264, -4),
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.TradeBalance{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseOpenOrders(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.OpenOrders
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"open": {
+						"OQCLML-BW3P3-BUCMWZ": {
+							"refid": null,
+							"userref": 0,
+							"status": "open",
+							"opentm": 1616666559.8974,
+							"starttm": 0,
+							"expiretm": 0,
+							"descr": {
+								"pair": "XBTUSD",
+								"type": "buy",
+								"ordertype": "limit",
+								"price": "30010.0",
+								"price2": "0",
+								"leverage": "none",
+								"order": "buy 1.25000000 XBTUSD @ limit 30010.0",
+								"close": ""
+							},
+							"vol": "1.25000000",
+							"vol_exec": "0.37500000",
+							"cost": "11253.7",
+							"fee": "0.00000",
+							"price": "30010.0",
+							"stopprice": "0.00000",
+							"limitprice": "0.00000",
+							"misc": "",
+							"oflags": "fciq",
+							"trades": [
+								"TCCCTY-WE2O6-P3NB37"
+							]
+						},
+						"OB5VMB-B4U2U-DK2WRW": {
+							"refid": null,
+							"userref": 120,
+							"status": "open",
+							"opentm": 1616665899.5699,
+							"starttm": 1616670000,
+							"expiretm": 1616680000,
+							"descr": {
+								"pair": "XBTUSD",
+								"type": "sell",
+								"ordertype": "market",
+								"price": "0",
+								"price2": "0",
+								"leverage": "5:1",
+								"order": "sell 0.10000000 XBTUSD @ market with 5:1 leverage",
+								"close": ""
+							},
+							"vol": "0.10000000",
+							"vol_exec": "0.00000000",
+							"cost": "0.00000",
+							"fee": "0.00000",
+							"price": "0.00000",
+							"stopprice": "0.00000",
+							"limitprice": "0.00000",
+							"misc": "",
+							"oflags": "fciq,post"
+						}
+					}
+				}
+			}
+			`),
+			expected: kraken.OpenOrders{
+				Orders: map[string]kraken.Order{
+					"OQCLML-BW3P3-BUCMWZ": {
+						TransactionID: "OQCLML-BW3P3-BUCMWZ",
+						Status:        kraken.OrderStatusOpen,
+						OpenTime:      time.Unix(1616666559, 897400000).UTC(),
+						Description: kraken.OrderDescription{
+							Pair:           "XBTUSD",
+							Action:         kraken.OrderActionBuy,
+							Type:           kraken.OrderTypeLimit,
+							Price:          decimal.New(30010, 0),
+							SecondaryPrice: decimal.New(0, 0),
+							Leverage:       "none",
+							Order:          "buy 1.25000000 XBTUSD @ limit 30010.0",
+						},
+						Volume:         decimal.New(125, -2),
+						VolumeExecuted: decimal.New(375, -3),
+						Cost:           decimal.New(112537, -1),
+						Fee:            decimal.New(0, 0),
+						AveragePrice:   decimal.New(30010, 0),
+						StopPrice:      decimal.New(0, 0),
+						LimitPrice:     decimal.New(0, 0),
+						Flags:          []string{"fciq"},
+						Trades:         []string{"TCCCTY-WE2O6-P3NB37"},
+					},
+					"OB5VMB-B4U2U-DK2WRW": {
+						TransactionID: "OB5VMB-B4U2U-DK2WRW",
+						UserReference: 120,
+						Status:        kraken.OrderStatusOpen,
+						OpenTime:      time.Unix(1616665899, 569900000).UTC(),
+						StartTime:     time.Unix(1616670000, 0).UTC(),
+						ExpireTime:    time.Unix(1616680000, 0).UTC(),
+						Description: kraken.OrderDescription{
+							Pair:           "XBTUSD",
+							Action:         kraken.OrderActionSell,
+							Type:           kraken.OrderTypeMarket,
+							Price:          decimal.New(0, 0),
+							SecondaryPrice: decimal.New(0, 0),
+							Leverage:       "5:1",
+							Order:          "sell 0.10000000 XBTUSD @ market with 5:1 leverage",
+						},
+						Volume:         decimal.New(1, -1),
+						VolumeExecuted: decimal.New(0, 0),
+						Cost:           decimal.New(0, 0),
+						Fee:            decimal.New(0, 0),
+						AveragePrice:   decimal.New(0, 0),
+						StopPrice:      decimal.New(0, 0),
+						LimitPrice:     decimal.New(0, 0),
+						Flags:          []string{"fciq", "post"},
+					},
+				},
+			},
+		},
+		{
+			name: "NoOpenOrders",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"open": {}
+				}
+			}
+			`),
+			expected: kraken.OpenOrders{
+				Orders: map[string]kraken.Order{},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.OpenOrders{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseOrderDescription(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		expected kraken.OrderDescription
+	}{
+		{
+			name: "StopLossLimit",
+			input: `{
+				"pair": "ETHUSD",
+				"type": "sell",
+				"ordertype": "stop-loss-limit",
+				"price": "2500.00",
+				"price2": "2450.50",
+				"leverage": "none",
+				"order": "sell 2.00000000 ETHUSD @ stop loss 2500.00 -> limit 2450.50",
+				"close": ""
+			}`,
+			expected: kraken.OrderDescription{
+				Pair:           "ETHUSD",
+				Action:         kraken.OrderActionSell,
+				Type:           kraken.OrderTypeStopLossLimit,
+				Price:          decimal.New(250000, -2),
+				SecondaryPrice: decimal.New(245050, -2),
+				Leverage:       "none",
+				Order:          "sell 2.00000000 ETHUSD @ stop loss 2500.00 -> limit 2450.50",
+			},
+		},
+		{
+			name: "TakeProfitWithClose",
+			input: `{
+				"pair": "XBTUSD",
+				"type": "buy",
+				"ordertype": "take-profit",
+				"price": "40000.0",
+				"price2": "0",
+				"leverage": "2:1",
+				"order": "buy 0.50000000 XBTUSD @ take profit 40000.0 with 2:1 leverage",
+				"close": "close position @ stop loss 35000.0"
+			}`,
+			expected: kraken.OrderDescription{
+				Pair:           "XBTUSD",
+				Action:         kraken.OrderActionBuy,
+				Type:           kraken.OrderTypeTakeProfit,
+				Price:          decimal.New(40000, 0),
+				SecondaryPrice: decimal.New(0, 0),
+				Leverage:       "2:1",
+				Order:          "buy 0.50000000 XBTUSD @ take profit 40000.0 with 2:1 leverage",
+				Close:          "close position @ stop loss 35000.0",
+			},
+		},
+		{
+			name: "UnknownTypes",
+			input: `{
+				"pair": "XBTUSD",
+				"type": "swap",
+				"ordertype": "iceberg",
+				"price": "",
+				"price2": ""
+			}`,
+			expected: kraken.OrderDescription{
+				Pair:   "XBTUSD",
+				Action: kraken.OrderActionUnknown,
+				Type:   kraken.OrderTypeUnknown,
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			input := []byte(`{"error":[],"result":{"open":{"OQCLML-BW3P3-BUCMWZ":{"status":"open","descr":` + tc.input + `}}}}`)
+
+			msg := kraken.OpenOrders{}
+			if err := p.Parse(input, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg.Orders["OQCLML-BW3P3-BUCMWZ"].Description); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseClosedOrders(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.ClosedOrders
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"closed": {
+						"O37652-RJWRT-IMO74O": {
+							"refid": "None",
+							"userref": 1,
+							"status": "canceled",
+							"reason": "User requested",
+							"opentm": 1688148493.7708,
+							"closetm": 1688148610.0482,
+							"starttm": 0,
+							"expiretm": 0,
+							"descr": {
+								"pair": "XBTGBP",
+								"type": "buy",
+								"ordertype": "stop-loss-limit",
+								"price": "23667.0",
+								"price2": "0",
+								"leverage": "none",
+								"order": "buy 0.00100000 XBTGBP @ limit 23667.0",
+								"close": ""
+							},
+							"vol": "0.00100000",
+							"vol_exec": "0.00000000",
+							"cost": "0.00000",
+							"fee": "0.00000",
+							"price": "0.00000",
+							"stopprice": "0.00000",
+							"limitprice": "0.00000",
+							"misc": "",
+							"oflags": "fciq"
+						}
+					},
+					"count": 1
+				}
+			}
+			`),
+			expected: kraken.ClosedOrders{
+				Orders: map[string]kraken.Order{
+					"O37652-RJWRT-IMO74O": {
+						TransactionID: "O37652-RJWRT-IMO74O",
+						ReferenceID:   "None",
+						UserReference: 1,
+						Status:        kraken.OrderStatusCanceled,
+						OpenTime:      time.Unix(1688148493, 770800000).UTC(),
+						CloseTime:     time.Unix(1688148610, 48200000).UTC(),
+						CloseReason:   "User requested",
+						Description: kraken.OrderDescription{
+							Pair:           "XBTGBP",
+							Action:         kraken.OrderActionBuy,
+							Type:           kraken.OrderTypeStopLossLimit,
+							Price:          decimal.New(23667, 0),
+							SecondaryPrice: decimal.New(0, 0),
+							Leverage:       "none",
+							Order:          "buy 0.00100000 XBTGBP @ limit 23667.0",
+						},
+						Volume:         decimal.New(1, -3),
+						VolumeExecuted: decimal.New(0, 0),
+						Cost:           decimal.New(0, 0),
+						Fee:            decimal.New(0, 0),
+						AveragePrice:   decimal.New(0, 0),
+						StopPrice:      decimal.New(0, 0),
+						LimitPrice:     decimal.New(0, 0),
+						Flags:          []string{"fciq"},
+					},
+				},
+				Count: 1,
+			},
+		},
+		{
+			name: "IncludesTrades",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"closed": {
+						"OTUVJB-NRZDD-XHQIMT": {
+							"refid": null,
+							"userref": 0,
+							"status": "closed",
+							"reason": null,
+							"opentm": 1644189769.5,
+							"closetm": 1644189770.25,
+							"starttm": 0,
+							"expiretm": 0,
+							"descr": {
+								"pair": "XBTUSD",
+								"type": "sell",
+								"ordertype": "market",
+								"price": "0",
+								"price2": "0",
+								"leverage": "none",
+								"order": "sell 0.20000000 XBTUSD @ market",
+								"close": ""
+							},
+							"vol": "0.20000000",
+							"vol_exec": "0.20000000",
+							"cost": "8485.6",
+							"fee": "22.06256",
+							"price": "42428.0",
+							"stopprice": "0.00000",
+							"limitprice": "0.00000",
+							"misc": "",
+							"oflags": "fciq",
+							"trades": [
+								"TZX2WP-XSEOP-FP7WYR",
+								"TJ5T3J-B7W5U-LFLZOL"
+							]
+						}
+					},
+					"count": 34
+				}
+			}
+			`),
+			expected: kraken.ClosedOrders{
+				Orders: map[string]kraken.Order{
+					"OTUVJB-NRZDD-XHQIMT": {
+						TransactionID: "OTUVJB-NRZDD-XHQIMT",
+						Status:        kraken.OrderStatusClosed,
+						OpenTime:      time.Unix(1644189769, 500000000).UTC(),
+						CloseTime:     time.Unix(1644189770, 250000000).UTC(),
+						Description: kraken.OrderDescription{
+							Pair:           "XBTUSD",
+							Action:         kraken.OrderActionSell,
+							Type:           kraken.OrderTypeMarket,
+							Price:          decimal.New(0, 0),
+							SecondaryPrice: decimal.New(0, 0),
+							Leverage:       "none",
+							Order:          "sell 0.20000000 XBTUSD @ market",
+						},
+						Volume:         decimal.New(2, -1),
+						VolumeExecuted: decimal.New(2, -1),
+						Cost:           decimal.New(84856, -1),
+						Fee:            decimal.New(2206256, -5),
+						AveragePrice:   decimal.New(42428, 0),
+						StopPrice:      decimal.New(0, 0),
+						LimitPrice:     decimal.New(0, 0),
+						Flags:          []string{"fciq"},
+						Trades:         []string{"TZX2WP-XSEOP-FP7WYR", "TJ5T3J-B7W5U-LFLZOL"},
+					},
+				},
+				Count: 34,
+			},
+		},
+		{
+			name: "EmptyClosedSet",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"closed": {},
+					"count": 0
+				}
+			}
+			`),
+			expected: kraken.ClosedOrders{
+				Orders: map[string]kraken.Order{},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.ClosedOrders{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseOrdersInfo(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.OrdersInfo
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"OBCMZD-JIEE7-77TH3F": {
+						"refid": null,
+						"userref": 0,
+						"status": "closed",
+						"reason": null,
+						"opentm": 1616665496.7808,
+						"closetm": 1616665499.1922,
+						"starttm": 0,
+						"expiretm": 0,
+						"descr": {
+							"pair": "XBTUSD",
+							"type": "buy",
+							"ordertype": "limit",
+							"price": "37500.0",
+							"price2": "0",
+							"leverage": "none",
+							"order": "buy 1.25000000 XBTUSD @ limit 37500.0",
+							"close": ""
+						},
+						"vol": "1.25000000",
+						"vol_exec": "1.25000000",
+						"cost": "37526.2",
+						"fee": "37.5",
+						"price": "30021.0",
+						"stopprice": "0.00000",
+						"limitprice": "0.00000",
+						"misc": "",
+						"oflags": "fciq",
+						"trades": [
+							"TZX2WP-XSEOP-FP7WYR"
+						]
+					}
+				}
+			}
+			`),
+			expected: kraken.OrdersInfo{
+				Orders: map[string]kraken.Order{
+					"OBCMZD-JIEE7-77TH3F": {
+						TransactionID: "OBCMZD-JIEE7-77TH3F",
+						Status:        kraken.OrderStatusClosed,
+						OpenTime:      time.Unix(1616665496, 780800000).UTC(),
+						CloseTime:     time.Unix(1616665499, 192200000).UTC(),
+						Description: kraken.OrderDescription{
+							Pair:           "XBTUSD",
+							Action:         kraken.OrderActionBuy,
+							Type:           kraken.OrderTypeLimit,
+							Price:          decimal.New(37500, 0),
+							SecondaryPrice: decimal.New(0, 0),
+							Leverage:       "none",
+							Order:          "buy 1.25000000 XBTUSD @ limit 37500.0",
+						},
+						Volume:         decimal.New(125, -2),
+						VolumeExecuted: decimal.New(125, -2),
+						Cost:           decimal.New(375262, -1),
+						Fee:            decimal.New(375, -1),
+						AveragePrice:   decimal.New(30021, 0),
+						StopPrice:      decimal.New(0, 0),
+						LimitPrice:     decimal.New(0, 0),
+						Flags:          []string{"fciq"},
+						Trades:         []string{"TZX2WP-XSEOP-FP7WYR"},
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.OrdersInfo{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseTradesHistory(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.TradesHistory
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"trades": {
+						"THVRQM-33VKH-UCI7BS": {
+							"ordertxid": "OQCLML-BW3P3-BUCMWZ",
+							"postxid": "TKH2SE-M7IF5-CFI7LT",
+							"pair": "XXBTZUSD",
+							"time": 1616667796.8802,
+							"type": "buy",
+							"ordertype": "limit",
+							"price": "30010.00000",
+							"cost": "600.20000",
+							"fee": "0.00000",
+							"vol": "0.02000000",
+							"margin": "0.00000",
+							"misc": ""
+						},
+						"TCWJEG-FL4SZ-3FKGH6": {
+							"ordertxid": "OQCLML-BW3P3-BUCMWZ",
+							"postxid": "TKH2SE-M7IF5-CFI7LT",
+							"pair": "XXBTZUSD",
+							"time": 1616667769.6396,
+							"type": "sell",
+							"ordertype": "market",
+							"price": "30010.00000",
+							"cost": "300.10000",
+							"fee": "0.78026",
+							"vol": "0.01000000",
+							"margin": "60.02000",
+							"misc": "closing"
+						}
+					},
+					"count": 2346
+				}
+			}
+			`),
+			expected: kraken.TradesHistory{
+				Trades: map[string]kraken.TradeHistoryEntry{
+					"THVRQM-33VKH-UCI7BS": {
+						TransactionID:         "THVRQM-33VKH-UCI7BS",
+						OrderTransactionID:    "OQCLML-BW3P3-BUCMWZ",
+						PositionTransactionID: "TKH2SE-M7IF5-CFI7LT",
+						Pair:                  "XXBTZUSD",
+						Time:                  time.Unix(1616667796, 880200000).UTC(),
+						Action:                kraken.OrderActionBuy,
+						Type:                  kraken.OrderTypeLimit,
+						Price:                 decimal.New(30010, 0),
+						Cost:                  decimal.New(6002, -1),
+						Fee:                   decimal.New(0, 0),
+						Volume:                decimal.New(2, -2),
+						Margin:                decimal.New(0, 0),
+					},
+					"TCWJEG-FL4SZ-3FKGH6": {
+						TransactionID:         "TCWJEG-FL4SZ-3FKGH6",
+						OrderTransactionID:    "OQCLML-BW3P3-BUCMWZ",
+						PositionTransactionID: "TKH2SE-M7IF5-CFI7LT",
+						Pair:                  "XXBTZUSD",
+						Time:                  time.Unix(1616667769, 639600000).UTC(),
+						Action:                kraken.OrderActionSell,
+						Type:                  kraken.OrderTypeMarket,
+						Price:                 decimal.New(30010, 0),
+						Cost:                  decimal.New(3001, -1),
+						Fee:                   decimal.New(78026, -5),
+						Volume:                decimal.New(1, -2),
+						Margin:                decimal.New(6002, -2),
+						Miscellaneous:         "closing",
+					},
+				},
+				Count: 2346,
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.TradesHistory{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseOpenPositions(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.OpenPositions
+		err      error
+	}{
+		{
+			name: "WithCalculations",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"TF5GVO-T7ZZ2-6NBKBI": {
+						"ordertxid": "OLWNFG-LLH4R-D6SFFP",
+						"posstatus": "open",
+						"pair": "XXBTZUSD",
+						"time": 1605280097.8294,
+						"type": "buy",
+						"ordertype": "limit",
+						"cost": "104610.52842",
+						"fee": "289.06565",
+						"vol": "8.82412861",
+						"vol_closed": "0.20200000",
+						"margin": "20922.10568",
+						"value": "258797.5",
+						"net": "+154186.9728",
+						"terms": "0.0100% per 4 hours",
+						"rollovertm": "1616672637",
+						"misc": "",
+						"oflags": ""
+					}
+				}
+			}
+			`),
+			expected: kraken.OpenPositions{
+				Positions: map[string]kraken.Position{
+					"TF5GVO-T7ZZ2-6NBKBI": {
+						TransactionID:      "TF5GVO-T7ZZ2-6NBKBI",
+						OrderTransactionID: "OLWNFG-LLH4R-D6SFFP",
+						Status:             "open",
+						Pair:               "XXBTZUSD",
+						Time:               time.Unix(1605280097, 829400000).UTC(),
+						Action:             kraken.OrderActionBuy,
+						Type:               kraken.OrderTypeLimit,
+						Cost:               decimal.New(10461052842, -5),
+						Fee:                decimal.New(28906565, -5),
+						Volume:             decimal.New(882412861, -8),
+						VolumeClosed:       decimal.New(202, -3),
+						Margin:             decimal.New(2092210568, -5),
+						Value:              decimal.New(2587975, -1),
+						NetPnL:             decimal.New(1541869728, -4),
+						Terms:              "0.0100% per 4 hours",
+						RolloverTime:       time.Unix(1616672637, 0).UTC(),
+					},
+				},
+			},
+		},
+		{
+			name: "WithoutCalculations",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"TYMRFG-URRG5-2ZTQSD": {
+						"ordertxid": "OF5WFH-V57DP-QANDAC",
+						"posstatus": "open",
+						"pair": "XETHZUSD",
+						"time": 1610448039.8374,
+						"type": "sell",
+						"ordertype": "market",
+						"cost": "0.00240",
+						"fee": "0.00000",
+						"vol": "0.00000010",
+						"vol_closed": "0.00000000",
+						"margin": "0.00048",
+						"terms": "0.0100% per 4 hours",
+						"rollovertm": "1616672637",
+						"misc": "",
+						"oflags": "fciq"
+					}
+				}
+			}
+			`),
+			expected: kraken.OpenPositions{
+				Positions: map[string]kraken.Position{
+					"TYMRFG-URRG5-2ZTQSD": {
+						TransactionID:      "TYMRFG-URRG5-2ZTQSD",
+						OrderTransactionID: "OF5WFH-V57DP-QANDAC",
+						Status:             "open",
+						Pair:               "XETHZUSD",
+						Time:               time.Unix(1610448039, 837400000).UTC(),
+						Action:             kraken.OrderActionSell,
+						Type:               kraken.OrderTypeMarket,
+						Cost:               decimal.New(24, -4),
+						Fee:                decimal.New(0, 0),
+						Volume:             decimal.New(1, -7),
+						VolumeClosed:       decimal.New(0, 0),
+						Margin:             decimal.New(48, -5),
+						Terms:              "0.0100% per 4 hours",
+						RolloverTime:       time.Unix(1616672637, 0).UTC(),
+						Flags:              []string{"fciq"},
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.OpenPositions{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseLedgers(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.Ledgers
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"ledger": {
+						"L4UESK-KG3EQ-UFO4T5": {
+							"refid": "TJKLXX-PGMUI-4NTLXU",
+							"time": 1610464484.1787,
+							"type": "trade",
+							"subtype": "",
+							"aclass": "currency",
+							"asset": "ZGBP",
+							"amount": "-24.5000",
+							"fee": "0.0490",
+							"balance": "459567.9171"
+						},
+						"LMKZCZ-Z3GVL-CXKK4H": {
+							"refid": "QCCNSEN-A5SAX-4CDJDG",
+							"time": 1610306738.2455,
+							"type": "staking",
+							"subtype": "stakingfromspot",
+							"aclass": "currency",
+							"asset": "DOT.S",
+							"amount": "10.0000000000",
+							"fee": "0.0000000000",
+							"balance": "10.0000000000"
+						}
+					},
+					"count": 2
+				}
+			}
+			`),
+			expected: kraken.Ledgers{
+				Entries: map[string]kraken.LedgerEntry{
+					"L4UESK-KG3EQ-UFO4T5": {
+						ID:          "L4UESK-KG3EQ-UFO4T5",
+						ReferenceID: "TJKLXX-PGMUI-4NTLXU",
+						Time:        time.Unix(1610464484, 178700000).UTC(),
+						Type:        kraken.LedgerTypeTrade,
+						AssetClass:  "currency",
+						Asset:       "ZGBP",
+						Amount:      decimal.New(-245, -1),
+						Fee:         decimal.New(49, -3),
+						Balance:     decimal.New(4595679171, -4),
+					},
+					"LMKZCZ-Z3GVL-CXKK4H": {
+						ID:          "LMKZCZ-Z3GVL-CXKK4H",
+						ReferenceID: "QCCNSEN-A5SAX-4CDJDG",
+						Time:        time.Unix(1610306738, 245500000).UTC(),
+						Type:        kraken.LedgerTypeStaking,
+						SubType:     "stakingfromspot",
+						AssetClass:  "currency",
+						Asset:       "DOT.S",
+						Amount:      decimal.New(10, 0),
+						Fee:         decimal.New(0, 0),
+						Balance:     decimal.New(10, 0),
+					},
+				},
+				Count: 2,
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.Ledgers{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseLedgersInfo(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.LedgersInfo
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"L4UESK-KG3EQ-UFO4T5": {
+						"refid": "TJKLXX-PGMUI-4NTLXU",
+						"time": 1610464484.1787,
+						"type": "deposit",
+						"subtype": "",
+						"aclass": "currency",
+						"asset": "ZGBP",
+						"amount": "24.5000",
+						"fee": "0.0000",
+						"balance": "459592.4171"
+					}
+				}
+			}
+			`),
+			expected: kraken.LedgersInfo{
+				Entries: map[string]kraken.LedgerEntry{
+					"L4UESK-KG3EQ-UFO4T5": {
+						ID:          "L4UESK-KG3EQ-UFO4T5",
+						ReferenceID: "TJKLXX-PGMUI-4NTLXU",
+						Time:        time.Unix(1610464484, 178700000).UTC(),
+						Type:        kraken.LedgerTypeDeposit,
+						AssetClass:  "currency",
+						Asset:       "ZGBP",
+						Amount:      decimal.New(245, -1),
+						Fee:         decimal.New(0, 0),
+						Balance:     decimal.New(4595924171, -4),
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.LedgersInfo{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseTradeVolume(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.TradeVolume
+		err      error
+	}{
+		{
+			name: "WithFeeInfo",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"currency": "ZUSD",
+					"volume": "60254.8271",
+					"fees": {
+						"XXBTZUSD": {
+							"fee": "0.2400",
+							"minfee": "0.1000",
+							"maxfee": "0.2600",
+							"nextfee": "0.2200",
+							"nextvolume": "100000.0000",
+							"tiervolume": "50000.0000"
+						},
+						"XETHZUSD": {
+							"fee": "0.1000",
+							"minfee": "0.1000",
+							"maxfee": "0.2600",
+							"nextfee": null,
+							"nextvolume": null,
+							"tiervolume": "10000000.0000"
+						}
+					},
+					"fees_maker": {
+						"XXBTZUSD": {
+							"fee": "0.1400",
+							"minfee": "0.0000",
+							"maxfee": "0.1600",
+							"nextfee": "0.1200",
+							"nextvolume": "100000.0000",
+							"tiervolume": "50000.0000"
+						}
+					}
+				}
+			}
+			`),
+			expected: kraken.TradeVolume{
+				Currency: "ZUSD",
+				Volume:   decimal.New(602548271, -4),
+				FeesTaker: map[string]kraken.TradeVolumeFee{
+					"XXBTZUSD": {
+						Current: kraken.Fee{Volume: 50000, Percentage: decimal.New(24, -2)},
+						Next:    &kraken.Fee{Volume: 100000, Percentage: decimal.New(22, -2)},
+						Minimum: decimal.New(1, -1),
+						Maximum: decimal.New(26, -2),
+					},
+					"XETHZUSD": {
+						Current: kraken.Fee{Volume: 10000000, Percentage: decimal.New(1, -1)},
+						Minimum: decimal.New(1, -1),
+						Maximum: decimal.New(26, -2),
+					},
+				},
+				FeesMaker: map[string]kraken.TradeVolumeFee{
+					"XXBTZUSD": {
+						Current: kraken.Fee{Volume: 50000, Percentage: decimal.New(14, -2)},
+						Next:    &kraken.Fee{Volume: 100000, Percentage: decimal.New(12, -2)},
+						Minimum: decimal.Zero,
+						Maximum: decimal.New(16, -2),
+					},
+				},
+			},
+		},
+		{
+			name: "WithoutFeeInfo",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"currency": "ZUSD",
+					"volume": "60254.8271"
+				}
+			}
+			`),
+			expected: kraken.TradeVolume{
+				Currency:  "ZUSD",
+				Volume:    decimal.New(602548271, -4),
+				FeesTaker: map[string]kraken.TradeVolumeFee{},
+				FeesMaker: map[string]kraken.TradeVolumeFee{},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.TradeVolume{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseAddOrderResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.AddOrderResult
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"descr": {
+						"order": "buy 2.12340000 XBTUSD @ limit 45000.1 with 2:1 leverage",
+						"close": "close position @ stop loss 38000.0 -> limit 36000.0"
+					},
+					"txid": [
+						"OUF4EM-FRGI2-MQMWZD"
+					]
+				}
+			}
+			`),
+			expected: kraken.AddOrderResult{
+				Description:      "buy 2.12340000 XBTUSD @ limit 45000.1 with 2:1 leverage",
+				CloseDescription: "close position @ stop loss 38000.0 -> limit 36000.0",
+				TransactionIDs:   []string{"OUF4EM-FRGI2-MQMWZD"},
+			},
+		},
+		{
+			name: "ValidateOnly",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"descr": {
+						"order": "sell 1.00000000 ETHUSD @ market"
+					}
+				}
+			}
+			`),
+			expected: kraken.AddOrderResult{
+				Description: "sell 1.00000000 ETHUSD @ market",
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.AddOrderResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseCancelResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.CancelResult
+		errs     []error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"count": 4
+				}
+			}
+			`),
+			expected: kraken.CancelResult{
+				Count: 4,
+			},
+		},
+		{
+			name: "Pending",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"count": 1,
+					"pending": true
+				}
+			}
+			`),
+			expected: kraken.CancelResult{
+				Count:   1,
+				Pending: true,
+			},
+		},
+		{
+			name: "UnknownOrder",
+			input: []byte(`
+			{
+				"error": [
+					"EOrder:Unknown order"
+				]
+			}
+			`),
+			errs: []error{kraken.ErrOrder},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.CancelResult{}
+			if err := p.Parse(tc.input, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(msg.Errors) != len(tc.errs) {
+				t.Fatalf("EXPECTED: %d errors\nACTUAL: %v", len(tc.errs), msg.Errors)
+			}
+
+			for i, err := range tc.errs {
+				if !errors.Is(msg.Errors[i], err) {
+					t.Fatalf("EXPECTED: %s\nACTUAL: %s", err, msg.Errors[i])
+				}
+			}
+
+			msg.Errors = nil
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseCancelAllOrdersAfterResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.CancelAllOrdersAfterResult
+		err      error
+	}{
+		{
+			name: "Enabled",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"currentTime": "2023-03-24T17:41:56Z",
+					"triggerTime": "2023-03-24T17:42:56Z"
+				}
+			}
+			`),
+			expected: kraken.CancelAllOrdersAfterResult{
+				CurrentTime: time.Date(2023, 3, 24, 17, 41, 56, 0, time.UTC),
+				TriggerTime: time.Date(2023, 3, 24, 17, 42, 56, 0, time.UTC),
+			},
+		},
+		{
+			name: "Disabled",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"currentTime": "2023-03-24T17:41:56Z",
+					"triggerTime": "0"
+				}
+			}
+			`),
+			expected: kraken.CancelAllOrdersAfterResult{
+				CurrentTime: time.Date(2023, 3, 24, 17, 41, 56, 0, time.UTC),
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.CancelAllOrdersAfterResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseEditOrderResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.EditOrderResult
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"status": "ok",
+					"txid": "OFVXHJ-KPQ3B-VS7ELA",
+					"originaltxid": "OHYO67-6LP66-HMQ437",
+					"volume": "0.00030000",
+					"price": "19500.0",
+					"price2": "32500.0",
+					"orders_cancelled": 1,
+					"descr": {
+						"order": "buy 0.00030000 XXBTZGBP @ limit 19500.0"
+					}
+				}
+			}
+			`),
+			expected: kraken.EditOrderResult{
+				Status:                "ok",
+				TransactionID:         "OFVXHJ-KPQ3B-VS7ELA",
+				OriginalTransactionID: "OHYO67-6LP66-HMQ437",
+				OriginalDeprecated:    true,
+				Volume:                decimal.New(3, -4),
+				Price:                 decimal.New(19500, 0),
+				SecondaryPrice:        decimal.New(32500, 0),
+				OrdersCancelled:       1,
+				Description:           "buy 0.00030000 XXBTZGBP @ limit 19500.0",
+			},
+		},
+		{
+			name: "ValidateOnly",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"status": "ok",
+					"originaltxid": "OHYO67-6LP66-HMQ437",
+					"volume": "0.00030000",
+					"price": "19500.0",
+					"descr": {
+						"order": "buy 0.00030000 XXBTZGBP @ limit 19500.0"
+					}
+				}
+			}
+			`),
+			expected: kraken.EditOrderResult{
+				Status:                "ok",
+				OriginalTransactionID: "OHYO67-6LP66-HMQ437",
+				Volume:                decimal.New(3, -4),
+				Price:                 decimal.New(19500, 0),
+				Description:           "buy 0.00030000 XXBTZGBP @ limit 19500.0",
+			},
+		},
+		{
+			name: "Failed",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"status": "err",
+					"error_message": "Order not found",
+					"originaltxid": "OHYO67-6LP66-HMQ437"
+				}
+			}
+			`),
+			expected: kraken.EditOrderResult{
+				Status:                "err",
+				ErrorMessage:          "Order not found",
+				OriginalTransactionID: "OHYO67-6LP66-HMQ437",
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.EditOrderResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseDepositMethods(t *testing.T) {
+	limit := decimal.New(25, 0)
+
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.DepositMethods
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": [
+					{
+						"method": "Bitcoin",
+						"limit": false,
+						"fee": "0.0000000000",
+						"gen-address": true,
+						"minimum": "0.00010000"
+					},
+					{
+						"method": "Bitcoin Lightning",
+						"limit": "25.00000000",
+						"fee": "0.00000000",
+						"address-setup-fee": "0.00100000",
+						"minimum": "0.00001000"
+					}
+				]
+			}
+			`),
+			expected: kraken.DepositMethods{
+				Methods: []kraken.DepositMethod{
+					{
+						Name:            "Bitcoin",
+						Fee:             decimal.New(0, 0),
+						GenerateAddress: true,
+						Minimum:         decimal.New(1, -4),
+					},
+					{
+						Name:            "Bitcoin Lightning",
+						Limit:           &limit,
+						Fee:             decimal.New(0, 0),
+						AddressSetupFee: decimal.New(1, -3),
+						Minimum:         decimal.New(1, -5),
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.DepositMethods{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseDepositAddresses(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.DepositAddresses
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": [
+					{
+						"address": "2N9fRkx5JTWXWHmXzZtvhQsufvoYRMq9ExV",
+						"expiretm": "0",
+						"new": true
+					},
+					{
+						"address": "rLHzPsX6oXkzU2qL12kHCH8G8cnZv1rBJh",
+						"expiretm": "1643832845",
+						"tag": "1361101127"
+					}
+				]
+			}
+			`),
+			expected: kraken.DepositAddresses{
+				Addresses: []kraken.DepositAddress{
+					{
+						Address: "2N9fRkx5JTWXWHmXzZtvhQsufvoYRMq9ExV",
+						New:     true,
+					},
+					{
+						Address:    "rLHzPsX6oXkzU2qL12kHCH8G8cnZv1rBJh",
+						ExpireTime: time.Unix(1643832845, 0).UTC(),
+						Tag:        "1361101127",
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.DepositAddresses{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseDepositStatus(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.DepositStatus
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": [
+					{
+						"method": "Bitcoin",
+						"aclass": "currency",
+						"asset": "XXBT",
+						"refid": "QSKZPIK-RP57WL-JD3YIS",
+						"txid": "df57a6bb0f6b5d2cd94d1cd4e53a6a3d3b6a35aba3dbd4e3e1a2d9a2d1aa43e6",
+						"info": "2Myd4eaAW96ojk38A2uDK4FbioCayvkEgVq",
+						"amount": "0.78125000",
+						"fee": "0.0000000000",
+						"time": 1617014586,
+						"status": "Success"
+					},
+					{
+						"method": "Bitcoin",
+						"aclass": "currency",
+						"asset": "XXBT",
+						"refid": "QSKZPIK-NE2PXE-DSQBRK",
+						"txid": "a9d2de7ad7de6a0bc25d9e4a4ae2e6b2d9d21ca3c8b4ddbe2b3d6c4a8e6a79b1",
+						"info": "2Myd4eaAW96ojk38A2uDK4FbioCayvkEgVq",
+						"amount": "0.10000000",
+						"fee": "0.0000000000",
+						"time": 1617015423,
+						"status": "Settled",
+						"status-prop": "onhold"
+					}
+				]
+			}
+			`),
+			expected: kraken.DepositStatus{
+				Deposits: []kraken.FundingTransaction{
+					{
+						Method:        "Bitcoin",
+						AssetClass:    "currency",
+						Asset:         "XXBT",
+						ReferenceID:   "QSKZPIK-RP57WL-JD3YIS",
+						TransactionID: "df57a6bb0f6b5d2cd94d1cd4e53a6a3d3b6a35aba3dbd4e3e1a2d9a2d1aa43e6",
+						Info:          "2Myd4eaAW96ojk38A2uDK4FbioCayvkEgVq",
+						Amount:        decimal.New(78125, -5),
+						Fee:           decimal.New(0, 0),
+						Time:          time.Unix(1617014586, 0).UTC(),
+						Status:        "Success",
+					},
+					{
+						Method:         "Bitcoin",
+						AssetClass:     "currency",
+						Asset:          "XXBT",
+						ReferenceID:    "QSKZPIK-NE2PXE-DSQBRK",
+						TransactionID:  "a9d2de7ad7de6a0bc25d9e4a4ae2e6b2d9d21ca3c8b4ddbe2b3d6c4a8e6a79b1",
+						Info:           "2Myd4eaAW96ojk38A2uDK4FbioCayvkEgVq",
+						Amount:         decimal.New(1, -1),
+						Fee:            decimal.New(0, 0),
+						Time:           time.Unix(1617015423, 0).UTC(),
+						Status:         "Settled",
+						StatusProperty: "onhold",
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.DepositStatus{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWithdrawInfo(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.WithdrawInfo
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"method": "Bitcoin",
+					"limit": "332.00956139",
+					"amount": "0.72480000",
+					"fee": "0.00020000"
+				}
+			}
+			`),
+			expected: kraken.WithdrawInfo{
+				Method: "Bitcoin",
+				Limit:  decimal.New(33200956139, -8),
+				Amount: decimal.New(7248, -4),
+				Fee:    decimal.New(2, -4),
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.WithdrawInfo{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWithdrawResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.WithdrawResult
+		errs     []error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"refid": "AGBSO6T-UFMTTQ-I7KGS6"
+				}
+			}
+			`),
+			expected: kraken.WithdrawResult{
+				ReferenceID: "AGBSO6T-UFMTTQ-I7KGS6",
+			},
+		},
+		{
+			name: "UnknownWithdrawKey",
+			input: []byte(`
+			{
+				"error": [
+					"EFunding:Unknown withdraw key"
+				]
+			}
+			`),
+			errs: []error{kraken.ErrFunding},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.WithdrawResult{}
+			if err := p.Parse(tc.input, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(msg.Errors) != len(tc.errs) {
+				t.Fatalf("EXPECTED: %d errors\nACTUAL: %v", len(tc.errs), msg.Errors)
+			}
+
+			for i, err := range tc.errs {
+				if !errors.Is(msg.Errors[i], err) {
+					t.Fatalf("EXPECTED: %s\nACTUAL: %s", err, msg.Errors[i])
+				}
+			}
+
+			msg.Errors = nil
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWithdrawStatus(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.WithdrawStatus
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": [
+					{
+						"method": "Bitcoin",
+						"aclass": "currency",
+						"asset": "XXBT",
+						"refid": "AGBZNBO-5P2XSB-RFVF6J",
+						"txid": "THVRQM-33VKH-UCI7BS",
+						"info": "mzp6yUVMRxfasyfwzTZjjy38dHqMX7Z3GR",
+						"amount": "0.72485000",
+						"fee": "0.00015000",
+						"time": 1617014586,
+						"status": "Pending",
+						"status-prop": "cancel-pending"
+					}
+				]
+			}
+			`),
+			expected: kraken.WithdrawStatus{
+				Withdrawals: []kraken.FundingTransaction{
+					{
+						Method:         "Bitcoin",
+						AssetClass:     "currency",
+						Asset:          "XXBT",
+						ReferenceID:    "AGBZNBO-5P2XSB-RFVF6J",
+						TransactionID:  "THVRQM-33VKH-UCI7BS",
+						Info:           "mzp6yUVMRxfasyfwzTZjjy38dHqMX7Z3GR",
+						Amount:         decimal.New(72485, -5),
+						Fee:            decimal.New(15, -5),
+						Time:           time.Unix(1617014586, 0).UTC(),
+						Status:         "Pending",
+						StatusProperty: "cancel-pending",
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.WithdrawStatus{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWithdrawCancelResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.WithdrawCancelResult
+		err      error
+	}{
+		{
+			name: "Cancelled",
+			input: []byte(`
+			{
+				"error": [],
+				"result": true
+			}
+			`),
+			expected: kraken.WithdrawCancelResult{
+				Cancelled: true,
+			},
+		},
+		{
+			name: "NotCancelled",
+			input: []byte(`
+			{
+				"error": [],
+				"result": false
+			}
+			`),
+			expected: kraken.WithdrawCancelResult{},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.WithdrawCancelResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWalletTransferResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.WalletTransferResult
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"refid": "BOG5AE5-KSCNR4-VPNPEV"
+				}
+			}
+			`),
+			expected: kraken.WalletTransferResult{
+				ReferenceID: "BOG5AE5-KSCNR4-VPNPEV",
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.WalletTransferResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseEarnStrategies(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.EarnStrategies
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"next_cursor": "2",
+					"items": [
+						{
+							"id": "ESRFUO3-Q62XD-WIOIL7",
+							"asset": "DOT",
+							"lock_type": {
+								"type": "bonded",
+								"payout_frequency": 604800,
+								"bonding_period": 0,
+								"bonding_period_variable": false,
+								"bonding_rewards": false,
+								"unbonding_period": 2419200,
+								"unbonding_period_variable": false,
+								"unbonding_rewards": false,
+								"exit_queue_period": 0
+							},
+							"apr_estimate": {
+								"low": "8.0000",
+								"high": "12.0000"
+							},
+							"user_min_allocation": "0.01",
+							"allocation_fee": "0.0000",
+							"deallocation_fee": 0.5,
+							"auto_compound": {
+								"type": "enabled"
+							},
+							"yield_source": {
+								"type": "staking"
+							},
+							"can_allocate": true,
+							"can_deallocate": true,
+							"allocation_restriction_info": []
+						}
+					]
+				}
+			}
+			`),
+			expected: kraken.EarnStrategies{
+				Strategies: []kraken.EarnStrategy{
+					{
+						ID:                "ESRFUO3-Q62XD-WIOIL7",
+						Asset:             "DOT",
+						LockType:          kraken.EarnLockTypeBonded,
+						APRLow:            decimal.New(8, 0),
+						APRHigh:           decimal.New(12, 0),
+						UnbondingPeriod:   28 * 24 * time.Hour,
+						MinimumAllocation: decimal.New(1, -2),
+						AllocationFee:     decimal.Zero,
+						DeallocationFee:   decimal.New(5, -1),
+						CanAllocate:       true,
+						CanDeallocate:     true,
+					},
+				},
+				NextCursor: "2",
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.EarnStrategies{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseEarnAllocationStatus(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.EarnAllocationStatus
+		err      error
+	}{
+		{
+			name: "Pending",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"pending": true
+				}
+			}
+			`),
+			expected: kraken.EarnAllocationStatus{
+				Pending: true,
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.EarnAllocationStatus{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseEarnAllocations(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.EarnAllocations
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"converted_asset": "USD",
+					"total_allocated": "49.2398",
+					"total_rewarded": "0.0675",
+					"next_cursor": "2",
+					"items": [
+						{
+							"strategy_id": "ESDQCOL-WTZEU-NU55QF",
+							"native_asset": "ETH",
+							"amount_allocated": {
+								"pending": {
+									"native": "0.1000000000",
+									"converted": "180.0000"
+								},
+								"total": {
+									"native": "0.0210000000",
+									"converted": "39.0645"
+								}
+							},
+							"total_rewarded": {
+								"native": "0",
+								"converted": "0.0000"
+							}
+						},
+						{
+							"strategy_id": "ESRFUO3-Q62XD-WIOIL7",
+							"native_asset": "DOT",
+							"amount_allocated": {
+								"total": {
+									"native": "1.5000000000",
+									"converted": "10.1753"
+								}
+							},
+							"total_rewarded": {
+								"native": "0.0100000000",
+								"converted": "0.0675"
+							}
+						}
+					]
+				}
+			}
+			`),
+			expected: kraken.EarnAllocations{
+				ConvertedAsset: "USD",
+				TotalAllocated: decimal.New(492398, -4),
+				TotalRewarded:  decimal.New(675, -4),
+				Allocations: []kraken.EarnAllocation{
+					{
+						StrategyID: "ESDQCOL-WTZEU-NU55QF",
+						Asset:      "ETH",
+						Allocated:  decimal.New(21, -3),
+						Pending:    decimal.New(1, -1),
+						Rewarded:   decimal.Zero,
+					},
+					{
+						StrategyID: "ESRFUO3-Q62XD-WIOIL7",
+						Asset:      "DOT",
+						Allocated:  decimal.New(15, -1),
+						Rewarded:   decimal.New(1, -2),
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.EarnAllocations{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseAddExportResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.AddExportResult
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"id": "TCJA"
+				}
+			}
+			`),
+			expected: kraken.AddExportResult{
+				ID: "TCJA",
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.AddExportResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseExportStatus(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.ExportStatus
+		err      error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": [
+					{
+						"id": "VSKC",
+						"descr": "my_trades_1",
+						"format": "CSV",
+						"report": "trades",
+						"subtype": "all",
+						"status": "Processed",
+						"flags": "0",
+						"fields": "all",
+						"createdtm": "1688669085",
+						"expiretm": "1689878685",
+						"starttm": "1688669093",
+						"completedtm": "1688669093",
+						"datastarttm": "1683556800",
+						"dataendtm": "1688669085",
+						"aclass": "forex",
+						"asset": "all"
+					},
+					{
+						"id": "TCJA",
+						"descr": "my_ledgers",
+						"format": "TSV",
+						"report": "ledgers",
+						"subtype": "all",
+						"status": "Queued",
+						"flags": "0",
+						"fields": "refid,time,amount",
+						"createdtm": "1688669100",
+						"expiretm": "1689878700",
+						"starttm": "0",
+						"completedtm": "0",
+						"datastarttm": "1683556800",
+						"dataendtm": "1688669100",
+						"aclass": "forex",
+						"asset": "all"
+					}
+				]
+			}
+			`),
+			expected: kraken.ExportStatus{
+				Exports: []kraken.Export{
+					{
+						ID:            "VSKC",
+						Description:   "my_trades_1",
+						Format:        kraken.ExportFormatCSV,
+						Report:        kraken.ExportReportTrades,
+						Status:        "Processed",
+						CreatedTime:   time.Unix(1688669085, 0).UTC(),
+						ExpireTime:    time.Unix(1689878685, 0).UTC(),
+						StartTime:     time.Unix(1688669093, 0).UTC(),
+						CompletedTime: time.Unix(1688669093, 0).UTC(),
+						DataStartTime: time.Unix(1683556800, 0).UTC(),
+						DataEndTime:   time.Unix(1688669085, 0).UTC(),
+						AssetClass:    "forex",
+						Asset:         "all",
+					},
+					{
+						ID:            "TCJA",
+						Description:   "my_ledgers",
+						Format:        kraken.ExportFormatTSV,
+						Report:        kraken.ExportReportLedgers,
+						Status:        "Queued",
+						Fields:        []string{"refid", "time", "amount"},
+						CreatedTime:   time.Unix(1688669100, 0).UTC(),
+						ExpireTime:    time.Unix(1689878700, 0).UTC(),
+						DataStartTime: time.Unix(1683556800, 0).UTC(),
+						DataEndTime:   time.Unix(1688669100, 0).UTC(),
+						AssetClass:    "forex",
+						Asset:         "all",
+					},
+				},
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.ExportStatus{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseRemoveExportResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.RemoveExportResult
+		err      error
+	}{
+		{
+			name: "Deleted",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"delete": true
+				}
+			}
+			`),
+			expected: kraken.RemoveExportResult{
+				Removed: true,
+			},
+		},
+		{
+			name: "Cancelled",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"cancel": true
+				}
+			}
+			`),
+			expected: kraken.RemoveExportResult{
+				Removed: true,
+			},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.RemoveExportResult{}
+			if err := p.Parse(tc.input, &msg); err != tc.err {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseAccountTransferResult(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.AccountTransferResult
+		errs     []error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"transfer_id": "TOH3AS2-LPCWR8-JDQGEU",
+					"status": "complete"
+				}
+			}
+			`),
+			expected: kraken.AccountTransferResult{
+				TransferID: "TOH3AS2-LPCWR8-JDQGEU",
+				Status:     "complete",
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			input: []byte(`
+			{
+				"error": [
+					"EFunding:Insufficient funds"
+				]
+			}
+			`),
+			errs: []error{kraken.ErrFunding},
+		},
+		{
+			name: "PermissionDenied",
+			input: []byte(`
+			{
+				"error": [
+					"EGeneral:Permission denied"
+				]
+			}
+			`),
+			errs: []error{kraken.ErrGeneral},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.AccountTransferResult{}
+			if err := p.Parse(tc.input, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(msg.Errors) != len(tc.errs) {
+				t.Fatalf("EXPECTED: %d errors\nACTUAL: %v", len(tc.errs), msg.Errors)
+			}
+
+			for i, err := range tc.errs {
+				if !errors.Is(msg.Errors[i], err) {
+					t.Fatalf("EXPECTED: %s\nACTUAL: %s", err, msg.Errors[i])
+				}
+			}
+
+			msg.Errors = nil
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseWebSocketsToken(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    []byte
+		expected kraken.WebSocketsToken
+		errs     []error
+	}{
+		{
+			name: "ValidPayload",
+			input: []byte(`
+			{
+				"error": [],
+				"result": {
+					"token": "1Dwc4lzSwNWOAwkMdqhssNNFhs1ed606d1WcF3XfEMw",
+					"expires": 900
+				}
+			}
+			`),
+			expected: kraken.WebSocketsToken{
+				Token:   "1Dwc4lzSwNWOAwkMdqhssNNFhs1ed606d1WcF3XfEMw",
+				Expires: 15 * time.Minute,
+			},
+		},
+		{
+			name: "PermissionDenied",
+			input: []byte(`
+			{
+				"error": [
+					"EGeneral:Permission denied"
+				]
+			}
+			`),
+			errs: []error{kraken.ErrGeneral},
+		},
+	}
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := kraken.WebSocketsToken{}
+			if err := p.Parse(tc.input, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(msg.Errors) != len(tc.errs) {
+				t.Fatalf("EXPECTED: %d errors\nACTUAL: %v", len(tc.errs), msg.Errors)
+			}
+
+			for i, err := range tc.errs {
+				if !errors.Is(msg.Errors[i], err) {
+					t.Fatalf("EXPECTED: %s\nACTUAL: %s", err, msg.Errors[i])
+				}
+			}
+
+			msg.Errors = nil
+			if diff := deep.Equal(tc.expected, msg); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParsePublicErrors(t *testing.T) {
+	tcs := []struct {
+		name string
+		msg  interface{}
+	}{
+		{
+			name: "AssetPairs",
+			msg:  &kraken.AssetPairs{},
+		},
+		{
+			name: "Tickers",
+			msg:  &kraken.Tickers{},
+		},
+		{
+			name: "OHLCs",
+			msg:  &kraken.OHLCs{},
+		},
+	}
+
+	input := []byte(`
+	{
+		"error": [
+			"EQuery:Unknown asset pair"
+		],
+		"result": {}
+	}
+	`)
+
+	p := kraken.Parser{}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := p.Parse(input, tc.msg); err != nil {
+				t.Fatal(err)
+			}
+
+			errs := reflect.ValueOf(tc.msg).Elem().FieldByName("Errors").Interface().([]error)
+			if len(errs) != 1 || !errors.Is(errs[0], kraken.ErrQuery) {
+				t.Fatalf("EXPECTED: [%s]\nACTUAL: %v", kraken.ErrQuery, errs)
+			}
+		})
+	}
+}
+
+// benchmarkPayload build a public result payload holding rows copies of row
+// for the XXBTZUSD pair
+func benchmarkPayload(row, last string, rows int) []byte {
+	values := make([]string, rows)
+	for i := range values {
+		values[i] = row
+	}
+
+	return []byte(fmt.Sprintf(`{"error":[],"result":{"XXBTZUSD":[%s],"last":%s}}`, strings.Join(values, ","), last))
+}
+
+func BenchmarkParseOHLC(b *testing.B) {
+	payload := benchmarkPayload(`[1643714160,"38311.6","38343.7","38311.6","38343.7","38320.8","0.40716249",11]`, `1643757240`, 720)
+
+	p := kraken.Parser{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := kraken.OHLCs{}
+		if err := p.Parse(payload, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseRecentTrades(b *testing.B) {
+	payload := benchmarkPayload(`["42428.00000","0.00109505",1644189769.9122,"b","l","",1]`, `"1644191265969108820"`, 1000)
+
+	p := kraken.Parser{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := kraken.RecentTrades{}
+		if err := p.Parse(payload, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseRecentSpreads(b *testing.B) {
+	payload := benchmarkPayload(`[1644356229,"44223.30000","44225.10000"]`, `1644356424`, 1000)
+
+	p := kraken.Parser{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := kraken.RecentSpreads{}
+		if err := p.Parse(payload, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseTickers(b *testing.B) {
+	tickers := make([]string, 0, 5)
+	for _, pair := range []string{"XXBTZUSD", "XETHZUSD", "XXRPZUSD", "SOLUSD", "DOTUSD"} {
+		tickers = append(tickers, fmt.Sprintf(`"%s":{"a":["38659.6","2","2.500"],"b":["38658.7","1","1.000"],"c":["38658.9","0.021208"],"v":["3150.86186124","3404.34671"],"p":["38609.60189","38601.37073"],"t":[24864,27336],"l":["38132.50000","38132.50000"],"h":["38988.00000","38988.00000"],"o":"38466.4"}`, pair))
+	}
+	payload := []byte(fmt.Sprintf(`{"error":[],"result":{%s}}`, strings.Join(tickers, ",")))
+
+	p := kraken.Parser{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := kraken.Tickers{}
+		if err := p.Parse(payload, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// PortfolioAsset the value of the balance of an asset in the quote currency
+// of a PortfolioValuation, with the steps it was converted through
+type PortfolioAsset struct {
+	Asset   string
+	Balance decimal.Decimal
+	Value   decimal.Decimal
+	Path    []ConversionStep
+}
+
+// PortfolioValuation the value of balances in a quote currency, the assets
+// are in name order and those without a pair to the quote are in Unpriced
+type PortfolioValuation struct {
+	Quote    string
+	Assets   []PortfolioAsset
+	Total    decimal.Decimal
+	Unpriced []string
+}
+
+// ValuePortfolio value balances keyed by Kraken asset name, as returned by the
+// "/private/Balance" API endpoint, in a quote currency given by any name
+// accepted by ToKrakenAsset. Each asset is converted with a pair to the quote,
+// or through one intermediate by FindConversionPath, at the best bid when
+// selling and ask when buying, with the tickers of every pair requested
+// together. Zero and negative balances are skipped
+func ValuePortfolio(ctx context.Context, client Client, balances map[string]decimal.Decimal, quote string) (PortfolioValuation, error) {
+	if client == nil {
+		return PortfolioValuation{}, fmt.Errorf("client is required")
+	}
+
+	if quote == "" {
+		return PortfolioValuation{}, fmt.Errorf("quote is required")
+	}
+
+	pairs, err := client.AssetPairs(ctx, AssetPairInfoInfo)
+	if err != nil {
+		return PortfolioValuation{}, err
+	}
+
+	index, err := NewPairIndex(pairs)
+	if err != nil {
+		return PortfolioValuation{}, err
+	}
+
+	valuation := PortfolioValuation{Quote: ToKrakenAsset(quote), Assets: []PortfolioAsset{}, Unpriced: []string{}}
+	assets := make([]string, 0, len(balances))
+	for asset, balance := range balances {
+		if balance.IsPositive() {
+			assets = append(assets, asset)
+		}
+	}
+	sort.Strings(assets)
+
+	paths := make(map[string][]ConversionStep, len(assets))
+	tickerPairs := []string{}
+	for _, asset := range assets {
+		path, err := index.FindConversionPath(asset, valuation.Quote, 2)
+		if err != nil {
+			valuation.Unpriced = append(valuation.Unpriced, asset)
+			continue
+		}
+
+		paths[asset] = path
+		for _, step := range path {
+			if !containsString(tickerPairs, step.Pair) {
+				tickerPairs = append(tickerPairs, step.Pair)
+			}
+		}
+	}
+
+	tickers := Tickers{Result: map[string]Ticker{}}
+	if len(tickerPairs) != 0 {
+		if tickers, err = client.Tickers(ctx, tickerPairs...); err != nil {
+			return PortfolioValuation{}, err
+		}
+
+		if len(tickers.Errors) != 0 {
+			return PortfolioValuation{}, tickers.Errors[0]
+		}
+	}
+
+	for _, asset := range assets {
+		path, ok := paths[asset]
+		if !ok {
+			continue
+		}
+
+		value := balances[asset]
+		for _, step := range path {
+			ticker, found := tickers.Result[step.Pair]
+			if value, ok = step.convert(value, ticker); !found || !ok {
+				break
+			}
+		}
+
+		if !ok {
+			valuation.Unpriced = append(valuation.Unpriced, asset)
+			continue
+		}
+
+		valuation.Assets = append(valuation.Assets, PortfolioAsset{Asset: asset, Balance: balances[asset], Value: value, Path: path})
+		valuation.Total = valuation.Total.Add(value)
+	}
+	sort.Strings(valuation.Unpriced)
+
+	return valuation, nil
+}
@@ -0,0 +1,152 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+// portfolioPairs an AssetPairs result with pairs to price assets in USD
+// directly, through XBT and by buying USD with JPY
+func portfolioPairs() kraken.AssetPairs {
+	return kraken.AssetPairs{
+		Pairs: map[string]kraken.AssetPair{
+			"XXBTZUSD":   {AltName: "XBTUSD", Base: "XXBT", Quote: "ZUSD"},
+			"XXBTZUSD.d": {AltName: "XBTUSD.d", Base: "XXBT", Quote: "ZUSD"},
+			"XETHXXBT":   {AltName: "ETHXBT", Base: "XETH", Quote: "XXBT"},
+			"ZUSDZJPY":   {AltName: "USDJPY", Base: "ZUSD", Quote: "ZJPY"},
+			"ADAUSD":     {AltName: "ADAUSD", Base: "ADA", Quote: "ZUSD"},
+		},
+	}
+}
+
+// portfolioTicker a ticker with a best ask and bid price
+func portfolioTicker(ask, bid string) kraken.Ticker {
+	return kraken.Ticker{
+		Ask: kraken.AskBid{Price: decimal.RequireFromString(ask)},
+		Bid: kraken.AskBid{Price: decimal.RequireFromString(bid)},
+	}
+}
+
+func TestValuePortfolio(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.ReturnAssetPairs(portfolioPairs(), nil)
+	m.ReturnTickers(kraken.Tickers{
+		Result: map[string]kraken.Ticker{
+			"XXBTZUSD": portfolioTicker("30010", "30000"),
+			"XETHXXBT": portfolioTicker("0.051", "0.05"),
+			"ZUSDZJPY": portfolioTicker("150", "149"),
+			"ADAUSD":   portfolioTicker("0", "0"),
+		},
+	}, nil)
+
+	balances := map[string]decimal.Decimal{
+		"XXBT": decimal.RequireFromString("0.5"),
+		"XETH": decimal.RequireFromString("2"),
+		"ZJPY": decimal.RequireFromString("15000"),
+		"ZUSD": decimal.RequireFromString("50"),
+		"ADA":  decimal.RequireFromString("10"),
+		"DOT":  decimal.Zero,
+		"FOO":  decimal.RequireFromString("5"),
+	}
+
+	valuation, err := kraken.ValuePortfolio(context.Background(), m, balances, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := kraken.PortfolioValuation{
+		Quote: "ZUSD",
+		Assets: []kraken.PortfolioAsset{
+			{
+				Asset:   "XETH",
+				Balance: balances["XETH"],
+				Value:   decimal.RequireFromString("3000"),
+				Path: []kraken.ConversionStep{
+					{Pair: "XETHXXBT", From: "XETH", To: "XXBT", Sell: true},
+					{Pair: "XXBTZUSD", From: "XXBT", To: "ZUSD", Sell: true},
+				},
+			},
+			{
+				Asset:   "XXBT",
+				Balance: balances["XXBT"],
+				Value:   decimal.RequireFromString("15000"),
+				Path:    []kraken.ConversionStep{{Pair: "XXBTZUSD", From: "XXBT", To: "ZUSD", Sell: true}},
+			},
+			{
+				Asset:   "ZJPY",
+				Balance: balances["ZJPY"],
+				Value:   decimal.RequireFromString("100"),
+				Path:    []kraken.ConversionStep{{Pair: "ZUSDZJPY", From: "ZJPY", To: "ZUSD"}},
+			},
+			{Asset: "ZUSD", Balance: balances["ZUSD"], Value: balances["ZUSD"], Path: []kraken.ConversionStep{}},
+		},
+		Total:    decimal.RequireFromString("18150"),
+		Unpriced: []string{"ADA", "FOO"},
+	}
+
+	if diff := deep.Equal(valuation, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// the tickers of every pair are requested together
+	if calls := m.CallsTo("Tickers"); len(calls) != 1 {
+		t.Fatalf("EXPECTED: 1\nACTUAL: %v", len(calls))
+	}
+
+	pairs := []string{"ADAUSD", "XETHXXBT", "XXBTZUSD", "ZUSDZJPY"}
+	if diff := deep.Equal(m.CallsTo("Tickers")[0].Args[0], pairs); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestValuePortfolioErrors(t *testing.T) {
+	balances := map[string]decimal.Decimal{"XXBT": decimal.RequireFromString("1")}
+
+	tcs := []struct {
+		name    string
+		pairs   kraken.AssetPairs
+		tickers kraken.Tickers
+		quote   string
+		err     error
+	}{
+		{name: "AssetPairs", pairs: kraken.AssetPairs{Errors: []error{kraken.ErrServiceUnavailable}}, quote: "USD", err: kraken.ErrServiceUnavailable},
+		{name: "Tickers", pairs: portfolioPairs(), tickers: kraken.Tickers{Errors: []error{kraken.ErrUnknownAssetPair}}, quote: "USD", err: kraken.ErrUnknownAssetPair},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := krakentest.NewMockClient(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.ReturnAssetPairs(tc.pairs, nil)
+			m.ReturnTickers(tc.tickers, nil)
+
+			if _, err := kraken.ValuePortfolio(context.Background(), m, balances, tc.quote); !errors.Is(err, tc.err) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.err, err)
+			}
+		})
+	}
+
+	if _, err := kraken.ValuePortfolio(context.Background(), nil, balances, "USD"); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kraken.ValuePortfolio(context.Background(), m, balances, ""); err == nil {
+		t.Error("EXPECTED: quote is required\nACTUAL: <nil>")
+	}
+}
@@ -0,0 +1,535 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Clock provides the current time and timers, allowing time to be controlled
+// in tests
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// AccountTier the verification tier of a Kraken account, which determines
+// the private API rate limits
+type AccountTier byte
+
+// String return a string value of the account tier
+func (t AccountTier) String() string {
+	switch t {
+	case AccountTierStarter:
+		return "starter"
+	case AccountTierIntermediate:
+		return "intermediate"
+	case AccountTierPro:
+		return "pro"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// AccountTierStarter enum representing a starter account
+	AccountTierStarter = iota
+	// AccountTierIntermediate enum representing an intermediate account
+	AccountTierIntermediate
+	// AccountTierPro enum representing a pro account
+	AccountTierPro
+	// AccountTierUnknown enum representing an unknown account tier
+	AccountTierUnknown
+)
+
+// RateLimitedClient handles rate limiting of calls to client functions.
+// Private calls follow the Kraken call counter model, each call increases a
+// counter which decays over time and calls block while the counter would
+// exceed the maximum of the account tier. Ledger and trade history calls cost
+// two, order placement and cancellation are limited separately by Kraken and
+// cost nothing. Public calls are spaced by a minimum interval
+type RateLimitedClient struct {
+	inner Client
+	clock Clock
+
+	mu             sync.Mutex
+	counter        float64
+	counterUpdated time.Time
+	maxCounter     float64
+	decayPerSecond float64
+	publicInterval time.Duration
+	lastPublic     time.Time
+}
+
+var _ Client = (*RateLimitedClient)(nil)
+
+// NewRateLimitedClient helper function for creating a new rate limited client
+// for an account tier, public calls are limited to one per second unless
+// otherwise given
+func NewRateLimitedClient(inner Client, tier AccountTier, opts ...RateLimitOption) (*RateLimitedClient, error) {
+	c := RateLimitedClient{
+		inner:          inner,
+		clock:          systemClock{},
+		publicInterval: time.Second,
+	}
+
+	switch tier {
+	case AccountTierStarter:
+		c.maxCounter, c.decayPerSecond = 15, 0.33
+	case AccountTierIntermediate:
+		c.maxCounter, c.decayPerSecond = 20, 0.5
+	case AccountTierPro:
+		c.maxCounter, c.decayPerSecond = 20, 1
+	default:
+		return nil, fmt.Errorf("unknown account tier")
+	}
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+// waitPrivate block until the call counter has decayed enough for a call of
+// the given cost, the cost is added to the counter before returning
+func (c *RateLimitedClient) waitPrivate(ctx context.Context, cost float64) error {
+	for {
+		c.mu.Lock()
+		now := c.clock.Now()
+		if !c.counterUpdated.IsZero() {
+			c.counter -= now.Sub(c.counterUpdated).Seconds() * c.decayPerSecond
+			if c.counter < 0 {
+				c.counter = 0
+			}
+		}
+		c.counterUpdated = now
+
+		// tolerate floating point error in the decay of the counter
+		excess := c.counter + cost - c.maxCounter
+		if excess <= 1e-9 {
+			c.counter += cost
+			c.mu.Unlock()
+
+			return nil
+		}
+		c.mu.Unlock()
+
+		wait := time.Duration(math.Ceil(excess / c.decayPerSecond * float64(time.Second)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock.After(wait):
+		}
+	}
+}
+
+// waitPublic block until the minimum interval since the last public call has
+// passed
+func (c *RateLimitedClient) waitPublic(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		now := c.clock.Now()
+		next := c.lastPublic.Add(c.publicInterval)
+		if c.lastPublic.IsZero() || !now.Before(next) {
+			c.lastPublic = now
+			c.mu.Unlock()
+
+			return nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock.After(next.Sub(now)):
+		}
+	}
+}
+
+// Time handles rate limiting for client Time function
+func (c *RateLimitedClient) Time(ctx context.Context) (Time, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return Time{}, err
+	}
+
+	return c.inner.Time(ctx)
+}
+
+// Status handles rate limiting for client Status function
+func (c *RateLimitedClient) Status(ctx context.Context) (SystemStatus, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return SystemStatus{}, err
+	}
+
+	return c.inner.Status(ctx)
+}
+
+// Assets handles rate limiting for client Assets function
+func (c *RateLimitedClient) Assets(ctx context.Context) (Assets, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return Assets{}, err
+	}
+
+	return c.inner.Assets(ctx)
+}
+
+// AssetPairs handles rate limiting for client AssetPairs function
+func (c *RateLimitedClient) AssetPairs(ctx context.Context, info AssetPairInfo, pairs ...string) (AssetPairs, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return AssetPairs{}, err
+	}
+
+	return c.inner.AssetPairs(ctx, info, pairs...)
+}
+
+// Tickers handles rate limiting for client Tickers function
+func (c *RateLimitedClient) Tickers(ctx context.Context, pairs ...string) (Tickers, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return Tickers{}, err
+	}
+
+	return c.inner.Tickers(ctx, pairs...)
+}
+
+// OHLC handles rate limiting for client OHLC function
+func (c *RateLimitedClient) OHLC(ctx context.Context, interval OHLCInterval, since *uint64, pairs ...string) (OHLCs, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return OHLCs{}, err
+	}
+
+	return c.inner.OHLC(ctx, interval, since, pairs...)
+}
+
+// OrderBook handles rate limiting for client OrderBook function
+func (c *RateLimitedClient) OrderBook(ctx context.Context, count uint, pairs ...string) (OrderBook, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return OrderBook{}, err
+	}
+
+	return c.inner.OrderBook(ctx, count, pairs...)
+}
+
+// RecentTrades handles rate limiting for client RecentTrades function
+func (c *RateLimitedClient) RecentTrades(ctx context.Context, since *uint64, pairs ...string) (RecentTrades, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return RecentTrades{}, err
+	}
+
+	return c.inner.RecentTrades(ctx, since, pairs...)
+}
+
+// RecentSpreads handles rate limiting for client RecentSpreads function
+func (c *RateLimitedClient) RecentSpreads(ctx context.Context, since *uint64, pairs ...string) (RecentSpreads, error) {
+	if err := c.waitPublic(ctx); err != nil {
+		return RecentSpreads{}, err
+	}
+
+	return c.inner.RecentSpreads(ctx, since, pairs...)
+}
+
+// TradeBalance handles rate limiting for client TradeBalance function
+func (c *RateLimitedClient) TradeBalance(ctx context.Context, asset string) (TradeBalance, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return TradeBalance{}, err
+	}
+
+	return c.inner.TradeBalance(ctx, asset)
+}
+
+// OpenOrders handles rate limiting for client OpenOrders function
+func (c *RateLimitedClient) OpenOrders(ctx context.Context, trades bool, userref *int32) (OpenOrders, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return OpenOrders{}, err
+	}
+
+	return c.inner.OpenOrders(ctx, trades, userref)
+}
+
+// ClosedOrders handles rate limiting for client ClosedOrders function
+func (c *RateLimitedClient) ClosedOrders(ctx context.Context, query ClosedOrdersQuery) (ClosedOrders, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return ClosedOrders{}, err
+	}
+
+	return c.inner.ClosedOrders(ctx, query)
+}
+
+// QueryOrders handles rate limiting for client QueryOrders function
+func (c *RateLimitedClient) QueryOrders(ctx context.Context, trades bool, userref *int32, txids ...string) (OrdersInfo, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return OrdersInfo{}, err
+	}
+
+	return c.inner.QueryOrders(ctx, trades, userref, txids...)
+}
+
+// TradesHistory handles rate limiting for client TradesHistory function
+func (c *RateLimitedClient) TradesHistory(ctx context.Context, query TradesHistoryQuery) (TradesHistory, error) {
+	if err := c.waitPrivate(ctx, 2); err != nil {
+		return TradesHistory{}, err
+	}
+
+	return c.inner.TradesHistory(ctx, query)
+}
+
+// OpenPositions handles rate limiting for client OpenPositions function
+func (c *RateLimitedClient) OpenPositions(ctx context.Context, docalcs bool, txids ...string) (OpenPositions, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return OpenPositions{}, err
+	}
+
+	return c.inner.OpenPositions(ctx, docalcs, txids...)
+}
+
+// Ledgers handles rate limiting for client Ledgers function
+func (c *RateLimitedClient) Ledgers(ctx context.Context, query LedgersQuery) (Ledgers, error) {
+	if err := c.waitPrivate(ctx, 2); err != nil {
+		return Ledgers{}, err
+	}
+
+	return c.inner.Ledgers(ctx, query)
+}
+
+// QueryLedgers handles rate limiting for client QueryLedgers function
+func (c *RateLimitedClient) QueryLedgers(ctx context.Context, ids ...string) (LedgersInfo, error) {
+	if err := c.waitPrivate(ctx, 2); err != nil {
+		return LedgersInfo{}, err
+	}
+
+	return c.inner.QueryLedgers(ctx, ids...)
+}
+
+// TradeVolume handles rate limiting for client TradeVolume function
+func (c *RateLimitedClient) TradeVolume(ctx context.Context, pairs ...string) (TradeVolume, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return TradeVolume{}, err
+	}
+
+	return c.inner.TradeVolume(ctx, pairs...)
+}
+
+// AddOrder handles rate limiting for client AddOrder function
+func (c *RateLimitedClient) AddOrder(ctx context.Context, req OrderRequest) (AddOrderResult, error) {
+	return c.inner.AddOrder(ctx, req)
+}
+
+// CancelOrder handles rate limiting for client CancelOrder function
+func (c *RateLimitedClient) CancelOrder(ctx context.Context, txid string) (CancelResult, error) {
+	return c.inner.CancelOrder(ctx, txid)
+}
+
+// CancelAllOrders handles rate limiting for client CancelAllOrders function
+func (c *RateLimitedClient) CancelAllOrders(ctx context.Context) (CancelResult, error) {
+	return c.inner.CancelAllOrders(ctx)
+}
+
+// CancelAllOrdersAfter handles rate limiting for client CancelAllOrdersAfter
+// function
+func (c *RateLimitedClient) CancelAllOrdersAfter(ctx context.Context, timeout time.Duration) (CancelAllOrdersAfterResult, error) {
+	return c.inner.CancelAllOrdersAfter(ctx, timeout)
+}
+
+// EditOrder handles rate limiting for client EditOrder function
+func (c *RateLimitedClient) EditOrder(ctx context.Context, req EditOrderRequest) (EditOrderResult, error) {
+	return c.inner.EditOrder(ctx, req)
+}
+
+// DepositMethods handles rate limiting for client DepositMethods function
+func (c *RateLimitedClient) DepositMethods(ctx context.Context, asset string) (DepositMethods, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return DepositMethods{}, err
+	}
+
+	return c.inner.DepositMethods(ctx, asset)
+}
+
+// DepositAddresses handles rate limiting for client DepositAddresses function
+func (c *RateLimitedClient) DepositAddresses(ctx context.Context, asset, method string, new bool) (DepositAddresses, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return DepositAddresses{}, err
+	}
+
+	return c.inner.DepositAddresses(ctx, asset, method, new)
+}
+
+// DepositStatus handles rate limiting for client DepositStatus function
+func (c *RateLimitedClient) DepositStatus(ctx context.Context, asset, method string) (DepositStatus, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return DepositStatus{}, err
+	}
+
+	return c.inner.DepositStatus(ctx, asset, method)
+}
+
+// WithdrawInfo handles rate limiting for client WithdrawInfo function
+func (c *RateLimitedClient) WithdrawInfo(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawInfo, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return WithdrawInfo{}, err
+	}
+
+	return c.inner.WithdrawInfo(ctx, asset, key, amount)
+}
+
+// Withdraw handles rate limiting for client Withdraw function
+func (c *RateLimitedClient) Withdraw(ctx context.Context, asset, key string, amount decimal.Decimal) (WithdrawResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return WithdrawResult{}, err
+	}
+
+	return c.inner.Withdraw(ctx, asset, key, amount)
+}
+
+// WithdrawStatus handles rate limiting for client WithdrawStatus function
+func (c *RateLimitedClient) WithdrawStatus(ctx context.Context, asset, method string) (WithdrawStatus, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return WithdrawStatus{}, err
+	}
+
+	return c.inner.WithdrawStatus(ctx, asset, method)
+}
+
+// WithdrawCancel handles rate limiting for client WithdrawCancel function
+func (c *RateLimitedClient) WithdrawCancel(ctx context.Context, asset, refid string) (WithdrawCancelResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return WithdrawCancelResult{}, err
+	}
+
+	return c.inner.WithdrawCancel(ctx, asset, refid)
+}
+
+// WalletTransfer handles rate limiting for client WalletTransfer function
+func (c *RateLimitedClient) WalletTransfer(ctx context.Context, asset string, from, to Wallet, amount decimal.Decimal) (WalletTransferResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return WalletTransferResult{}, err
+	}
+
+	return c.inner.WalletTransfer(ctx, asset, from, to, amount)
+}
+
+// EarnStrategies handles rate limiting for client EarnStrategies function
+func (c *RateLimitedClient) EarnStrategies(ctx context.Context, asset, cursor string) (EarnStrategies, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return EarnStrategies{}, err
+	}
+
+	return c.inner.EarnStrategies(ctx, asset, cursor)
+}
+
+// EarnAllocate handles rate limiting for client EarnAllocate function
+func (c *RateLimitedClient) EarnAllocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return EarnAllocationResult{}, err
+	}
+
+	return c.inner.EarnAllocate(ctx, strategyID, amount)
+}
+
+// EarnDeallocate handles rate limiting for client EarnDeallocate function
+func (c *RateLimitedClient) EarnDeallocate(ctx context.Context, strategyID string, amount decimal.Decimal) (EarnAllocationResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return EarnAllocationResult{}, err
+	}
+
+	return c.inner.EarnDeallocate(ctx, strategyID, amount)
+}
+
+// EarnAllocationStatus handles rate limiting for client EarnAllocationStatus
+// function
+func (c *RateLimitedClient) EarnAllocationStatus(ctx context.Context, strategyID string) (EarnAllocationStatus, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return EarnAllocationStatus{}, err
+	}
+
+	return c.inner.EarnAllocationStatus(ctx, strategyID)
+}
+
+// EarnAllocations handles rate limiting for client EarnAllocations function
+func (c *RateLimitedClient) EarnAllocations(ctx context.Context) (EarnAllocations, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return EarnAllocations{}, err
+	}
+
+	return c.inner.EarnAllocations(ctx)
+}
+
+// AddExport handles rate limiting for client AddExport function
+func (c *RateLimitedClient) AddExport(ctx context.Context, req ExportRequest) (AddExportResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return AddExportResult{}, err
+	}
+
+	return c.inner.AddExport(ctx, req)
+}
+
+// ExportStatus handles rate limiting for client ExportStatus function
+func (c *RateLimitedClient) ExportStatus(ctx context.Context, report ExportReport) (ExportStatus, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return ExportStatus{}, err
+	}
+
+	return c.inner.ExportStatus(ctx, report)
+}
+
+// RetrieveExport handles rate limiting for client RetrieveExport function
+func (c *RateLimitedClient) RetrieveExport(ctx context.Context, id string) (io.ReadCloser, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	return c.inner.RetrieveExport(ctx, id)
+}
+
+// RemoveExport handles rate limiting for client RemoveExport function
+func (c *RateLimitedClient) RemoveExport(ctx context.Context, id string, removal ExportRemoval) (RemoveExportResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return RemoveExportResult{}, err
+	}
+
+	return c.inner.RemoveExport(ctx, id, removal)
+}
+
+// CreateSubaccount handles rate limiting for client CreateSubaccount function
+func (c *RateLimitedClient) CreateSubaccount(ctx context.Context, username, email string) (CreateSubaccountResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return CreateSubaccountResult{}, err
+	}
+
+	return c.inner.CreateSubaccount(ctx, username, email)
+}
+
+// AccountTransfer handles rate limiting for client AccountTransfer function
+func (c *RateLimitedClient) AccountTransfer(ctx context.Context, asset string, amount decimal.Decimal, fromUser, toUser string) (AccountTransferResult, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return AccountTransferResult{}, err
+	}
+
+	return c.inner.AccountTransfer(ctx, asset, amount, fromUser, toUser)
+}
+
+// GetWebSocketsToken handles rate limiting for client GetWebSocketsToken
+// function
+func (c *RateLimitedClient) GetWebSocketsToken(ctx context.Context) (WebSocketsToken, error) {
+	if err := c.waitPrivate(ctx, 1); err != nil {
+		return WebSocketsToken{}, err
+	}
+
+	return c.inner.GetWebSocketsToken(ctx)
+}
@@ -0,0 +1,150 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+)
+
+// fakeClock a clock which advances instantly when waited on, recording each
+// wait
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+	block bool
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	if c.block {
+		return ch
+	}
+
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch <- c.now
+
+	return ch
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	tcs := []struct {
+		name     string
+		tier     kraken.AccountTier
+		calls    func(c *kraken.RateLimitedClient, clock *fakeClock)
+		expected []time.Duration
+	}{
+		{
+			name: "StarterCounterExhausted",
+			tier: kraken.AccountTierStarter,
+			calls: func(c *kraken.RateLimitedClient, clock *fakeClock) {
+				for i := 0; i < 16; i++ {
+					c.TradeBalance(context.Background(), "")
+				}
+			},
+			expected: []time.Duration{3030303031},
+		},
+		{
+			name: "CounterDecay",
+			tier: kraken.AccountTierIntermediate,
+			calls: func(c *kraken.RateLimitedClient, clock *fakeClock) {
+				for i := 0; i < 20; i++ {
+					c.OpenOrders(context.Background(), false, nil)
+				}
+
+				// decays the counter from 20 to 15
+				clock.now = clock.now.Add(10 * time.Second)
+
+				for i := 0; i < 6; i++ {
+					c.OpenOrders(context.Background(), false, nil)
+				}
+			},
+			expected: []time.Duration{2 * time.Second},
+		},
+		{
+			name: "LedgersCostTwo",
+			tier: kraken.AccountTierPro,
+			calls: func(c *kraken.RateLimitedClient, clock *fakeClock) {
+				for i := 0; i < 11; i++ {
+					c.Ledgers(context.Background(), kraken.LedgersQuery{})
+				}
+			},
+			expected: []time.Duration{2 * time.Second},
+		},
+		{
+			name: "TradingNotCounted",
+			tier: kraken.AccountTierStarter,
+			calls: func(c *kraken.RateLimitedClient, clock *fakeClock) {
+				for i := 0; i < 30; i++ {
+					c.CancelOrder(context.Background(), "OYVGEW-VYV5B-UUEXSK")
+				}
+			},
+		},
+		{
+			name: "PublicInterval",
+			tier: kraken.AccountTierStarter,
+			calls: func(c *kraken.RateLimitedClient, clock *fakeClock) {
+				c.Time(context.Background())
+				c.Time(context.Background())
+
+				clock.now = clock.now.Add(300 * time.Millisecond)
+				c.Tickers(context.Background())
+			},
+			expected: []time.Duration{time.Second, 700 * time.Millisecond},
+		},
+	}
+
+	inner, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(1616663618, 0)}
+
+			c, err := kraken.NewRateLimitedClient(inner, tc.tier, kraken.RateLimitWithClock(clock))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.calls(c, clock)
+
+			if diff := deep.Equal(tc.expected, clock.waits); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRateLimitedClientContext(t *testing.T) {
+	inner, err := kraken.NewHTTPClient(kraken.HTTPClientDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fakeClock{now: time.Unix(1616663618, 0), block: true}
+	c, err := kraken.NewRateLimitedClient(inner, kraken.AccountTierStarter, kraken.RateLimitWithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Time(context.Background()); !errors.Is(err, kraken.ErrDryRun) {
+		t.Fatalf("EXPECTED: %s\nACTUAL: %s", kraken.ErrDryRun, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.Time(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("EXPECTED: %s\nACTUAL: %s", context.Canceled, err)
+	}
+}
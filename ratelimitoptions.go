@@ -0,0 +1,37 @@
+package kraken
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitOption options used when creating a new RateLimitedClient
+type RateLimitOption func(c *RateLimitedClient) error
+
+// RateLimitWithClock set the clock used to track the call counter and public
+// call interval
+func RateLimitWithClock(clock Clock) RateLimitOption {
+	return RateLimitOption(func(c *RateLimitedClient) error {
+		if clock == nil {
+			return fmt.Errorf("clock is required")
+		}
+
+		c.clock = clock
+
+		return nil
+	})
+}
+
+// RateLimitWithPublicInterval set the minimum interval between public calls,
+// an interval of zero disables limiting of public calls
+func RateLimitWithPublicInterval(interval time.Duration) RateLimitOption {
+	return RateLimitOption(func(c *RateLimitedClient) error {
+		if interval < 0 {
+			return fmt.Errorf("interval must not be negative")
+		}
+
+		c.publicInterval = interval
+
+		return nil
+	})
+}
@@ -1,5 +1,15 @@
 package kraken
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type responsePublicTime struct {
 	Errors []string                 `json:"error"`
 	Result responsePublicTimeResult `json:"result"`
@@ -33,61 +43,74 @@ type responsePublicAssetsResultAsset struct {
 }
 
 type responsePublicAssetPairs struct {
-	Errors []string
-	Result map[string]responsePublicAssetPairResultPair
+	Errors []string                                     `json:"error"`
+	Result map[string]responsePublicAssetPairResultPair `json:"result"`
 }
 
 type responsePublicAssetPairResultPair struct {
-	AltName           string      `json:"altname"`
-	WSName            string      `json:"wsname"`
-	AClassBase        string      `json:"aclass_base"`
-	Base              string      `json:"base"`
-	AClassQuote       string      `json:"aclass_quote"`
-	Quote             string      `json:"quote"`
-	Lot               string      `json:"lot"`
-	PairDecimals      int         `json:"pair_decimals"`
-	LotDecimals       int         `json:"lot_decimals"`
-	LotMultiplier     int         `json:"lot_multiplier"`
-	LeverageBuy       []int       `json:"leverage_buy"`
-	LeverageSell      []int       `json:"leverage_sell"`
-	Fees              [][]float32 `json:"fees"`
-	FeesMaker         [][]float32 `json:"fees_maker"`
-	FeeVolumeCurrency string      `json:"fee_volume_currency"`
-	MarginCalls       int         `json:"margin_call"`
-	MarginStop        int         `json:"margin_stop"`
-	OrderMin          float32     `json:"ordermin"`
+	AltName            string          `json:"altname"`
+	WSName             string          `json:"wsname"`
+	AClassBase         string          `json:"aclass_base"`
+	Base               string          `json:"base"`
+	AClassQuote        string          `json:"aclass_quote"`
+	Quote              string          `json:"quote"`
+	Lot                string          `json:"lot"`
+	PairDecimals       int             `json:"pair_decimals"`
+	LotDecimals        int             `json:"lot_decimals"`
+	LotMultiplier      int             `json:"lot_multiplier"`
+	LeverageBuy        []int           `json:"leverage_buy"`
+	LeverageSell       []int           `json:"leverage_sell"`
+	Fees               [][]json.Number `json:"fees"`
+	FeesMaker          [][]json.Number `json:"fees_maker"`
+	FeeVolumeCurrency  string          `json:"fee_volume_currency"`
+	MarginCalls        int             `json:"margin_call"`
+	MarginStop         int             `json:"margin_stop"`
+	MarginLevel        int             `json:"margin_level"`
+	OrderMin           json.Number     `json:"ordermin"`
+	CostMin            string          `json:"costmin"`
+	TickSize           string          `json:"tick_size"`
+	Status             string          `json:"status"`
+	LongPositionLimit  int             `json:"long_position_limit"`
+	ShortPositionLimit int             `json:"short_position_limit"`
 }
 
 type responsePublicTicker struct {
-	Errors []string
-	Result map[string]responsePublicTickerInformation
+	Errors []string                                   `json:"error"`
+	Result map[string]responsePublicTickerInformation `json:"result"`
 }
 
 type responsePublicTickerInformation struct {
-	Ask                        []string `json:"a"`
-	Bid                        []string `json:"b"`
-	LastClose                  []string `json:"c"`
-	Volume                     []string `json:"v"`
-	VolumeWeightedAveragePrice []string `json:"p"`
-	NumberOfTrades             []uint64 `json:"t"`
-	Low                        []string `json:"l"`
-	High                       []string `json:"h"`
-	Open                       string   `json:"o"`
+	Ask                        flexibleDecimals `json:"a"`
+	Bid                        flexibleDecimals `json:"b"`
+	LastClose                  flexibleDecimals `json:"c"`
+	Volume                     flexibleDecimals `json:"v"`
+	VolumeWeightedAveragePrice flexibleDecimals `json:"p"`
+	NumberOfTrades             []uint64         `json:"t"`
+	Low                        flexibleDecimals `json:"l"`
+	High                       flexibleDecimals `json:"h"`
+	Open                       flexibleDecimal  `json:"o"`
 }
 
 type responsePublicOHLC struct {
-	Errors []string
-	Result map[string]interface{}
+	Errors []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
 }
 
+// responsePublicOHLCValue is a [time, open, high, low, close, vwap, volume,
+// count] row
 type responsePublicOHLCValue struct {
-	Timestamp uint64
-	Open      string
-	High      string
-	Low       string
-	Close     string
-	Volume    string
-	Count     uint64
+	Time                       flexibleTime
+	Open                       flexibleDecimal
+	High                       flexibleDecimal
+	Low                        flexibleDecimal
+	Close                      flexibleDecimal
+	VolumeWeightedAveragePrice flexibleDecimal
+	Volume                     flexibleDecimal
+	Count                      uint64
+}
+
+func (v *responsePublicOHLCValue) UnmarshalJSON(data []byte) error {
+	return unmarshalTuple(data, 8, &v.Time, &v.Open, &v.High, &v.Low, &v.Close, &v.VolumeWeightedAveragePrice, &v.Volume, &v.Count)
 }
 
 type responsePublicOrderBook struct {
@@ -96,16 +119,849 @@ type responsePublicOrderBook struct {
 }
 
 type responsePublicOrderBookResultAskBid struct {
-	Asks [][]interface{} `json:"asks"`
-	Bids [][]interface{} `json:"bids"`
+	Asks [][]json.Number `json:"asks"`
+	Bids [][]json.Number `json:"bids"`
 }
 
 type responsePublicRecentTrades struct {
-	Error  []string               `json:"error"`
-	Result map[string]interface{} `json:"result"`
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// responsePublicRecentTradeValue is a [price, volume, time, action, type,
+// miscellaneous, trade id] row, older rows omit the trade ID
+type responsePublicRecentTradeValue struct {
+	Price         flexibleDecimal
+	Volume        flexibleDecimal
+	Time          flexibleTime
+	Action        string
+	Type          string
+	Miscellaneous string
+	TradeID       uint64
+}
+
+func (v *responsePublicRecentTradeValue) UnmarshalJSON(data []byte) error {
+	return unmarshalTuple(data, 6, &v.Price, &v.Volume, &v.Time, &v.Action, &v.Type, &v.Miscellaneous, &v.TradeID)
 }
 
 type responsePublicRecentSpreads struct {
-	Error  []string               `json:"error"`
-	Result map[string]interface{} `json:"result"`
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// responsePublicRecentSpreadValue is a [time, bid, ask] row
+type responsePublicRecentSpreadValue struct {
+	Time flexibleTime
+	Bid  flexibleDecimal
+	Ask  flexibleDecimal
+}
+
+func (v *responsePublicRecentSpreadValue) UnmarshalJSON(data []byte) error {
+	return unmarshalTuple(data, 3, &v.Time, &v.Bid, &v.Ask)
+}
+
+// unmarshalTuple unmarshal a JSON array positionally into the pointers in
+// fields, the array must hold at least required values and any values beyond
+// fields are ignored. Rows are split by hand rather than through
+// json.Unmarshal as large OHLC and trade payloads hold thousands of them.
+func unmarshalTuple(data []byte, required int, fields ...interface{}) error {
+	values, err := eachTupleValue(data, func(i int, value []byte) error {
+		if i < len(fields) {
+			return unmarshalTupleValue(value, fields[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if values < required {
+		return fmt.Errorf("%d values, expected at least %d", values, required)
+	}
+
+	return nil
+}
+
+// eachTupleValue call fn with each value of a JSON array and its index,
+// returning the number of values
+func eachTupleValue(data []byte, fn func(i int, value []byte) error) (int, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '[' || data[len(data)-1] != ']' {
+		return 0, fmt.Errorf("expected an array")
+	}
+
+	values := 0
+	depth, quoted, escaped := 0, false, false
+	start := 1
+	for i := 1; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case escaped:
+			escaped = false
+			continue
+		case quoted:
+			escaped = c == '\\'
+			quoted = c != '"'
+			continue
+		case c == '"':
+			quoted = true
+			continue
+		case c == '[' || c == '{':
+			depth++
+			continue
+		case c == ']' && depth > 0, c == '}':
+			depth--
+			continue
+		case c != ',' && c != ']', depth > 0:
+			continue
+		}
+
+		// c is a top level separator or the closing bracket
+		value := bytes.TrimSpace(data[start:i])
+		start = i + 1
+		if len(value) == 0 {
+			break
+		}
+
+		if err := fn(values, value); err != nil {
+			return 0, err
+		}
+		values++
+	}
+
+	return values, nil
+}
+
+// unmarshalTupleValue unmarshal a single tuple value, strings and numbers
+// are handled directly and anything else falls back to json.Unmarshal
+func unmarshalTupleValue(value []byte, field interface{}) error {
+	switch f := field.(type) {
+	case *string:
+		if value[0] == '"' && bytes.IndexByte(value, '\\') < 0 {
+			*f = string(value[1 : len(value)-1])
+			return nil
+		}
+	case *json.Number:
+		if value[0] == '-' || (value[0] >= '0' && value[0] <= '9') {
+			*f = json.Number(value)
+			return nil
+		}
+	case *uint64:
+		if n, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			*f = n
+			return nil
+		}
+	case json.Unmarshaler:
+		return f.UnmarshalJSON(value)
+	}
+
+	return json.Unmarshal(value, field)
+}
+
+// flexibleTime a unix timestamp given as either a JSON number or string, whole
+// seconds are parsed directly from the payload and anything else as by
+// Parser.parseTimestamp
+type flexibleTime time.Time
+
+func (t *flexibleTime) UnmarshalJSON(data []byte) error {
+	var seconds int64
+	for i, c := range data {
+		if c < '0' || c > '9' || i == 18 {
+			var n json.Number
+			if err := json.Unmarshal(data, &n); err != nil {
+				return err
+			}
+
+			timestamp, err := (&Parser{}).parseTimestamp(n)
+			if err != nil {
+				return err
+			}
+
+			*t = flexibleTime(timestamp)
+			return nil
+		}
+
+		seconds = seconds*10 + int64(c-'0')
+	}
+
+	*t = flexibleTime(time.Time{})
+	if seconds != 0 {
+		*t = flexibleTime(time.Unix(seconds, 0).UTC())
+	}
+
+	return nil
+}
+
+// flexibleDecimals a JSON array of flexibleDecimals, allocated at its final
+// length
+type flexibleDecimals []flexibleDecimal
+
+func (d *flexibleDecimals) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*d = nil
+		return nil
+	}
+
+	n, err := eachTupleValue(data, func(int, []byte) error { return nil })
+	if err != nil {
+		return err
+	}
+
+	values := make(flexibleDecimals, n)
+	if _, err := eachTupleValue(data, func(i int, value []byte) error {
+		return values[i].UnmarshalJSON(value)
+	}); err != nil {
+		return err
+	}
+
+	*d = values
+	return nil
+}
+
+// flexibleDecimal a decimal given as either a JSON string or number, as Kraken
+// uses both depending on the endpoint version and pair. The decimal is parsed
+// directly from the payload rather than through an intermediate string
+type flexibleDecimal decimal.Decimal
+
+func (d *flexibleDecimal) UnmarshalJSON(data []byte) error {
+	switch {
+	case len(data) > 1 && data[0] == '"' && data[len(data)-1] == '"' && bytes.IndexByte(data, '\\') < 0:
+		data = data[1 : len(data)-1]
+	case len(data) > 0 && (data[0] == '-' || (data[0] >= '0' && data[0] <= '9')):
+	default:
+		// unescape escaped strings, values which are neither strings nor
+		// numbers fail as they would for a json.Number
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+
+		data = []byte(n)
+	}
+
+	v, err := parseDecimal(data)
+	if err != nil {
+		return err
+	}
+
+	*d = flexibleDecimal(v)
+	return nil
+}
+
+type responsePrivateTradeBalance struct {
+	Errors []string                          `json:"error"`
+	Result responsePrivateTradeBalanceResult `json:"result"`
+}
+
+type responsePrivateTradeBalanceResult struct {
+	EquivalentBalance string `json:"eb"`
+	TradeBalance      string `json:"tb"`
+	MarginUsed        string `json:"m"`
+	UnrealizedPnL     string `json:"n"`
+	CostBasis         string `json:"c"`
+	FloatingValuation string `json:"v"`
+	Equity            string `json:"e"`
+	FreeMargin        string `json:"mf"`
+	MarginLevel       string `json:"ml"`
+	UnexecutedValue   string `json:"uv"`
+}
+
+type responsePrivateOpenOrders struct {
+	Errors []string                        `json:"error"`
+	Result responsePrivateOpenOrdersResult `json:"result"`
+}
+
+type responsePrivateOpenOrdersResult struct {
+	Open map[string]responsePrivateOrder `json:"open"`
+}
+
+type responsePrivateClosedOrders struct {
+	Errors []string                          `json:"error"`
+	Result responsePrivateClosedOrdersResult `json:"result"`
+}
+
+type responsePrivateClosedOrdersResult struct {
+	Closed map[string]responsePrivateOrder `json:"closed"`
+	Count  uint64                          `json:"count"`
+}
+
+type responsePrivateQueryOrders struct {
+	Errors []string                        `json:"error"`
+	Result map[string]responsePrivateOrder `json:"result"`
+}
+
+type responsePrivateOrder struct {
+	RefID          string                          `json:"refid"`
+	UserRef        int32                           `json:"userref"`
+	Status         string                          `json:"status"`
+	OpenTime       json.Number                     `json:"opentm"`
+	StartTime      json.Number                     `json:"starttm"`
+	ExpireTime     json.Number                     `json:"expiretm"`
+	CloseTime      json.Number                     `json:"closetm"`
+	Reason         string                          `json:"reason"`
+	Description    responsePrivateOrderDescription `json:"descr"`
+	Volume         string                          `json:"vol"`
+	VolumeExecuted string                          `json:"vol_exec"`
+	Cost           string                          `json:"cost"`
+	Fee            string                          `json:"fee"`
+	Price          string                          `json:"price"`
+	StopPrice      string                          `json:"stopprice"`
+	LimitPrice     string                          `json:"limitprice"`
+	Misc           string                          `json:"misc"`
+	OrderFlags     string                          `json:"oflags"`
+	Trades         []string                        `json:"trades"`
+}
+
+type responsePrivateOrderDescription struct {
+	Pair      string `json:"pair"`
+	Type      string `json:"type"`
+	OrderType string `json:"ordertype"`
+	Price     string `json:"price"`
+	Price2    string `json:"price2"`
+	Leverage  string `json:"leverage"`
+	Order     string `json:"order"`
+	Close     string `json:"close"`
+}
+
+type responsePrivateTradesHistory struct {
+	Errors []string                           `json:"error"`
+	Result responsePrivateTradesHistoryResult `json:"result"`
+}
+
+type responsePrivateTradesHistoryResult struct {
+	Trades map[string]responsePrivateTrade `json:"trades"`
+	Count  uint64                          `json:"count"`
+}
+
+type responsePrivateTrade struct {
+	OrderTxID    string      `json:"ordertxid"`
+	PositionTxID string      `json:"postxid"`
+	Pair         string      `json:"pair"`
+	Time         json.Number `json:"time"`
+	Type         string      `json:"type"`
+	OrderType    string      `json:"ordertype"`
+	Price        string      `json:"price"`
+	Cost         string      `json:"cost"`
+	Fee          string      `json:"fee"`
+	Volume       string      `json:"vol"`
+	Margin       string      `json:"margin"`
+	Misc         string      `json:"misc"`
+}
+
+type responsePrivateOpenPositions struct {
+	Errors []string                           `json:"error"`
+	Result map[string]responsePrivatePosition `json:"result"`
+}
+
+type responsePrivatePosition struct {
+	OrderTxID    string      `json:"ordertxid"`
+	Status       string      `json:"posstatus"`
+	Pair         string      `json:"pair"`
+	Time         json.Number `json:"time"`
+	Type         string      `json:"type"`
+	OrderType    string      `json:"ordertype"`
+	Cost         string      `json:"cost"`
+	Fee          string      `json:"fee"`
+	Volume       string      `json:"vol"`
+	VolumeClosed string      `json:"vol_closed"`
+	Margin       string      `json:"margin"`
+	Value        string      `json:"value"`
+	Net          string      `json:"net"`
+	Terms        string      `json:"terms"`
+	RolloverTime json.Number `json:"rollovertm"`
+	Misc         string      `json:"misc"`
+	OrderFlags   string      `json:"oflags"`
+}
+
+type responsePrivateLedgers struct {
+	Errors []string                     `json:"error"`
+	Result responsePrivateLedgersResult `json:"result"`
+}
+
+type responsePrivateLedgersResult struct {
+	Ledger map[string]responsePrivateLedgerEntry `json:"ledger"`
+	Count  uint64                                `json:"count"`
+}
+
+type responsePrivateQueryLedgers struct {
+	Errors []string                              `json:"error"`
+	Result map[string]responsePrivateLedgerEntry `json:"result"`
+}
+
+type responsePrivateLedgerEntry struct {
+	RefID      string      `json:"refid"`
+	Time       json.Number `json:"time"`
+	Type       string      `json:"type"`
+	SubType    string      `json:"subtype"`
+	AssetClass string      `json:"aclass"`
+	Asset      string      `json:"asset"`
+	Amount     string      `json:"amount"`
+	Fee        string      `json:"fee"`
+	Balance    string      `json:"balance"`
+}
+
+type responsePrivateTradeVolume struct {
+	Errors []string                         `json:"error"`
+	Result responsePrivateTradeVolumeResult `json:"result"`
+}
+
+type responsePrivateTradeVolumeResult struct {
+	Currency  string                                   `json:"currency"`
+	Volume    string                                   `json:"volume"`
+	Fees      map[string]responsePrivateTradeVolumeFee `json:"fees"`
+	FeesMaker map[string]responsePrivateTradeVolumeFee `json:"fees_maker"`
+}
+
+type responsePrivateTradeVolumeFee struct {
+	Fee        string  `json:"fee"`
+	MinFee     string  `json:"minfee"`
+	MaxFee     string  `json:"maxfee"`
+	NextFee    *string `json:"nextfee"`
+	NextVolume *string `json:"nextvolume"`
+	TierVolume string  `json:"tiervolume"`
+}
+
+type responsePrivateAddOrder struct {
+	Errors []string                      `json:"error"`
+	Result responsePrivateAddOrderResult `json:"result"`
+}
+
+type responsePrivateAddOrderResult struct {
+	Description responsePrivateOrderDescription `json:"descr"`
+	TxIDs       []string                        `json:"txid"`
+}
+
+type responsePrivateCancel struct {
+	Errors []string                    `json:"error"`
+	Result responsePrivateCancelResult `json:"result"`
+}
+
+type responsePrivateCancelResult struct {
+	Count   uint64 `json:"count"`
+	Pending bool   `json:"pending"`
+}
+
+type responsePrivateCancelAllOrdersAfter struct {
+	Errors []string                                  `json:"error"`
+	Result responsePrivateCancelAllOrdersAfterResult `json:"result"`
+}
+
+type responsePrivateCancelAllOrdersAfterResult struct {
+	CurrentTime string `json:"currentTime"`
+	TriggerTime string `json:"triggerTime"`
+}
+
+type responsePrivateEditOrder struct {
+	Errors []string                       `json:"error"`
+	Result responsePrivateEditOrderResult `json:"result"`
+}
+
+type responsePrivateEditOrderResult struct {
+	Status          string                          `json:"status"`
+	ErrorMessage    string                          `json:"error_message"`
+	TxID            string                          `json:"txid"`
+	OriginalTxID    string                          `json:"originaltxid"`
+	Volume          string                          `json:"volume"`
+	Price           string                          `json:"price"`
+	Price2          string                          `json:"price2"`
+	OrdersCancelled uint64                          `json:"orders_cancelled"`
+	Description     responsePrivateOrderDescription `json:"descr"`
+}
+
+type responsePrivateDepositMethods struct {
+	Errors []string                       `json:"error"`
+	Result []responsePrivateDepositMethod `json:"result"`
+}
+
+type responsePrivateDepositMethod struct {
+	Method          string      `json:"method"`
+	Limit           interface{} `json:"limit"`
+	Fee             string      `json:"fee"`
+	AddressSetupFee string      `json:"address-setup-fee"`
+	GenAddress      bool        `json:"gen-address"`
+	Minimum         string      `json:"minimum"`
+}
+
+type responsePrivateDepositAddresses struct {
+	Errors []string                        `json:"error"`
+	Result []responsePrivateDepositAddress `json:"result"`
+}
+
+type responsePrivateDepositAddress struct {
+	Address    string      `json:"address"`
+	ExpireTime json.Number `json:"expiretm"`
+	New        bool        `json:"new"`
+	Tag        string      `json:"tag"`
+}
+
+type responsePrivateFundingStatus struct {
+	Errors []string                            `json:"error"`
+	Result []responsePrivateFundingTransaction `json:"result"`
+}
+
+type responsePrivateFundingTransaction struct {
+	Method     string      `json:"method"`
+	AssetClass string      `json:"aclass"`
+	Asset      string      `json:"asset"`
+	RefID      string      `json:"refid"`
+	TxID       string      `json:"txid"`
+	Info       string      `json:"info"`
+	Amount     string      `json:"amount"`
+	Fee        string      `json:"fee"`
+	Time       json.Number `json:"time"`
+	Status     string      `json:"status"`
+	StatusProp string      `json:"status-prop"`
+}
+
+type responsePrivateWithdrawInfo struct {
+	Errors []string                          `json:"error"`
+	Result responsePrivateWithdrawInfoResult `json:"result"`
+}
+
+type responsePrivateWithdrawInfoResult struct {
+	Method string `json:"method"`
+	Limit  string `json:"limit"`
+	Amount string `json:"amount"`
+	Fee    string `json:"fee"`
+}
+
+type responsePrivateWithdraw struct {
+	Errors []string                      `json:"error"`
+	Result responsePrivateWithdrawResult `json:"result"`
+}
+
+type responsePrivateWithdrawResult struct {
+	RefID string `json:"refid"`
+}
+
+type responsePrivateWithdrawCancel struct {
+	Errors []string `json:"error"`
+	Result bool     `json:"result"`
+}
+
+type responsePrivateWalletTransfer struct {
+	Errors []string                            `json:"error"`
+	Result responsePrivateWalletTransferResult `json:"result"`
+}
+
+type responsePrivateWalletTransferResult struct {
+	RefID string `json:"refid"`
+}
+
+type responsePrivateEarnStrategies struct {
+	Errors []string                            `json:"error"`
+	Result responsePrivateEarnStrategiesResult `json:"result"`
+}
+
+type responsePrivateEarnStrategiesResult struct {
+	Items      []responsePrivateEarnStrategy `json:"items"`
+	NextCursor string                        `json:"next_cursor"`
+}
+
+type responsePrivateEarnStrategy struct {
+	ID                string                         `json:"id"`
+	Asset             string                         `json:"asset"`
+	LockType          responsePrivateEarnLockType    `json:"lock_type"`
+	APREstimate       responsePrivateEarnAPREstimate `json:"apr_estimate"`
+	UserMinAllocation string                         `json:"user_min_allocation"`
+	AllocationFee     json.Number                    `json:"allocation_fee"`
+	DeallocationFee   json.Number                    `json:"deallocation_fee"`
+	CanAllocate       bool                           `json:"can_allocate"`
+	CanDeallocate     bool                           `json:"can_deallocate"`
+}
+
+type responsePrivateEarnLockType struct {
+	Type            string `json:"type"`
+	BondingPeriod   int64  `json:"bonding_period"`
+	UnbondingPeriod int64  `json:"unbonding_period"`
+}
+
+type responsePrivateEarnAPREstimate struct {
+	Low  string `json:"low"`
+	High string `json:"high"`
+}
+
+type responsePrivateEarnAllocation struct {
+	Errors []string `json:"error"`
+	Result bool     `json:"result"`
+}
+
+type responsePrivateEarnAllocationStatus struct {
+	Errors []string                                  `json:"error"`
+	Result responsePrivateEarnAllocationStatusResult `json:"result"`
+}
+
+type responsePrivateEarnAllocationStatusResult struct {
+	Pending bool `json:"pending"`
+}
+
+type responsePrivateEarnAllocations struct {
+	Errors []string                             `json:"error"`
+	Result responsePrivateEarnAllocationsResult `json:"result"`
+}
+
+type responsePrivateEarnAllocationsResult struct {
+	ConvertedAsset string                              `json:"converted_asset"`
+	TotalAllocated string                              `json:"total_allocated"`
+	TotalRewarded  string                              `json:"total_rewarded"`
+	Items          []responsePrivateEarnAllocationItem `json:"items"`
+}
+
+type responsePrivateEarnAllocationItem struct {
+	StrategyID      string                             `json:"strategy_id"`
+	NativeAsset     string                             `json:"native_asset"`
+	AmountAllocated responsePrivateEarnAmountAllocated `json:"amount_allocated"`
+	TotalRewarded   responsePrivateEarnAmount          `json:"total_rewarded"`
+}
+
+type responsePrivateEarnAmountAllocated struct {
+	Pending *responsePrivateEarnAmount `json:"pending"`
+	Total   responsePrivateEarnAmount  `json:"total"`
+}
+
+type responsePrivateEarnAmount struct {
+	Native    string `json:"native"`
+	Converted string `json:"converted"`
+}
+
+type responseErrors struct {
+	Errors []string `json:"error"`
+}
+
+type responsePrivateAddExport struct {
+	Errors []string                       `json:"error"`
+	Result responsePrivateAddExportResult `json:"result"`
+}
+
+type responsePrivateAddExportResult struct {
+	ID string `json:"id"`
+}
+
+type responsePrivateExportStatus struct {
+	Errors []string                `json:"error"`
+	Result []responsePrivateExport `json:"result"`
+}
+
+type responsePrivateExport struct {
+	ID            string      `json:"id"`
+	Description   string      `json:"descr"`
+	Format        string      `json:"format"`
+	Report        string      `json:"report"`
+	Status        string      `json:"status"`
+	Fields        string      `json:"fields"`
+	CreatedTime   json.Number `json:"createdtm"`
+	ExpireTime    json.Number `json:"expiretm"`
+	StartTime     json.Number `json:"starttm"`
+	CompletedTime json.Number `json:"completedtm"`
+	DataStartTime json.Number `json:"datastarttm"`
+	DataEndTime   json.Number `json:"dataendtm"`
+	AssetClass    string      `json:"aclass"`
+	Asset         string      `json:"asset"`
+}
+
+type responsePrivateRemoveExport struct {
+	Errors []string                          `json:"error"`
+	Result responsePrivateRemoveExportResult `json:"result"`
+}
+
+type responsePrivateRemoveExportResult struct {
+	Delete bool `json:"delete"`
+	Cancel bool `json:"cancel"`
+}
+
+type responsePrivateCreateSubaccount struct {
+	Errors []string `json:"error"`
+	Result bool     `json:"result"`
+}
+
+type responsePrivateAccountTransfer struct {
+	Errors []string                             `json:"error"`
+	Result responsePrivateAccountTransferResult `json:"result"`
+}
+
+type responsePrivateAccountTransferResult struct {
+	TransferID string `json:"transfer_id"`
+	Status     string `json:"status"`
+}
+
+type responsePrivateGetWebSocketsToken struct {
+	Errors []string                                `json:"error"`
+	Result responsePrivateGetWebSocketsTokenResult `json:"result"`
+}
+
+type responsePrivateGetWebSocketsTokenResult struct {
+	Token   string `json:"token"`
+	Expires int64  `json:"expires"`
+}
+
+// responseWSStatus a websocket subscriptionStatus, addOrderStatus or
+// cancelOrderStatus event, or an error event in response to a request
+type responseWSStatus struct {
+	Event         string `json:"event"`
+	ReqID         uint64 `json:"reqid"`
+	Status        string `json:"status"`
+	Pair          string `json:"pair"`
+	ChannelName   string `json:"channelName"`
+	ErrorMessage  string `json:"errorMessage"`
+	Description   string `json:"descr"`
+	TransactionID string `json:"txid"`
+}
+
+// responseWSTicker the data of a websocket ticker channel message, unlike the
+// REST endpoint the open is given for both today and the last 24 hours
+type responseWSTicker struct {
+	responsePublicTickerInformation
+	Open flexibleDecimals `json:"o"`
+}
+
+// responseWSOHLCValue is a [time, etime, open, high, low, close, vwap,
+// volume, count] websocket OHLC channel message
+type responseWSOHLCValue struct {
+	Time                       flexibleTime
+	EndTime                    flexibleTime
+	Open                       flexibleDecimal
+	High                       flexibleDecimal
+	Low                        flexibleDecimal
+	Close                      flexibleDecimal
+	VolumeWeightedAveragePrice flexibleDecimal
+	Volume                     flexibleDecimal
+	Count                      uint64
+}
+
+func (v *responseWSOHLCValue) UnmarshalJSON(data []byte) error {
+	return unmarshalTuple(data, 9, &v.Time, &v.EndTime, &v.Open, &v.High, &v.Low, &v.Close, &v.VolumeWeightedAveragePrice, &v.Volume, &v.Count)
+}
+
+// responseWSSpreadValue is a [bid, ask, timestamp, bidVolume, askVolume]
+// websocket spread channel message
+type responseWSSpreadValue struct {
+	Bid       flexibleDecimal
+	Ask       flexibleDecimal
+	Time      flexibleTime
+	BidVolume flexibleDecimal
+	AskVolume flexibleDecimal
+}
+
+func (v *responseWSSpreadValue) UnmarshalJSON(data []byte) error {
+	return unmarshalTuple(data, 3, &v.Bid, &v.Ask, &v.Time, &v.BidVolume, &v.AskVolume)
+}
+
+// responseWSBook the data of a websocket book channel message, a snapshot
+// has the "as" and "bs" levels while an update has the "a" or "b" levels
+// and a checksum
+type responseWSBook struct {
+	AskSnapshot []responseWSBookLevel `json:"as"`
+	BidSnapshot []responseWSBookLevel `json:"bs"`
+	Asks        []responseWSBookLevel `json:"a"`
+	Bids        []responseWSBookLevel `json:"b"`
+	Checksum    string                `json:"c"`
+}
+
+// responseWSBookLevel is a [price, volume, timestamp, updateType] book
+// level, the update type is only given for republished levels
+type responseWSBookLevel struct {
+	Price      flexibleDecimal
+	Volume     flexibleDecimal
+	Time       flexibleTime
+	UpdateType string
+}
+
+func (v *responseWSBookLevel) UnmarshalJSON(data []byte) error {
+	return unmarshalTuple(data, 3, &v.Price, &v.Volume, &v.Time, &v.UpdateType)
+}
+
+// responseWSOwnTrade a trade of a websocket ownTrades channel message
+type responseWSOwnTrade struct {
+	responsePrivateTrade
+	UserRef int32 `json:"userref"`
+}
+
+// responseWSOrder an order of a websocket openOrders channel message, the
+// average price and cancel reason are named differently to the REST API
+type responseWSOrder struct {
+	responsePrivateOrder
+	AveragePrice string `json:"avg_price"`
+	CancelReason string `json:"cancel_reason"`
+}
+
+// responseWSV2Message a v2 websocket message, either the response to a
+// request with its method or a channel message with its data
+type responseWSV2Message struct {
+	Method  string             `json:"method"`
+	ReqID   uint64             `json:"req_id"`
+	Success bool               `json:"success"`
+	Error   string             `json:"error"`
+	Symbol  string             `json:"symbol"`
+	Result  responseWSV2Symbol `json:"result"`
+	Channel string             `json:"channel"`
+	Type    json.RawMessage    `json:"type"`
+	Data    []json.RawMessage  `json:"data"`
+}
+
+// responseWSV2Symbol the channel and symbol of a v2 request's result or a
+// value of a channel message, the interval is only given by the ohlc channel
+type responseWSV2Symbol struct {
+	Channel  string `json:"channel"`
+	Symbol   string `json:"symbol"`
+	Interval int    `json:"interval"`
+}
+
+// responseWSV2Ticker a value of a v2 websocket ticker channel message, the
+// change is that of the last 24 hours
+type responseWSV2Ticker struct {
+	Symbol    string          `json:"symbol"`
+	Bid       flexibleDecimal `json:"bid"`
+	BidQty    flexibleDecimal `json:"bid_qty"`
+	Ask       flexibleDecimal `json:"ask"`
+	AskQty    flexibleDecimal `json:"ask_qty"`
+	Last      flexibleDecimal `json:"last"`
+	Volume    flexibleDecimal `json:"volume"`
+	VWAP      flexibleDecimal `json:"vwap"`
+	Low       flexibleDecimal `json:"low"`
+	High      flexibleDecimal `json:"high"`
+	Change    flexibleDecimal `json:"change"`
+	ChangePct flexibleDecimal `json:"change_pct"`
+}
+
+// responseWSV2OHLC a value of a v2 websocket ohlc channel message
+type responseWSV2OHLC struct {
+	Symbol        string          `json:"symbol"`
+	Open          flexibleDecimal `json:"open"`
+	High          flexibleDecimal `json:"high"`
+	Low           flexibleDecimal `json:"low"`
+	Close         flexibleDecimal `json:"close"`
+	Trades        uint64          `json:"trades"`
+	Volume        flexibleDecimal `json:"volume"`
+	VWAP          flexibleDecimal `json:"vwap"`
+	IntervalBegin time.Time       `json:"interval_begin"`
+	Interval      int             `json:"interval"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// responseWSV2Trade a value of a v2 websocket trade channel message
+type responseWSV2Trade struct {
+	Symbol    string          `json:"symbol"`
+	Side      string          `json:"side"`
+	Price     flexibleDecimal `json:"price"`
+	Qty       flexibleDecimal `json:"qty"`
+	OrdType   string          `json:"ord_type"`
+	TradeID   uint64          `json:"trade_id"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// responseWSV2Book a value of a v2 websocket book channel message, the
+// timestamp is only given by updates
+type responseWSV2Book struct {
+	Symbol    string                  `json:"symbol"`
+	Bids      []responseWSV2BookLevel `json:"bids"`
+	Asks      []responseWSV2BookLevel `json:"asks"`
+	Checksum  uint32                  `json:"checksum"`
+	Timestamp time.Time               `json:"timestamp"`
+}
+
+// responseWSV2BookLevel a level of a v2 websocket book channel message
+type responseWSV2BookLevel struct {
+	Price flexibleDecimal `json:"price"`
+	Qty   flexibleDecimal `json:"qty"`
 }
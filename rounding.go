@@ -0,0 +1,91 @@
+package kraken
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode the direction a price or volume is rounded in
+type RoundingMode byte
+
+// String return a string value of the rounding mode
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundingModeNearest:
+		return "nearest"
+	case RoundingModeFloor:
+		return "floor"
+	case RoundingModeCeil:
+		return "ceil"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// RoundingModeNearest enum representing rounding to the nearest value,
+	// halfway values are rounded away from zero
+	RoundingModeNearest = iota
+	// RoundingModeFloor enum representing rounding down
+	RoundingModeFloor
+	// RoundingModeCeil enum representing rounding up
+	RoundingModeCeil
+	// RoundingModeUnknown enum representing an unknown rounding mode
+	RoundingModeUnknown
+)
+
+// RoundPrice round a price to a multiple of the pair's tick size, or to its
+// PairPrecision decimal places when it has no tick size, so the API does not
+// reject it with EOrder:Invalid price
+func (p AssetPair) RoundPrice(d decimal.Decimal, mode RoundingMode) decimal.Decimal {
+	if !p.TickSize.IsPositive() {
+		return roundPlaces(d, int32(p.PairPrecision), mode)
+	}
+
+	q, r := d.QuoRem(p.TickSize, 0)
+	switch mode {
+	case RoundingModeFloor:
+		if r.IsNegative() {
+			q = q.Sub(decimal.NewFromInt(1))
+		}
+	case RoundingModeCeil:
+		if r.IsPositive() {
+			q = q.Add(decimal.NewFromInt(1))
+		}
+	default:
+		if r.Abs().Mul(decimal.NewFromInt(2)).GreaterThanOrEqual(p.TickSize) {
+			q = q.Add(decimal.NewFromInt(int64(d.Sign())))
+		}
+	}
+
+	return q.Mul(p.TickSize)
+}
+
+// RoundVolume round a volume to the pair's LotPrecision decimal places, so the
+// API does not reject it with EGeneral:Invalid arguments:volume
+func (p AssetPair) RoundVolume(d decimal.Decimal, mode RoundingMode) decimal.Decimal {
+	return roundPlaces(d, int32(p.LotPrecision), mode)
+}
+
+// FormatPrice return a price rounded with RoundPrice as sent to the API, with
+// PairPrecision decimal places
+func (p AssetPair) FormatPrice(d decimal.Decimal, mode RoundingMode) string {
+	return p.RoundPrice(d, mode).StringFixed(int32(p.PairPrecision))
+}
+
+// FormatVolume return a volume rounded with RoundVolume as sent to the API,
+// with LotPrecision decimal places
+func (p AssetPair) FormatVolume(d decimal.Decimal, mode RoundingMode) string {
+	return p.RoundVolume(d, mode).StringFixed(int32(p.LotPrecision))
+}
+
+// roundPlaces round a decimal to a number of decimal places
+func roundPlaces(d decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	switch mode {
+	case RoundingModeFloor:
+		return d.RoundFloor(places)
+	case RoundingModeCeil:
+		return d.RoundCeil(places)
+	default:
+		return d.Round(places)
+	}
+}
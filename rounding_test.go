@@ -0,0 +1,75 @@
+package kraken_test
+
+import (
+	"testing"
+
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+func TestAssetPairRoundPrice(t *testing.T) {
+	precision := kraken.AssetPair{PairPrecision: 1}
+	tick := kraken.AssetPair{PairPrecision: 2, TickSize: decimal.RequireFromString("0.05")}
+
+	tcs := []struct {
+		name      string
+		pair      kraken.AssetPair
+		input     string
+		mode      kraken.RoundingMode
+		expected  string
+		formatted string
+	}{
+		{name: "PrecisionNearest", pair: precision, input: "37639.45", mode: kraken.RoundingModeNearest, expected: "37639.5", formatted: "37639.5"},
+		{name: "PrecisionFloor", pair: precision, input: "37639.49", mode: kraken.RoundingModeFloor, expected: "37639.4", formatted: "37639.4"},
+		{name: "PrecisionCeil", pair: precision, input: "37639.41", mode: kraken.RoundingModeCeil, expected: "37639.5", formatted: "37639.5"},
+		{name: "PrecisionAligned", pair: precision, input: "37639", mode: kraken.RoundingModeCeil, expected: "37639", formatted: "37639.0"},
+		{name: "TickNearestDown", pair: tick, input: "1.324", mode: kraken.RoundingModeNearest, expected: "1.3", formatted: "1.30"},
+		{name: "TickNearestHalf", pair: tick, input: "1.325", mode: kraken.RoundingModeNearest, expected: "1.35", formatted: "1.35"},
+		{name: "TickFloor", pair: tick, input: "1.349", mode: kraken.RoundingModeFloor, expected: "1.3", formatted: "1.30"},
+		{name: "TickCeil", pair: tick, input: "1.301", mode: kraken.RoundingModeCeil, expected: "1.35", formatted: "1.35"},
+		{name: "TickAligned", pair: tick, input: "1.35", mode: kraken.RoundingModeCeil, expected: "1.35", formatted: "1.35"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			input := decimal.RequireFromString(tc.input)
+			if actual := tc.pair.RoundPrice(input, tc.mode); !actual.Equal(decimal.RequireFromString(tc.expected)) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expected, actual)
+			}
+
+			if actual := tc.pair.FormatPrice(input, tc.mode); actual != tc.formatted {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.formatted, actual)
+			}
+		})
+	}
+}
+
+func TestAssetPairRoundVolume(t *testing.T) {
+	pair := kraken.AssetPair{LotPrecision: 8}
+
+	tcs := []struct {
+		name      string
+		input     string
+		mode      kraken.RoundingMode
+		expected  string
+		formatted string
+	}{
+		{name: "Nearest", input: "0.123456785", mode: kraken.RoundingModeNearest, expected: "0.12345679", formatted: "0.12345679"},
+		{name: "Floor", input: "0.123456789", mode: kraken.RoundingModeFloor, expected: "0.12345678", formatted: "0.12345678"},
+		{name: "Ceil", input: "0.123456781", mode: kraken.RoundingModeCeil, expected: "0.12345679", formatted: "0.12345679"},
+		{name: "Aligned", input: "1.5", mode: kraken.RoundingModeFloor, expected: "1.5", formatted: "1.50000000"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			input := decimal.RequireFromString(tc.input)
+			if actual := pair.RoundVolume(input, tc.mode); !actual.Equal(decimal.RequireFromString(tc.expected)) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.expected, actual)
+			}
+
+			if actual := pair.FormatVolume(input, tc.mode); actual != tc.formatted {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", tc.formatted, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package kraken
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SpreadBucket the statistics of the spreads of an interval, in basis points
+// of their mid prices, with the last spread of the interval
+type SpreadBucket struct {
+	Time    time.Time
+	Samples int
+	MeanBPS decimal.Decimal
+	MinBPS  decimal.Decimal
+	MaxBPS  decimal.Decimal
+	Last    Spread
+}
+
+// Mid return the price halfway between the bid and ask of the spread
+func (s Spread) Mid() decimal.Decimal {
+	return s.Bid.Add(s.Ask).Div(decimal.NewFromInt(2))
+}
+
+// BPS return the difference between the ask and bid of the spread in basis
+// points of its mid price, false is returned when the mid price is not
+// positive
+func (s Spread) BPS() (decimal.Decimal, bool) {
+	mid := s.Mid()
+	if !mid.IsPositive() {
+		return decimal.Zero, false
+	}
+
+	return s.Ask.Sub(s.Bid).Div(mid).Mul(basisPoints), true
+}
+
+// TimeWeightedSpreadBPS return the average spread in basis points weighted by
+// how long each spread lasted, until the next spread or the end of the window
+// for the last. Spreads without a positive mid price are skipped and the
+// spreads need not be sorted. When the spreads last no time, such as a single
+// spread at the end of the window, their plain average is returned
+func TimeWeightedSpreadBPS(spreads []Spread, end time.Time) (decimal.Decimal, error) {
+	sorted, values := spreadBPS(spreads)
+	if len(sorted) == 0 {
+		return decimal.Zero, fmt.Errorf("no spreads with a positive mid price")
+	}
+
+	if end.Before(sorted[len(sorted)-1].Timestamp) {
+		return decimal.Zero, fmt.Errorf("end must not be before the last spread")
+	}
+
+	weighted, total, sum := decimal.Zero, decimal.Zero, decimal.Zero
+	for i, spread := range sorted {
+		until := end
+		if i+1 < len(sorted) {
+			until = sorted[i+1].Timestamp
+		}
+
+		weight := decimal.NewFromInt(int64(until.Sub(spread.Timestamp)))
+		weighted = weighted.Add(values[i].Mul(weight))
+		total = total.Add(weight)
+		sum = sum.Add(values[i])
+	}
+
+	if total.IsZero() {
+		return sum.Div(decimal.NewFromInt(int64(len(values)))), nil
+	}
+
+	return weighted.Div(total), nil
+}
+
+// MedianSpreadBPS return the median spread in basis points, the 50th
+// percentile of SpreadPercentileBPS
+func MedianSpreadBPS(spreads []Spread) (decimal.Decimal, error) {
+	return SpreadPercentileBPS(spreads, 50)
+}
+
+// SpreadPercentileBPS return a percentile, from 0 to 100, of the spreads in
+// basis points, interpolating linearly between the closest spreads. Spreads
+// without a positive mid price are skipped
+func SpreadPercentileBPS(spreads []Spread, percentile float64) (decimal.Decimal, error) {
+	if percentile < 0 || percentile > 100 {
+		return decimal.Zero, fmt.Errorf("percentile must be from 0 to 100")
+	}
+
+	_, values := spreadBPS(spreads)
+	if len(values) == 0 {
+		return decimal.Zero, fmt.Errorf("no spreads with a positive mid price")
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].LessThan(values[j]) })
+
+	rank := decimal.NewFromFloat(percentile).Mul(decimal.NewFromInt(int64(len(values) - 1))).Div(hundred)
+	lower := rank.Floor()
+	i := int(lower.IntPart())
+	if i+1 >= len(values) {
+		return values[i], nil
+	}
+
+	return values[i].Add(values[i+1].Sub(values[i]).Mul(rank.Sub(lower))), nil
+}
+
+// ResampleSpreads bucket spreads into intervals aligned to UTC boundaries for
+// charting, in time order. Intervals without a spread have no bucket and
+// spreads without a positive mid price are skipped
+func ResampleSpreads(spreads []Spread, interval time.Duration) ([]SpreadBucket, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+
+	sorted, values := spreadBPS(spreads)
+	buckets := []SpreadBucket{}
+	sum := decimal.Zero
+	for i, spread := range sorted {
+		ns := spread.Timestamp.UnixNano()
+		start := time.Unix(0, ns-mod(ns, int64(interval))).UTC()
+		if len(buckets) == 0 || !buckets[len(buckets)-1].Time.Equal(start) {
+			buckets = append(buckets, SpreadBucket{Time: start, MinBPS: values[i], MaxBPS: values[i]})
+			sum = decimal.Zero
+		}
+
+		bucket := &buckets[len(buckets)-1]
+		bucket.Samples++
+		bucket.MinBPS = decimal.Min(bucket.MinBPS, values[i])
+		bucket.MaxBPS = decimal.Max(bucket.MaxBPS, values[i])
+		bucket.Last = spread
+		sum = sum.Add(values[i])
+		bucket.MeanBPS = sum.Div(decimal.NewFromInt(int64(bucket.Samples)))
+	}
+
+	return buckets, nil
+}
+
+// spreadBPS a copy of the spreads with a positive mid price sorted by time,
+// with the spread of each in basis points
+func spreadBPS(spreads []Spread) ([]Spread, []decimal.Decimal) {
+	sorted := make([]Spread, 0, len(spreads))
+	for _, spread := range spreads {
+		if spread.Mid().IsPositive() {
+			sorted = append(sorted, spread)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	values := make([]decimal.Decimal, 0, len(sorted))
+	for _, spread := range sorted {
+		value, _ := spread.BPS()
+		values = append(values, value)
+	}
+
+	return sorted, values
+}
@@ -0,0 +1,155 @@
+package kraken_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/shopspring/decimal"
+)
+
+// spread a spread at an offset in seconds around a mid price of 100
+func spread(offset int, bid, ask string) kraken.Spread {
+	return kraken.Spread{
+		Timestamp: time.Unix(1643714160+int64(offset), 0).UTC(),
+		Bid:       decimal.RequireFromString(bid),
+		Ask:       decimal.RequireFromString(ask),
+	}
+}
+
+// spreadSamples spreads of 20, 10, 40 and 60 basis points out of time order,
+// with a spread without a mid price
+func spreadSamples() []kraken.Spread {
+	return []kraken.Spread{
+		spread(40, "99.8", "100.2"),
+		spread(0, "99.9", "100.1"),
+		spread(5, "0", "0"),
+		spread(50, "99.7", "100.3"),
+		spread(10, "99.95", "100.05"),
+	}
+}
+
+func TestTimeWeightedSpreadBPS(t *testing.T) {
+	tcs := []struct {
+		name     string
+		spreads  []kraken.Spread
+		end      time.Time
+		expected string
+	}{
+		{name: "Weighted", spreads: spreadSamples(), end: spread(60, "0", "0").Timestamp, expected: "25"},
+		{name: "Single", spreads: spreadSamples()[1:2], end: spread(60, "0", "0").Timestamp, expected: "20"},
+		{name: "NoDuration", spreads: []kraken.Spread{spread(0, "99.9", "100.1"), spread(0, "99.95", "100.05")}, end: spread(0, "0", "0").Timestamp, expected: "15"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := kraken.TimeWeightedSpreadBPS(tc.spreads, tc.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := decimal.RequireFromString(tc.expected); !actual.Equal(expected) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", expected, actual)
+			}
+		})
+	}
+
+	if _, err := kraken.TimeWeightedSpreadBPS(nil, time.Now()); err == nil {
+		t.Error("EXPECTED: no spreads with a positive mid price\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.TimeWeightedSpreadBPS(spreadSamples(), spread(0, "0", "0").Timestamp); err == nil {
+		t.Error("EXPECTED: end must not be before the last spread\nACTUAL: <nil>")
+	}
+}
+
+func TestSpreadPercentileBPS(t *testing.T) {
+	tcs := []struct {
+		name       string
+		spreads    []kraken.Spread
+		percentile float64
+		expected   string
+	}{
+		{name: "Min", spreads: spreadSamples(), percentile: 0, expected: "10"},
+		{name: "Median", spreads: spreadSamples(), percentile: 50, expected: "30"},
+		{name: "P90", spreads: spreadSamples(), percentile: 90, expected: "54"},
+		{name: "Max", spreads: spreadSamples(), percentile: 100, expected: "60"},
+		{name: "Single", spreads: spreadSamples()[:1], percentile: 90, expected: "40"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := kraken.SpreadPercentileBPS(tc.spreads, tc.percentile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := decimal.RequireFromString(tc.expected); !actual.Equal(expected) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", expected, actual)
+			}
+		})
+	}
+
+	if median, err := kraken.MedianSpreadBPS(spreadSamples()); err != nil || !median.Equal(decimal.RequireFromString("30")) {
+		t.Errorf("EXPECTED: 30 <nil>\nACTUAL: %v %v", median, err)
+	}
+
+	for _, percentile := range []float64{-1, 101} {
+		if _, err := kraken.SpreadPercentileBPS(spreadSamples(), percentile); err == nil {
+			t.Error("EXPECTED: percentile must be from 0 to 100\nACTUAL: <nil>")
+		}
+	}
+
+	if _, err := kraken.MedianSpreadBPS([]kraken.Spread{spread(0, "0", "0")}); err == nil {
+		t.Error("EXPECTED: no spreads with a positive mid price\nACTUAL: <nil>")
+	}
+}
+
+func TestResampleSpreads(t *testing.T) {
+	spreads := append(spreadSamples(), spread(95, "99.9", "100.1"))
+
+	buckets, err := kraken.ResampleSpreads(spreads, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []kraken.SpreadBucket{
+		{
+			Time:    spread(0, "0", "0").Timestamp,
+			Samples: 2,
+			MeanBPS: decimal.RequireFromString("15"),
+			MinBPS:  decimal.RequireFromString("10"),
+			MaxBPS:  decimal.RequireFromString("20"),
+			Last:    spread(10, "99.95", "100.05"),
+		},
+		{
+			Time:    spread(30, "0", "0").Timestamp,
+			Samples: 2,
+			MeanBPS: decimal.RequireFromString("50"),
+			MinBPS:  decimal.RequireFromString("40"),
+			MaxBPS:  decimal.RequireFromString("60"),
+			Last:    spread(50, "99.7", "100.3"),
+		},
+		{
+			Time:    spread(90, "0", "0").Timestamp,
+			Samples: 1,
+			MeanBPS: decimal.RequireFromString("20"),
+			MinBPS:  decimal.RequireFromString("20"),
+			MaxBPS:  decimal.RequireFromString("20"),
+			Last:    spread(95, "99.9", "100.1"),
+		},
+	}
+
+	if diff := deep.Equal(buckets, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	if buckets, err := kraken.ResampleSpreads(nil, time.Minute); err != nil || len(buckets) != 0 {
+		t.Errorf("EXPECTED: [] <nil>\nACTUAL: %v %v", buckets, err)
+	}
+
+	if _, err := kraken.ResampleSpreads(spreads, 0); err == nil {
+		t.Error("EXPECTED: interval must be positive\nACTUAL: <nil>")
+	}
+}
@@ -0,0 +1,390 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// watcherBufferSize the number of updates and errors buffered by a
+	// watcher, errors are dropped while their buffer is full
+	watcherBufferSize = 64
+)
+
+// TickerWatcher poll the tickers of pairs from the REST API for users who
+// cannot use the WSClient, only tickers which changed since the previous
+// poll are delivered. Pairs can be added and removed while running
+type TickerWatcher struct {
+	client   Client
+	interval time.Duration
+	updates  chan Ticker
+	errs     chan error
+
+	mu       sync.Mutex
+	pairs    []string
+	previous map[string]Ticker
+}
+
+// NewTickerWatcher helper function for creating a new TickerWatcher polling
+// every interval once Run
+func NewTickerWatcher(client Client, interval time.Duration, pairs ...string) (*TickerWatcher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+
+	w := TickerWatcher{
+		client:   client,
+		interval: interval,
+		updates:  make(chan Ticker, watcherBufferSize),
+		errs:     make(chan error, watcherBufferSize),
+		previous: map[string]Ticker{},
+	}
+	w.AddPairs(pairs...)
+
+	return &w, nil
+}
+
+// Updates return the channel changed tickers are delivered on
+func (w *TickerWatcher) Updates() <-chan Ticker {
+	return w.updates
+}
+
+// Errors return the channel failed polls are reported on, errors are dropped
+// while its buffer is full
+func (w *TickerWatcher) Errors() <-chan error {
+	return w.errs
+}
+
+// AddPairs add pairs to those polled, from the next poll
+func (w *TickerWatcher) AddPairs(pairs ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, pair := range pairs {
+		if !containsString(w.pairs, pair) {
+			w.pairs = append(w.pairs, pair)
+		}
+	}
+}
+
+// RemovePairs remove pairs from those polled, from the next poll
+func (w *TickerWatcher) RemovePairs(pairs ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	kept := w.pairs[:0]
+	for _, pair := range w.pairs {
+		if !containsString(pairs, pair) {
+			kept = append(kept, pair)
+		}
+	}
+	w.pairs = kept
+}
+
+// Pairs return the pairs polled
+func (w *TickerWatcher) Pairs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.pairs...)
+}
+
+// Run poll the tickers every interval until ctx is done, delivering those
+// which changed. A failed poll is reported on Errors and polling continues
+func (w *TickerWatcher) Run(ctx context.Context) error {
+	return runPolling(ctx, w.interval, w.poll, w.errs)
+}
+
+// poll request the tickers of the pairs and deliver those which changed,
+// the tickers of pairs no longer polled are forgotten
+func (w *TickerWatcher) poll(ctx context.Context) error {
+	pairs := w.Pairs()
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	res, err := w.client.Tickers(ctx, pairs...)
+	if err != nil {
+		return err
+	}
+
+	if len(res.Errors) != 0 {
+		return res.Errors[0]
+	}
+
+	w.mu.Lock()
+	changed := []Ticker{}
+	for pair, ticker := range res.Result {
+		if previous, ok := w.previous[pair]; !ok || !reflect.DeepEqual(previous, ticker) {
+			changed = append(changed, ticker)
+		}
+	}
+	w.previous = res.Result
+	w.mu.Unlock()
+
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Pair < changed[j].Pair })
+
+	for _, ticker := range changed {
+		select {
+		case w.updates <- ticker:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// runPolling call poll immediately and then every interval until ctx is done,
+// failed polls are sent on errs unless its buffer is full
+func runPolling(ctx context.Context, interval time.Duration, poll func(ctx context.Context) error, errs chan<- error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// containsString whether s contains v
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OrderBookSideDiff the changes to one side of a book between two polls,
+// changed levels are given as they are after the change
+type OrderBookSideDiff struct {
+	Added   []AskBid
+	Removed []AskBid
+	Changed []AskBid
+}
+
+// Empty whether the side did not change
+func (d OrderBookSideDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// OrderBookDiff the changes to a pair's book between two polls of an
+// OrderBookWatcher. Every level of a snapshot is added, so a snapshot
+// replaces the book it is applied to
+type OrderBookDiff struct {
+	Pair     string
+	Snapshot bool
+	Asks     OrderBookSideDiff
+	Bids     OrderBookSideDiff
+}
+
+// DiffOrderBookSide the levels added, removed and changed from previous to
+// current, levels are matched by price so the sides may be in either order.
+// Added and changed levels are in the order of current and removed levels in
+// the order of previous. A level changes when its volume or timestamp does,
+// and levels of a side at the same price are merged with their volumes
+// summed and the latest timestamp
+func DiffOrderBookSide(previous, current []AskBid) OrderBookSideDiff {
+	before, beforeOrder := mergeOrderBookLevels(previous)
+	after, afterOrder := mergeOrderBookLevels(current)
+
+	diff := OrderBookSideDiff{}
+	for _, price := range afterOrder {
+		level := after[price]
+		prev, ok := before[price]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, level)
+		case !prev.Volume.Equal(level.Volume) || !prev.Timestamp.Equal(level.Timestamp):
+			diff.Changed = append(diff.Changed, level)
+		}
+	}
+
+	for _, price := range beforeOrder {
+		if _, ok := after[price]; !ok {
+			diff.Removed = append(diff.Removed, before[price])
+		}
+	}
+
+	return diff
+}
+
+// mergeOrderBookLevels the levels of a side keyed by price, with the prices
+// in the order first seen
+func mergeOrderBookLevels(levels []AskBid) (map[string]AskBid, []string) {
+	merged := make(map[string]AskBid, len(levels))
+	order := make([]string, 0, len(levels))
+	for _, level := range levels {
+		price := level.Price.String()
+		existing, ok := merged[price]
+		if !ok {
+			merged[price] = level
+			order = append(order, price)
+			continue
+		}
+
+		existing.Volume = existing.Volume.Add(level.Volume)
+		if level.Timestamp.After(existing.Timestamp) {
+			existing.Timestamp = level.Timestamp
+		}
+		merged[price] = existing
+	}
+
+	return merged, order
+}
+
+// OrderBookWatcher poll the books of pairs from the REST API for users who
+// cannot use the WSClient, delivering the changes to each book since the
+// previous poll as an approximation of the websocket book channel. The whole
+// book is delivered by the first poll of a pair and every N polls when
+// configured
+type OrderBookWatcher struct {
+	client        Client
+	interval      time.Duration
+	count         uint
+	pairs         []string
+	snapshotEvery int
+	updates       chan OrderBookDiff
+	errs          chan error
+
+	polls int
+	books map[string]localOrderBook
+}
+
+// NewOrderBookWatcher helper function for creating a new OrderBookWatcher
+// polling up to count levels of each side every interval once Run
+func NewOrderBookWatcher(client Client, interval time.Duration, count uint, pairs []string, opts ...OrderBookWatcherOption) (*OrderBookWatcher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("at least one pair is required")
+	}
+
+	w := OrderBookWatcher{
+		client:   client,
+		interval: interval,
+		count:    count,
+		pairs:    pairs,
+		updates:  make(chan OrderBookDiff, watcherBufferSize),
+		errs:     make(chan error, watcherBufferSize),
+		books:    map[string]localOrderBook{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(&w); err != nil {
+			return nil, err
+		}
+	}
+
+	return &w, nil
+}
+
+// Updates return the channel the changes to books are delivered on
+func (w *OrderBookWatcher) Updates() <-chan OrderBookDiff {
+	return w.updates
+}
+
+// Errors return the channel failed polls are reported on, errors are dropped
+// while its buffer is full
+func (w *OrderBookWatcher) Errors() <-chan error {
+	return w.errs
+}
+
+// Run poll the books every interval until ctx is done, delivering the
+// changes to each. A failed poll is reported on Errors and polling continues
+func (w *OrderBookWatcher) Run(ctx context.Context) error {
+	return runPolling(ctx, w.interval, w.poll, w.errs)
+}
+
+// poll request the books of the pairs and deliver the changes to each, a
+// pair missing from the response has an empty book
+func (w *OrderBookWatcher) poll(ctx context.Context) error {
+	res, err := w.client.OrderBook(ctx, w.count, w.pairs...)
+	if err != nil {
+		return err
+	}
+
+	if len(res.Errors) != 0 {
+		return res.Errors[0]
+	}
+
+	w.polls++
+	snapshot := w.snapshotEvery > 0 && w.polls%w.snapshotEvery == 0
+
+	pairs := make([]string, 0, len(res.Asks)+len(res.Bids))
+	for pair := range res.Asks {
+		pairs = append(pairs, pair)
+	}
+	for pair := range res.Bids {
+		if _, ok := res.Asks[pair]; !ok {
+			pairs = append(pairs, pair)
+		}
+	}
+	for pair := range w.books {
+		if _, ok := res.Asks[pair]; !ok {
+			if _, ok := res.Bids[pair]; !ok {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	sort.Strings(pairs)
+
+	diffs := []OrderBookDiff{}
+	for _, pair := range pairs {
+		previous, ok := w.books[pair]
+		diff := OrderBookDiff{Pair: pair, Snapshot: snapshot || !ok}
+		if diff.Snapshot {
+			previous = localOrderBook{}
+		}
+
+		current := localOrderBook{asks: res.Asks[pair], bids: res.Bids[pair]}
+		diff.Asks = DiffOrderBookSide(previous.asks, current.asks)
+		diff.Bids = DiffOrderBookSide(previous.bids, current.bids)
+		w.books[pair] = current
+
+		if diff.Snapshot || !diff.Asks.Empty() || !diff.Bids.Empty() {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	for _, diff := range diffs {
+		select {
+		case w.updates <- diff:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
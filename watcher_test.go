@@ -0,0 +1,368 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+func TestTickerWatcher(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second poll fails and the XBTUSD ask changes from the third
+	calls := 0
+	m.OnTickers(func(ctx context.Context, pairs ...string) (kraken.Tickers, error) {
+		calls++
+		if calls == 2 {
+			return kraken.Tickers{}, kraken.ErrServiceUnavailable
+		}
+
+		result := map[string]kraken.Ticker{}
+		for _, pair := range pairs {
+			ask := decimal.NewFromInt(100)
+			if pair == "XBTUSD" && calls >= 3 {
+				ask = decimal.NewFromInt(101)
+			}
+			result[pair] = kraken.Ticker{Pair: pair, Ask: kraken.AskBid{Price: ask}}
+		}
+
+		return kraken.Tickers{Result: result}, nil
+	})
+
+	w, err := kraken.NewTickerWatcher(m, time.Millisecond, "XBTUSD", "ETHUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	receive := func() kraken.Ticker {
+		select {
+		case ticker := <-w.Updates():
+			return ticker
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+
+		return kraken.Ticker{}
+	}
+
+	// every ticker is delivered by the first poll
+	pairs := []string{receive().Pair, receive().Pair}
+	if diff := deep.Equal([]string{"ETHUSD", "XBTUSD"}, pairs); diff != nil {
+		t.Error(diff)
+	}
+
+	select {
+	case err := <-w.Errors():
+		if !errors.Is(err, kraken.ErrServiceUnavailable) {
+			t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrServiceUnavailable, err)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// only the changed ticker is delivered
+	if ticker := receive(); ticker.Pair != "XBTUSD" || !ticker.Ask.Price.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("EXPECTED: XBTUSD 101\nACTUAL: %s %s", ticker.Pair, ticker.Ask.Price)
+	}
+
+	w.RemovePairs("ETHUSD")
+	w.AddPairs("XBTUSD", "SOLUSD")
+	if diff := deep.Equal([]string{"XBTUSD", "SOLUSD"}, w.Pairs()); diff != nil {
+		t.Error(diff)
+	}
+
+	if ticker := receive(); ticker.Pair != "SOLUSD" {
+		t.Errorf("EXPECTED: SOLUSD\nACTUAL: %s", ticker.Pair)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, err)
+	}
+}
+
+func TestNewTickerWatcher(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kraken.NewTickerWatcher(nil, time.Second, "XBTUSD"); err == nil {
+		t.Error("EXPECTED: client is required\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewTickerWatcher(m, 0, "XBTUSD"); err == nil {
+		t.Error("EXPECTED: interval must be positive\nACTUAL: <nil>")
+	}
+}
+
+// askBid a level at a price and volume, with a timestamp in seconds
+func askBid(price, volume string, timestamp int64) kraken.AskBid {
+	return kraken.AskBid{Price: decimal.RequireFromString(price), Volume: decimal.RequireFromString(volume), Timestamp: time.Unix(timestamp, 0)}
+}
+
+func TestDiffOrderBookSide(t *testing.T) {
+	tcs := []struct {
+		name     string
+		previous []kraken.AskBid
+		current  []kraken.AskBid
+		expected kraken.OrderBookSideDiff
+	}{
+		{
+			name:     "BothEmpty",
+			expected: kraken.OrderBookSideDiff{},
+		},
+		{
+			name:     "FromEmpty",
+			current:  []kraken.AskBid{askBid("100", "1", 1), askBid("101", "2", 1)},
+			expected: kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("100", "1", 1), askBid("101", "2", 1)}},
+		},
+		{
+			name:     "ToEmpty",
+			previous: []kraken.AskBid{askBid("100", "1", 1), askBid("101", "2", 1)},
+			expected: kraken.OrderBookSideDiff{Removed: []kraken.AskBid{askBid("100", "1", 1), askBid("101", "2", 1)}},
+		},
+		{
+			name:     "Unchanged",
+			previous: []kraken.AskBid{askBid("100", "1", 1), askBid("101", "2", 1)},
+			current:  []kraken.AskBid{askBid("100.000", "1.0", 1), askBid("101", "2", 1)},
+			expected: kraken.OrderBookSideDiff{},
+		},
+		{
+			name:     "AddedRemovedChanged",
+			previous: []kraken.AskBid{askBid("100", "1", 1), askBid("101", "2", 1), askBid("102", "3", 1)},
+			current:  []kraken.AskBid{askBid("99", "5", 2), askBid("100", "1", 1), askBid("101", "1.5", 2)},
+			expected: kraken.OrderBookSideDiff{
+				Added:   []kraken.AskBid{askBid("99", "5", 2)},
+				Removed: []kraken.AskBid{askBid("102", "3", 1)},
+				Changed: []kraken.AskBid{askBid("101", "1.5", 2)},
+			},
+		},
+		{
+			name:     "TimestampChanged",
+			previous: []kraken.AskBid{askBid("100", "1", 1)},
+			current:  []kraken.AskBid{askBid("100", "1", 2)},
+			expected: kraken.OrderBookSideDiff{Changed: []kraken.AskBid{askBid("100", "1", 2)}},
+		},
+		{
+			name:     "DescendingBids",
+			previous: []kraken.AskBid{askBid("101", "1", 1), askBid("100", "1", 1)},
+			current:  []kraken.AskBid{askBid("102", "1", 2), askBid("100", "1", 1), askBid("98", "1", 2)},
+			expected: kraken.OrderBookSideDiff{
+				Added:   []kraken.AskBid{askBid("102", "1", 2), askBid("98", "1", 2)},
+				Removed: []kraken.AskBid{askBid("101", "1", 1)},
+			},
+		},
+		{
+			name:     "TiedPrices",
+			previous: []kraken.AskBid{askBid("100", "1", 1), askBid("100", "2", 3), askBid("101", "1", 1)},
+			current:  []kraken.AskBid{askBid("100", "3", 3), askBid("101", "0.5", 1), askBid("101", "0.5", 1)},
+			expected: kraken.OrderBookSideDiff{},
+		},
+		{
+			name:     "TiedPricesChanged",
+			previous: []kraken.AskBid{askBid("100", "1", 1)},
+			current:  []kraken.AskBid{askBid("100", "1", 1), askBid("100", "1", 2)},
+			expected: kraken.OrderBookSideDiff{Changed: []kraken.AskBid{askBid("100", "2", 2)}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := kraken.DiffOrderBookSide(tc.previous, tc.current)
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+
+			if actual.Empty() != (len(tc.expected.Added)+len(tc.expected.Removed)+len(tc.expected.Changed) == 0) {
+				t.Errorf("EXPECTED: %v\nACTUAL: %v", !actual.Empty(), actual.Empty())
+			}
+		})
+	}
+}
+
+func TestOrderBookWatcher(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the book of each poll, the third is crossed and the ETHUSD book is
+	// missing from the fourth
+	books := []kraken.OrderBook{
+		{
+			Asks: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("101", "1", 1)}, "XETHZUSD": {askBid("11", "1", 1)}},
+			Bids: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("100", "1", 1)}, "XETHZUSD": {askBid("10", "1", 1)}},
+		},
+		{
+			Asks: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("101", "1", 1)}, "XETHZUSD": {askBid("11", "1", 1)}},
+			Bids: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("100", "1", 1)}, "XETHZUSD": {askBid("10", "1", 1)}},
+		},
+		{
+			Asks: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("99", "1", 2)}, "XETHZUSD": {askBid("11", "1", 1)}},
+			Bids: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("100", "2", 2)}, "XETHZUSD": {askBid("10", "1", 1)}},
+		},
+		{
+			Asks: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("99", "1", 2)}},
+			Bids: map[string][]kraken.AskBid{"XXBTZUSD": {askBid("100", "2", 2)}},
+		},
+	}
+
+	calls := 0
+	m.OnOrderBook(func(ctx context.Context, count uint, pairs ...string) (kraken.OrderBook, error) {
+		if count != 10 {
+			t.Errorf("EXPECTED: 10\nACTUAL: %d", count)
+		}
+
+		calls++
+		if calls > len(books) {
+			return books[len(books)-1], nil
+		}
+
+		return books[calls-1], nil
+	})
+
+	w, err := kraken.NewOrderBookWatcher(m, time.Millisecond, 10, []string{"XBTUSD", "ETHUSD"}, kraken.OrderBookWatcherWithSnapshotEvery(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	expected := []kraken.OrderBookDiff{
+		{
+			Pair:     "XETHZUSD",
+			Snapshot: true,
+			Asks:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("11", "1", 1)}},
+			Bids:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("10", "1", 1)}},
+		},
+		{
+			Pair:     "XXBTZUSD",
+			Snapshot: true,
+			Asks:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("101", "1", 1)}},
+			Bids:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("100", "1", 1)}},
+		},
+		// the second poll is unchanged
+		{
+			Pair: "XXBTZUSD",
+			Asks: kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("99", "1", 2)}, Removed: []kraken.AskBid{askBid("101", "1", 1)}},
+			Bids: kraken.OrderBookSideDiff{Changed: []kraken.AskBid{askBid("100", "2", 2)}},
+		},
+		// the fourth poll is a snapshot of every pair
+		{
+			Pair:     "XETHZUSD",
+			Snapshot: true,
+		},
+		{
+			Pair:     "XXBTZUSD",
+			Snapshot: true,
+			Asks:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("99", "1", 2)}},
+			Bids:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("100", "2", 2)}},
+		},
+	}
+
+	for _, e := range expected {
+		select {
+		case actual := <-w.Updates():
+			if diff := deep.Equal(e, actual); diff != nil {
+				t.Error(diff)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, err)
+	}
+}
+
+func TestOrderBookWatcherCrossed(t *testing.T) {
+	// the book is parsed from the payload, so a crossed book must not be
+	// reported as an error of the response
+	s, c, err := krakentest.NewServer(krakentest.ServerWithPayload("Depth", `{"error":[],"result":{"XXBTZUSD":{"asks":[["100.0","1",1]],"bids":[["100.5","2",1]]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	w, err := kraken.NewOrderBookWatcher(c, time.Millisecond, 10, []string{"XBTUSD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	expected := kraken.OrderBookDiff{
+		Pair:     "XXBTZUSD",
+		Snapshot: true,
+		Asks:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("100.0", "1", 1)}},
+		Bids:     kraken.OrderBookSideDiff{Added: []kraken.AskBid{askBid("100.5", "2", 1)}},
+	}
+
+	select {
+	case actual := <-w.Updates():
+		if diff := deep.Equal(expected, actual); diff != nil {
+			t.Error(diff)
+		}
+	case err := <-w.Errors():
+		t.Errorf("EXPECTED: no error\nACTUAL: %v", err)
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.Canceled, err)
+	}
+}
+
+func TestNewOrderBookWatcher(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name   string
+		client kraken.Client
+		pairs  []string
+		opts   []kraken.OrderBookWatcherOption
+	}{
+		{name: "Client", pairs: []string{"XBTUSD"}},
+		{name: "Pairs", client: m},
+		{name: "SnapshotEvery", client: m, pairs: []string{"XBTUSD"}, opts: []kraken.OrderBookWatcherOption{kraken.OrderBookWatcherWithSnapshotEvery(0)}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kraken.NewOrderBookWatcher(tc.client, time.Second, 10, tc.pairs, tc.opts...); err == nil {
+				t.Error("EXPECTED: error\nACTUAL: <nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package kraken
+
+import (
+	"fmt"
+)
+
+// OrderBookWatcherOption options used when creating a new OrderBookWatcher
+type OrderBookWatcherOption func(w *OrderBookWatcher) error
+
+// OrderBookWatcherWithSnapshotEvery deliver the whole book of each pair every
+// n polls rather than its changes, only the first poll of a pair is a
+// snapshot by default
+func OrderBookWatcherWithSnapshotEvery(n int) OrderBookWatcherOption {
+	return OrderBookWatcherOption(func(w *OrderBookWatcher) error {
+		if n <= 0 {
+			return fmt.Errorf("snapshot interval must be positive")
+		}
+
+		w.snapshotEvery = n
+
+		return nil
+	})
+}
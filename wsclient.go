@@ -0,0 +1,1223 @@
+package kraken
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oliread/kraken/internal/websocket"
+)
+
+const (
+	// wsPublicURL the url of the public websocket API
+	wsPublicURL = "wss://ws.kraken.com"
+	// wsPublicURLV2 the url of the v2 public websocket API
+	wsPublicURLV2 = "wss://ws.kraken.com/v2"
+	// wsAuthURL the url of the authenticated websocket API
+	wsAuthURL = "wss://ws-auth.kraken.com"
+	// wsTokenRefreshMargin the time before its expiry a token is replaced
+	wsTokenRefreshMargin = time.Minute
+)
+
+// WSClient used to interact with the Kraken websocket API, the parsed
+// messages of each subscription are delivered on a channel of their own
+type WSClient struct {
+	url          string
+	version      WSVersion
+	parser       Parser
+	bufferSize   int
+	backpressure WSBackpressure
+	onError      func(error)
+	auth         Client
+	metrics      *wsMetrics
+
+	validateOrders bool
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
+
+	mu       sync.Mutex
+	closed   bool
+	dialled  bool
+	conn     *wsConnection
+	err      error
+	reqID    uint64
+	pending  map[uint64]*wsRequest
+	channels map[wsChannelKey]*wsSubscription
+	active   map[wsActiveKey]*wsSubscription
+}
+
+// wsConnection a websocket connection, done is closed once it has ended
+type wsConnection struct {
+	conn *websocket.Conn
+	done chan struct{}
+	once sync.Once
+	err  error
+}
+
+// end close the connection, the first error it ended with is kept
+func (c *wsConnection) end(err error) {
+	c.once.Do(func() {
+		c.err = err
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// wsChannelKey identify the messages of a channel for a pair, such as the
+// "ohlc-5" channel for "XBT/USD"
+type wsChannelKey struct {
+	name string
+	pair string
+}
+
+// wsActiveKey identify a subscription to a channel for a pair by the options
+// it was requested with, as a channel name is only known once subscribed
+type wsActiveKey struct {
+	name     string
+	interval int
+	depth    int
+	pair     string
+}
+
+// wsSubscription a subscription for one or more pairs, the data of its
+// channel messages is passed to handle and close is called once it has ended
+type wsSubscription struct {
+	handle func(pair string, data []json.RawMessage) error
+	close  func()
+}
+
+// wsRequest a request awaiting the status of each of its pairs, or its
+// single status when not for pairs. sub is nil unless subscribing
+type wsRequest struct {
+	sub *wsSubscription
+	// success the status of each pair once the request has succeeded
+	success  string
+	statuses chan responseWSStatus
+}
+
+// wsAddOrder an addOrder request message
+type wsAddOrder struct {
+	Event     string `json:"event"`
+	Token     string `json:"token"`
+	ReqID     uint64 `json:"reqid"`
+	OrderType string `json:"ordertype"`
+	Type      string `json:"type"`
+	Pair      string `json:"pair"`
+	Price     string `json:"price,omitempty"`
+	Price2    string `json:"price2,omitempty"`
+	Volume    string `json:"volume"`
+	Leverage  string `json:"leverage,omitempty"`
+	OFlags    string `json:"oflags,omitempty"`
+	StartTm   string `json:"starttm,omitempty"`
+	ExpireTm  string `json:"expiretm,omitempty"`
+	UserRef   string `json:"userref,omitempty"`
+	Validate  string `json:"validate,omitempty"`
+}
+
+// wsCancelOrder a cancelOrder request message
+type wsCancelOrder struct {
+	Event string   `json:"event"`
+	Token string   `json:"token"`
+	ReqID uint64   `json:"reqid"`
+	TxID  []string `json:"txid"`
+}
+
+// wsSubscribe a subscribe or unsubscribe request message
+type wsSubscribe struct {
+	Event        string                `json:"event"`
+	ReqID        uint64                `json:"reqid"`
+	Pair         []string              `json:"pair"`
+	Subscription wsSubscriptionOptions `json:"subscription"`
+}
+
+// wsSubscriptionOptions the subscription of a subscribe request message
+type wsSubscriptionOptions struct {
+	Name     string `json:"name"`
+	Interval int    `json:"interval,omitempty"`
+	Depth    int    `json:"depth,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// wsV2Subscribe a v2 subscribe or unsubscribe request message
+type wsV2Subscribe struct {
+	Method string              `json:"method"`
+	Params wsV2SubscribeParams `json:"params"`
+	ReqID  uint64              `json:"req_id"`
+}
+
+// wsV2SubscribeParams the params of a v2 subscribe request message
+type wsV2SubscribeParams struct {
+	Channel      string   `json:"channel"`
+	Symbol       []string `json:"symbol"`
+	Interval     int      `json:"interval,omitempty"`
+	Depth        int      `json:"depth,omitempty"`
+	EventTrigger string   `json:"event_trigger,omitempty"`
+}
+
+// NewWSClient helper function for creating a new WSClient, a connection is
+// opened by Connect or the first subscription. The client connects to the
+// authenticated API when created with WSClientWithAuthentication
+func NewWSClient(opts ...WSClientOption) (*WSClient, error) {
+	c := WSClient{
+		parser:       Parser{},
+		bufferSize:   64,
+		backpressure: WSBackpressureBlock,
+		pending:      map[uint64]*wsRequest{},
+		channels:     map[wsChannelKey]*wsSubscription{},
+		active:       map[wsActiveKey]*wsSubscription{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.url == "" {
+		switch {
+		case c.version == WSVersion2:
+			c.url = wsPublicURLV2
+		case c.auth != nil:
+			c.url = wsAuthURL
+		default:
+			c.url = wsPublicURL
+		}
+	}
+
+	return &c, nil
+}
+
+// Connect open the websocket connection, nothing is done when the client is
+// already connected
+func (c *WSClient) Connect(ctx context.Context) error {
+	_, err := c.connect(ctx)
+	return err
+}
+
+// Close close the websocket connection, the channels of every subscription
+// are closed and the client cannot be used again
+func (c *WSClient) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn != nil {
+		conn.end(ErrWSClosed)
+	}
+
+	return nil
+}
+
+// Err return the error the last connection ended with, nil while connected
+func (c *WSClient) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// SubscribeTicker subscribe to the ticker channel of pairs, named as by the
+// websocket API such as "XBT/USD". Tickers of every pair are delivered on C of
+// the returned subscription until it is unsubscribed or the connection ends
+func (c *WSClient) SubscribeTicker(ctx context.Context, pairs []string, opts ...SubscribeOption) (*Subscription[Ticker], error) {
+	parse := c.parser.parseWSTicker
+	if c.version == WSVersion2 {
+		parse = c.parser.parseWSV2Ticker
+	}
+
+	return subscribe(ctx, c, wsSubscriptionOptions{Name: "ticker"}, pairs, opts, parse)
+}
+
+// SubscribeOHLC subscribe to the OHLC channel of pairs at an interval, an
+// update is delivered each time the in-progress candle changes. A candle has
+// closed once an update with a later EndTime is received for its pair, see
+// SubscribeWithOHLCGapHandler for detecting candles without an update
+func (c *WSClient) SubscribeOHLC(ctx context.Context, interval OHLCInterval, pairs []string, opts ...SubscribeOption) (*Subscription[OHLCUpdate], error) {
+	switch interval {
+	case OHLCIntervalMinute, OHLCInterval5Minutes, OHLCInterval15Minutes, OHLCInterval30Minutes, OHLCIntervalHour,
+		OHLCInterval4Hour, OHLCIntervalDaily, OHLCIntervalWeekly, OHLCInterval15Days:
+	default:
+		return nil, fmt.Errorf("unsupported ohlc interval %d", interval)
+	}
+
+	config, err := c.subscribeConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the latest end time of each pair, only used by the read loop
+	endTimes := map[string]time.Time{}
+
+	return subscribe(ctx, c, wsSubscriptionOptions{Name: "ohlc", Interval: int(interval)}, pairs, opts, func(pair string, data []json.RawMessage) (OHLCUpdate, error) {
+		var update OHLCUpdate
+		var err error
+		if c.version == WSVersion2 {
+			update, err = c.parser.parseWSV2OHLC(pair, data)
+		} else {
+			update, err = c.parser.parseWSOHLC(interval, pair, data)
+		}
+
+		if err != nil || config.onOHLCGap == nil {
+			return update, err
+		}
+
+		previous, ok := endTimes[pair]
+		if ok && (update.EndTime.After(previous.Add(time.Duration(interval)*time.Minute)) || update.EndTime.Before(previous)) {
+			config.onOHLCGap(OHLCGap{Pair: pair, Interval: interval, PreviousEndTime: previous, EndTime: update.EndTime})
+		}
+
+		if !ok || update.EndTime.After(previous) {
+			endTimes[pair] = update.EndTime
+		}
+
+		return update, nil
+	})
+}
+
+// SubscribeTrades subscribe to the trade channel of pairs, every trade of a
+// message is delivered in order
+func (c *WSClient) SubscribeTrades(ctx context.Context, pairs []string, opts ...SubscribeOption) (*Subscription[TradeUpdate], error) {
+	parse := c.parser.parseWSTrades
+	if c.version == WSVersion2 {
+		parse = c.parser.parseWSV2Trades
+	}
+
+	return subscribeEach(ctx, c, wsSubscriptionOptions{Name: "trade"}, pairs, opts, parse)
+}
+
+// SubscribeSpread subscribe to the spread channel of pairs, an update is
+// delivered each time the best bid or ask changes. v2 has no spread channel
+// so its ticker channel is used instead, a pair cannot be subscribed to both
+// tickers and spreads with v2
+func (c *WSClient) SubscribeSpread(ctx context.Context, pairs []string, opts ...SubscribeOption) (*Subscription[SpreadUpdate], error) {
+	parse := c.parser.parseWSSpread
+	if c.version == WSVersion2 {
+		parse = c.parser.parseWSV2Spread
+	}
+
+	return subscribe(ctx, c, wsSubscriptionOptions{Name: "spread"}, pairs, opts, parse)
+}
+
+// SubscribeBook subscribe to the book channel of pairs at a depth of 10, 25,
+// 100, 500 or 1000 levels. A snapshot of each pair's book is delivered first
+// followed by updates of the levels changed, see OrderBookKeeper for
+// maintaining a local book
+func (c *WSClient) SubscribeBook(ctx context.Context, depth int, pairs []string, opts ...SubscribeOption) (*Subscription[BookUpdate], error) {
+	if err := validateBookDepth(depth); err != nil {
+		return nil, err
+	}
+
+	return subscribe(ctx, c, wsSubscriptionOptions{Name: "book", Depth: depth}, pairs, opts, func(pair string, data []json.RawMessage) (BookUpdate, error) {
+		if c.version == WSVersion2 {
+			return c.parser.parseWSV2Book(depth, pair, data)
+		}
+
+		return c.parser.parseWSBook(depth, pair, data)
+	})
+}
+
+// validateBookDepth check depth is supported by the book channel
+func validateBookDepth(depth int) error {
+	switch depth {
+	case 10, 25, 100, 500, 1000:
+		return nil
+	default:
+		return fmt.Errorf("unsupported book depth %d", depth)
+	}
+}
+
+// SubscribeOwnTrades subscribe to the trades of the account, the most recent
+// trades are delivered first followed by each new trade. The client must be
+// created with WSClientWithAuthentication. C is closed when the connection
+// ends as the client does not reconnect, subscribing again connects with a
+// new token
+func (c *WSClient) SubscribeOwnTrades(ctx context.Context, opts ...SubscribeOption) (*Subscription[TradeHistoryEntry], error) {
+	return subscribePrivate(ctx, c, "ownTrades", opts, c.parser.parseWSOwnTrades)
+}
+
+// SubscribeOpenOrders subscribe to the open orders of the account, an update
+// is delivered for each order open when subscribing followed by each change
+// to an order until it is closed, canceled or expired. The client must be
+// created with WSClientWithAuthentication. C is closed when the connection
+// ends as by SubscribeOwnTrades
+func (c *WSClient) SubscribeOpenOrders(ctx context.Context, opts ...SubscribeOption) (*Subscription[OrderUpdate], error) {
+	orders := map[string]*wsOrderState{}
+	return subscribePrivate(ctx, c, "openOrders", opts, func(data []json.RawMessage) ([]OrderUpdate, error) {
+		return c.parser.parseWSOpenOrders(orders, data)
+	})
+}
+
+// AddOrder place an order over the authenticated websocket API, the order is
+// validated as by the REST AddOrder, including ValidateOrder with
+// WSClientWithOrderValidation. An error returned by the API is parsed as a
+// KrakenError and ErrWSClosed is returned when the connection ends before the
+// order is acknowledged
+func (c *WSClient) AddOrder(ctx context.Context, order OrderRequest) (AddOrderResult, error) {
+	order, err := order.prepare(c.validateOrders)
+	if err != nil {
+		return AddOrderResult{}, err
+	}
+
+	msg := wsAddOrder{
+		Event:     "addOrder",
+		OrderType: order.Type.String(),
+		Type:      order.Action.String(),
+		Pair:      order.Pair,
+		Volume:    order.Volume.String(),
+		Leverage:  order.Leverage,
+		OFlags:    strings.Join(order.Flags, ","),
+	}
+
+	if !order.Price.IsZero() {
+		msg.Price = order.Price.String()
+	}
+
+	if !order.SecondaryPrice.IsZero() {
+		msg.Price2 = order.SecondaryPrice.String()
+	}
+
+	if !order.StartTime.IsZero() {
+		msg.StartTm = strconv.FormatInt(order.StartTime.Unix(), 10)
+	}
+
+	if !order.ExpireTime.IsZero() {
+		msg.ExpireTm = strconv.FormatInt(order.ExpireTime.Unix(), 10)
+	}
+
+	if order.UserReference != nil {
+		msg.UserRef = strconv.FormatInt(int64(*order.UserReference), 10)
+	}
+
+	if order.ValidateOnly {
+		msg.Validate = strconv.FormatBool(true)
+	}
+
+	status, err := c.call(ctx, func(token string, reqID uint64) interface{} {
+		msg.Token, msg.ReqID = token, reqID
+		return msg
+	})
+	if err != nil {
+		return AddOrderResult{}, err
+	}
+
+	result := AddOrderResult{Description: status.Description}
+	if status.TransactionID != "" {
+		result.TransactionIDs = []string{status.TransactionID}
+	}
+
+	return result, nil
+}
+
+// CancelOrder cancel orders by transaction ID or user reference over the
+// authenticated websocket API, errors are returned as by AddOrder. The
+// websocket API does not report how many orders were cancelled, so the Count
+// of the result is always zero
+func (c *WSClient) CancelOrder(ctx context.Context, txids ...string) (CancelResult, error) {
+	if len(txids) == 0 {
+		return CancelResult{}, fmt.Errorf("txid is required")
+	}
+
+	_, err := c.call(ctx, func(token string, reqID uint64) interface{} {
+		return wsCancelOrder{Event: "cancelOrder", Token: token, ReqID: reqID, TxID: txids}
+	})
+	if err != nil {
+		return CancelResult{}, err
+	}
+
+	return CancelResult{}, nil
+}
+
+// call write an authenticated request built by msg and wait for its status,
+// a status other than "ok" is returned as a parsed API error
+func (c *WSClient) call(ctx context.Context, msg func(token string, reqID uint64) interface{}) (responseWSStatus, error) {
+	token, err := c.authenticate(ctx)
+	if err != nil {
+		return responseWSStatus{}, err
+	}
+
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return responseWSStatus{}, err
+	}
+
+	reqID, req := c.register(nil, "ok", 1)
+	defer c.unregister(reqID)
+
+	if err := c.write(conn, msg(token, reqID)); err != nil {
+		return responseWSStatus{}, err
+	}
+
+	select {
+	case status := <-req.statuses:
+		if status.Status != req.success {
+			return status, c.parser.parseError(status.ErrorMessage)
+		}
+
+		return status, nil
+	case <-ctx.Done():
+		return responseWSStatus{}, ctx.Err()
+	case <-conn.done:
+		return responseWSStatus{}, conn.err
+	}
+}
+
+// subscribePrivate subscribe to a private channel of the account, which is
+// authenticated with a token from the client's authentication
+func subscribePrivate[T any](ctx context.Context, c *WSClient, name string, opts []SubscribeOption, parse func(data []json.RawMessage) ([]T, error)) (*Subscription[T], error) {
+	token, err := c.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscribeEach(ctx, c, wsSubscriptionOptions{Name: name, Token: token}, nil, opts, func(pair string, data []json.RawMessage) ([]T, error) {
+		return parse(data)
+	})
+}
+
+// authenticate return a token for private channels, a new token is requested
+// when there is none or it is close to its expiry
+func (c *WSClient) authenticate(ctx context.Context) (string, error) {
+	if c.auth == nil {
+		return "", fmt.Errorf("private channels require WSClientWithAuthentication")
+	}
+
+	if c.version != WSVersion1 {
+		return "", fmt.Errorf("private channels are not supported by websocket %s", c.version)
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry.Add(-wsTokenRefreshMargin)) {
+		return c.token, nil
+	}
+
+	res, err := c.auth.GetWebSocketsToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(res.Errors) != 0 {
+		return "", res.Errors[0]
+	}
+
+	c.token = res.Token
+	c.tokenExpiry = time.Now().Add(res.Expires)
+
+	return c.token, nil
+}
+
+// subscribe send a subscribe request and wait for the status of each pair,
+// the parsed messages of the subscription are delivered on its channel
+func subscribe[T any](ctx context.Context, c *WSClient, options wsSubscriptionOptions, pairs []string, opts []SubscribeOption, parse func(pair string, data []json.RawMessage) (T, error)) (*Subscription[T], error) {
+	return subscribeEach(ctx, c, options, pairs, opts, func(pair string, data []json.RawMessage) ([]T, error) {
+		v, err := parse(pair, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return []T{v}, nil
+	})
+}
+
+// subscribeEach subscribe as subscribe, for channels with messages parsed
+// into any number of values. Pairs are required unless subscribing to a
+// private channel
+func subscribeEach[T any](ctx context.Context, c *WSClient, options wsSubscriptionOptions, pairs []string, opts []SubscribeOption, parse func(pair string, data []json.RawMessage) ([]T, error)) (*Subscription[T], error) {
+	if len(pairs) == 0 && options.Token == "" {
+		return nil, fmt.Errorf("at least one pair is required")
+	}
+
+	config, err := c.subscribeConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.pairChannels && options.Token != "" {
+		return nil, fmt.Errorf("pair channels are not supported by private channels")
+	}
+
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Subscription[T]{
+		client:  c,
+		conn:    conn,
+		options: options,
+		pairs:   pairs,
+	}
+
+	// every pair is delivered on ch unless each has a channel of its own
+	var ch chan T
+	channels := map[string]chan T{}
+	if config.pairChannels {
+		for _, pair := range pairs {
+			channels[pair] = make(chan T, config.bufferSize)
+		}
+		s.pairChannels = channels
+	} else {
+		ch = make(chan T, config.bufferSize)
+		s.C = ch
+	}
+
+	// the channels are only closed while holding mu, after stopped has
+	// released a delivery blocked on a full buffer, so values are never sent
+	// once closed
+	stopped := make(chan struct{})
+	var (
+		mu     sync.Mutex
+		closed bool
+		once   sync.Once
+	)
+
+	s.sub = &wsSubscription{
+		handle: func(pair string, data []json.RawMessage) error {
+			values, err := parse(pair, data)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if closed {
+				return nil
+			}
+
+			ch := ch
+			if config.pairChannels {
+				var ok bool
+				if ch, ok = channels[pair]; !ok {
+					return nil
+				}
+			}
+
+			var dropped int
+			for _, v := range values {
+				switch config.backpressure {
+				case WSBackpressureDrop:
+					select {
+					case ch <- v:
+					default:
+						dropped++
+					}
+				case WSBackpressureDropOldest:
+					select {
+					case ch <- v:
+						continue
+					default:
+					}
+
+					select {
+					case <-ch:
+					default:
+					}
+					dropped++
+
+					// only an unbuffered channel without a receiver is
+					// still full, when the newest is dropped instead
+					select {
+					case ch <- v:
+					default:
+					}
+				default:
+					select {
+					case ch <- v:
+					case <-conn.done:
+						return nil
+					case <-stopped:
+						return nil
+					}
+				}
+			}
+
+			if dropped > 0 {
+				s.dropped.Add(uint64(dropped))
+				c.metrics.droppedMessages(options.Name, pair, dropped)
+				return fmt.Errorf("%w: %d %s %s", ErrWSDropped, dropped, options.Name, pair)
+			}
+
+			return nil
+		},
+		close: func() {
+			once.Do(func() {
+				close(stopped)
+
+				mu.Lock()
+				closed = true
+				if ch != nil {
+					close(ch)
+				}
+				for _, ch := range channels {
+					close(ch)
+				}
+				mu.Unlock()
+			})
+		},
+	}
+
+	if err := c.subscribe(ctx, conn, options, pairs, s.sub); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// subscribeConfig return the configuration of a subscription with opts
+func (c *WSClient) subscribeConfig(opts []SubscribeOption) (wsSubscribeConfig, error) {
+	config := wsSubscribeConfig{bufferSize: c.bufferSize, backpressure: c.backpressure}
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return wsSubscribeConfig{}, err
+		}
+	}
+
+	return config, nil
+}
+
+// wsSubscribeConfig the configuration of a subscription, the buffer size and
+// backpressure are those of the client unless given by a SubscribeOption
+type wsSubscribeConfig struct {
+	bufferSize   int
+	backpressure WSBackpressure
+	pairChannels bool
+	onOHLCGap    func(OHLCGap)
+}
+
+// Subscription a subscription of a WSClient, values are delivered on C until
+// it is unsubscribed or the connection ends, when C is closed. C is nil when
+// subscribed with SubscribeWithPairChannels, see Pair
+type Subscription[T any] struct {
+	C <-chan T
+
+	client       *WSClient
+	conn         *wsConnection
+	options      wsSubscriptionOptions
+	pairs        []string
+	sub          *wsSubscription
+	pairChannels map[string]chan T
+	dropped      atomic.Uint64
+
+	mu           sync.Mutex
+	unsubscribed bool
+}
+
+// Pair return the channel the values of a pair are delivered on when
+// subscribed with SubscribeWithPairChannels, nil otherwise. The channel is
+// closed as C would be
+func (s *Subscription[T]) Pair(pair string) <-chan T {
+	return s.pairChannels[pair]
+}
+
+// Dropped return the number of values dropped as a buffer was full
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe send an unsubscribe request for the pairs of the subscription
+// and wait for their status, C is closed once it has returned. Subscriptions
+// to other pairs are unaffected and unsubscribing again does nothing
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.unsubscribed {
+		return nil
+	}
+	s.unsubscribed = true
+
+	// values are no longer delivered while waiting, a delivery blocked on a
+	// full C would otherwise hold up reading the status
+	s.sub.close()
+	defer s.client.release(s.sub)
+
+	err := s.client.unsubscribe(ctx, s.conn, s.options, s.pairs)
+	select {
+	case <-s.conn.done:
+		// the subscription ended with the connection
+		return nil
+	default:
+	}
+
+	return err
+}
+
+// subscribe send a subscribe request for sub and wait for the status of each
+// pair, on failure none of the pairs are delivered to sub. A channel already
+// subscribed to for a pair is not subscribed to again, as both subscriptions
+// would share the channel and unsubscribing either would end the other
+func (c *WSClient) subscribe(ctx context.Context, conn *wsConnection, options wsSubscriptionOptions, pairs []string, sub *wsSubscription) error {
+	if err := c.reserve(options, pairs, sub); err != nil {
+		c.metrics.subscriptionError(options.Name)
+		return err
+	}
+
+	err := c.request(ctx, conn, "subscribe", "subscribed", options, pairs, sub)
+	if err != nil {
+		c.metrics.subscriptionError(options.Name)
+
+		c.mu.Lock()
+		for _, pair := range pairs {
+			for key, s := range c.channels {
+				if s == sub && key.pair == pair {
+					delete(c.channels, key)
+				}
+			}
+		}
+		for _, key := range wsActiveKeys(options, pairs) {
+			if c.active[key] == sub {
+				delete(c.active, key)
+			}
+		}
+		c.metrics.setSubscriptions(len(c.channels))
+		c.mu.Unlock()
+	}
+
+	return err
+}
+
+// reserve mark the channel of each pair as subscribed to by sub, nothing is
+// reserved when any of them is already subscribed to by another subscription
+func (c *WSClient) reserve(options wsSubscriptionOptions, pairs []string, sub *wsSubscription) error {
+	keys := wsActiveKeys(options, pairs)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if s, ok := c.active[key]; ok && s != sub {
+			if key.pair == "" {
+				return fmt.Errorf("%w: %s: already subscribed", ErrSubscription, key.name)
+			}
+
+			return fmt.Errorf("%w: %s %s: already subscribed", ErrSubscription, key.name, key.pair)
+		}
+	}
+
+	for _, key := range keys {
+		c.active[key] = sub
+	}
+
+	return nil
+}
+
+// wsActiveKeys the keys of the channel of each pair subscribed to with options,
+// or the single key of a private channel
+func wsActiveKeys(options wsSubscriptionOptions, pairs []string) []wsActiveKey {
+	if len(pairs) == 0 {
+		return []wsActiveKey{{name: options.Name}}
+	}
+
+	keys := make([]wsActiveKey, 0, len(pairs))
+	for _, pair := range pairs {
+		keys = append(keys, wsActiveKey{name: options.Name, interval: options.Interval, depth: options.Depth, pair: pair})
+	}
+
+	return keys
+}
+
+// unsubscribe send an unsubscribe request and wait for the status of each
+// pair, messages of the pairs are no longer delivered once it has returned
+func (c *WSClient) unsubscribe(ctx context.Context, conn *wsConnection, options wsSubscriptionOptions, pairs []string) error {
+	return c.request(ctx, conn, "unsubscribe", "unsubscribed", options, pairs, nil)
+}
+
+// release stop routing the channel messages of any pair to sub, its channels
+// may then be subscribed to again
+func (c *WSClient) release(sub *wsSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, s := range c.channels {
+		if s == sub {
+			delete(c.channels, key)
+		}
+	}
+	for key, s := range c.active {
+		if s == sub {
+			delete(c.active, key)
+		}
+	}
+	c.metrics.setSubscriptions(len(c.channels))
+}
+
+// request send a subscribe or unsubscribe request and wait for the status of
+// each pair, or the single status of a private channel
+func (c *WSClient) request(ctx context.Context, conn *wsConnection, event, success string, options wsSubscriptionOptions, pairs []string, sub *wsSubscription) error {
+	reqID, req := c.register(sub, success, max(len(pairs), 1))
+	defer c.unregister(reqID)
+
+	return c.await(ctx, conn, wsSubscribe{
+		Event:        event,
+		ReqID:        reqID,
+		Pair:         pairs,
+		Subscription: options,
+	}, req)
+}
+
+// register add a pending request awaiting count statuses, the request must
+// be unregistered once complete
+func (c *WSClient) register(sub *wsSubscription, success string, count int) (uint64, *wsRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reqID++
+	req := &wsRequest{
+		sub:      sub,
+		success:  success,
+		statuses: make(chan responseWSStatus, count),
+	}
+	c.pending[c.reqID] = req
+
+	return c.reqID, req
+}
+
+// unregister remove a pending request, later statuses for it are ignored
+func (c *WSClient) unregister(reqID uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, reqID)
+}
+
+// await write a subscribe or unsubscribe request and wait for the status of
+// each of its pairs
+func (c *WSClient) await(ctx context.Context, conn *wsConnection, msg wsSubscribe, req *wsRequest) error {
+	if err := c.write(conn, c.subscribeMessage(msg)); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := 0; i < max(len(msg.Pair), 1); i++ {
+		select {
+		case status := <-req.statuses:
+			if status.Status == req.success {
+				continue
+			}
+
+			if status.Pair == "" {
+				// an error for the whole request rather than a single pair
+				return fmt.Errorf("%w: %s: %s", ErrSubscription, msg.Subscription.Name, status.ErrorMessage)
+			}
+
+			errs = append(errs, fmt.Errorf("%w: %s %s: %s", ErrSubscription, msg.Subscription.Name, status.Pair, status.ErrorMessage))
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-conn.done:
+			return conn.err
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// subscribeMessage return a subscribe or unsubscribe request message in the
+// protocol of the client's version
+func (c *WSClient) subscribeMessage(msg wsSubscribe) interface{} {
+	if c.version != WSVersion2 {
+		return msg
+	}
+
+	params := wsV2SubscribeParams{
+		Channel:  msg.Subscription.Name,
+		Symbol:   msg.Pair,
+		Interval: msg.Subscription.Interval,
+		Depth:    msg.Subscription.Depth,
+	}
+	if params.Channel == "spread" {
+		params.Channel = "ticker"
+		params.EventTrigger = "bbo"
+	}
+
+	return wsV2Subscribe{Method: msg.Event, Params: params, ReqID: msg.ReqID}
+}
+
+// connect return the open connection, dialling one when not connected
+func (c *WSClient) connect(ctx context.Context) (*wsConnection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrWSClosed
+	}
+
+	if c.conn != nil {
+		return c.conn, nil
+	}
+
+	ws, err := websocket.Dial(ctx, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNetwork, err)
+	}
+
+	c.conn = &wsConnection{conn: ws, done: make(chan struct{})}
+	c.err = nil
+
+	if c.dialled {
+		c.metrics.reconnected()
+	}
+	c.dialled = true
+	c.metrics.setConnected(true)
+	go c.read(c.conn)
+
+	return c.conn, nil
+}
+
+// write marshal and write a message to the connection
+func (c *WSClient) write(conn *wsConnection, v interface{}) error {
+	msg, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := conn.conn.WriteMessage(msg); err != nil {
+		if errors.Is(err, websocket.ErrClosed) {
+			return ErrWSClosed
+		}
+
+		return fmt.Errorf("%w: %s", ErrNetwork, err)
+	}
+
+	return nil
+}
+
+// read handle every message of a connection until it ends, the channels of
+// its subscriptions are then closed
+func (c *WSClient) read(conn *wsConnection) {
+	for {
+		msg, err := conn.conn.ReadMessage()
+		if err != nil {
+			conn.end(fmt.Errorf("%w: %s", ErrWSClosed, err))
+			break
+		}
+
+		if err := c.handle(msg, time.Now()); err != nil {
+			c.reportError(err)
+		}
+	}
+
+	// a token is only kept valid by the connection it was used on, so the
+	// next connection is authenticated with a new token
+	c.tokenMu.Lock()
+	c.token = ""
+	c.tokenMu.Unlock()
+
+	c.mu.Lock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	c.err = conn.err
+
+	subs := map[*wsSubscription]struct{}{}
+	for _, sub := range c.channels {
+		subs[sub] = struct{}{}
+	}
+	c.channels = map[wsChannelKey]*wsSubscription{}
+	c.active = map[wsActiveKey]*wsSubscription{}
+	c.metrics.setSubscriptions(0)
+	if c.conn == nil {
+		c.metrics.setConnected(false)
+	}
+	c.mu.Unlock()
+
+	for sub := range subs {
+		sub.close()
+	}
+}
+
+// handle a single message received at a time, either an event object or a
+// channel message array
+func (c *WSClient) handle(msg []byte, received time.Time) error {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 {
+		return fmt.Errorf("%w: empty websocket message", ErrParse)
+	}
+
+	if c.version == WSVersion2 {
+		return c.handleV2(msg, received)
+	}
+
+	if msg[0] == '{' {
+		return c.handleEvent(msg)
+	}
+
+	return c.handleChannelMessage(msg, received)
+}
+
+// handleEvent handle the status event of a request, other events such as
+// heartbeats are ignored
+func (c *WSClient) handleEvent(msg []byte) error {
+	event := responseWSStatus{}
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	switch event.Event {
+	case "subscriptionStatus", "addOrderStatus", "cancelOrderStatus", "error":
+		c.handleStatus(event)
+	}
+
+	return nil
+}
+
+// handleV2 handle a v2 message, the response to a subscribe or unsubscribe
+// request is handled as the subscriptionStatus of v1 and the data of a
+// channel message is passed to the subscription of each symbol as its value
+// followed by the message type. Other messages such as heartbeats are ignored
+func (c *WSClient) handleV2(msg []byte, received time.Time) error {
+	v := responseWSV2Message{}
+	if err := json.Unmarshal(msg, &v); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	switch v.Method {
+	case "subscribe", "unsubscribe":
+		status := responseWSStatus{
+			Event:        "subscriptionStatus",
+			ReqID:        v.ReqID,
+			Status:       "error",
+			Pair:         v.Symbol,
+			ChannelName:  wsV2ChannelName(v.Result.Channel, v.Result.Interval),
+			ErrorMessage: v.Error,
+		}
+		if v.Result.Symbol != "" {
+			status.Pair = v.Result.Symbol
+		}
+		if v.Success {
+			status.Status = v.Method + "d"
+		}
+
+		c.handleStatus(status)
+		return nil
+	case "":
+	default:
+		return nil
+	}
+
+	var errs []error
+	for _, data := range v.Data {
+		key := responseWSV2Symbol{}
+		if err := json.Unmarshal(data, &key); err != nil {
+			errs = append(errs, fmt.Errorf("%w:%s symbol: %s", ErrParse, v.Channel, err))
+			continue
+		}
+
+		name := wsV2ChannelName(v.Channel, key.Interval)
+		c.metrics.received(name, key.Symbol)
+
+		c.mu.Lock()
+		sub, ok := c.channels[wsChannelKey{name: name, pair: key.Symbol}]
+		c.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		if err := sub.handle(key.Symbol, []json.RawMessage{data, v.Type}); err != nil {
+			errs = append(errs, err)
+		}
+		c.metrics.processed(name, received)
+	}
+
+	return errors.Join(errs...)
+}
+
+// wsV2ChannelName the name of a v2 channel as routed, an interval is added
+// to the ohlc channel as it is by v1 so each interval is routed separately
+func wsV2ChannelName(channel string, interval int) string {
+	if channel == "ohlc" {
+		return fmt.Sprintf("ohlc-%d", interval)
+	}
+
+	return channel
+}
+
+// handleStatus pass a status to the request awaiting it, routing the channel
+// of a pair once subscribed and removing it once unsubscribed
+func (c *WSClient) handleStatus(event responseWSStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req, ok := c.pending[event.ReqID]
+	if !ok {
+		return
+	}
+
+	key := wsChannelKey{name: event.ChannelName, pair: event.Pair}
+	switch event.Status {
+	case "subscribed":
+		if req.sub != nil {
+			c.channels[key] = req.sub
+		}
+	case "unsubscribed":
+		delete(c.channels, key)
+	}
+	c.metrics.setSubscriptions(len(c.channels))
+
+	select {
+	case req.statuses <- event:
+	default:
+	}
+}
+
+// handleChannelMessage pass the data of a [channelID, data..., channel name,
+// pair] public channel message or a [data, channel name, {"sequence": n}]
+// private channel message to its subscription
+func (c *WSClient) handleChannelMessage(msg []byte, received time.Time) error {
+	values := []json.RawMessage{}
+	if err := json.Unmarshal(msg, &values); err != nil {
+		return fmt.Errorf("%w:%s", ErrParse, err)
+	}
+
+	key := wsChannelKey{}
+	var data []json.RawMessage
+	switch {
+	case len(values) == 3 && bytes.HasPrefix(values[2], []byte("{")):
+		data = values[:1]
+	case len(values) >= 4:
+		if err := json.Unmarshal(values[len(values)-1], &key.pair); err != nil {
+			return fmt.Errorf("%w:pair: %s", ErrParse, err)
+		}
+
+		data = values[1 : len(values)-2]
+	default:
+		return fmt.Errorf("%w:channel message has %d values, expected at least 3", ErrParse, len(values))
+	}
+
+	if err := json.Unmarshal(values[len(values)-2], &key.name); err != nil {
+		return fmt.Errorf("%w:channel name: %s", ErrParse, err)
+	}
+
+	c.metrics.received(key.name, key.pair)
+
+	c.mu.Lock()
+	sub, ok := c.channels[key]
+	c.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	defer c.metrics.processed(key.name, received)
+
+	return sub.handle(key.pair, data)
+}
+
+// reportError pass an error handling a message to the error handler
+func (c *WSClient) reportError(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
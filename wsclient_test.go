@@ -0,0 +1,1441 @@
+package kraken_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/shopspring/decimal"
+)
+
+// newWSTestServer start a krakentest.WSServer and a client connected to it
+func newWSTestServer(t *testing.T, opts ...krakentest.WSServerOption) (*krakentest.WSServer, *kraken.WSClient) {
+	s, c, err := krakentest.NewWSServer(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, c
+}
+
+// wsScript the messages written by a websocket test server once a channel
+// and pair are subscribed to
+func wsScript(channelName, pair string, payloads ...string) krakentest.WSServerOption {
+	return krakentest.WSServerWithScript(channelName, pair, wsFrames(payloads...)...)
+}
+
+// wsFrames the frames writing each payload
+func wsFrames(payloads ...string) []krakentest.WSFrame {
+	frames := []krakentest.WSFrame{}
+	for _, payload := range payloads {
+		frames = append(frames, krakentest.WSMessage(payload))
+	}
+
+	return frames
+}
+
+func TestWSClientSubscribeTicker(t *testing.T) {
+	s, c := newWSTestServer(t, wsScript("ticker", "XBT/USD",
+		`{"event":"heartbeat"}`,
+		`[340,{"a":["38659.60000",2,"2.50000000"],"b":["38658.70000",1,"1.00000000"],"c":["38658.90000","0.02120800"],"v":["3150.86186124","3404.34671000"],"p":["38609.60189","38601.37073"],"t":[24864,27336],"l":["38132.50000","38132.50000"],"h":["38988.00000","38988.00000"],"o":["38466.40000","38401.10000"]},"ticker","XBT/USD"]`,
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := kraken.Ticker{
+		Pair: "XBT/USD",
+		Ask: kraken.AskBid{
+			Price:          decimal.RequireFromString("38659.6"),
+			WholeLotVolume: decimal.RequireFromString("2"),
+			Volume:         decimal.RequireFromString("2.5"),
+		},
+		Bid: kraken.AskBid{
+			Price:          decimal.RequireFromString("38658.7"),
+			WholeLotVolume: decimal.RequireFromString("1"),
+			Volume:         decimal.RequireFromString("1"),
+		},
+		LastClose: kraken.Close{
+			Price:  decimal.RequireFromString("38658.9"),
+			Volume: decimal.RequireFromString("0.021208"),
+		},
+		VolumeToday:                           decimal.RequireFromString("3150.86186124"),
+		VolumeLast24Hours:                     decimal.RequireFromString("3404.34671"),
+		VolumeWeightedAveragePriceToday:       decimal.RequireFromString("38609.60189"),
+		VolumeWeightedAveragePriceLast24Hours: decimal.RequireFromString("38601.37073"),
+		NumberOfTradesToday:                   24864,
+		NumberOfTradesLast24Hours:             27336,
+		LowToday:                              decimal.RequireFromString("38132.5"),
+		LowLast24Hours:                        decimal.RequireFromString("38132.5"),
+		HighToday:                             decimal.RequireFromString("38988"),
+		HighLast24Hours:                       decimal.RequireFromString("38988"),
+		Open:                                  decimal.RequireFromString("38466.4"),
+	}
+
+	select {
+	case ticker := <-tickers.C:
+		if diff := deep.Equal(expected, ticker); diff != nil {
+			t.Error(diff)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestWSClientSubscribeOHLC(t *testing.T) {
+	s, c := newWSTestServer(t, wsScript("ohlc-5", "XBT/USD",
+		`[42,["1542057314.748456","1542057360.435743","3586.70000","3586.70000","3586.60000","3586.60000","3586.68894","0.03373000",2],"ohlc-5","XBT/USD"]`,
+		`[42,["1542057365.124560","1542057660.000000","3586.60000","3586.90000","3586.60000","3586.90000","3586.75000",1.5,3],"ohlc-5","XBT/USD"]`,
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	updates, err := c.SubscribeOHLC(ctx, kraken.OHLCInterval5Minutes, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []kraken.OHLCUpdate{
+		{
+			Pair: "XBT/USD",
+			OHLC: kraken.OHLC{
+				Time:                       time.Unix(1542057060, 435743000).UTC(),
+				Open:                       decimal.RequireFromString("3586.7"),
+				High:                       decimal.RequireFromString("3586.7"),
+				Low:                        decimal.RequireFromString("3586.6"),
+				Close:                      decimal.RequireFromString("3586.6"),
+				VolumeWeightedAveragePrice: decimal.RequireFromString("3586.68894"),
+				Volume:                     decimal.RequireFromString("0.03373"),
+				Count:                      2,
+			},
+			EndTime: time.Unix(1542057360, 435743000).UTC(),
+		},
+		{
+			Pair: "XBT/USD",
+			OHLC: kraken.OHLC{
+				Time:                       time.Unix(1542057360, 0).UTC(),
+				Open:                       decimal.RequireFromString("3586.6"),
+				High:                       decimal.RequireFromString("3586.9"),
+				Low:                        decimal.RequireFromString("3586.6"),
+				Close:                      decimal.RequireFromString("3586.9"),
+				VolumeWeightedAveragePrice: decimal.RequireFromString("3586.75"),
+				Volume:                     decimal.RequireFromString("1.5"),
+				Count:                      3,
+			},
+			EndTime: time.Unix(1542057660, 0).UTC(),
+		},
+	}
+
+	for _, e := range expected {
+		select {
+		case update := <-updates.C:
+			if diff := deep.Equal(e, update); diff != nil {
+				t.Error(diff)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
+
+func TestWSClientSubscribeOHLCGaps(t *testing.T) {
+	end := int64(1542057600)
+	ohlc := func(endTime int64) string {
+		return fmt.Sprintf(`[42,["%d.000000","%d.000000","3586.70000","3586.70000","3586.60000","3586.60000","3586.68894","0.03373000",2],"ohlc-5","XBT/USD"]`, endTime-60, endTime)
+	}
+
+	s, c := newWSTestServer(t, wsScript("ohlc-5", "XBT/USD",
+		ohlc(end),
+		ohlc(end),
+		// the candle ending at end+300 is missing
+		ohlc(end+600),
+		// received out of order
+		ohlc(end+300),
+		ohlc(end+900),
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	gaps := make(chan kraken.OHLCGap, 5)
+	updates, err := c.SubscribeOHLC(ctx, kraken.OHLCInterval5Minutes, []string{"XBT/USD"}, kraken.SubscribeWithOHLCGapHandler(func(gap kraken.OHLCGap) { gaps <- gap }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		select {
+		case <-updates.C:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+	close(gaps)
+
+	actual := []kraken.OHLCGap{}
+	for gap := range gaps {
+		actual = append(actual, gap)
+	}
+
+	expected := []kraken.OHLCGap{
+		{Pair: "XBT/USD", Interval: kraken.OHLCInterval5Minutes, PreviousEndTime: time.Unix(end, 0).UTC(), EndTime: time.Unix(end+600, 0).UTC()},
+		{Pair: "XBT/USD", Interval: kraken.OHLCInterval5Minutes, PreviousEndTime: time.Unix(end+600, 0).UTC(), EndTime: time.Unix(end+300, 0).UTC()},
+	}
+	if diff := deep.Equal(expected, actual); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestWSClientSubscribeOHLCInterval(t *testing.T) {
+	c, err := kraken.NewWSClient(kraken.WSClientWithURL("ws://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "unsupported ohlc interval 2"
+	if _, err := c.SubscribeOHLC(context.Background(), kraken.OHLCInterval(2), []string{"XBT/USD"}); err == nil || err.Error() != expected {
+		t.Errorf("EXPECTED: %s\nACTUAL: %v", expected, err)
+	}
+}
+
+// wsTicker a ticker channel message of a pair
+func wsTicker(pair string) string {
+	return fmt.Sprintf(`[340,%s,"ticker",%q]`, `{"a":["38659.60000",2,"2.50000000"],"b":["38658.70000",1,"1.00000000"],"c":["38658.90000","0.02120800"],"v":["3150.86186124","3404.34671000"],"p":["38609.60189","38601.37073"],"t":[24864,27336],"l":["38132.50000","38132.50000"],"h":["38988.00000","38988.00000"],"o":["38466.40000","38401.10000"]}`, pair)
+}
+
+// wsTrades a trade channel message with three trades
+const wsTrades = `[0,[["5541.20000","0.15850568","1534614057.321597","s","l",""],["6060.00000","0.02455000","1534614057.324998","b","l",""],["6060.00000",0.5,1534614058,"b","m",""]],"trade","XBT/USD"]`
+
+// wsTradeUpdates the trades of wsTrades
+var wsTradeUpdates = []kraken.TradeUpdate{
+	{
+		Pair: "XBT/USD",
+		Trade: kraken.RecentTrade{
+			Price:  decimal.RequireFromString("5541.2"),
+			Volume: decimal.RequireFromString("0.15850568"),
+			Time:   time.Unix(1534614057, 321597000).UTC(),
+			Action: kraken.OrderActionSell,
+			Type:   kraken.OrderTypeLimit,
+		},
+	},
+	{
+		Pair: "XBT/USD",
+		Trade: kraken.RecentTrade{
+			Price:  decimal.RequireFromString("6060"),
+			Volume: decimal.RequireFromString("0.02455"),
+			Time:   time.Unix(1534614057, 324998000).UTC(),
+			Action: kraken.OrderActionBuy,
+			Type:   kraken.OrderTypeLimit,
+		},
+	},
+	{
+		Pair: "XBT/USD",
+		Trade: kraken.RecentTrade{
+			Price:  decimal.RequireFromString("6060"),
+			Volume: decimal.RequireFromString("0.5"),
+			Time:   time.Unix(1534614058, 0).UTC(),
+			Action: kraken.OrderActionBuy,
+			Type:   kraken.OrderTypeMarket,
+		},
+	},
+}
+
+func TestWSClientSubscribeTrades(t *testing.T) {
+	s, c := newWSTestServer(t, wsScript("trade", "XBT/USD", wsTrades))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	trades, err := c.SubscribeTrades(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range wsTradeUpdates {
+		select {
+		case trade := <-trades.C:
+			if diff := deep.Equal(expected, trade); diff != nil {
+				t.Error(diff)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
+
+func TestWSClientWithBackpressure(t *testing.T) {
+	errs := make(chan error, 1)
+	s, c := newWSTestServer(t,
+		wsScript("trade", "XBT/USD", wsTrades),
+		krakentest.WSServerWithClientOptions(
+			kraken.WSClientWithBufferSize(1),
+			kraken.WSClientWithBackpressure(kraken.WSBackpressureDrop),
+			kraken.WSClientWithErrorHandler(func(err error) { errs <- err }),
+		),
+	)
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	trades, err := c.SubscribeTrades(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		expected := "websocket message dropped: 2 trade XBT/USD"
+		if !errors.Is(err, kraken.ErrWSDropped) || err.Error() != expected {
+			t.Errorf("EXPECTED: %s\nACTUAL: %v", expected, err)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	if diff := deep.Equal(wsTradeUpdates[0], <-trades.C); diff != nil {
+		t.Error(diff)
+	}
+
+	if _, err := kraken.NewWSClient(kraken.WSClientWithBackpressure(kraken.WSBackpressure(3))); err == nil {
+		t.Error("EXPECTED: unknown backpressure error\nACTUAL: <nil>")
+	}
+}
+
+func TestWSClientSubscribePairChannels(t *testing.T) {
+	s, c := newWSTestServer(t,
+		wsScript("trade", "XBT/USD", wsTrades),
+		wsScript("trade", "ETH/USD", `[0,[["2000.00000","1.00000000","1534614059.000000","b","m",""]],"trade","ETH/USD"]`),
+		krakentest.WSServerWithClientOptions(kraken.WSClientWithErrorHandler(func(error) {})),
+	)
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	trades, err := c.SubscribeTrades(ctx, []string{"XBT/USD", "ETH/USD"},
+		kraken.SubscribeWithPairChannels(),
+		kraken.SubscribeWithBufferSize(1),
+		kraken.SubscribeWithBackpressure(kraken.WSBackpressureDropOldest),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trades.C != nil || trades.Pair("XBT/EUR") != nil {
+		t.Error("EXPECTED: only channels of the subscribed pairs\nACTUAL: other channels")
+	}
+
+	// the consumer of XBT/USD has stalled, which must not delay ETH/USD
+	select {
+	case trade := <-trades.Pair("ETH/USD"):
+		if trade.Pair != "ETH/USD" || !trade.Trade.Price.Equal(decimal.RequireFromString("2000")) {
+			t.Errorf("EXPECTED: ETH/USD trade at 2000\nACTUAL: %+v", trade)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	if diff := deep.Equal(wsTradeUpdates[2], <-trades.Pair("XBT/USD")); diff != nil {
+		t.Error(diff)
+	}
+
+	if trades.Dropped() != 2 {
+		t.Errorf("EXPECTED: 2 dropped\nACTUAL: %d", trades.Dropped())
+	}
+
+	if err := trades.Unsubscribe(ctx); err != nil {
+		t.Error(err)
+	}
+
+	for _, pair := range []string{"XBT/USD", "ETH/USD"} {
+		if _, ok := <-trades.Pair(pair); ok {
+			t.Errorf("EXPECTED: closed %s channel\nACTUAL: trade delivered", pair)
+		}
+	}
+}
+
+func TestSubscribeOptions(t *testing.T) {
+	c, err := kraken.NewWSClient(kraken.WSClientWithURL("ws://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name     string
+		opt      kraken.SubscribeOption
+		expected string
+	}{
+		{name: "buffer size", opt: kraken.SubscribeWithBufferSize(-1), expected: "buffer size must not be negative"},
+		{name: "backpressure", opt: kraken.SubscribeWithBackpressure(kraken.WSBackpressure(3)), expected: "unknown backpressure 3"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := c.SubscribeTrades(context.Background(), []string{"XBT/USD"}, tc.opt); err == nil || err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestWSClientSubscribeSpread(t *testing.T) {
+	s, c := newWSTestServer(t, wsScript("spread", "XBT/USD",
+		`[0,["5698.40000","5700.00000","1542057299.545897","1.01234567","0.98765432"],"spread","XBT/USD"]`,
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	spreads, err := c.SubscribeSpread(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := kraken.SpreadUpdate{
+		Pair: "XBT/USD",
+		Spread: kraken.Spread{
+			Timestamp: time.Unix(1542057299, 545897000).UTC(),
+			Bid:       decimal.RequireFromString("5698.4"),
+			Ask:       decimal.RequireFromString("5700"),
+		},
+	}
+
+	select {
+	case spread := <-spreads.C:
+		if diff := deep.Equal(expected, spread); diff != nil {
+			t.Error(diff)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestWSClientSubscribeBook(t *testing.T) {
+	s, c := newWSTestServer(t, wsScript("book-10", "XBT/USD",
+		`[0,{"as":[["5541.30000","2.50700000","1534614248.123678"],["5541.80000","0.33000000","1534614098.345543"]],"bs":[["5541.20000","1.52900000","1534614248.765567"]]},"book-10","XBT/USD"]`,
+		`[1234,{"a":[["5541.30000","0.00000000","1534614335.345903"],["5542.00000","1.00000000","1534614335.345903","r"]],"c":"974942666"},"book-10","XBT/USD"]`,
+		`[1234,{"a":[["5541.80000","0.40000000","1534614335.456738"]]},{"b":[["5541.20000","1.00000000","1534614335.456738"]],"c":"3310070434"},"book-10","XBT/USD"]`,
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	books, err := c.SubscribeBook(ctx, 10, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []kraken.BookUpdate{
+		{
+			Pair:     "XBT/USD",
+			Depth:    10,
+			Snapshot: true,
+			Asks: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5541.3"), Volume: decimal.RequireFromString("2.507"), Timestamp: time.Unix(1534614248, 123678000).UTC()},
+				{Price: decimal.RequireFromString("5541.8"), Volume: decimal.RequireFromString("0.33"), Timestamp: time.Unix(1534614098, 345543000).UTC()},
+			},
+			Bids: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5541.2"), Volume: decimal.RequireFromString("1.529"), Timestamp: time.Unix(1534614248, 765567000).UTC()},
+			},
+		},
+		{
+			Pair:  "XBT/USD",
+			Depth: 10,
+			Asks: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5541.3"), Volume: decimal.Zero, Timestamp: time.Unix(1534614335, 345903000).UTC()},
+				{Price: decimal.RequireFromString("5542"), Volume: decimal.RequireFromString("1"), Timestamp: time.Unix(1534614335, 345903000).UTC()},
+			},
+			Checksum: 974942666,
+		},
+		{
+			Pair:  "XBT/USD",
+			Depth: 10,
+			Asks: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5541.8"), Volume: decimal.RequireFromString("0.4"), Timestamp: time.Unix(1534614335, 456738000).UTC()},
+			},
+			Bids: []kraken.AskBid{
+				{Price: decimal.RequireFromString("5541.2"), Volume: decimal.RequireFromString("1"), Timestamp: time.Unix(1534614335, 456738000).UTC()},
+			},
+			Checksum: 3310070434,
+		},
+	}
+
+	for _, e := range expected {
+		select {
+		case book := <-books.C:
+			if diff := deep.Equal(e, book); diff != nil {
+				t.Error(diff)
+			}
+
+			// the exponent given is kept for calculating checksums
+			if exp := book.Asks[0].Price.Exponent(); exp != -5 {
+				t.Errorf("EXPECTED: -5\nACTUAL: %d", exp)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
+
+func TestWSClientSubscribeBookDepth(t *testing.T) {
+	c, err := kraken.NewWSClient(kraken.WSClientWithURL("ws://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "unsupported book depth 50"
+	if _, err := c.SubscribeBook(context.Background(), 50, []string{"XBT/USD"}); err == nil || err.Error() != expected {
+		t.Errorf("EXPECTED: %s\nACTUAL: %v", expected, err)
+	}
+}
+
+// wsTestOrder the order fields of an addOrder or cancelOrder request
+// received by a websocket test server
+type wsTestOrder struct {
+	OrderType string   `json:"ordertype"`
+	Price     string   `json:"price"`
+	TxID      []string `json:"txid"`
+}
+
+// newWSAuthTestServer start a websocket server as newWSTestServer with a
+// client authenticated by a mock client returning tokens valid for expires,
+// events are replied to by their handler or the server's default once the
+// token of the subscription or order is checked
+func newWSAuthTestServer(t *testing.T, expires time.Duration, handlers map[string]krakentest.WSHandler, opts ...krakentest.WSServerOption) (*krakentest.WSServer, *kraken.WSClient, *krakentest.MockClient) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tokens atomic.Int32
+	m.OnGetWebSocketsToken(func(ctx context.Context) (kraken.WebSocketsToken, error) {
+		return kraken.WebSocketsToken{Token: fmt.Sprintf("TOKEN%d", tokens.Add(1)), Expires: expires}, nil
+	})
+
+	s, c := newWSTestServer(t, append(opts, krakentest.WSServerWithClientOptions(kraken.WSClientWithAuthentication(m)))...)
+
+	if _, ok := handlers["subscribe"]; !ok {
+		handlers["subscribe"] = s.Subscribe
+	}
+
+	for event, handler := range handlers {
+		handler := handler
+		s.Handle(event, func(req krakentest.WSRequest) []krakentest.WSFrame {
+			token := req.Subscription.Token
+			if req.Token != "" {
+				token = req.Token
+			}
+
+			if expected := fmt.Sprintf("TOKEN%d", tokens.Load()); token != expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", expected, token)
+			}
+
+			return handler(req)
+		})
+	}
+
+	return s, c, m
+}
+
+func TestWSClientSubscribeOwnTrades(t *testing.T) {
+	s, c, _ := newWSAuthTestServer(t, 15*time.Minute, map[string]krakentest.WSHandler{}, wsScript("ownTrades", "",
+		`[[{"TDLH43-DVQXD-2KHVYY":{"cost":"1000000.00000","fee":"1600.00000","margin":"0.00000","ordertxid":"TDLH43-DVQXD-KHVYY","ordertype":"limit","pair":"XBT/EUR","postxid":"OGTT3Y-C6I3P-XRI6HX","price":"100000.00000","time":"1560516023.070651","type":"sell","vol":"10.00000000"}},{"TDLH43-DVQXD-2KHVYZ":{"cost":"500.00000","fee":"0.80000","margin":"0.00000","ordertxid":"TDLH43-DVQXD-KHVYZ","ordertype":"market","pair":"XBT/EUR","postxid":"","price":"50000.00000","time":"1560516024","type":"buy","userref":1,"vol":"0.01000000"}}],"ownTrades",{"sequence":1}]`,
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	trades, err := c.SubscribeOwnTrades(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []kraken.TradeHistoryEntry{
+		{
+			TransactionID:         "TDLH43-DVQXD-2KHVYY",
+			OrderTransactionID:    "TDLH43-DVQXD-KHVYY",
+			PositionTransactionID: "OGTT3Y-C6I3P-XRI6HX",
+			Pair:                  "XBT/EUR",
+			Time:                  time.Unix(1560516023, 70651000).UTC(),
+			Action:                kraken.OrderActionSell,
+			Type:                  kraken.OrderTypeLimit,
+			Price:                 decimal.RequireFromString("100000"),
+			Cost:                  decimal.RequireFromString("1000000"),
+			Fee:                   decimal.RequireFromString("1600"),
+			Volume:                decimal.RequireFromString("10"),
+			Margin:                decimal.Zero,
+		},
+		{
+			TransactionID:      "TDLH43-DVQXD-2KHVYZ",
+			OrderTransactionID: "TDLH43-DVQXD-KHVYZ",
+			Pair:               "XBT/EUR",
+			Time:               time.Unix(1560516024, 0).UTC(),
+			Action:             kraken.OrderActionBuy,
+			Type:               kraken.OrderTypeMarket,
+			Price:              decimal.RequireFromString("50000"),
+			Cost:               decimal.RequireFromString("500"),
+			Fee:                decimal.RequireFromString("0.8"),
+			Volume:             decimal.RequireFromString("0.01"),
+			Margin:             decimal.Zero,
+		},
+	}
+
+	for _, e := range expected {
+		select {
+		case trade := <-trades.C:
+			if diff := deep.Equal(e, trade); diff != nil {
+				t.Error(diff)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
+
+func TestWSClientPrivateDrop(t *testing.T) {
+	s, c, m := newWSAuthTestServer(t, 15*time.Minute, map[string]krakentest.WSHandler{})
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	trades, err := c.SubscribeOwnTrades(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the client does not reconnect, the subscription ends with the
+	// connection
+	s.Drop()
+	select {
+	case _, ok := <-trades.C:
+		if ok {
+			t.Error("EXPECTED: closed\nACTUAL: trade")
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// subscribing again opens a second connection with a new token
+	if _, err := c.SubscribeOwnTrades(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := len(m.CallsTo("GetWebSocketsToken")); calls != 2 {
+		t.Errorf("EXPECTED: 2\nACTUAL: %d", calls)
+	}
+
+	if connections := s.Connections(); connections != 2 {
+		t.Errorf("EXPECTED: 2\nACTUAL: %d", connections)
+	}
+}
+
+func TestWSClientSubscribeOpenOrders(t *testing.T) {
+	s, c, _ := newWSAuthTestServer(t, 15*time.Minute, map[string]krakentest.WSHandler{}, wsScript("openOrders", "",
+		`[[{"OGTT3Y-C6I3P-XRI6HX":{"avg_price":"0.00000","cost":"0.00000","descr":{"close":null,"leverage":null,"order":"sell 10.00000000 XBT/EUR @ limit 34.50000","ordertype":"limit","pair":"XBT/EUR","price":"34.50000","price2":"0.00000","position":"","type":"sell"},"expiretm":null,"fee":"0.00000","limitprice":"34.50000","misc":"","oflags":"fcib","opentm":"1560516023.070651","refid":null,"starttm":null,"status":"pending","stopprice":"0.000000","userref":0,"vol":"10.00000000","vol_exec":"0.00000000"}}],"openOrders",{"sequence":1}]`,
+		`[[{"OGTT3Y-C6I3P-XRI6HX":{"status":"open","userref":0}}],"openOrders",{"sequence":2}]`,
+		`[[{"OGTT3Y-C6I3P-XRI6HX":{"vol_exec":"4.00000000","cost":"138.00000","fee":"0.22000","avg_price":"34.50000","userref":0}}],"openOrders",{"sequence":3}]`,
+		`[[{"OGTT3Y-C6I3P-XRI6HX":{"lastupdated":"1560516030.123456","status":"canceled","vol_exec":"4.00000000","cost":"138.00000","fee":"0.22000","avg_price":"34.50000","userref":0,"cancel_reason":"User requested"}}],"openOrders",{"sequence":4}]`,
+	))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	updates, err := c.SubscribeOpenOrders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		updateType     kraken.OrderUpdateType
+		status         kraken.OrderStatus
+		previousStatus kraken.OrderStatus
+		volumeExecuted string
+		reason         string
+	}{
+		{updateType: kraken.OrderUpdateTypeNew, status: kraken.OrderStatusPending, volumeExecuted: "0"},
+		{updateType: kraken.OrderUpdateTypeStatus, status: kraken.OrderStatusOpen, previousStatus: kraken.OrderStatusPending, volumeExecuted: "0"},
+		{updateType: kraken.OrderUpdateTypeChanged, status: kraken.OrderStatusOpen, previousStatus: kraken.OrderStatusOpen, volumeExecuted: "4"},
+		{updateType: kraken.OrderUpdateTypeStatus, status: kraken.OrderStatusCanceled, previousStatus: kraken.OrderStatusOpen, volumeExecuted: "4", reason: "User requested"},
+	}
+
+	for _, e := range expected {
+		select {
+		case update := <-updates.C:
+			if update.Type != e.updateType || update.Order.Status != e.status || update.PreviousStatus != e.previousStatus {
+				t.Errorf("EXPECTED: %s %s -> %s\nACTUAL: %s %s -> %s", e.updateType, e.previousStatus, e.status, update.Type, update.PreviousStatus, update.Order.Status)
+			}
+
+			if !update.Order.VolumeExecuted.Equal(decimal.RequireFromString(e.volumeExecuted)) || update.Order.CloseReason != e.reason {
+				t.Errorf("EXPECTED: %s %q\nACTUAL: %s %q", e.volumeExecuted, e.reason, update.Order.VolumeExecuted, update.Order.CloseReason)
+			}
+
+			// fields of earlier updates are kept
+			if update.Order.TransactionID != "OGTT3Y-C6I3P-XRI6HX" || update.Order.Description.Pair != "XBT/EUR" || !update.Order.Volume.Equal(decimal.RequireFromString("10")) {
+				t.Errorf("EXPECTED: complete order\nACTUAL: %+v", update.Order)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
+
+func TestWSClientWithAuthentication(t *testing.T) {
+	tcs := []struct {
+		name     string
+		expires  time.Duration
+		expected int
+	}{
+		{name: "TokenReused", expires: 15 * time.Minute, expected: 1},
+		{name: "TokenRefreshed", expires: 30 * time.Second, expected: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s, c, m := newWSAuthTestServer(t, tc.expires, map[string]krakentest.WSHandler{})
+			defer s.Close()
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if _, err := c.SubscribeOwnTrades(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := c.SubscribeOpenOrders(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if calls := len(m.CallsTo("GetWebSocketsToken")); calls != tc.expected {
+				t.Errorf("EXPECTED: %d\nACTUAL: %d", tc.expected, calls)
+			}
+		})
+	}
+
+	c, err := kraken.NewWSClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.SubscribeOwnTrades(context.Background()); err == nil {
+		t.Error("EXPECTED: authentication required error\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewWSClient(kraken.WSClientWithAuthentication(nil)); err == nil {
+		t.Error("EXPECTED: client required error\nACTUAL: <nil>")
+	}
+}
+
+func TestWSClientAddOrder(t *testing.T) {
+	s, c, _ := newWSAuthTestServer(t, 15*time.Minute, map[string]krakentest.WSHandler{"addOrder": func(req krakentest.WSRequest) []krakentest.WSFrame {
+		order := wsTestOrder{}
+		if err := json.Unmarshal(req.Raw, &order); err != nil {
+			t.Error(err)
+		}
+
+		switch {
+		case order.Price == "1":
+			return wsFrames(fmt.Sprintf(`{"errorMessage":"EOrder:Order minimum not met","event":"addOrderStatus","status":"error","reqid":%d}`, req.ReqID))
+		case order.Price == "2":
+			return []krakentest.WSFrame{krakentest.WSDrop()}
+		case order.Price == "3":
+			return nil
+		}
+
+		if len(req.Pairs) != 1 || req.Pairs[0] != "XBTUSD" || order.OrderType != "limit" {
+			t.Errorf("EXPECTED: XBTUSD limit\nACTUAL: %v %s", req.Pairs, order.OrderType)
+		}
+
+		return wsFrames(
+			`{"event":"addOrderStatus","status":"ok","reqid":999}`,
+			fmt.Sprintf(`{"descr":"buy 0.01770000 XBTUSD @ limit 4000","event":"addOrderStatus","status":"ok","txid":"ONPNXH-KMKMU-F4MR5V","reqid":%d}`, req.ReqID),
+		)
+	}})
+	defer s.Close()
+	defer c.Close()
+
+	order := func(price string) kraken.OrderRequest {
+		return kraken.OrderRequest{
+			Pair:   "XBTUSD",
+			Action: kraken.OrderActionBuy,
+			Type:   kraken.OrderTypeLimit,
+			Volume: decimal.RequireFromString("0.0177"),
+			Price:  decimal.RequireFromString(price),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := c.AddOrder(ctx, order("4000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := kraken.AddOrderResult{Description: "buy 0.01770000 XBTUSD @ limit 4000", TransactionIDs: []string{"ONPNXH-KMKMU-F4MR5V"}}
+	if diff := deep.Equal(expected, res); diff != nil {
+		t.Error(diff)
+	}
+
+	if _, err := c.AddOrder(ctx, order("1")); !errors.Is(err, kraken.ErrOrderMinimum) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrOrderMinimum, err)
+	}
+
+	// the order is validated as by the REST client
+	invalid := order("4000")
+	invalid.Price = decimal.Zero
+	if _, err := c.AddOrder(ctx, invalid); err == nil || err.Error() != "price is required for limit orders" {
+		t.Errorf("EXPECTED: price is required for limit orders\nACTUAL: %v", err)
+	}
+
+	timeout, cancelTimeout := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelTimeout()
+
+	if _, err := c.AddOrder(timeout, order("3")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.DeadlineExceeded, err)
+	}
+
+	if _, err := c.AddOrder(ctx, order("2")); !errors.Is(err, kraken.ErrWSClosed) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrWSClosed, err)
+	}
+}
+
+func TestWSClientAddOrderValidation(t *testing.T) {
+	s, c, _ := newWSAuthTestServer(t, 15*time.Minute, map[string]krakentest.WSHandler{"addOrder": func(req krakentest.WSRequest) []krakentest.WSFrame {
+		return wsFrames(fmt.Sprintf(`{"descr":"buy 0.00001000 XBTUSD @ limit 38000","event":"addOrderStatus","status":"ok","txid":"ONPNXH-KMKMU-F4MR5V","reqid":%d}`, req.ReqID))
+	}}, krakentest.WSServerWithClientOptions(kraken.WSClientWithOrderValidation()))
+	defer s.Close()
+	defer c.Close()
+
+	pair := validationPair(kraken.AssetPairStatusOnline)
+	order := limitOrder(kraken.OrderActionBuy, "0.00001", "38000")
+	order.AssetPair = &pair
+	order.ExactValues = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := c.AddOrder(ctx, order); !errors.Is(err, kraken.ErrOrderValidation) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrOrderValidation, err)
+	}
+
+	// the order is rejected before anything is written to the connection
+	if requests := s.Requests(); len(requests) != 0 {
+		t.Errorf("EXPECTED: no requests\nACTUAL: %v", requests)
+	}
+
+	// orders without an AssetPair are not validated
+	order.AssetPair = nil
+	if _, err := c.AddOrder(ctx, order); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWSClientCancelOrder(t *testing.T) {
+	cancelled := make(chan []string, 1)
+	s, c, _ := newWSAuthTestServer(t, 15*time.Minute, map[string]krakentest.WSHandler{"cancelOrder": func(req krakentest.WSRequest) []krakentest.WSFrame {
+		order := wsTestOrder{}
+		if err := json.Unmarshal(req.Raw, &order); err != nil || len(order.TxID) == 0 {
+			t.Errorf("EXPECTED: cancelOrder of orders\nACTUAL: %v %v", order.TxID, err)
+			return nil
+		}
+		cancelled <- order.TxID
+
+		if order.TxID[0] == "UNKNOWN" {
+			return wsFrames(fmt.Sprintf(`{"errorMessage":"EOrder:Unknown order","event":"cancelOrderStatus","status":"error","reqid":%d}`, req.ReqID))
+		}
+
+		return wsFrames(fmt.Sprintf(`{"event":"cancelOrderStatus","status":"ok","reqid":%d}`, req.ReqID))
+	}})
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tcs := []struct {
+		name  string
+		txids []string
+	}{
+		{name: "TransactionIDs", txids: []string{"OGTT3Y-C6I3P-XRI6HX", "OKAGJC-YHIWK-WIOZWG"}},
+		{name: "UserReference", txids: []string{"1234"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := c.CancelOrder(ctx, tc.txids...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the websocket API reports no count of the orders cancelled
+			if res.Count != 0 {
+				t.Errorf("EXPECTED: 0\nACTUAL: %d", res.Count)
+			}
+
+			if diff := deep.Equal(<-cancelled, tc.txids); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+
+	if _, err := c.CancelOrder(ctx, "UNKNOWN", "OKAGJC-YHIWK-WIOZWG"); !errors.Is(err, kraken.ErrOrder) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrOrder, err)
+	}
+
+	if _, err := c.CancelOrder(ctx); err == nil {
+		t.Error("EXPECTED: txid is required\nACTUAL: <nil>")
+	}
+}
+
+func TestWSClientSubscribeErrors(t *testing.T) {
+	tcs := []struct {
+		name     string
+		pairs    []string
+		reply    func(s *krakentest.WSServer, req krakentest.WSRequest) []krakentest.WSFrame
+		expected string
+	}{
+		{
+			name:  "PairError",
+			pairs: []string{"XBT/USD", "FOO/BAR"},
+			reply: func(s *krakentest.WSServer, req krakentest.WSRequest) []krakentest.WSFrame {
+				unsupported := req
+				req.Pairs, unsupported.Pairs = req.Pairs[:1], req.Pairs[1:]
+
+				return append(s.Subscribe(req), krakentest.WSSubscriptionStatus(unsupported, "error", "Currency pair not supported FOO/BAR")...)
+			},
+			expected: "subscription error: ticker FOO/BAR: Currency pair not supported FOO/BAR",
+		},
+		{
+			name:  "RequestError",
+			pairs: []string{"XBT/USD", "ETH/USD"},
+			reply: func(s *krakentest.WSServer, req krakentest.WSRequest) []krakentest.WSFrame {
+				req.Pairs = nil
+
+				return krakentest.WSSubscriptionStatus(req, "error", "Subscription name invalid")
+			},
+			expected: "subscription error: ticker: Subscription name invalid",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			s, c := newWSTestServer(t)
+			defer s.Close()
+			defer c.Close()
+
+			s.Handle("subscribe", func(req krakentest.WSRequest) []krakentest.WSFrame { return tc.reply(s, req) })
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			_, err := c.SubscribeTicker(ctx, tc.pairs)
+			if !errors.Is(err, kraken.ErrSubscription) {
+				t.Fatalf("EXPECTED: %v\nACTUAL: %v", kraken.ErrSubscription, err)
+			}
+
+			if err.Error() != tc.expected {
+				t.Errorf("EXPECTED: %s\nACTUAL: %s", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestWSClientSubscribeTimeout(t *testing.T) {
+	s, c := newWSTestServer(t, krakentest.WSServerWithHandler("subscribe", func(req krakentest.WSRequest) []krakentest.WSFrame { return nil }))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.SubscribeTicker(ctx, []string{"XBT/USD"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestWSClientUnsubscribe(t *testing.T) {
+	s, c := newWSTestServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	s.Handle("unsubscribe", func(req krakentest.WSRequest) []krakentest.WSFrame {
+		if len(req.Pairs) != 1 || req.Pairs[0] != "XBT/USD" {
+			t.Errorf("EXPECTED: XBT/USD unsubscribed\nACTUAL: %v", req.Pairs)
+		}
+
+		return append(s.Unsubscribe(req), wsFrames(wsTicker("XBT/USD"), wsTicker("ETH/USD"))...)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	xbt, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eth, err := c.SubscribeTicker(ctx, []string{"ETH/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := xbt.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for range xbt.C {
+		t.Error("EXPECTED: closed channel\nACTUAL: ticker delivered")
+	}
+
+	if err := xbt.Unsubscribe(ctx); err != nil {
+		t.Errorf("EXPECTED: <nil>\nACTUAL: %v", err)
+	}
+
+	select {
+	case ticker, ok := <-eth.C:
+		if !ok || ticker.Pair != "ETH/USD" {
+			t.Errorf("EXPECTED: ETH/USD ticker\nACTUAL: %v %v", ticker.Pair, ok)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestWSClientSubscribeDuplicate(t *testing.T) {
+	s, c := newWSTestServer(t, wsScript("ticker", "XBT/USD", wsTicker("XBT/USD")))
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "subscription error: ticker XBT/USD: already subscribed"
+	if _, err := c.SubscribeTicker(ctx, []string{"ETH/USD", "XBT/USD"}); !errors.Is(err, kraken.ErrSubscription) || err.Error() != expected {
+		t.Errorf("EXPECTED: %s\nACTUAL: %v", expected, err)
+	}
+
+	select {
+	case ticker, ok := <-tickers.C:
+		if !ok || ticker.Pair != "XBT/USD" {
+			t.Errorf("EXPECTED: XBT/USD ticker\nACTUAL: %v %v", ticker.Pair, ok)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// the pairs of a rejected subscription are not reserved
+	if _, err := c.SubscribeTicker(ctx, []string{"ETH/USD"}); err != nil {
+		t.Errorf("EXPECTED: <nil>\nACTUAL: %v", err)
+	}
+
+	if err := tickers.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.SubscribeTicker(ctx, []string{"XBT/USD"}); err != nil {
+		t.Errorf("EXPECTED: <nil>\nACTUAL: %v", err)
+	}
+}
+
+func TestWSClientUnsubscribeClosed(t *testing.T) {
+	s, c := newWSTestServer(t)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Close()
+
+	if err := tickers.Unsubscribe(ctx); err != nil {
+		t.Errorf("EXPECTED: <nil>\nACTUAL: %v", err)
+	}
+
+	if _, ok := <-tickers.C; ok {
+		t.Error("EXPECTED: closed channel\nACTUAL: ticker delivered")
+	}
+}
+
+func TestWSClientClose(t *testing.T) {
+	s, c := newWSTestServer(t)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Close()
+
+	select {
+	case _, ok := <-tickers.C:
+		if ok {
+			t.Fatal("EXPECTED: closed channel\nACTUAL: ticker delivered")
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	if err := c.Err(); !errors.Is(err, kraken.ErrWSClosed) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrWSClosed, err)
+	}
+
+	if _, err := c.SubscribeTicker(ctx, []string{"XBT/USD"}); !errors.Is(err, kraken.ErrWSClosed) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrWSClosed, err)
+	}
+}
+
+func TestWSClientParseError(t *testing.T) {
+	errs := make(chan error, 1)
+	s, c := newWSTestServer(t,
+		wsScript("ticker", "XBT/USD", `[340,{"a":["38659.60000"]},"ticker","XBT/USD"]`),
+		krakentest.WSServerWithClientOptions(kraken.WSClientWithErrorHandler(func(err error) { errs <- err })),
+	)
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := c.SubscribeTicker(ctx, []string{"XBT/USD"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, kraken.ErrParse) {
+			t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrParse, err)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestWSClientWithURL(t *testing.T) {
+	for _, u := range []string{"https://ws.kraken.com", "ws://", "::"} {
+		if _, err := kraken.NewWSClient(kraken.WSClientWithURL(u)); err == nil {
+			t.Errorf("EXPECTED: error for %q\nACTUAL: <nil>", u)
+		}
+	}
+}
+
+// wsTestV2Params the params of a v2 request received by a websocket test
+// server
+type wsTestV2Params struct {
+	Channel      string   `json:"channel"`
+	Symbol       []string `json:"symbol"`
+	Interval     int      `json:"interval,omitempty"`
+	Depth        int      `json:"depth,omitempty"`
+	EventTrigger string   `json:"event_trigger,omitempty"`
+}
+
+// wsV2Receive receive n values of a subscription
+func wsV2Receive[T any](ctx context.Context, sub *kraken.Subscription[T], err error, n int) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{}
+	for len(values) < n {
+		select {
+		case v := <-sub.C:
+			values = append(values, v)
+		case <-ctx.Done():
+			return values, ctx.Err()
+		}
+	}
+
+	return values, nil
+}
+
+func TestWSClientV2(t *testing.T) {
+	raw, err := os.ReadFile("testdata/fixtures/wsv2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := map[string][]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name      string
+		fixture   string
+		subscribe func(ctx context.Context, c *kraken.WSClient) ([]interface{}, error)
+		params    wsTestV2Params
+		expected  []interface{}
+	}{
+		{
+			name:    "ticker",
+			fixture: "ticker",
+			subscribe: func(ctx context.Context, c *kraken.WSClient) ([]interface{}, error) {
+				sub, err := c.SubscribeTicker(ctx, []string{"ALGO/USD"})
+				return wsV2Receive(ctx, sub, err, 2)
+			},
+			params: wsTestV2Params{Channel: "ticker", Symbol: []string{"ALGO/USD"}},
+			expected: []interface{}{
+				kraken.Ticker{
+					Pair:                                  "ALGO/USD",
+					Ask:                                   kraken.AskBid{Price: decimal.RequireFromString("0.10036"), Volume: decimal.RequireFromString("1361.44813783")},
+					Bid:                                   kraken.AskBid{Price: decimal.RequireFromString("0.10025"), Volume: decimal.RequireFromString("740")},
+					LastClose:                             kraken.Close{Price: decimal.RequireFromString("0.10035")},
+					VolumeLast24Hours:                     decimal.RequireFromString("997038.98383185"),
+					VolumeWeightedAveragePriceLast24Hours: decimal.RequireFromString("0.10148"),
+					LowLast24Hours:                        decimal.RequireFromString("0.09979"),
+					HighLast24Hours:                       decimal.RequireFromString("0.10285"),
+					Open:                                  decimal.RequireFromString("0.10052"),
+				},
+				kraken.Ticker{
+					Pair:                                  "ALGO/USD",
+					Ask:                                   kraken.AskBid{Price: decimal.RequireFromString("0.10036"), Volume: decimal.RequireFromString("1361.44813783")},
+					Bid:                                   kraken.AskBid{Price: decimal.RequireFromString("0.10026"), Volume: decimal.RequireFromString("500")},
+					LastClose:                             kraken.Close{Price: decimal.RequireFromString("0.10036")},
+					VolumeLast24Hours:                     decimal.RequireFromString("997040.12"),
+					VolumeWeightedAveragePriceLast24Hours: decimal.RequireFromString("0.10148"),
+					LowLast24Hours:                        decimal.RequireFromString("0.09979"),
+					HighLast24Hours:                       decimal.RequireFromString("0.10285"),
+					Open:                                  decimal.RequireFromString("0.10052"),
+				},
+			},
+		},
+		{
+			name:    "spread",
+			fixture: "ticker",
+			subscribe: func(ctx context.Context, c *kraken.WSClient) ([]interface{}, error) {
+				sub, err := c.SubscribeSpread(ctx, []string{"ALGO/USD"})
+				return wsV2Receive(ctx, sub, err, 2)
+			},
+			params: wsTestV2Params{Channel: "ticker", Symbol: []string{"ALGO/USD"}, EventTrigger: "bbo"},
+			expected: []interface{}{
+				kraken.SpreadUpdate{Pair: "ALGO/USD", Spread: kraken.Spread{Bid: decimal.RequireFromString("0.10025"), Ask: decimal.RequireFromString("0.10036")}},
+				kraken.SpreadUpdate{Pair: "ALGO/USD", Spread: kraken.Spread{Bid: decimal.RequireFromString("0.10026"), Ask: decimal.RequireFromString("0.10036")}},
+			},
+		},
+		{
+			name:    "ohlc",
+			fixture: "ohlc",
+			subscribe: func(ctx context.Context, c *kraken.WSClient) ([]interface{}, error) {
+				sub, err := c.SubscribeOHLC(ctx, kraken.OHLCInterval5Minutes, []string{"MATIC/USD"})
+				return wsV2Receive(ctx, sub, err, 2)
+			},
+			params: wsTestV2Params{Channel: "ohlc", Symbol: []string{"MATIC/USD"}, Interval: 5},
+			expected: []interface{}{
+				kraken.OHLCUpdate{
+					Pair: "MATIC/USD",
+					OHLC: kraken.OHLC{
+						Time:                       time.Date(2023, 10, 4, 16, 25, 0, 0, time.UTC),
+						Open:                       decimal.RequireFromString("0.5624"),
+						High:                       decimal.RequireFromString("0.5628"),
+						Low:                        decimal.RequireFromString("0.5622"),
+						Close:                      decimal.RequireFromString("0.5627"),
+						Volume:                     decimal.RequireFromString("30927.68066226"),
+						VolumeWeightedAveragePrice: decimal.RequireFromString("0.5626"),
+						Count:                      12,
+					},
+					EndTime: time.Date(2023, 10, 4, 16, 30, 0, 0, time.UTC),
+				},
+				kraken.OHLCUpdate{
+					Pair: "MATIC/USD",
+					OHLC: kraken.OHLC{
+						Time:                       time.Date(2023, 10, 4, 16, 30, 0, 0, time.UTC),
+						Open:                       decimal.RequireFromString("0.5627"),
+						High:                       decimal.RequireFromString("0.5627"),
+						Low:                        decimal.RequireFromString("0.5625"),
+						Close:                      decimal.RequireFromString("0.5625"),
+						Volume:                     decimal.RequireFromString("151.2"),
+						VolumeWeightedAveragePrice: decimal.RequireFromString("0.5626"),
+						Count:                      2,
+					},
+					EndTime: time.Date(2023, 10, 4, 16, 35, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:    "trade",
+			fixture: "trade",
+			subscribe: func(ctx context.Context, c *kraken.WSClient) ([]interface{}, error) {
+				sub, err := c.SubscribeTrades(ctx, []string{"MATIC/USD"})
+				return wsV2Receive(ctx, sub, err, 2)
+			},
+			params: wsTestV2Params{Channel: "trade", Symbol: []string{"MATIC/USD"}},
+			expected: []interface{}{
+				kraken.TradeUpdate{Pair: "MATIC/USD", Trade: kraken.RecentTrade{
+					Price:   decimal.RequireFromString("0.5117"),
+					Volume:  decimal.RequireFromString("40"),
+					Time:    time.Date(2023, 9, 25, 7, 49, 37, 708706000, time.UTC),
+					Action:  kraken.OrderActionSell,
+					Type:    kraken.OrderTypeMarket,
+					TradeID: 4665906,
+				}},
+				kraken.TradeUpdate{Pair: "MATIC/USD", Trade: kraken.RecentTrade{
+					Price:   decimal.RequireFromString("0.5118"),
+					Volume:  decimal.RequireFromString("12.5"),
+					Time:    time.Date(2023, 9, 25, 7, 49, 38, 100000000, time.UTC),
+					Action:  kraken.OrderActionBuy,
+					Type:    kraken.OrderTypeLimit,
+					TradeID: 4665907,
+				}},
+			},
+		},
+		{
+			name:    "book",
+			fixture: "book",
+			subscribe: func(ctx context.Context, c *kraken.WSClient) ([]interface{}, error) {
+				sub, err := c.SubscribeBook(ctx, 10, []string{"MATIC/USD"})
+				return wsV2Receive(ctx, sub, err, 2)
+			},
+			params: wsTestV2Params{Channel: "book", Symbol: []string{"MATIC/USD"}, Depth: 10},
+			expected: []interface{}{
+				kraken.BookUpdate{
+					Pair:     "MATIC/USD",
+					Depth:    10,
+					Snapshot: true,
+					Asks: []kraken.AskBid{
+						{Price: decimal.RequireFromString("0.5668"), Volume: decimal.RequireFromString("4410.79769272")},
+						{Price: decimal.RequireFromString("0.5669"), Volume: decimal.RequireFromString("4655.40412013")},
+					},
+					Bids: []kraken.AskBid{
+						{Price: decimal.RequireFromString("0.5666"), Volume: decimal.RequireFromString("4831.75496356")},
+						{Price: decimal.RequireFromString("0.5665"), Volume: decimal.RequireFromString("6658.22734739")},
+					},
+					Checksum: 2439117997,
+				},
+				kraken.BookUpdate{
+					Pair:  "MATIC/USD",
+					Depth: 10,
+					Asks:  []kraken.AskBid{},
+					Bids: []kraken.AskBid{
+						{Price: decimal.RequireFromString("0.5657"), Volume: decimal.RequireFromString("1098.3947558"), Timestamp: time.Date(2023, 10, 6, 17, 35, 55, 440295000, time.UTC)},
+					},
+					Checksum: 2114181697,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			params := make(chan wsTestV2Params, 1)
+			s, c := newWSTestServer(t, krakentest.WSServerWithClientOptions(kraken.WSClientWithVersion(kraken.WSVersion2)))
+			defer s.Close()
+			defer c.Close()
+
+			s.Handle("subscribe", func(req krakentest.WSRequest) []krakentest.WSFrame {
+				v2 := struct {
+					Params wsTestV2Params `json:"params"`
+				}{}
+				if err := json.Unmarshal(req.Raw, &v2); err != nil {
+					t.Error(err)
+				}
+				params <- v2.Params
+
+				frames := s.Subscribe(req)
+				for _, msg := range fixtures[tc.fixture] {
+					frames = append(frames, krakentest.WSMessage(string(msg)))
+				}
+
+				return frames
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			actual, err := tc.subscribe(ctx, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(tc.params, <-params); diff != nil {
+				t.Error(diff)
+			}
+
+			if diff := deep.Equal(tc.expected, actual); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestWSClientV2Errors(t *testing.T) {
+	m, err := krakentest.NewMockClient(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, c := newWSTestServer(t,
+		krakentest.WSServerWithHandler("subscribe", func(req krakentest.WSRequest) []krakentest.WSFrame {
+			return krakentest.WSSubscriptionStatus(req, "error", "Currency pair not supported ABC/USD")
+		}),
+		krakentest.WSServerWithClientOptions(kraken.WSClientWithVersion(kraken.WSVersion2), kraken.WSClientWithAuthentication(m)),
+	)
+	defer s.Close()
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := c.SubscribeTicker(ctx, []string{"ABC/USD"}); !errors.Is(err, kraken.ErrSubscription) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrSubscription, err)
+	}
+
+	if _, err := c.SubscribeOwnTrades(ctx); err == nil {
+		t.Error("EXPECTED: error for a private channel\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewOrderBookKeeper(c, 10, "XBT/USD"); err == nil {
+		t.Error("EXPECTED: error for book checksums\nACTUAL: <nil>")
+	}
+
+	if _, err := kraken.NewWSClient(kraken.WSClientWithVersion(kraken.WSVersion2 + 1)); err == nil {
+		t.Error("EXPECTED: error for an unknown version\nACTUAL: <nil>")
+	}
+}
@@ -0,0 +1,201 @@
+package kraken
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// WSClientOption options used when creating a new WSClient
+type WSClientOption func(c *WSClient) error
+
+// WSClientWithURL set the url of the websocket API, the url must use the ws
+// or wss scheme
+func WSClientWithURL(rawURL string) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid url: %s", err)
+		}
+
+		if (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return fmt.Errorf("invalid url: %s must use the ws or wss scheme and include a host", rawURL)
+		}
+
+		c.url = rawURL
+
+		return nil
+	})
+}
+
+// WSClientWithBufferSize set the number of parsed messages buffered on the
+// channel of each subscription unless SubscribeWithBufferSize is given, see
+// WSClientWithBackpressure for the behaviour once a buffer is full
+func WSClientWithBufferSize(size int) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		if size < 0 {
+			return fmt.Errorf("buffer size must not be negative")
+		}
+
+		c.bufferSize = size
+
+		return nil
+	})
+}
+
+// WSClientWithBackpressure set the behaviour of subscriptions when their
+// buffer is full unless SubscribeWithBackpressure is given, by default
+// reading from the connection blocks
+func WSClientWithBackpressure(backpressure WSBackpressure) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		if err := validateBackpressure(backpressure); err != nil {
+			return err
+		}
+
+		c.backpressure = backpressure
+
+		return nil
+	})
+}
+
+// validateBackpressure check backpressure is a known behaviour
+func validateBackpressure(backpressure WSBackpressure) error {
+	switch backpressure {
+	case WSBackpressureBlock, WSBackpressureDrop, WSBackpressureDropOldest:
+		return nil
+	default:
+		return fmt.Errorf("unknown backpressure %d", backpressure)
+	}
+}
+
+// WSClientWithAuthentication authenticate private channels with tokens from
+// the GetWebSocketsToken endpoint of client, the client connects to the
+// authenticated API unless WSClientWithURL is given. A token is requested when
+// subscribing to a private channel or placing an order and is renewed at the
+// next of these close to its expiry, not on a timer. The client does not
+// reconnect, when the connection ends private subscriptions are closed with
+// every other and must be subscribed to again, which requests a new token
+func WSClientWithAuthentication(client Client) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		if client == nil {
+			return fmt.Errorf("client is required")
+		}
+
+		c.auth = client
+
+		return nil
+	})
+}
+
+// WSClientWithVersion set the version of the websocket API protocol, the
+// client connects to the API of the version unless WSClientWithURL is given.
+// Messages of either version are delivered as the same types, although v2
+// tickers only have the values of the last 24 hours and v2 spreads have no
+// timestamp
+func WSClientWithVersion(version WSVersion) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		switch version {
+		case WSVersion1, WSVersion2:
+		default:
+			return fmt.Errorf("unknown websocket version %d", version)
+		}
+
+		c.version = version
+
+		return nil
+	})
+}
+
+// WSClientWithInstrumentation add prometheus metrics of the messages,
+// subscriptions and connections of the client, registered as by
+// NewInstrumentationClient with opts
+func WSClientWithInstrumentation(opts ...InstrumentationOption) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		metrics, err := newWSMetrics(opts)
+		if err != nil {
+			return err
+		}
+
+		c.metrics = metrics
+
+		return nil
+	})
+}
+
+// WSClientWithErrorHandler set a function called with errors handling
+// messages which cannot be returned to a caller, such as a channel message
+// which cannot be parsed. The function is called from the connection's read
+// loop so must not block
+func WSClientWithErrorHandler(handler func(error)) WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		c.onError = handler
+
+		return nil
+	})
+}
+
+// WSClientWithOrderValidation check orders with an AssetPair against it with
+// ValidateOrder before they are sent, failing with the violations joined into
+// an error matching ErrOrderValidation
+func WSClientWithOrderValidation() WSClientOption {
+	return WSClientOption(func(c *WSClient) error {
+		c.validateOrders = true
+
+		return nil
+	})
+}
+
+// SubscribeOption options used when subscribing with a WSClient
+type SubscribeOption func(c *wsSubscribeConfig) error
+
+// SubscribeWithBufferSize set the number of parsed messages buffered on the
+// channel of the subscription, or on the channel of each pair with
+// SubscribeWithPairChannels
+func SubscribeWithBufferSize(size int) SubscribeOption {
+	return SubscribeOption(func(c *wsSubscribeConfig) error {
+		if size < 0 {
+			return fmt.Errorf("buffer size must not be negative")
+		}
+
+		c.bufferSize = size
+
+		return nil
+	})
+}
+
+// SubscribeWithBackpressure set the behaviour of the subscription when a
+// buffer is full, a blocked subscription stops reading from the connection
+// so delays the messages of every other subscription
+func SubscribeWithBackpressure(backpressure WSBackpressure) SubscribeOption {
+	return SubscribeOption(func(c *wsSubscribeConfig) error {
+		if err := validateBackpressure(backpressure); err != nil {
+			return err
+		}
+
+		c.backpressure = backpressure
+
+		return nil
+	})
+}
+
+// SubscribeWithPairChannels deliver the messages of each pair on a channel
+// of its own, see Subscription.Pair. With a drop backpressure a consumer of
+// one pair which has stalled does not delay the other pairs
+func SubscribeWithPairChannels() SubscribeOption {
+	return SubscribeOption(func(c *wsSubscribeConfig) error {
+		c.pairChannels = true
+
+		return nil
+	})
+}
+
+// SubscribeWithOHLCGapHandler detect gaps between the updates of each pair
+// of an OHLC subscription, handler is called with each gap before the
+// update after it is delivered. The function is called from the
+// connection's read loop so must not block
+func SubscribeWithOHLCGapHandler(handler func(OHLCGap)) SubscribeOption {
+	return SubscribeOption(func(c *wsSubscribeConfig) error {
+		c.onOHLCGap = handler
+
+		return nil
+	})
+}
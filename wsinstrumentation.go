@@ -0,0 +1,196 @@
+package kraken
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// wsMetrics the prometheus metrics of a WSClient, nothing is recorded by a
+// nil *wsMetrics so a client without instrumentation has none
+type wsMetrics struct {
+	messages           *prometheus.CounterVec
+	processingDuration *prometheus.HistogramVec
+	droppedCount       *prometheus.CounterVec
+	subscriptionErrors *prometheus.CounterVec
+	reconnects         prometheus.Counter
+	subscriptions      prometheus.Gauge
+	connected          prometheus.Gauge
+}
+
+// newWSMetrics create and register the metrics of a WSClient, configured by
+// the defaults and opts as an InstrumentationClient would be
+func newWSMetrics(opts []InstrumentationOption) (*wsMetrics, error) {
+	c, err := newInstrumentationConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_messages_total",
+			Help:      "Number of Kraken websocket channel messages received.",
+		},
+		[]string{"channel", "pair"},
+	)
+
+	processingDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_processing_duration_seconds",
+			Help:      "Duration from receiving a Kraken websocket channel message to delivering it to its subscription.",
+			Buckets:   c.durationBuckets,
+		},
+		[]string{"channel"},
+	)
+
+	droppedCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_dropped_messages_total",
+			Help:      "Number of Kraken websocket values dropped as the buffer of their subscription was full.",
+		},
+		[]string{"channel", "pair"},
+	)
+
+	subscriptionErrors := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_subscription_errors_total",
+			Help:      "Number of Kraken websocket subscribe requests which failed.",
+		},
+		[]string{"channel"},
+	)
+
+	reconnects := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_reconnects_total",
+			Help:      "Number of Kraken websocket connections opened after the first.",
+		},
+	)
+
+	subscriptions := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_subscriptions",
+			Help:      "Number of Kraken websocket channels subscribed to for a pair.",
+		},
+	)
+
+	connected := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: c.namespace,
+			Subsystem: c.subsystem,
+			Name:      "ws_connected",
+			Help:      "Whether the Kraken websocket client is connected.",
+		},
+	)
+
+	m := wsMetrics{}
+	if m.messages, err = registerCounterVec(c.registerer, messages); err != nil {
+		return nil, err
+	}
+
+	if m.processingDuration, err = registerHistogramVec(c.registerer, processingDuration); err != nil {
+		return nil, err
+	}
+
+	if m.droppedCount, err = registerCounterVec(c.registerer, droppedCount); err != nil {
+		return nil, err
+	}
+
+	if m.subscriptionErrors, err = registerCounterVec(c.registerer, subscriptionErrors); err != nil {
+		return nil, err
+	}
+
+	if m.reconnects, err = registerCounter(c.registerer, reconnects); err != nil {
+		return nil, err
+	}
+
+	if m.subscriptions, err = registerGauge(c.registerer, subscriptions); err != nil {
+		return nil, err
+	}
+
+	if m.connected, err = registerGauge(c.registerer, connected); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// received count a channel message received for a pair
+func (m *wsMetrics) received(channel, pair string) {
+	if m == nil {
+		return
+	}
+
+	m.messages.WithLabelValues(channel, pair).Inc()
+}
+
+// processed observe the duration since a channel message delivered to its
+// subscription was received
+func (m *wsMetrics) processed(channel string, received time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.processingDuration.WithLabelValues(channel).Observe(time.Since(received).Seconds())
+}
+
+// droppedMessages count the values of a pair dropped by a subscription
+func (m *wsMetrics) droppedMessages(channel, pair string, dropped int) {
+	if m == nil {
+		return
+	}
+
+	m.droppedCount.WithLabelValues(channel, pair).Add(float64(dropped))
+}
+
+// subscriptionError count a failed subscribe request
+func (m *wsMetrics) subscriptionError(channel string) {
+	if m == nil {
+		return
+	}
+
+	m.subscriptionErrors.WithLabelValues(channel).Inc()
+}
+
+// reconnected count a connection opened after the first, by Connect or a
+// request once the previous connection has ended
+func (m *wsMetrics) reconnected() {
+	if m == nil {
+		return
+	}
+
+	m.reconnects.Inc()
+}
+
+// setSubscriptions set the number of channels subscribed to for a pair
+func (m *wsMetrics) setSubscriptions(n int) {
+	if m == nil {
+		return
+	}
+
+	m.subscriptions.Set(float64(n))
+}
+
+// setConnected set whether the client is connected
+func (m *wsMetrics) setConnected(connected bool) {
+	if m == nil {
+		return
+	}
+
+	if connected {
+		m.connected.Set(1)
+	} else {
+		m.connected.Set(0)
+	}
+}
@@ -0,0 +1,145 @@
+package kraken_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/oliread/kraken"
+	"github.com/oliread/kraken/krakentest"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherWSMetrics the value of each metric of a registry keyed by its name
+// and labels, the sample count is given for histograms
+func gatherWSMetrics(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := []string{}
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%s", label.GetName(), label.GetValue()))
+			}
+			sort.Strings(labels)
+
+			key := family.GetName()
+			if len(labels) != 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+
+			switch {
+			case metric.GetHistogram() != nil:
+				values[key] = float64(metric.GetHistogram().GetSampleCount())
+			case metric.GetGauge() != nil:
+				values[key] = metric.GetGauge().GetValue()
+			default:
+				values[key] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return values
+}
+
+func TestWSClientWithInstrumentation(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s, c := newWSTestServer(t,
+		wsScript("ticker", "XBT/USD", wsTicker("XBT/USD"), wsTicker("ETH/USD")),
+		wsScript("trade", "XBT/USD", wsTrades, wsTicker("XBT/USD")),
+		krakentest.WSServerWithClientOptions(
+			kraken.WSClientWithInstrumentation(kraken.InstrumentationWithRegisterer(reg), kraken.InstrumentationWithNamespace("test")),
+			kraken.WSClientWithErrorHandler(func(error) {}),
+		),
+	)
+	defer s.Close()
+	defer c.Close()
+
+	s.Handle("subscribe", func(req krakentest.WSRequest) []krakentest.WSFrame {
+		switch {
+		case req.Subscription.Name == "spread":
+			return []krakentest.WSFrame{krakentest.WSDrop()}
+		case len(req.Pairs) == 1 && req.Pairs[0] == "ABC/USD":
+			return krakentest.WSSubscriptionStatus(req, "error", "Currency pair not supported ABC/USD")
+		}
+
+		return s.Subscribe(req)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tickers, err := c.SubscribeTicker(ctx, []string{"XBT/USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.SubscribeTrades(ctx, []string{"XBT/USD"}, kraken.SubscribeWithBufferSize(1), kraken.SubscribeWithBackpressure(kraken.WSBackpressureDrop)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the status of a request is handled after every message before it, so
+	// the metrics of those messages are recorded once it has returned
+	if _, err := c.SubscribeTicker(ctx, []string{"ABC/USD"}); !errors.Is(err, kraken.ErrSubscription) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrSubscription, err)
+	}
+
+	expected := map[string]float64{
+		`test_ws_messages_total{channel=ticker,pair=XBT/USD}`:        2,
+		`test_ws_messages_total{channel=ticker,pair=ETH/USD}`:        1,
+		`test_ws_messages_total{channel=trade,pair=XBT/USD}`:         1,
+		`test_ws_processing_duration_seconds{channel=ticker}`:        2,
+		`test_ws_processing_duration_seconds{channel=trade}`:         1,
+		`test_ws_dropped_messages_total{channel=trade,pair=XBT/USD}`: 2,
+		`test_ws_subscription_errors_total{channel=ticker}`:          1,
+		`test_ws_reconnects_total`:                                   0,
+		`test_ws_subscriptions`:                                      2,
+		`test_ws_connected`:                                          1,
+	}
+	if diff := deep.Equal(expected, gatherWSMetrics(t, reg)); diff != nil {
+		t.Error(diff)
+	}
+
+	// the server closes the connection when subscribing to spreads
+	if _, err := c.SubscribeSpread(ctx, []string{"XBT/USD"}); !errors.Is(err, kraken.ErrWSClosed) {
+		t.Errorf("EXPECTED: %v\nACTUAL: %v", kraken.ErrWSClosed, err)
+	}
+
+	for range tickers.C {
+	}
+
+	actual := gatherWSMetrics(t, reg)
+	for key, value := range map[string]float64{
+		`test_ws_subscription_errors_total{channel=spread}`: 1,
+		`test_ws_subscriptions`:                             0,
+		`test_ws_connected`:                                 0,
+	} {
+		if actual[key] != value {
+			t.Errorf("%s EXPECTED: %v\nACTUAL: %v", key, value, actual[key])
+		}
+	}
+
+	if _, err := c.SubscribeTicker(ctx, []string{"XBT/USD"}); err != nil {
+		t.Fatal(err)
+	}
+
+	actual = gatherWSMetrics(t, reg)
+	for key, value := range map[string]float64{
+		`test_ws_reconnects_total`: 1,
+		`test_ws_subscriptions`:    1,
+		`test_ws_connected`:        1,
+	} {
+		if actual[key] != value {
+			t.Errorf("%s EXPECTED: %v\nACTUAL: %v", key, value, actual[key])
+		}
+	}
+}